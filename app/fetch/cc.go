@@ -0,0 +1,91 @@
+package fetch
+
+/*
+#include "../../csrc/fetch/cc.h"
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+
+	"github.com/zyedidia/generic"
+)
+
+// Defaults and limits of CcConfig.
+const (
+	DefaultFixedCwnd     = 64
+	MaxFixedCwnd     int = math.MaxInt32
+)
+
+// CcAlgorithm indicates a congestion control algorithm.
+type CcAlgorithm string
+
+// CcAlgorithm values.
+const (
+	// CcCubic is TCP CUBIC, as specified in RFC 8312.
+	CcCubic CcAlgorithm = "cubic"
+
+	// CcAimd is additive increase multiplicative decrease with slow start, similar to TCP Reno.
+	CcAimd CcAlgorithm = "aimd"
+
+	// CcBbr is a BBR-style model-based algorithm.
+	// It estimates bottleneck bandwidth and minimum RTT from Data arrivals, and sets the congestion
+	// window to the estimated bandwidth-delay product multiplied by a gain that cycles to probe for
+	// more bandwidth. Unlike BBR, there is no pacing: the gain is applied to the congestion window.
+	CcBbr CcAlgorithm = "bbr"
+
+	// CcFixed uses a fixed congestion window.
+	// It does not react to congestion signals.
+	CcFixed CcAlgorithm = "fixed"
+)
+
+var ccAlgorithms = map[CcAlgorithm]C.FetchCcAlgo{
+	"":      C.FetchCcCubic,
+	CcCubic: C.FetchCcCubic,
+	CcAimd:  C.FetchCcAimd,
+	CcBbr:   C.FetchCcBbr,
+	CcFixed: C.FetchCcFixed,
+}
+
+// CcConfig contains congestion control configuration.
+//
+// In all algorithms except CcFixed, the congestion window is decreased, at most once per RTO,
+// upon RTO expiry, Data with congestion mark, or Nack~Congestion.
+// Nack~Congestion additionally causes immediate retransmission.
+// Other Nack reasons, such as NoRoute, are counted, and the Interest is retransmitted after RTO.
+type CcConfig struct {
+	// CongestionControl selects the congestion control algorithm.
+	// Default is CcCubic.
+	CongestionControl CcAlgorithm `json:"congestionControl,omitempty"`
+
+	// FixedCwnd is the congestion window of CcFixed algorithm.
+	// Default is DefaultFixedCwnd. Maximum is MaxFixedCwnd.
+	FixedCwnd int `json:"fixedCwnd,omitempty"`
+}
+
+func (cfg *CcConfig) validate() error {
+	if _, ok := ccAlgorithms[cfg.CongestionControl]; !ok {
+		return fmt.Errorf("unknown congestion control algorithm %s", cfg.CongestionControl)
+	}
+	if cfg.FixedCwnd <= 0 {
+		cfg.FixedCwnd = DefaultFixedCwnd
+	}
+	cfg.FixedCwnd = generic.Clamp(cfg.FixedCwnd, 1, MaxFixedCwnd)
+	return nil
+}
+
+// inherit fills zero fields from fetcher-level defaults.
+func (cfg CcConfig) inherit(dflt CcConfig) CcConfig {
+	if cfg.CongestionControl == "" {
+		cfg.CongestionControl = dflt.CongestionControl
+	}
+	if cfg.FixedCwnd == 0 {
+		cfg.FixedCwnd = dflt.FixedCwnd
+	}
+	return cfg
+}
+
+func (cfg CcConfig) apply(cc *C.FetchCc) {
+	cc.algo = ccAlgorithms[cfg.CongestionControl]
+	cc.fixedCwnd = C.uint32_t(cfg.FixedCwnd)
+}
@@ -0,0 +1,373 @@
+package fetch
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/rdr/ndn6file"
+	"github.com/usnistgov/ndn-dpdk/ndni"
+	"go.uber.org/zap"
+)
+
+// Defaults of DirDef.
+const (
+	DefaultDirConcurrency = 4
+	DefaultDirMaxDepth    = 32
+
+	dirPollInterval = 10 * time.Millisecond
+)
+
+var (
+	lastDirContextID int
+	dirContextByID   = map[int]*DirContext{}
+)
+
+// DirDef defines a recursive fetch task that retrieves a directory tree from ndn6-file-server.
+//
+// The fetcher retrieves the directory metadata and listing, creates the local directory, and descends into
+// subdirectories.
+// After the whole tree has been listed, each file is fetched as a TaskDef with Discover enabled.
+type DirDef struct {
+	// InterestTemplateConfig contains the unversioned directory name prefix, InterestLifetime, etc.
+	ndni.InterestTemplateConfig
+
+	// CcConfig selects the congestion control algorithm of file tasks.
+	CcConfig
+
+	// OutputDir is the local output directory.
+	// It is created if it does not exist.
+	OutputDir string `json:"outputDir"`
+
+	// Concurrency is the maximum number of simultaneous file tasks.
+	// Default is DefaultDirConcurrency.
+	// It is further limited by the number of task slots.
+	Concurrency int `json:"concurrency,omitempty"`
+
+	// MaxDepth is the maximum depth of subdirectories below the top directory.
+	// Deeper subdirectories are recorded as failures and not listed.
+	// This guards against directory cycles such as symbolic links on the server.
+	// Default is DefaultDirMaxDepth.
+	MaxDepth int `json:"maxDepth,omitempty"`
+}
+
+// DirFailure describes a file or directory that could not be retrieved.
+type DirFailure struct {
+	Name  ndn.Name `json:"name"`
+	Error string   `json:"error"`
+}
+
+// DirCounters contains progress counters of a recursive fetch task.
+type DirCounters struct {
+	Elapsed   time.Duration  `json:"elapsed" gqldesc:"Duration since start fetching."`
+	Finished  *time.Duration `json:"finished" gqldesc:"Duration between start and finish; null if not finished."`
+	Listed    bool           `json:"listed" gqldesc:"Whether the whole directory tree has been listed."`
+	NDirs     int            `json:"nDirs" gqldesc:"Directories listed."`
+	NFiles    int            `json:"nFiles" gqldesc:"Files found in directory listings."`
+	NRunning  int            `json:"nRunning" gqldesc:"Files being fetched."`
+	NFinished int            `json:"nFinished" gqldesc:"Files fetched successfully."`
+	NFailed   int            `json:"nFailed" gqldesc:"Files or directories that could not be retrieved."`
+	NOctets   int64          `json:"nOctets" gqldesc:"Total size of fetched files."`
+	Failures  []DirFailure   `json:"failures" gqldesc:"Files or directories that could not be retrieved."`
+}
+
+func (cnt DirCounters) String() string {
+	return fmt.Sprintf("%d dirs, %d files, %d running, %d finished, %d failed, %d octets",
+		cnt.NDirs, cnt.NFiles, cnt.NRunning, cnt.NFinished, cnt.NFailed, cnt.NOctets)
+}
+
+type dirFile struct {
+	name     ndn.Name
+	filename string
+}
+
+// DirContext provides contextual information about an active recursive fetch task.
+type DirContext struct {
+	d         DirDef
+	id        int
+	fetcher   *Fetcher
+	logger    *zap.Logger
+	startTime time.Time
+	stopping  chan struct{}
+	done      chan struct{}
+
+	mutex sync.Mutex
+	cnt   DirCounters
+}
+
+// Counters returns progress counters.
+func (dc *DirContext) Counters() (cnt DirCounters) {
+	dc.mutex.Lock()
+	defer dc.mutex.Unlock()
+	cnt = dc.cnt
+	cnt.Elapsed = time.Since(dc.startTime)
+	cnt.Failures = append([]DirFailure{}, dc.cnt.Failures...)
+	return cnt
+}
+
+// Finished determines whether all files have been either fetched or failed.
+func (dc *DirContext) Finished() bool {
+	select {
+	case <-dc.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stop aborts/stops the recursive fetch task.
+// This should be called even if the task has finished.
+func (dc *DirContext) Stop() {
+	close(dc.stopping)
+	<-dc.done
+	taskContextLock.Lock()
+	defer taskContextLock.Unlock()
+	delete(dirContextByID, dc.id)
+}
+
+func (dc *DirContext) isStopping() bool {
+	select {
+	case <-dc.stopping:
+		return true
+	default:
+		return false
+	}
+}
+
+func (dc *DirContext) update(f func(cnt *DirCounters)) {
+	dc.mutex.Lock()
+	defer dc.mutex.Unlock()
+	f(&dc.cnt)
+}
+
+func (dc *DirContext) fail(name ndn.Name, e error) {
+	dc.logger.Warn("recursive fetch failure", zap.Stringer("name", name), zap.Error(e))
+	dc.update(func(cnt *DirCounters) {
+		cnt.NFailed++
+		cnt.Failures = append(cnt.Failures, DirFailure{Name: name, Error: e.Error()})
+	})
+}
+
+// waitRetry waits before retrying an operation that failed due to ErrTooManyTasks.
+// Returns false if the task is stopping.
+func (dc *DirContext) waitRetry() bool {
+	select {
+	case <-dc.stopping:
+		return false
+	case <-time.After(dirPollInterval):
+		return true
+	}
+}
+
+func (dc *DirContext) run() {
+	defer close(dc.done)
+
+	var files []dirFile
+	dc.walk(dc.d.Prefix, dc.d.OutputDir, 0, &files)
+	dc.update(func(cnt *DirCounters) { cnt.Listed = true })
+
+	concurrency := dc.d.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultDirConcurrency
+	}
+	concurrency = min(concurrency, len(dc.fetcher.taskSlots))
+
+	jobs := make(chan dirFile)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range jobs {
+				dc.fetchFile(f)
+			}
+		}()
+	}
+FEED:
+	for _, f := range files {
+		select {
+		case <-dc.stopping:
+			break FEED
+		case jobs <- f:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	dc.update(func(cnt *DirCounters) {
+		finished := time.Since(dc.startTime)
+		cnt.Finished = &finished
+	})
+	dc.logger.Info("recursive fetch finished", zap.Stringer("counters", dc.Counters()))
+}
+
+func (dc *DirContext) walk(name ndn.Name, dir string, depth int, files *[]dirFile) {
+	if dc.isStopping() {
+		return
+	}
+	if depth > dc.d.MaxDepth {
+		dc.fail(name, fmt.Errorf("directory depth exceeds %d", dc.d.MaxDepth))
+		return
+	}
+
+	ls, e := dc.list(name)
+	if e == nil {
+		e = os.MkdirAll(dir, 0o777)
+	}
+	if e != nil {
+		dc.fail(name, e)
+		return
+	}
+	dc.update(func(cnt *DirCounters) { cnt.NDirs++ })
+
+	for _, entry := range ls {
+		filename := entry.Name()
+		if filename == "." || !filepath.IsLocal(filename) || strings.ContainsRune(filename, os.PathSeparator) {
+			dc.fail(name, fmt.Errorf("bad directory entry %q", filename))
+			continue
+		}
+
+		child := name.Append(ndn.MakeNameComponent(an.TtGenericNameComponent, []byte(filename)))
+		path := filepath.Join(dir, filename)
+		if entry.IsDir() {
+			dc.walk(child, path, depth+1, files)
+		} else {
+			*files = append(*files, dirFile{name: child, filename: path})
+			dc.update(func(cnt *DirCounters) { cnt.NFiles++ })
+		}
+	}
+}
+
+func (dc *DirContext) list(name ndn.Name) (ndn6file.DirectoryListing, error) {
+	ts, e := dc.fetcher.reserveSlot()
+	for errors.Is(e, ErrTooManyTasks) && dc.waitRetry() {
+		ts, e = dc.fetcher.reserveSlot()
+	}
+	if e != nil {
+		return nil, e
+	}
+	defer dc.fetcher.releaseSlot(ts)
+
+	face := dc.fetcher.Face()
+	cfg := dc.d.InterestTemplateConfig
+	cfg.Prefix = name
+	m, e := ts.retrieveMetadata(face, cfg)
+	if e != nil {
+		return nil, e
+	}
+	if !m.IsDir() {
+		return nil, fmt.Errorf("mode %o is not a directory", m.Mode)
+	}
+	return ts.retrieveListing(face, cfg, m.Name)
+}
+
+func (dc *DirContext) fetchFile(f dirFile) {
+	d := TaskDef{
+		InterestTemplateConfig: dc.d.InterestTemplateConfig,
+		Discover:               true,
+		CcConfig:               dc.d.CcConfig,
+		Filename:               f.filename,
+	}
+	d.Prefix = f.name
+
+	task, e := dc.fetcher.Fetch(d)
+	for errors.Is(e, ErrTooManyTasks) && dc.waitRetry() {
+		task, e = dc.fetcher.Fetch(d)
+	}
+	if e != nil {
+		if !dc.isStopping() {
+			dc.fail(f.name, e)
+		}
+		return
+	}
+
+	dc.update(func(cnt *DirCounters) { cnt.NRunning++ })
+	ticker := time.NewTicker(dirPollInterval)
+	defer ticker.Stop()
+WAIT:
+	for !task.Finished() && task.active() {
+		select {
+		case <-dc.stopping:
+			break WAIT
+		case <-ticker.C:
+		}
+	}
+	finished := task.Finished()
+	if task.active() {
+		task.Stop()
+	}
+	if e := task.Err(); e != nil {
+		dc.update(func(cnt *DirCounters) { cnt.NRunning-- })
+		dc.fail(f.name, e)
+		return
+	}
+
+	dc.update(func(cnt *DirCounters) {
+		cnt.NRunning--
+		if finished {
+			cnt.NFinished++
+			if size := task.d.FileSize; size != nil {
+				cnt.NOctets += *size
+			}
+		}
+	})
+	if !finished && !dc.isStopping() {
+		dc.fail(f.name, errors.New("fetch task removed"))
+	}
+}
+
+// FetchDir starts a recursive fetch task.
+func (fetcher *Fetcher) FetchDir(d DirDef) (dc *DirContext, e error) {
+	if d.OutputDir == "" {
+		return nil, errors.New("OutputDir is required")
+	}
+	if d.MaxDepth <= 0 {
+		d.MaxDepth = DefaultDirMaxDepth
+	}
+	d.CcConfig = d.CcConfig.inherit(fetcher.cfg.CcConfig)
+	if e = d.CcConfig.validate(); e != nil {
+		return nil, e
+	}
+
+	dc = &DirContext{
+		d:         d,
+		fetcher:   fetcher,
+		startTime: time.Now(),
+		stopping:  make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	taskContextLock.Lock()
+	lastDirContextID++
+	dc.id = lastDirContextID
+	dirContextByID[dc.id] = dc
+	taskContextLock.Unlock()
+
+	dc.logger = logger.With(
+		zap.Int("dir-id", dc.id),
+		zap.Stringer("prefix", d.Prefix),
+		zap.String("output-dir", d.OutputDir),
+	)
+	dc.logger.Info("recursive fetch start")
+	go dc.run()
+	return dc, nil
+}
+
+// Dirs returns running recursive fetch tasks.
+func (fetcher *Fetcher) Dirs() (list []*DirContext) {
+	taskContextLock.RLock()
+	defer taskContextLock.RUnlock()
+	list = []*DirContext{}
+	for _, dc := range dirContextByID {
+		if dc.fetcher == fetcher {
+			list = append(list, dc)
+		}
+	}
+	return
+}
@@ -0,0 +1,152 @@
+package fetch
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+	"github.com/usnistgov/ndn-dpdk/dpdk/pktmbuf"
+	"github.com/usnistgov/ndn-dpdk/iface"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/rdr"
+	"github.com/usnistgov/ndn-dpdk/ndn/rdr/ndn6file"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+	"github.com/usnistgov/ndn-dpdk/ndni"
+	"go.uber.org/zap"
+)
+
+// Limits and defaults of metadata discovery.
+const (
+	DefaultDiscoverLifetime = 1000 * time.Millisecond
+	DiscoverRetries         = 3
+
+	// MaxListingSegments is the maximum number of segments in a directory listing.
+	MaxListingSegments = 4096
+)
+
+// ErrDiscover indicates RDR metadata discovery failure.
+var ErrDiscover = errors.New("RDR metadata discovery failed")
+
+// express sends an Interest through the task slot and waits for a Data under the Interest name.
+// The Interest is retransmitted up to DiscoverRetries times.
+// The task slot must be reserved but not yet added to a worker, so that its RxQueueD has no other consumer.
+func (ts *taskSlot) express(face iface.Face, cfg ndni.InterestTemplateConfig) (*ndn.Data, error) {
+	lifetime := cfg.InterestLifetime.DurationOr(nnduration.Milliseconds(DefaultDiscoverLifetime / time.Millisecond))
+	cfg.InterestLifetime = nnduration.Milliseconds(lifetime / time.Millisecond)
+
+	var tpl ndni.InterestTemplate
+	cfg.Apply(&tpl)
+
+	mp := ndni.InterestMempool.Get(face.NumaSocket())
+	vec := make(pktmbuf.Vector, iface.MaxBurstSize)
+	for range DiscoverRetries {
+		mbufs, e := mp.Alloc(1)
+		if e != nil {
+			return nil, e
+		}
+		pkt := tpl.Encode(mbufs[0], nil, rand.Uint32())
+		pkt.SetPitToken([]byte{byte(ts.index)})
+		iface.TxBurst(face.ID(), []*ndni.Packet{pkt})
+
+		for deadline := time.Now().Add(lifetime); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+			n, _ := ts.RxQueueD().Pop(vec, eal.TscNow())
+			var data *ndn.Data
+			for _, mbuf := range vec[:n] {
+				if pkt := ndni.PacketFromPtr(mbuf.Ptr()); data == nil && pkt.Type() == ndni.PktData {
+					if d := pkt.ToNPacket().Data; d != nil && cfg.Prefix.IsPrefixOf(d.Name) {
+						data = d
+					}
+				}
+				mbuf.Close()
+			}
+			if data != nil {
+				return data, nil
+			}
+		}
+		logger.Debug("Interest timeout, retrying",
+			zap.Int("slot-index", int(ts.index)),
+			zap.Stringer("name", cfg.Prefix),
+		)
+	}
+	return nil, fmt.Errorf("Interest %s timed out", cfg.Prefix)
+}
+
+// retrieveMetadata retrieves RDR metadata with ndn6-file-server extensions.
+// cfg.Prefix should be the unversioned name.
+func (ts *taskSlot) retrieveMetadata(face iface.Face, cfg ndni.InterestTemplateConfig) (m ndn6file.Metadata, e error) {
+	interest := rdr.MakeDiscoveryInterest(cfg.Prefix)
+	cfg.Prefix, cfg.CanBePrefix, cfg.MustBeFresh = interest.Name, interest.CanBePrefix, interest.MustBeFresh
+
+	data, e := ts.express(face, cfg)
+	if e != nil {
+		return m, fmt.Errorf("%w: %w", ErrDiscover, e)
+	}
+	if e = m.UnmarshalBinary(data.Content); e != nil {
+		return m, fmt.Errorf("%w: %w", ErrDiscover, e)
+	}
+	if len(m.Name) == 0 {
+		return m, fmt.Errorf("%w: metadata has empty name", ErrDiscover)
+	}
+	return m, nil
+}
+
+// retrieveListing retrieves a directory listing.
+// name should be the versioned name from directory metadata.
+func (ts *taskSlot) retrieveListing(face iface.Face, cfg ndni.InterestTemplateConfig, name ndn.Name) (ls ndn6file.DirectoryListing, e error) {
+	var payload []byte
+	for i := range MaxListingSegments {
+		cfg.Prefix = name.Append(ndn.NameComponentFrom(an.TtSegmentNameComponent, tlv.NNI(i)))
+		cfg.CanBePrefix, cfg.MustBeFresh = false, false
+
+		data, e := ts.express(face, cfg)
+		if e != nil {
+			return nil, e
+		}
+		payload = append(payload, data.Content...)
+		if data.IsFinalBlock() {
+			e = ls.UnmarshalBinary(payload)
+			return ls, e
+		}
+	}
+	return nil, fmt.Errorf("directory listing %s exceeds %d segments", name, MaxListingSegments)
+}
+
+// discover retrieves RDR metadata through the task slot, and fills in versioned prefix and segment information.
+func (ts *taskSlot) discover(face iface.Face, d *TaskDef) error {
+	m, e := ts.retrieveMetadata(face, d.InterestTemplateConfig)
+	if e != nil {
+		return fmt.Errorf("%w (%s)", e, d.Prefix)
+	}
+
+	d.applyMetadata(m)
+	logger.Info("metadata discovered",
+		zap.Int("slot-index", int(ts.index)),
+		zap.Stringer("versioned", d.Prefix),
+		zap.Uint64("segment-end", d.SegmentEnd),
+	)
+	return nil
+}
+
+func (d *TaskDef) applyMetadata(m ndn6file.Metadata) {
+	d.Prefix = m.Name
+	if d.SegmentEnd == 0 {
+		d.SegmentEnd = m.SegmentEnd()
+	}
+	if m.SegmentSize > 0 {
+		if d.SegmentLen == 0 {
+			d.SegmentLen = m.SegmentSize
+		}
+		if d.FileSize == nil {
+			size := m.Size
+			d.FileSize = &size
+		}
+	}
+	if d.Filename != "" && d.Sha256 == "" && len(m.Sha256) > 0 {
+		d.Sha256 = hex.EncodeToString(m.Sha256)
+	}
+}
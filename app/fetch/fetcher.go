@@ -16,6 +16,7 @@ import (
 	"github.com/usnistgov/ndn-dpdk/dpdk/ealthread"
 	"github.com/usnistgov/ndn-dpdk/iface"
 	"github.com/zyedidia/generic"
+	"go.uber.org/zap"
 )
 
 var logger = logging.New("fetch")
@@ -24,6 +25,13 @@ var logger = logging.New("fetch")
 type Config struct {
 	TaskSlotConfig
 
+	// CcConfig contains default congestion control configuration.
+	// It can be overridden in each TaskDef.
+	CcConfig
+
+	// HistoryConfig configures per-task counters history.
+	HistoryConfig
+
 	// NThreads is the number of worker threads.
 	// Each worker thread can serve multiple fetch tasks.
 	NThreads int `json:"nThreads,omitempty"`
@@ -38,13 +46,16 @@ func (cfg *Config) Validate() error {
 	cfg.TaskSlotConfig.applyDefaults()
 	cfg.NThreads = generic.Clamp(cfg.NThreads, 1, math.MaxInt8)
 	cfg.NTasks = generic.Clamp(cfg.NTasks, 1, iface.MaxInputDemuxDest)
-	return nil
+	cfg.HistoryConfig.validate()
+	return cfg.CcConfig.validate()
 }
 
 // Fetcher controls worker threads and task slots on a face.
 type Fetcher struct {
+	cfg       Config
 	workers   []*worker
 	taskSlots []*taskSlot
+	reserved  map[*taskSlot]bool // task slots undergoing metadata discovery
 }
 
 var _ tgdef.Consumer = &Fetcher{}
@@ -54,14 +65,15 @@ func (fetcher *Fetcher) Face() iface.Face {
 	return fetcher.workers[0].Face()
 }
 
-// ConnectRxQueues connects Data InputDemux to RxQueues.
-// Nack InputDemux is set to drop packets because fetcher does not support Nacks.
+// ConnectRxQueues connects Data and Nack InputDemux to RxQueues.
+// Nacks are delivered to the same RxQueue as Data, to be processed by congestion control.
 func (fetcher *Fetcher) ConnectRxQueues(demuxD, demuxN *iface.InputDemux) {
-	demuxD.InitToken(0)
-	for i, ts := range fetcher.taskSlots {
-		demuxD.SetDest(i, ts.RxQueueD())
+	for _, demux := range []*iface.InputDemux{demuxD, demuxN} {
+		demux.InitToken(0)
+		for i, ts := range fetcher.taskSlots {
+			demux.SetDest(i, ts.RxQueueD())
+		}
 	}
-	demuxN.InitDrop()
 }
 
 // Workers returns worker threads.
@@ -82,30 +94,83 @@ func (fetcher *Fetcher) Tasks() (list []*TaskContext) {
 	return
 }
 
-// Fetch starts a fetch task.
-func (fetcher *Fetcher) Fetch(d TaskDef) (task *TaskContext, e error) {
+// ErrTooManyTasks indicates all task slots are in use.
+var ErrTooManyTasks = errors.New("too many running tasks")
+
+// reserveSlot reserves an unused task slot.
+func (fetcher *Fetcher) reserveSlot() (ts *taskSlot, e error) {
 	eal.CallMain(func() {
-		task = &TaskContext{
-			d:        d,
-			fetcher:  fetcher,
-			w:        fetcher.workers[0],
-			stopping: make(chan struct{}),
+		for _, slot := range fetcher.taskSlots {
+			if slot.worker == -1 && !fetcher.reserved[slot] {
+				ts = slot
+				fetcher.reserved[ts] = true
+				return
+			}
 		}
+	})
+	if ts == nil {
+		return nil, ErrTooManyTasks
+	}
+	return ts, nil
+}
 
-		for _, ts := range fetcher.taskSlots {
-			if ts.worker == -1 {
-				task.ts = ts
-				break
-			}
+// releaseSlot releases a reserved task slot.
+// If a task has been added to the slot, it remains in use until the task is stopped.
+func (fetcher *Fetcher) releaseSlot(ts *taskSlot) {
+	eal.CallMain(func() { delete(fetcher.reserved, ts) })
+}
+
+// Fetch starts a fetch task.
+// If d.Discover is set, this blocks until RDR metadata has been retrieved.
+func (fetcher *Fetcher) Fetch(d TaskDef) (task *TaskContext, e error) {
+	d.CcConfig = d.CcConfig.inherit(fetcher.cfg.CcConfig)
+	if e = d.CcConfig.validate(); e != nil {
+		return nil, e
+	}
+
+	ts, e := fetcher.reserveSlot()
+	if e != nil {
+		return nil, e
+	}
+	defer fetcher.releaseSlot(ts)
+
+	if d.Discover {
+		if e = ts.discover(fetcher.Face(), &d); e != nil {
+			return nil, e
 		}
-		if task.ts == nil {
-			task, e = nil, errors.New("too many running tasks")
-			return
+	}
+	if e = d.validateOutput(); e != nil {
+		return nil, e
+	}
+
+	eal.CallMain(func() {
+		task = &TaskContext{
+			d:          d,
+			fetcher:    fetcher,
+			w:          fetcher.workers[0],
+			ts:         ts,
+			stopping:   make(chan struct{}),
+			outputDone: make(chan struct{}),
 		}
 		if e = task.ts.Init(d); e != nil {
 			task = nil
 			return
 		}
+		if d.Filename != "" {
+			if task.bm, e = openDoneBitmap(d); e != nil {
+				task.ts.closeFd(nil)
+				task = nil
+				return
+			}
+			task.ts.Logic().setDoneBitmap(task.bm)
+			if d.Resume {
+				logger.Info("task resumed",
+					zap.Int("slot-index", int(ts.index)),
+					zap.String("filename", d.Filename),
+					zap.Uint64("done", task.bm.count(d.SegmentEnd)),
+				)
+			}
+		}
 
 		for _, w := range fetcher.workers {
 			if task.w.nTasks > w.nTasks {
@@ -121,6 +186,12 @@ func (fetcher *Fetcher) Fetch(d TaskDef) (task *TaskContext, e error) {
 		task.id = lastTaskContextID
 		taskContextByID[task.id] = task
 	})
+	if task != nil {
+		go task.sampleHistory(fetcher.cfg.HistoryConfig)
+		if task.bm != nil {
+			go task.watchOutput()
+		}
+	}
 	return
 }
 
@@ -134,21 +205,35 @@ func (fetcher *Fetcher) Stop() error {
 	return tgdef.StopWorkers(fetcher.workers)
 }
 
-// Reset aborts all tasks and stops all worker threads.
+// Reset aborts all tasks, including recursive fetch tasks, and stops all worker threads.
 func (fetcher *Fetcher) Reset() {
+	for _, dc := range fetcher.Dirs() {
+		dc.Stop()
+	}
+	tasks := fetcher.Tasks()
+	for _, task := range tasks {
+		task.abort()
+	}
 	fetcher.Stop()
 	for _, w := range fetcher.workers {
 		w.ClearTasks()
 	}
 	for _, ts := range fetcher.taskSlots {
+		ts.Logic().setDoneBitmap(nil)
 		ts.closeFd(nil)
 		ts.worker = -1
 	}
+	for _, task := range tasks {
+		task.closeBitmap()
+	}
 	maps.DeleteFunc(taskContextByID, func(id int, task *TaskContext) bool { return task.fetcher == fetcher })
 }
 
 // Close deallocates data structures.
 func (fetcher *Fetcher) Close() error {
+	for _, dc := range fetcher.Dirs() {
+		dc.Stop()
+	}
 	errs := []error{
 		fetcher.Stop(),
 	}
@@ -167,11 +252,15 @@ func (fetcher *Fetcher) Close() error {
 
 // New creates a Fetcher.
 func New(face iface.Face, cfg Config) (*Fetcher, error) {
-	cfg.applyDefaults()
+	if e := cfg.Validate(); e != nil {
+		return nil, e
+	}
 
 	fetcher := &Fetcher{
+		cfg:       cfg,
 		workers:   make([]*worker, cfg.NThreads),
 		taskSlots: make([]*taskSlot, cfg.NTasks),
+		reserved:  map[*taskSlot]bool{},
 	}
 	for i := range fetcher.workers {
 		fetcher.workers[i] = newWorker(face, i)
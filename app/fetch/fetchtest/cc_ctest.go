@@ -0,0 +1,90 @@
+package fetchtest
+
+/*
+#include "../../../csrc/fetch/cc.h"
+*/
+import "C"
+import (
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+)
+
+func ctestFetchCc(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	now := eal.TscNow()
+	rtt := 100 * time.Millisecond
+	makeCc := func(algo C.FetchCcAlgo) (cwnd func() int, increase func(rtt time.Duration), decrease func()) {
+		cc := &C.FetchCc{algo: algo, fixedCwnd: 30}
+		C.FetchCc_Init(cc)
+		cwnd = func() int { return int(C.FetchCc_GetCwnd(cc)) }
+		increase = func(rtt time.Duration) {
+			rttTsc := eal.ToTscDuration(rtt)
+			C.FetchCc_Increase(cc, C.TscTime(now), C.TscDuration(rttTsc), C.double(rttTsc))
+		}
+		decrease = func() { C.FetchCc_Decrease(cc, C.TscTime(now)) }
+		return
+	}
+
+	t.Run("aimd", func(t *testing.T) {
+		cwnd, increase, decrease := makeCc(C.FetchCcAimd)
+		assert.Equal(2, cwnd())
+		for range 98 {
+			increase(rtt)
+		}
+		assert.Equal(100, cwnd())
+
+		decrease()
+		assert.Equal(50, cwnd())
+		for range 60 {
+			increase(rtt)
+		}
+		assert.Equal(51, cwnd())
+	})
+
+	t.Run("bbr", func(t *testing.T) {
+		cwnd, increase, decrease := makeCc(C.FetchCcBbr)
+		// deliver min(cwnd,capacity) Data per RTT, evenly spaced
+		deliver := func(nRounds, capacity int) {
+			for range nRounds {
+				n := min(cwnd(), capacity)
+				for range n {
+					now = now.Add(rtt / time.Duration(n))
+					increase(rtt)
+				}
+			}
+		}
+
+		// startup grows exponentially
+		deliver(6, 1000)
+		assert.Greater(cwnd(), 200)
+
+		// bottleneck bandwidth of 200 Data per RTT is found, window settles around BDP
+		deliver(30, 200)
+		for range 8 {
+			deliver(1, 200)
+			assert.InDelta(200, cwnd(), 60)
+		}
+
+		// congestion signal caps the window at BDP
+		decrease()
+		assert.LessOrEqual(cwnd(), 210)
+		deliver(1, 200)
+		assert.InDelta(200, cwnd(), 20)
+
+		// reduced bottleneck bandwidth is reflected after the max filter window
+		deliver(20, 100)
+		assert.InDelta(100, cwnd(), 35)
+	})
+
+	t.Run("fixed", func(t *testing.T) {
+		cwnd, increase, decrease := makeCc(C.FetchCcFixed)
+		assert.Equal(30, cwnd())
+		increase(rtt)
+		assert.Equal(30, cwnd())
+		decrease()
+		assert.Equal(30, cwnd())
+	})
+}
@@ -0,0 +1,167 @@
+package fetchtest
+
+import (
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/app/fetch"
+	"github.com/usnistgov/ndn-dpdk/app/tg/tgtestenv"
+	"github.com/usnistgov/ndn-dpdk/iface/intface"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/rdr"
+	"github.com/usnistgov/ndn-dpdk/ndn/rdr/ndn6file"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+const testDirSegmentLen = 1000
+
+// testDirServer is a minimal ndn6-file-server serving an in-memory directory tree.
+type testDirServer struct {
+	byPrefix    map[string]*ndn6file.Metadata
+	byVersioned map[string][]byte
+}
+
+func (srv *testDirServer) add(path string, mode uint16, payload []byte, digest []byte) {
+	prefix := ndn.Name{}
+	for comp := range strings.SplitSeq(path, "/") {
+		prefix = prefix.Append(ndn.MakeNameComponent(an.TtGenericNameComponent, []byte(comp)))
+	}
+
+	m := &ndn6file.Metadata{Mode: mode, Sha256: digest}
+	m.Name = prefix.Append(ndn.NameComponentFrom(an.TtVersionNameComponent, tlv.NNI(1)))
+	if mode&0o100000 != 0 {
+		m.SegmentSize, m.Size = testDirSegmentLen, int64(len(payload))
+		m.FinalBlock = ndn.NameComponentFrom(an.TtSegmentNameComponent, tlv.NNI((len(payload)-1)/testDirSegmentLen))
+	}
+	srv.byPrefix[prefix.String()] = m
+	srv.byVersioned[m.Name.String()] = payload
+}
+
+func (srv *testDirServer) AddFile(path string, payload []byte, digest []byte) {
+	srv.add(path, 0o100644, payload, digest)
+}
+
+func (srv *testDirServer) AddDir(path string, entries ...string) {
+	var payload []byte
+	for _, entry := range entries {
+		payload = append(append(payload, entry...), 0)
+	}
+	srv.add(path, 0o40755, payload, nil)
+}
+
+func (srv *testDirServer) Serve(interest *ndn.Interest) (data ndn.Data, ok bool) {
+	if rdr.IsDiscoveryInterest(*interest) {
+		m := srv.byPrefix[interest.Name.GetPrefix(-1).String()]
+		if m == nil {
+			return data, false
+		}
+		content, _ := m.MarshalBinary()
+		return ndn.MakeData(interest, content), true
+	}
+
+	payload, found := srv.byVersioned[interest.Name.GetPrefix(-1).String()]
+	lastComp := interest.Name.Get(-1)
+	var segNum tlv.NNI
+	if !found || lastComp.Type != an.TtSegmentNameComponent || segNum.UnmarshalBinary(lastComp.Value) != nil {
+		return data, false
+	}
+	if int(segNum)*testDirSegmentLen >= max(len(payload), 1) {
+		return data, false
+	}
+	end := int(segNum+1) * testDirSegmentLen
+	if end >= len(payload) {
+		return ndn.MakeData(interest, payload[int(segNum)*testDirSegmentLen:], ndn.FinalBlockFlag), true
+	}
+	return ndn.MakeData(interest, payload[int(segNum)*testDirSegmentLen:end]), true
+}
+
+func TestFetchDir(t *testing.T) {
+	assert, require := makeAR(t)
+
+	payloadA, payloadB, payloadC := make([]byte, 3500), make([]byte, 2000), make([]byte, 1200)
+	randBytes(payloadA)
+	randBytes(payloadB)
+	randBytes(payloadC)
+	digestA, digestB := sha256.Sum256(payloadA), sha256.Sum256(payloadB)
+
+	srv := &testDirServer{
+		byPrefix:    map[string]*ndn6file.Metadata{},
+		byVersioned: map[string][]byte{},
+	}
+	srv.AddDir("T", "a.bin", "c.bin", "sub/", "./", "deep/")
+	srv.AddFile("T/a.bin", payloadA, digestA[:])
+	srv.AddFile("T/c.bin", payloadC, digestA[:]) // digest mismatch
+	srv.AddDir("T/sub", "b.bin")
+	srv.AddFile("T/sub/b.bin", payloadB, digestB[:])
+	srv.AddDir("T/deep", "d/")
+	srv.AddDir("T/deep/d", "d/")
+	srv.AddDir("T/deep/d/d") // exceeds MaxDepth
+
+	intFace := intface.MustNew()
+	t.Cleanup(func() { intFace.D.Close() })
+	go func() {
+		for packet := range intFace.Rx {
+			if !assert.NotNil(packet.Interest) {
+				continue
+			}
+			if data, ok := srv.Serve(packet.Interest); ok {
+				intFace.Tx <- data
+			}
+		}
+	}()
+
+	var cfg fetch.Config
+	cfg.NThreads = 1
+	cfg.NTasks = 4
+	fetcher, e := fetch.New(intFace.D, cfg)
+	require.NoError(e)
+	tgtestenv.Open(t, fetcher)
+	t.Cleanup(func() { fetcher.Close() })
+	fetcher.Launch()
+
+	outputDir := t.TempDir()
+	var d fetch.DirDef
+	d.Prefix = ndn.ParseName("/T")
+	d.OutputDir = outputDir
+	d.MaxDepth = 2
+	dc, e := fetcher.FetchDir(d)
+	require.NoError(e)
+	defer dc.Stop()
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for deadline := time.Now().Add(20 * time.Second); !dc.Finished() && time.Now().Before(deadline); {
+		<-ticker.C
+	}
+	require.True(dc.Finished())
+
+	cnt := dc.Counters()
+	assert.True(cnt.Listed)
+	assert.Equal(4, cnt.NDirs) // T, T/sub, T/deep, T/deep/d
+	assert.Equal(3, cnt.NFiles)
+	assert.Zero(cnt.NRunning)
+	assert.Equal(2, cnt.NFinished)
+	assert.Equal(3, cnt.NFailed)
+	assert.EqualValues(len(payloadA)+len(payloadB), cnt.NOctets)
+
+	failures := map[string]string{}
+	for _, f := range cnt.Failures {
+		failures[f.Name.String()] = f.Error
+	}
+	assert.Contains(failures["/8=T"], "bad directory entry")
+	assert.Contains(failures["/8=T/8=deep/8=d/8=d"], "depth")
+	assert.Contains(failures["/8=T/8=c.bin"], fetch.ErrVerify.Error())
+
+	if written, e := os.ReadFile(filepath.Join(outputDir, "a.bin")); assert.NoError(e) {
+		assert.Equal(payloadA, written)
+	}
+	if written, e := os.ReadFile(filepath.Join(outputDir, "sub", "b.bin")); assert.NoError(e) {
+		assert.Equal(payloadB, written)
+	}
+	assert.NoDirExists(filepath.Join(outputDir, "deep", "d", "d"))
+}
@@ -1,10 +1,13 @@
 package fetchtest
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"math/rand/v2"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -24,6 +27,7 @@ type testFetcherTask struct {
 	Payload    []byte
 	PInterests map[tlv.NNI]int
 	NInterests int
+	Task       *fetch.TaskContext
 }
 
 func (ft *testFetcherTask) Run(t *testing.T, fetcher *fetch.Fetcher) (cnt fetch.Counters) {
@@ -32,6 +36,7 @@ func (ft *testFetcherTask) Run(t *testing.T, fetcher *fetch.Fetcher) (cnt fetch.
 
 	task, e := fetcher.Fetch(ft.TaskDef)
 	require.NoError(e)
+	ft.Task = task
 
 	t0 := time.Now()
 	ticker := time.NewTicker(time.Millisecond)
@@ -176,6 +181,51 @@ func TestFetcher(t *testing.T) {
 		assert.InDelta(float64(ft.NInterests), float64(cnt.NTxRetx+cnt.NRxData), testFetcherWindowCapacity)
 	})
 
+	t.Run("R", func(t *testing.T) {
+		assert, _ := makeAR(t)
+
+		ft := newTestFetcherTask('R')
+		ft.SegmentBegin, ft.SegmentEnd, ft.SegmentLen = 0, 700, 300
+		ft.Filename = filepath.Join(tempDir, "R.bin")
+		fileSize := int64(ft.SegmentLen)*int64(ft.SegmentEnd) - 11
+		ft.FileSize = &fileSize
+		ft.Payload = make([]byte, fileSize)
+		randBytes(ft.Payload)
+		digest := sha256.Sum256(ft.Payload)
+		ft.Resume, ft.Sha256 = true, hex.EncodeToString(digest[:])
+		ftByName['R'] = ft
+		// write to file with sidecar bitmap, verify digest
+
+		cnt := ft.Run(t, fetcher)
+		assert.Zero(cnt.NSkipped)
+		assert.Equal(fetch.TaskSucceeded, ft.Task.State())
+		assert.True(ft.Task.Written())
+		assert.True(ft.Task.Verified())
+		assert.NoError(ft.Task.Err())
+		assert.NoFileExists(ft.Filename + fetch.SidecarSuffix)
+	})
+
+	t.Run("V", func(t *testing.T) {
+		assert, _ := makeAR(t)
+
+		ft := newTestFetcherTask('V')
+		ft.SegmentBegin, ft.SegmentEnd, ft.SegmentLen = 0, 300, 500
+		ft.Filename = filepath.Join(tempDir, "V.bin")
+		fileSize := int64(ft.SegmentLen) * int64(ft.SegmentEnd)
+		ft.FileSize = &fileSize
+		ft.Payload = make([]byte, fileSize)
+		randBytes(ft.Payload)
+		ft.Sha256 = strings.Repeat("A0", sha256.Size)
+		ftByName['V'] = ft
+		// verify digest, mismatch
+
+		ft.Run(t, fetcher)
+		assert.Equal(fetch.TaskFailed, ft.Task.State())
+		assert.True(ft.Task.Written())
+		assert.False(ft.Task.Verified())
+		assert.ErrorIs(ft.Task.Err(), fetch.ErrVerify)
+	})
+
 	go func() {
 		for packet := range intFace.Rx {
 			if !assert.NotNil(packet.Interest) || !assert.Len(packet.Interest.Name, 2) {
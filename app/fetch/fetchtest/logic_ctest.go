@@ -29,7 +29,7 @@ func ctestLogic(t *testing.T) {
 
 	fl.Reset(segmented.SegmentRange{
 		SegmentEnd: finalSeg + 1,
-	})
+	}, fetch.CcConfig{})
 
 	rxData := make(chan uint64)
 	txCounts := map[uint64]int{}
@@ -80,3 +80,45 @@ func ctestLogic(t *testing.T) {
 	assert.Greater(txCountFreq[1], 1700)
 	assert.Less(txCountFreq[9], 20)
 }
+
+func ctestLogicResume(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	fl := eal.Zmalloc[fetch.Logic]("FetchLogic", unsafe.Sizeof(fetch.Logic{}), eal.NumaSocket{})
+	defer eal.Free(fl)
+	fl.Init(64, eal.NumaSocket{})
+	defer fl.Close()
+	flC := (*C.FetchLogic)(unsafe.Pointer(fl))
+
+	fl.Reset(segmented.SegmentRange{
+		SegmentBegin: 1000,
+		SegmentEnd:   1200,
+	}, fetch.CcConfig{})
+
+	bitmap := eal.Zmalloc[C.uint64_t]("bitmap", 4*C.sizeof_uint64_t, eal.NumaSocket{})
+	defer eal.Free(bitmap)
+	flC.doneBitmap, flC.doneBegin, flC.doneEnd = bitmap, 1000, 1200
+	for segNum := 1000; segNum < 1200; segNum += 2 {
+		C.FetchLogic_SetDone(flC, C.uint64_t(segNum))
+	}
+	assert.True(bool(C.FetchLogic_IsDone(flC, 1198)))
+	assert.False(bool(C.FetchLogic_IsDone(flC, 1199)))
+	assert.False(bool(C.FetchLogic_IsDone(flC, 1200)))
+
+	txCounts := map[uint64]int{}
+	for !fl.Finished() {
+		var txSegNumC C.uint64_t
+		nTx := C.FetchLogic_TxInterestBurst(flC, &txSegNumC, 1, C.TscTime(eal.TscNow()))
+		if nTx > 0 {
+			txCounts[uint64(txSegNumC)]++
+		}
+		C.FetchLogic_RxDataBurst(flC, &C.FetchLogicRxData{segNum: txSegNumC}, C.size_t(nTx), C.TscTime(eal.TscNow()))
+	}
+
+	assert.Len(txCounts, 100)
+	for segNum := range txCounts {
+		assert.Equal(uint64(1), segNum%2, "%d", segNum)
+	}
+	assert.EqualValues(100, fl.Counters().NSkipped)
+	flC.doneBitmap = nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/usnistgov/ndn-dpdk/app/tg/tggql"
 	"github.com/usnistgov/ndn-dpdk/core/gqlserver"
 	"github.com/usnistgov/ndn-dpdk/core/jsonhelper"
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
 	"github.com/usnistgov/ndn-dpdk/dpdk/ealthread"
 	"github.com/usnistgov/ndn-dpdk/iface"
 	"github.com/usnistgov/ndn-dpdk/ndni"
@@ -23,6 +24,9 @@ var (
 	GqlTaskDefInput    *graphql.InputObject
 	GqlTaskDefType     *graphql.Object
 	GqlTaskContextType *gqlserver.NodeType[*TaskContext]
+	GqlDirDefInput     *graphql.InputObject
+	GqlDirDefType      *graphql.Object
+	GqlDirContextType  *gqlserver.NodeType[*DirContext]
 	GqlFetcherType     *gqlserver.NodeType[*Fetcher]
 )
 
@@ -31,7 +35,8 @@ func init() {
 		Name:        "FetcherConfigInput",
 		Description: "Fetcher config.",
 		Fields: gqlserver.BindInputFields[Config](gqlserver.FieldTypes{
-			reflect.TypeFor[iface.PktQueueConfig](): iface.GqlPktQueueInput,
+			reflect.TypeFor[iface.PktQueueConfig]():    iface.GqlPktQueueInput,
+			reflect.TypeFor[nnduration.Milliseconds](): nnduration.GqlMilliseconds,
 		}),
 	})
 
@@ -65,6 +70,49 @@ func init() {
 					return task.d, nil
 				},
 			},
+			"history": &graphql.Field{
+				Description: "Periodically sampled counters, in chronological order.",
+				Type:        gqlserver.NewListNonNullBoth(gqlserver.JSON),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					task := p.Source.(*TaskContext)
+					return task.History(), nil
+				},
+			},
+			"state": &graphql.Field{
+				Description: "Task state: fetching, writing, succeeded, or failed.",
+				Type:        gqlserver.NonNullString,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					task := p.Source.(*TaskContext)
+					return string(task.State()), nil
+				},
+			},
+			"written": &graphql.Field{
+				Description: "Whether all segments have been written to the output file.",
+				Type:        gqlserver.NonNullBoolean,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					task := p.Source.(*TaskContext)
+					return task.Written(), nil
+				},
+			},
+			"verified": &graphql.Field{
+				Description: "Whether the output file has been verified against the expected digest.",
+				Type:        gqlserver.NonNullBoolean,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					task := p.Source.(*TaskContext)
+					return task.Verified(), nil
+				},
+			},
+			"error": &graphql.Field{
+				Description: "Failure reason; null if the task has not failed.",
+				Type:        graphql.String,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					task := p.Source.(*TaskContext)
+					if e := task.Err(); e != nil {
+						return e.Error(), nil
+					}
+					return nil, nil
+				},
+			},
 			"worker": ealthread.GqlWithWorker(func(p graphql.ResolveParams) ealthread.Thread {
 				task := p.Source.(*TaskContext)
 				return task.w
@@ -82,6 +130,49 @@ func init() {
 		},
 	})
 
+	GqlDirDefInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "FetchDirDefInput",
+		Description: "Recursive fetch task definition.",
+		Fields:      gqlserver.BindInputFields[DirDef](ndni.GqlInterestTemplateFieldTypes),
+	})
+	GqlDirDefType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "FetchDirDef",
+		Description: "Recursive fetch task definition.",
+		Fields:      gqlserver.BindFields[DirDef](ndni.GqlInterestTemplateFieldTypes),
+	})
+
+	GqlDirContextType = gqlserver.NewNodeType(graphql.ObjectConfig{
+		Name:        "FetchDirContext",
+		Description: "Recursive fetch task context.",
+		Fields: graphql.Fields{
+			"nid": &graphql.Field{
+				Type: gqlserver.NonNullInt,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					dc := p.Source.(*DirContext)
+					return dc.id, nil
+				},
+			},
+			"task": &graphql.Field{
+				Description: "Task definition.",
+				Type:        graphql.NewNonNull(GqlDirDefType),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					dc := p.Source.(*DirContext)
+					return dc.d, nil
+				},
+			},
+		},
+	}, gqlserver.NodeConfig[*DirContext]{
+		RetrieveInt: func(id int) *DirContext {
+			taskContextLock.RLock()
+			defer taskContextLock.RUnlock()
+			return dirContextByID[id]
+		},
+		Delete: func(dc *DirContext) error {
+			dc.Stop()
+			return nil
+		},
+	})
+
 	GqlFetcherType = gqlserver.NewNodeType(graphql.ObjectConfig{
 		Name: "Fetcher",
 		Fields: tggql.CommonFields(graphql.Fields{
@@ -93,6 +184,14 @@ func init() {
 					return fetcher.Tasks(), nil
 				},
 			},
+			"dirs": &graphql.Field{
+				Description: "Running recursive fetch tasks.",
+				Type:        gqlserver.NewListNonNullBoth(GqlDirContextType.Object),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					fetcher := p.Source.(*Fetcher)
+					return fetcher.Dirs(), nil
+				},
+			},
 		}),
 	}, tggql.NodeConfig(&GqlRetrieveByFaceID))
 
@@ -150,4 +249,59 @@ func init() {
 			return task.Counters(), nil
 		},
 	})
+
+	gqlserver.AddMutation(&graphql.Field{
+		Name:        "fetchDirectory",
+		Description: "Start a recursive fetch task.",
+		Args: graphql.FieldConfigArgument{
+			"fetcher": &graphql.ArgumentConfig{
+				Description: "Fetcher ID.",
+				Type:        gqlserver.NonNullID,
+			},
+			"task": &graphql.ArgumentConfig{
+				Description: "Recursive fetch task definition.",
+				Type:        graphql.NewNonNull(GqlDirDefInput),
+			},
+		},
+		Type: graphql.NewNonNull(GqlDirContextType.Object),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			fetcher := GqlFetcherType.Retrieve(p.Args["fetcher"].(string))
+			if fetcher == nil {
+				return nil, errors.New("fetcher not found")
+			}
+
+			var d DirDef
+			if e := jsonhelper.Roundtrip(p.Args["task"], &d, jsonhelper.DisallowUnknownFields); e != nil {
+				return nil, e
+			}
+
+			return fetcher.FetchDir(d)
+		},
+	})
+
+	gqlserver.AddCounters(&gqlserver.CountersConfig{
+		Description:  "Recursive fetch progress and per-file failures.",
+		Parent:       GqlDirContextType.Object,
+		Name:         "counters",
+		Subscription: "fetchDirCounters",
+		NoDiff:       true,
+		FindArgs: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Description: "Recursive fetch task context.",
+				Type:        gqlserver.NonNullID,
+			},
+		},
+		Find: func(p graphql.ResolveParams) (source any, enders []any, e error) {
+			dc := GqlDirContextType.Retrieve(p.Args["id"].(string))
+			if dc == nil {
+				return nil, nil, nil
+			}
+			return dc, []any{dc.stopping}, nil
+		},
+		Type: gqlserver.NonNullJSON,
+		Read: func(p graphql.ResolveParams) (any, error) {
+			dc := p.Source.(*DirContext)
+			return dc.Counters(), nil
+		},
+	})
 }
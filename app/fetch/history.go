@@ -0,0 +1,77 @@
+package fetch
+
+import (
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
+)
+
+// Defaults of HistoryConfig.
+const (
+	DefaultHistoryInterval = 100 * time.Millisecond
+	DefaultHistoryCapacity = 600
+)
+
+// HistoryConfig contains configuration of per-task counters history.
+//
+// Each fetch task periodically samples its Counters, so that cwnd, RTT, and retransmission trends can be
+// retrieved after the fact, such as when comparing congestion control algorithms.
+type HistoryConfig struct {
+	// HistoryInterval is the sampling interval.
+	// Default is DefaultHistoryInterval.
+	HistoryInterval nnduration.Milliseconds `json:"historyInterval,omitempty"`
+
+	// HistoryCapacity is the maximum number of retained samples.
+	// When exceeded, oldest samples are discarded.
+	// Default is DefaultHistoryCapacity.
+	// Negative value disables history.
+	HistoryCapacity int `json:"historyCapacity,omitempty"`
+}
+
+func (cfg *HistoryConfig) validate() {
+	if cfg.HistoryInterval <= 0 {
+		cfg.HistoryInterval = nnduration.Milliseconds(DefaultHistoryInterval / time.Millisecond)
+	}
+	if cfg.HistoryCapacity == 0 {
+		cfg.HistoryCapacity = DefaultHistoryCapacity
+	}
+}
+
+// History returns sampled counters in chronological order.
+func (task *TaskContext) History() []Counters {
+	task.historyLock.Lock()
+	defer task.historyLock.Unlock()
+	return append([]Counters{}, task.history...)
+}
+
+func (task *TaskContext) sampleHistory(cfg HistoryConfig) {
+	if cfg.HistoryCapacity <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(cfg.HistoryInterval.Duration())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-task.stopping:
+			return
+		case <-ticker.C:
+		}
+
+		if !task.active() { // fetcher has been reset
+			return
+		}
+
+		cnt := task.Counters()
+		task.historyLock.Lock()
+		if len(task.history) >= cfg.HistoryCapacity {
+			task.history = append(task.history[:0], task.history[len(task.history)-cfg.HistoryCapacity+1:]...)
+		}
+		task.history = append(task.history, cnt)
+		task.historyLock.Unlock()
+
+		if cnt.Finished != nil {
+			return
+		}
+	}
+}
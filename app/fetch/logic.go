@@ -7,6 +7,7 @@ import "C"
 import (
 	"fmt"
 	"time"
+	"unsafe"
 
 	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
 	"github.com/usnistgov/ndn-dpdk/ndn/segmented"
@@ -25,11 +26,29 @@ func (fl *Logic) Init(winCapacity int, socket eal.NumaSocket) {
 }
 
 // Reset resets this to initial state.
-func (fl *Logic) Reset(r segmented.SegmentRange) {
+func (fl *Logic) Reset(r segmented.SegmentRange, cc CcConfig) {
 	r.SegmentRangeApplyDefaults()
+	cc.apply(&fl.cc)
 	C.FetchLogic_Reset(fl.ptr(), C.uint64_t(r.SegmentBegin), C.uint64_t(r.SegmentEnd))
 }
 
+// currentSegmentEnd returns the last segment number plus one.
+// This may be reduced from the initial SegmentEnd upon receiving a Data with FinalBlockId.
+func (fl *Logic) currentSegmentEnd() uint64 {
+	return uint64(fl.segmentEnd)
+}
+
+// setDoneBitmap assigns or clears the bitmap of written segments.
+// When clearing, the caller must wait for an RCU grace period before releasing the bitmap memory.
+func (fl *Logic) setDoneBitmap(bm *doneBitmap) {
+	if bm == nil {
+		fl.doneBitmap = nil
+		return
+	}
+	fl.doneBegin, fl.doneEnd = C.uint64_t(bm.begin), C.uint64_t(bm.end)
+	fl.doneBitmap = (*C.uint64_t)(unsafe.Pointer(unsafe.SliceData(bm.words)))
+}
+
 // Close deallocates data structures.
 func (fl *Logic) Close() error {
 	C.FetchLogic_Free(fl.ptr())
@@ -51,10 +70,12 @@ func (fl *Logic) Counters() (cnt Counters) {
 	cnt.LastRtt = eal.FromTscDuration(int64(fl.rtte.last))
 	cnt.SRtt = eal.FromTscDuration(int64(fl.rtte.rttv.sRtt))
 	cnt.Rto = eal.FromTscDuration(int64(fl.rtte.rto))
-	cnt.Cwnd = int(C.TcpCubic_GetCwnd(&fl.ca))
+	cnt.Cwnd = int(C.FetchCc_GetCwnd(&fl.cc))
 	cnt.NInFlight = uint32(fl.nInFlight)
 	cnt.NTxRetx = uint64(fl.nTxRetx)
 	cnt.NRxData = uint64(fl.nRxData)
+	cnt.NRxNack = uint64(fl.nRxNack)
+	cnt.NSkipped = uint64(fl.nSkipped)
 	return cnt
 }
 
@@ -69,10 +90,12 @@ type Counters struct {
 	NInFlight uint32         `json:"nInFlight" gqldesc:"Currently in-flight Interests."`
 	NTxRetx   uint64         `json:"nTxRetx" gqldesc:"Retransmitted Interests."`
 	NRxData   uint64         `json:"nRxData" gqldesc:"Data satisfying pending Interests."`
+	NRxNack   uint64         `json:"nRxNack" gqldesc:"Nacks of pending Interests."`
+	NSkipped  uint64         `json:"nSkipped" gqldesc:"Segments skipped because they were written before resuming."`
 }
 
 func (cnt Counters) String() string {
-	return fmt.Sprintf("rtt=%dms srtt=%dms rto=%dms cwnd=%d %dP %dR %dD",
+	return fmt.Sprintf("rtt=%dms srtt=%dms rto=%dms cwnd=%d %dP %dR %dD %dN %dS",
 		cnt.LastRtt.Milliseconds(), cnt.SRtt.Milliseconds(), cnt.Rto.Milliseconds(),
-		cnt.Cwnd, cnt.NInFlight, cnt.NTxRetx, cnt.NRxData)
+		cnt.Cwnd, cnt.NInFlight, cnt.NTxRetx, cnt.NRxData, cnt.NRxNack, cnt.NSkipped)
 }
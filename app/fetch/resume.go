@@ -0,0 +1,320 @@
+package fetch
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"go.uber.org/zap"
+	"golang.org/x/sys/unix"
+)
+
+// SidecarSuffix is appended to the output filename to form the sidecar filename in resume mode.
+const SidecarSuffix = ".ndnfetch"
+
+const (
+	sidecarHeaderLen       = 4096
+	sidecarPersistInterval = time.Second
+	outputPollInterval     = 10 * time.Millisecond
+	verifyChunkLen         = 1 << 20
+)
+
+// ErrVerify indicates the output file does not match the expected digest.
+var ErrVerify = errors.New("output file digest mismatch")
+
+// sidecarHeader identifies the fetch task that a sidecar file belongs to.
+// A sidecar is only reused if its header matches the current TaskDef.
+type sidecarHeader struct {
+	Prefix       ndn.Name `json:"prefix"`
+	SegmentBegin uint64   `json:"segmentBegin"`
+	SegmentEnd   uint64   `json:"segmentEnd"`
+	SegmentLen   int      `json:"segmentLen"`
+}
+
+// doneBitmap is a bitmap of segments that have been written to the output file.
+// It is mapped from anonymous memory and updated by the worker thread upon write completion.
+//
+// In resume mode, the bitmap is loaded from and persisted to a sidecar file.
+// The bitmap is persisted only after the output file has been synced, so that the sidecar file
+// never marks a segment whose payload has not reached the disk.
+type doneBitmap struct {
+	mapping []byte
+	words   []uint64
+	begin   uint64
+	end     uint64
+	sidecar *os.File // sidecar file, nil if not persisted
+}
+
+// openDoneBitmap creates or reopens the bitmap of written segments.
+// d.Filename, d.SegmentRange, and d.SegmentLen must have been validated.
+func openDoneBitmap(d TaskDef) (bm *doneBitmap, e error) {
+	bm = &doneBitmap{
+		begin: d.SegmentBegin,
+		end:   d.SegmentEnd,
+	}
+	nWords := int((bm.end - bm.begin + 63) / 64)
+
+	if bm.mapping, e = unix.Mmap(-1, 0, nWords*8, unix.PROT_READ|unix.PROT_WRITE,
+		unix.MAP_SHARED|unix.MAP_ANONYMOUS); e != nil {
+		return nil, fmt.Errorf("unix.Mmap: %w", e)
+	}
+	bm.words = unsafe.Slice((*uint64)(unsafe.Pointer(unsafe.SliceData(bm.mapping))), nWords)
+
+	if d.Resume {
+		if bm.sidecar, e = openSidecar(d.Filename+SidecarSuffix, sidecarHeader{
+			Prefix:       d.Prefix,
+			SegmentBegin: d.SegmentBegin,
+			SegmentEnd:   d.SegmentEnd,
+			SegmentLen:   d.SegmentLen,
+		}, bm.mapping); e != nil {
+			unix.Munmap(bm.mapping)
+			return nil, e
+		}
+	}
+	return bm, nil
+}
+
+// openSidecar opens or creates a sidecar file, and loads the persisted bitmap into bitmap.
+func openSidecar(filename string, hdr sidecarHeader, bitmap []byte) (f *os.File, e error) {
+	hdrJ, e := json.Marshal(hdr)
+	if e != nil {
+		return nil, e
+	}
+	if len(hdrJ) >= sidecarHeaderLen {
+		return nil, errors.New("sidecar header too long")
+	}
+
+	if f, e = os.OpenFile(filename, os.O_RDWR|os.O_CREATE, 0o666); e != nil {
+		return nil, e
+	}
+
+	size := int64(sidecarHeaderLen + len(bitmap))
+	existing := make([]byte, sidecarHeaderLen)
+	_, eRead := io.ReadFull(f, existing)
+	if st, eStat := f.Stat(); eRead == nil && eStat == nil && st.Size() == size &&
+		bytes.Equal(bytes.TrimRight(existing, "\x00"), hdrJ) {
+		if _, e = f.ReadAt(bitmap, sidecarHeaderLen); e == nil {
+			return f, nil
+		}
+	}
+
+	// sidecar is new, belongs to a different task, or is unreadable: start over
+	clear(bitmap)
+	if e = f.Truncate(0); e == nil {
+		if _, e = f.WriteAt(hdrJ, 0); e == nil {
+			e = f.Truncate(size)
+		}
+	}
+	if e != nil {
+		f.Close()
+		return nil, e
+	}
+	return f, nil
+}
+
+// persist writes the bitmap to the sidecar file.
+// The output file is synced first, so that every segment marked in the persisted bitmap has reached the disk.
+func (bm *doneBitmap) persist(fd int) error {
+	if bm.sidecar == nil {
+		return nil
+	}
+
+	snapshot := make([]byte, len(bm.mapping))
+	for i := range bm.words {
+		binary.NativeEndian.PutUint64(snapshot[8*i:], atomic.LoadUint64(&bm.words[i]))
+	}
+
+	if e := unix.Fdatasync(fd); e != nil {
+		return fmt.Errorf("unix.Fdatasync: %w", e)
+	}
+	_, e := bm.sidecar.WriteAt(snapshot, sidecarHeaderLen)
+	return e
+}
+
+// count returns the number of segments within [begin,end) that are marked done.
+func (bm *doneBitmap) count(end uint64) (n uint64) {
+	end = min(end, bm.end)
+	if end <= bm.begin {
+		return 0
+	}
+	nBits := end - bm.begin
+	for i := range nBits / 64 {
+		n += uint64(bits.OnesCount64(atomic.LoadUint64(&bm.words[i])))
+	}
+	if rem := nBits % 64; rem > 0 {
+		n += uint64(bits.OnesCount64(atomic.LoadUint64(&bm.words[nBits/64]) & (1<<rem - 1)))
+	}
+	return n
+}
+
+// complete determines whether all segments within [begin,end) are marked done.
+func (bm *doneBitmap) complete(end uint64) bool {
+	return end <= bm.begin || bm.count(end) == min(end, bm.end)-bm.begin
+}
+
+// close unmaps the bitmap, and closes the sidecar file and deletes it if requested.
+func (bm *doneBitmap) close(removeSidecar bool) (e error) {
+	e = unix.Munmap(bm.mapping)
+	if bm.sidecar != nil {
+		e = errors.Join(e, bm.sidecar.Close())
+		if removeSidecar {
+			e = errors.Join(e, os.Remove(bm.sidecar.Name()))
+		}
+	}
+	return e
+}
+
+// Written determines whether all segments have been written to the output file.
+func (task *TaskContext) Written() bool {
+	task.outputLock.Lock()
+	defer task.outputLock.Unlock()
+	return task.written
+}
+
+// Verified determines whether the output file has been verified against the expected digest.
+func (task *TaskContext) Verified() bool {
+	task.outputLock.Lock()
+	defer task.outputLock.Unlock()
+	return task.verified
+}
+
+// Err returns an error if the task has failed, such as ErrVerify.
+func (task *TaskContext) Err() error {
+	task.outputLock.Lock()
+	defer task.outputLock.Unlock()
+	return task.err
+}
+
+// persistBitmap persists the bitmap of written segments in resume mode.
+func (task *TaskContext) persistBitmap() {
+	if e := task.bm.persist(int(task.ts.fd)); e != nil {
+		logger.Warn("doneBitmap persist error",
+			zap.Int("slot-index", int(task.ts.index)),
+			zap.Error(e),
+		)
+	}
+}
+
+// watchOutput waits for all segments to be written to the output file, and then verifies its digest.
+// In resume mode, it also persists the bitmap of written segments periodically and upon abort.
+func (task *TaskContext) watchOutput() {
+	defer close(task.outputDone)
+	fl := task.ts.Logic()
+
+	ticker := time.NewTicker(outputPollInterval)
+	defer ticker.Stop()
+	lastPersist := time.Now()
+	for !(fl.Finished() && task.bm.complete(fl.currentSegmentEnd())) {
+		select {
+		case <-task.stopping:
+			task.persistBitmap()
+			return
+		case now := <-ticker.C:
+			if now.Sub(lastPersist) >= sidecarPersistInterval {
+				task.persistBitmap()
+				lastPersist = now
+			}
+		}
+	}
+
+	task.outputLock.Lock()
+	task.written = true
+	task.outputLock.Unlock()
+
+	if task.d.Sha256 == "" {
+		return
+	}
+
+	e := task.verify()
+	logEntry := logger.With(
+		zap.Int("slot-index", int(task.ts.index)),
+		zap.String("filename", task.d.Filename),
+	)
+	if e == nil {
+		logEntry.Info("output file verified")
+	} else {
+		logEntry.Warn("output file verification failed", zap.Error(e))
+	}
+
+	task.outputLock.Lock()
+	defer task.outputLock.Unlock()
+	task.verified, task.err = e == nil, e
+}
+
+// verify computes SHA-256 digest of the output file and compares with the expected digest.
+// Once started, verification is not interrupted by Stop.
+func (task *TaskContext) verify() error {
+	f, e := os.Open(task.d.Filename)
+	if e != nil {
+		return e
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, e = io.CopyBuffer(h, io.LimitReader(f, *task.d.FileSize), make([]byte, verifyChunkLen)); e != nil {
+		return e
+	}
+
+	if digest := hex.EncodeToString(h.Sum(nil)); digest != task.d.Sha256 {
+		return fmt.Errorf("%w: expected %s, actual %s", ErrVerify, task.d.Sha256, digest)
+	}
+	return nil
+}
+
+// validateOutput validates Resume and Sha256 fields.
+// This should be called after RDR metadata discovery.
+func (d *TaskDef) validateOutput() error {
+	if d.Filename == "" {
+		if d.Resume || d.Sha256 != "" {
+			return errors.New("Resume and Sha256 require Filename")
+		}
+		return nil
+	}
+
+	if d.Sha256 != "" {
+		digest, e := hex.DecodeString(d.Sha256)
+		if e != nil || len(digest) != sha256.Size {
+			return errors.New("bad Sha256")
+		}
+		d.Sha256 = hex.EncodeToString(digest)
+		if d.FileSize == nil {
+			return errors.New("Sha256 requires FileSize")
+		}
+	}
+	return nil
+}
+
+// abort signals the task to stop, and waits for output file verification to complete.
+func (task *TaskContext) abort() {
+	task.stopOnce.Do(func() { close(task.stopping) })
+	if task.bm != nil {
+		<-task.outputDone
+	}
+}
+
+// closeBitmap releases the bitmap of written segments.
+// The sidecar file is deleted if all segments have been written.
+// The bitmap must have been detached from Logic and an RCU grace period has elapsed.
+func (task *TaskContext) closeBitmap() {
+	if task.bm == nil {
+		return
+	}
+	if e := task.bm.close(task.Written()); e != nil {
+		logger.Warn("doneBitmap close error",
+			zap.Int("slot-index", int(task.ts.index)),
+			zap.Error(e),
+		)
+	}
+	task.bm = nil
+}
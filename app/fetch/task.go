@@ -35,6 +35,17 @@ type TaskContext struct {
 	w        *worker
 	ts       *taskSlot
 	stopping chan struct{}
+	stopOnce sync.Once
+
+	historyLock sync.Mutex
+	history     []Counters
+
+	bm         *doneBitmap
+	outputDone chan struct{}
+	outputLock sync.Mutex
+	written    bool
+	verified   bool
+	err        error
 }
 
 // Counters returns congestion control and scheduling counters.
@@ -44,34 +55,87 @@ func (task *TaskContext) Counters() Counters {
 
 // Stop aborts/stops the fetch task.
 // This should be called even if the fetch task has succeeded.
+// If all segments have been written to the output file and Sha256 is set, this waits for verification to complete.
 func (task *TaskContext) Stop() {
+	task.abort()
 	eal.CallMain(func() {
+		task.ts.Logic().setDoneBitmap(nil)
 		task.w.RemoveTask(eal.MainReadSide, task.ts)
 		task.ts.closeFd(task.d.FileSize)
-		close(task.stopping)
 		taskContextLock.Lock()
 		defer taskContextLock.Unlock()
 		delete(taskContextByID, task.id)
 	})
+	task.closeBitmap()
+}
+
+// active determines whether the task is still registered, i.e. neither stopped nor removed by Fetcher.Reset.
+func (task *TaskContext) active() bool {
+	taskContextLock.RLock()
+	defer taskContextLock.RUnlock()
+	return taskContextByID[task.id] == task
 }
 
-// Finished determines if all segments have been fetched.
+// TaskState indicates the state of a fetch task.
+type TaskState string
+
+// TaskState values.
+const (
+	TaskFetching  TaskState = "fetching"  // fetching segments
+	TaskWriting   TaskState = "writing"   // all segments fetched, writing or verifying the output file
+	TaskSucceeded TaskState = "succeeded" // all segments fetched, written, and verified if requested
+	TaskFailed    TaskState = "failed"    // output file verification failed
+)
+
+// State returns the task state.
+func (task *TaskContext) State() TaskState {
+	task.outputLock.Lock()
+	defer task.outputLock.Unlock()
+	switch {
+	case task.err != nil:
+		return TaskFailed
+	case !task.ts.Logic().Finished():
+		return TaskFetching
+	case task.d.Filename != "" && (!task.written || (task.d.Sha256 != "" && !task.verified)):
+		return TaskWriting
+	}
+	return TaskSucceeded
+}
+
+// Finished determines whether the task has either succeeded or failed.
+// If writing to a file, this includes waiting for all segments to be written and the output file to be verified.
 func (task *TaskContext) Finished() bool {
-	return task.ts.Logic().Finished()
+	switch task.State() {
+	case TaskSucceeded, TaskFailed:
+		return true
+	}
+	return false
 }
 
 // TaskDef defines a fetch task that retrieves one segmented object.
 type TaskDef struct {
 	// InterestTemplateConfig contains the name prefix, InterestLifetime, etc.
 	//
-	// The fetcher neither retrieves metadata nor performs version discovery.
+	// If Discover is false, the fetcher neither retrieves metadata nor performs version discovery.
 	// If the content is published with version component, it should appear in the name prefix.
 	//
 	// CanBePrefix and MustBeFresh are not normally used, but they may be included for benchmarking purpose.
 	ndni.InterestTemplateConfig
 
+	// Discover enables RDR metadata discovery.
+	// If true, Prefix should be the unversioned name prefix.
+	// Before starting the fetch task, the fetcher sends an RDR discovery Interest, which is retransmitted up to
+	// DiscoverRetries times, and parses the metadata packet with ndn6-file-server extensions.
+	// Prefix is then replaced by the versioned name in the metadata.
+	// SegmentEnd, FileSize, and SegmentLen are filled from the metadata, unless explicitly specified.
+	Discover bool `json:"discover,omitempty"`
+
+	// CcConfig selects the congestion control algorithm.
+	// Zero fields inherit from fetcher Config.
+	CcConfig
+
 	// SegmentRange specifies range of segment numbers.
-	// If writing to a file, SegmentEnd must be explicitly specified.
+	// If writing to a file, SegmentEnd must be explicitly specified or discovered from metadata.
 	segmented.SegmentRange
 
 	// Filename is the output file name.
@@ -87,6 +151,21 @@ type TaskDef struct {
 	// This is only needed when writing to a file.
 	// If any segment has incorrect Content TLV-LENGTH, the output file would not contain correct payload.
 	SegmentLen int `json:"segmentLen,omitempty"`
+
+	// Resume enables resumable fetch.
+	// This is only relevant when writing to a file.
+	// If true, a bitmap of segments that have been written is kept in a sidecar file, whose name is
+	// Filename plus SidecarSuffix.
+	// If the sidecar file exists and was created by a task with the same Prefix, SegmentRange, and SegmentLen,
+	// segments marked in the bitmap are not fetched again.
+	// The sidecar file is deleted after all segments have been written.
+	Resume bool `json:"resume,omitempty"`
+
+	// Sha256 is the expected SHA-256 digest of the output file, in hexadecimal.
+	// If Discover is true and the metadata contains a digest, it is filled from the metadata unless explicitly specified.
+	// If set, FileSize is required, and the output file is verified after all segments have been written.
+	// Upon mismatch, the task is marked failed with ErrVerify.
+	Sha256 string `json:"sha256,omitempty"`
 }
 
 // TaskSlotConfig contains task slot configuration.
@@ -110,7 +189,7 @@ type taskSlot C.FetchTask
 // This should only be called on an inactive task slot.
 func (ts *taskSlot) Init(d TaskDef) error {
 	fl := ts.Logic()
-	fl.Reset(d.SegmentRange)
+	fl.Reset(d.SegmentRange, d.CcConfig)
 
 	tpl := ndni.InterestTemplateFromPtr(unsafe.Pointer(&ts.tpl))
 	d.InterestTemplateConfig.Apply(tpl)
@@ -160,6 +239,7 @@ func (ts *taskSlot) Init(d TaskDef) error {
 
 	logEntry.Info("task init",
 		zap.Uint64s("segment-range", []uint64{d.SegmentBegin, d.SegmentEnd}),
+		zap.String("cc", string(d.CongestionControl)),
 	)
 	return nil
 }
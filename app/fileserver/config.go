@@ -53,7 +53,8 @@ const (
 
 	EstimatedMetadataSize = 4 + // NameTL, excluding NameV
 		2 + 10 + // FinalBlockId
-		7*(4+8) // NNI fields
+		7*(4+8) + // NNI fields
+		4 + 32 // SHA-256 digest
 
 	MetadataFreshness = 1
 
@@ -102,6 +103,15 @@ type Config struct {
 	// StatValidity is the validity period of statx result.
 	StatValidity nnduration.Nanoseconds `json:"statValidity,omitempty" gqldesc:"statx result validity period."`
 
+	// Signing enables Data signing.
+	// If omitted, Data packets have Null signature.
+	Signing *ndni.SigningConfig `json:"signing,omitempty"`
+
+	// Sha256 enables publishing SHA-256 digest of file content in metadata packets.
+	// The digest is computed in the background when metadata is requested for the first time after a file is opened or modified.
+	// Until the digest is ready, metadata packets are served without the digest.
+	Sha256 bool `json:"sha256,omitempty" gqldesc:"Publish SHA-256 digest of file content in metadata."`
+
 	// WantVersionBypass allows setting special values in version component to bypass version check.
 	// This is intended for fileserver benchmarks and should not be set in normal operation.
 	WantVersionBypass bool `json:"wantVersionBypass,omitempty" gqldesc:"Allow bypassing version check in benchmarks."`
@@ -164,6 +174,12 @@ func (cfg *Config) Validate() error {
 		return errors.New("openFds must be greater than keepFds")
 	}
 
+	if cfg.Signing != nil {
+		if e := cfg.Signing.Validate(); e != nil {
+			return fmt.Errorf("signing: %w", e)
+		}
+	}
+
 	if e := cfg.checkPayloadMempool(); e != nil {
 		return e
 	}
@@ -199,8 +215,9 @@ func (cfg *Config) checkPayloadMempool() error {
 		)
 	}
 
+	sigLen := cfg.Signing.TrailerLen()
 	suggestDataroom := pktmbuf.DefaultHeadroom + ndni.NameMaxLength +
-		max(cfg.SegmentLen, ndni.NameMaxLength+EstimatedMetadataSize) + ndni.DataEncNullSigLen + 64
+		max(cfg.SegmentLen, ndni.NameMaxLength+EstimatedMetadataSize) + sigLen + 64
 	if tpl.Dataroom < suggestDataroom {
 		logger.Warn("PAYLOAD dataroom too small for configured segmentLen, Interests with long names may be dropped",
 			zap.Int("configured-dataroom", tpl.Dataroom),
@@ -209,7 +226,7 @@ func (cfg *Config) checkPayloadMempool() error {
 		)
 	}
 
-	cfg.payloadHeadroom = tpl.Dataroom - ndni.DataEncNullSigLen - max(cfg.SegmentLen, EstimatedMetadataSize)
+	cfg.payloadHeadroom = tpl.Dataroom - sigLen - max(cfg.SegmentLen, EstimatedMetadataSize)
 	if cfg.payloadHeadroom < pktmbuf.DefaultHeadroom {
 		return fmt.Errorf("PAYLOAD dataroom %d too small for segmentLen %d; increase PAYLOAD dataroom to %d",
 			tpl.Dataroom, cfg.SegmentLen, suggestDataroom)
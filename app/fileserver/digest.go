@@ -0,0 +1,169 @@
+package fileserver
+
+/*
+#include "../../csrc/fileserver/fd.h"
+*/
+import "C"
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+	"github.com/usnistgov/ndn-dpdk/dpdk/mempool"
+	"github.com/usnistgov/ndn-dpdk/dpdk/ringbuffer"
+	"go.uber.org/zap"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	digestJobs         = 63
+	digestBufferSize   = 1 << 20
+	digestPollInterval = 10 * time.Millisecond
+)
+
+var (
+	errDigestStopped = errors.New("digest computation stopped")
+	errDigestChanged = errors.New("file changed during digest computation")
+)
+
+// digester computes SHA-256 digest of file content on behalf of a worker thread.
+//
+// The worker submits jobs via digestReq ring, and receives results via digestDone ring.
+// Reading the file happens in a goroutine, so that it does not block the worker thread.
+type digester struct {
+	mp      *mempool.Mempool
+	req     *ringbuffer.Ring
+	done    *ringbuffer.Ring
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+func (d *digester) assign(c *C.FileServer) {
+	c.digestMp = (*C.struct_rte_mempool)(d.mp.Ptr())
+	c.digestReq = (*C.struct_rte_ring)(d.req.Ptr())
+	c.digestDone = (*C.struct_rte_ring)(d.done.Ptr())
+}
+
+func (d *digester) run() {
+	defer close(d.stopped)
+	ticker := time.NewTicker(digestPollInterval)
+	defer ticker.Stop()
+
+	buf := make([]byte, digestBufferSize)
+	jobs := make([]*C.FileServerDigestJob, 16)
+	for {
+		n := ringbuffer.Dequeue(d.req, jobs)
+		for _, job := range jobs[:n] {
+			e := d.compute(job, buf)
+			job.ok = C.bool(e == nil)
+			if e != nil {
+				logger.Warn("SHA-256 digest error",
+					zap.Uint64("version", uint64(job.version)),
+					zap.Uint64("size", uint64(job.size)),
+					zap.Error(e),
+				)
+			}
+		}
+		// done ring is larger than mempool capacity, so that it can accept every job
+		ringbuffer.Enqueue(d.done, jobs[:n])
+		if n > 0 {
+			continue
+		}
+
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *digester) compute(job *C.FileServerDigestJob, buf []byte) error {
+	f := os.NewFile(uintptr(job.fd), "digest")
+	defer f.Close()
+
+	h := sha256.New()
+	size := int64(job.size)
+	for offset := int64(0); offset < size; {
+		select {
+		case <-d.stop:
+			return errDigestStopped
+		default:
+		}
+
+		n, e := f.ReadAt(buf[:min(int64(len(buf)), size-offset)], offset)
+		h.Write(buf[:n])
+		offset += int64(n)
+		if e != nil && (e != io.EOF || offset < size) {
+			return e
+		}
+	}
+
+	var st unix.Stat_t
+	if e := unix.Fstat(int(f.Fd()), &st); e != nil {
+		return e
+	}
+	if st.Size != size || uint64(unix.TimespecToNsec(st.Mtim)) != uint64(job.version) {
+		return errDigestChanged
+	}
+
+	for i, b := range h.Sum(nil) {
+		job.digest[i] = C.uint8_t(b)
+	}
+	return nil
+}
+
+func (d *digester) close() error {
+	if d.stopped != nil {
+		close(d.stop)
+		<-d.stopped
+	}
+
+	errs := []error{}
+	if d.req != nil {
+		jobs := make([]*C.FileServerDigestJob, 16)
+		for n := ringbuffer.Dequeue(d.req, jobs); n > 0; n = ringbuffer.Dequeue(d.req, jobs) {
+			for _, job := range jobs[:n] {
+				unix.Close(int(job.fd))
+			}
+		}
+		errs = append(errs, d.req.Close())
+	}
+	if d.done != nil {
+		errs = append(errs, d.done.Close())
+	}
+	if d.mp != nil {
+		errs = append(errs, d.mp.Close())
+	}
+	return errors.Join(errs...)
+}
+
+func newDigester(socket eal.NumaSocket) (d *digester, e error) {
+	d = &digester{}
+	if d.mp, e = mempool.New(mempool.Config{
+		Capacity:       digestJobs,
+		ElementSize:    C.sizeof_FileServerDigestJob,
+		Socket:         socket,
+		SingleProducer: true,
+		SingleConsumer: true,
+	}); e != nil {
+		d.close()
+		return nil, e
+	}
+
+	if d.req, e = ringbuffer.New(2*d.mp.Capacity(), socket, ringbuffer.ProducerSingle, ringbuffer.ConsumerSingle); e != nil {
+		d.close()
+		return nil, e
+	}
+	if d.done, e = ringbuffer.New(2*d.mp.Capacity(), socket, ringbuffer.ProducerSingle, ringbuffer.ConsumerSingle); e != nil {
+		d.close()
+		return nil, e
+	}
+
+	d.stop, d.stopped = make(chan struct{}), make(chan struct{})
+	go d.run()
+	return d, nil
+}
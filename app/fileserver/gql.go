@@ -8,6 +8,7 @@ import (
 	"github.com/usnistgov/ndn-dpdk/core/gqlserver"
 	"github.com/usnistgov/ndn-dpdk/iface"
 	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndni"
 )
 
 // GqlRetrieveByFaceID returns *FileServer associated with a face.
@@ -36,12 +37,15 @@ func init() {
 		Fields: gqlserver.BindInputFields[Config](gqlserver.FieldTypes{
 			reflect.TypeFor[iface.PktQueueConfig](): iface.GqlPktQueueInput,
 			reflect.TypeFor[Mount]():                GqlMountInput,
+			reflect.TypeFor[ndni.SigningConfig]():   ndni.GqlSigningInput,
 		}),
 	})
 	GqlCountersType = graphql.NewObject(graphql.ObjectConfig{
 		Name:        "FileServerCounters",
 		Description: "File server counters.",
-		Fields:      gqlserver.BindFields[Counters](nil),
+		Fields: gqlserver.BindFields[Counters](gqlserver.FieldTypes{
+			reflect.TypeFor[ndni.SignerCounters](): ndni.GqlSignerCountersType,
+		}),
 	})
 
 	GqlServerType = gqlserver.NewNodeType(graphql.ObjectConfig{
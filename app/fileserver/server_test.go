@@ -11,8 +11,10 @@ import (
 	"math/rand/v2"
 	"os"
 	"path"
+	"path/filepath"
 	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -21,6 +23,7 @@ import (
 	"github.com/jacobsa/fuse"
 	"github.com/jacobsa/fuse/fuseops"
 	"github.com/jacobsa/fuse/fuseutil"
+	"github.com/usnistgov/ndn-dpdk/app/fetch"
 	"github.com/usnistgov/ndn-dpdk/app/fileserver"
 	"github.com/usnistgov/ndn-dpdk/app/tg/tgtestenv"
 	"github.com/usnistgov/ndn-dpdk/core/logging"
@@ -745,3 +748,89 @@ func TestFuse(t *testing.T) {
 	cntJ, _ := json.Marshal(cnt)
 	t.Log(string(cntJ))
 }
+
+func TestSha256Fetch(t *testing.T) {
+	assert, require := makeAR(t)
+
+	srcDir, outDir := t.TempDir(), t.TempDir()
+	content := make([]byte, 3000*7+100)
+	randBytes(content)
+	require.NoError(os.WriteFile(filepath.Join(srcDir, "F"), content, 0o644))
+	digest := sha256.Sum256(content)
+
+	f := newFileServerFixture(t, fileserver.Config{
+		Mounts: []fileserver.Mount{
+			{Prefix: ndn.ParseName("/sha"), Path: srcDir},
+		},
+		SegmentLen: 3000,
+		Sha256:     true,
+	})
+
+	// digest is computed in the background; metadata is served without digest until it is ready
+	require.Eventually(func() bool {
+		m, e := f.RetrieveMetadata("/sha/F")
+		if e != nil || len(m.Sha256) == 0 {
+			return false
+		}
+		assert.Equal(digest[:], m.Sha256)
+		return true
+	}, 5*time.Second, 20*time.Millisecond)
+
+	fetchFace := intface.MustNew()
+	t.Cleanup(func() { fetchFace.D.Close() })
+	_, e := f.fw.AddFace(fetchFace.A)
+	require.NoError(e)
+
+	var cfg fetch.Config
+	cfg.NThreads = 1
+	cfg.NTasks = 2
+	fetcher, e := fetch.New(fetchFace.D, cfg)
+	require.NoError(e)
+	tgtestenv.Open(t, fetcher)
+	t.Cleanup(func() { fetcher.Close() })
+	fetcher.Launch()
+
+	fetchFile := func(t *testing.T, filename, sha256hex string) *fetch.TaskContext {
+		_, require := makeAR(t)
+		var d fetch.TaskDef
+		d.Prefix = ndn.ParseName("/sha/F")
+		d.Discover = true
+		d.Filename = filepath.Join(outDir, filename)
+		d.Sha256 = sha256hex
+		task, e := fetcher.Fetch(d)
+		require.NoError(e)
+
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for !task.Finished() {
+			select {
+			case <-f.timeout.Done():
+				require.FailNow("fetch timeout", "state %s", task.State())
+			case <-ticker.C:
+			}
+		}
+		task.Stop()
+		return task
+	}
+
+	t.Run("match", func(t *testing.T) {
+		assert, require := makeAR(t)
+		task := fetchFile(t, "match", "")
+		assert.Equal(fetch.TaskSucceeded, task.State())
+		assert.NoError(task.Err())
+		assert.True(task.Verified())
+
+		written, e := os.ReadFile(filepath.Join(outDir, "match"))
+		require.NoError(e)
+		assert.Equal(content, written)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		assert, _ := makeAR(t)
+		task := fetchFile(t, "mismatch", strings.Repeat("A0", sha256.Size))
+		assert.True(task.Finished())
+		assert.Equal(fetch.TaskFailed, task.State())
+		assert.ErrorIs(task.Err(), fetch.ErrVerify)
+		assert.False(task.Verified())
+	})
+}
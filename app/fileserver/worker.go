@@ -23,9 +23,10 @@ import (
 
 type worker struct {
 	ealthread.ThreadWithCtrl
-	c    *C.FileServer
-	opMp *mempool.Mempool
-	fdMp *mempool.Mempool
+	c      *C.FileServer
+	opMp   *mempool.Mempool
+	fdMp   *mempool.Mempool
+	digest *digester
 }
 
 var _ interface {
@@ -51,6 +52,13 @@ func (w worker) rxQueue() *iface.PktQueue {
 	return iface.PktQueueFromPtr(unsafe.Pointer(&w.c.rxQueue))
 }
 
+func (w worker) signer() *ndni.DataSigner {
+	if w.c.signer == nil {
+		return nil
+	}
+	return ndni.DataSignerFromPtr(unsafe.Pointer(w.c.signer))
+}
+
 func (w *worker) close() error {
 	errs := []error{}
 	if w.opMp != nil {
@@ -61,6 +69,14 @@ func (w *worker) close() error {
 		errs = append(errs, w.fdMp.Close())
 		w.fdMp = nil
 	}
+	if w.digest != nil {
+		errs = append(errs, w.digest.close())
+		w.digest = nil
+	}
+	if signer := w.signer(); signer != nil {
+		errs = append(errs, signer.Close())
+		w.c.signer = nil
+	}
 	errs = append(errs, w.rxQueue().Close())
 	eal.Free(w.c)
 	w.c = nil
@@ -80,6 +96,9 @@ func (w worker) addToCounters(cnt *Counters) {
 	cnt.UringSubmitNonBlock += uint64(w.c.ur.nSubmitNonBlock)
 	cnt.UringSubmitWait += uint64(w.c.ur.nSubmitWait)
 	cnt.UringCqeFail += uint64(w.c.cnt.cqeFail)
+	if signer := w.signer(); signer != nil {
+		cnt.Signing = cnt.Signing.Add(signer.Counters())
+	}
 }
 
 func newWorker(faceID iface.ID, socket eal.NumaSocket, cfg Config) (w *worker, e error) {
@@ -114,6 +133,23 @@ func newWorker(faceID iface.ID, socket eal.NumaSocket, cfg Config) (w *worker, e
 		return nil, e
 	}
 
+	if cfg.Sha256 {
+		if w.digest, e = newDigester(socket); e != nil {
+			w.close()
+			return nil, e
+		}
+		w.digest.assign(w.c)
+	}
+
+	if cfg.Signing != nil {
+		signer, e := ndni.NewDataSigner(cfg.Signing, socket)
+		if e != nil {
+			w.close()
+			return nil, e
+		}
+		w.c.signer = (*C.DataSigner)(signer.Ptr())
+	}
+
 	(*ndni.Mempools)(unsafe.Pointer(&w.c.mp)).Assign(socket)
 	w.c.opMp = (*C.struct_rte_mempool)(w.opMp.Ptr())
 	w.c.fdMp = (*C.struct_rte_mempool)(w.fdMp.Ptr())
@@ -151,16 +187,17 @@ func newWorker(faceID iface.ID, socket eal.NumaSocket, cfg Config) (w *worker, e
 
 // Counters contains file server counters.
 type Counters struct {
-	ReqRead             uint64 `json:"reqRead" gqldesc:"Received read requests."`
-	ReqLs               uint64 `json:"reqLs" gqldesc:"Received directory listing requests."`
-	ReqMetadata         uint64 `json:"reqMetadata" gqldesc:"Received metadata requests."`
-	FdNew               uint64 `json:"fdNew" gqldesc:"Successfully opened file descriptors."`
-	FdNotFound          uint64 `json:"fdNotFound" gqldesc:"File not found."`
-	FdUpdateStat        uint64 `json:"fdUpdateStat" gqldesc:"Update stat on already open file descriptors."`
-	FdClose             uint64 `json:"fdClose" gqldesc:"Closed file descriptors."`
-	UringAllocError     uint64 `json:"uringAllocErrs" gqldesc:"uring SQE allocation errors."`
-	UringSubmitted      uint64 `json:"uringSubmitted" gqldesc:"uring submitted SQEs."`
-	UringSubmitNonBlock uint64 `json:"uringSubmitNonBlock" gqldesc:"uring non-blocking submission batches."`
-	UringSubmitWait     uint64 `json:"uringSubmitWait" gqldesc:"uring waiting submission batches."`
-	UringCqeFail        uint64 `json:"cqeFail" gqldesc:"uring failed CQEs."`
+	ReqRead             uint64              `json:"reqRead" gqldesc:"Received read requests."`
+	ReqLs               uint64              `json:"reqLs" gqldesc:"Received directory listing requests."`
+	ReqMetadata         uint64              `json:"reqMetadata" gqldesc:"Received metadata requests."`
+	FdNew               uint64              `json:"fdNew" gqldesc:"Successfully opened file descriptors."`
+	FdNotFound          uint64              `json:"fdNotFound" gqldesc:"File not found."`
+	FdUpdateStat        uint64              `json:"fdUpdateStat" gqldesc:"Update stat on already open file descriptors."`
+	FdClose             uint64              `json:"fdClose" gqldesc:"Closed file descriptors."`
+	UringAllocError     uint64              `json:"uringAllocErrs" gqldesc:"uring SQE allocation errors."`
+	UringSubmitted      uint64              `json:"uringSubmitted" gqldesc:"uring submitted SQEs."`
+	UringSubmitNonBlock uint64              `json:"uringSubmitNonBlock" gqldesc:"uring non-blocking submission batches."`
+	UringSubmitWait     uint64              `json:"uringSubmitWait" gqldesc:"uring waiting submission batches."`
+	UringCqeFail        uint64              `json:"cqeFail" gqldesc:"uring failed CQEs."`
+	Signing             ndni.SignerCounters `json:"signing" gqldesc:"Data signing counters."`
 }
@@ -45,6 +45,7 @@ type Config struct {
 	FwdDataQueue          iface.PktQueueConfig `json:"fwdDataQueue,omitempty"`
 	FwdNackQueue          iface.PktQueueConfig `json:"fwdNackQueue,omitempty"`
 	LatencySampleInterval int                  `json:"latencySampleInterval,omitempty"`
+	PrefixStat            PrefixStatConfig     `json:"prefixStat,omitempty"`
 }
 
 func (cfg *Config) validate() error {
@@ -66,7 +67,7 @@ func (cfg *Config) validate() error {
 	if cfg.LatencySampleInterval <= 0 {
 		cfg.LatencySampleInterval = 1 << 16
 	}
-	return nil
+	return cfg.PrefixStat.validate()
 }
 
 // DefaultAlloc is the default lcore allocation algorithm.
@@ -237,6 +238,7 @@ func New(cfg Config) (dp *DataPlane, e error) {
 		if e != nil {
 			return nil, fmt.Errorf("Fwd[%d].Init(): %w", i, e)
 		}
+		fwd.initPrefixStat(cfg.PrefixStat, lc.NumaSocket())
 		dp.fwds = append(dp.fwds, fwd)
 		fibFwds = append(fibFwds, fwd)
 	}
@@ -33,6 +33,9 @@ type Fwd struct {
 	queueI *iface.PktQueue
 	queueD *iface.PktQueue
 	queueN *iface.PktQueue
+
+	csCapMemoryLimit   int // maximum CS memory capacity in runtime reconfiguration
+	csCapIndirectLimit int // maximum CS indirect capacity in runtime reconfiguration
 }
 
 var _ interface {
@@ -43,6 +46,7 @@ var _ interface {
 // Close stops and releases the thread.
 func (fwd *Fwd) Close() error {
 	defer eal.Free(fwd.c)
+	defer fwd.closePrefixStat()
 	return errors.Join(
 		fwd.Stop(),
 		fwd.queueI.Close(),
@@ -149,6 +153,9 @@ func newFwd(id int, lc eal.LCore, pcctCfg pcct.Config, qcfgI, qcfgD, qcfgN iface
 	pcctC := (*C.Pcct)(fwd.pcct.Ptr())
 	fwd.c.pit = &pcctC.pit
 	fwd.c.cs = &pcctC.cs
+	pcctCap := fwd.pcct.AsMempool().Capacity()
+	fwd.csCapMemoryLimit = max(fwd.Cs().Capacity(cs.ListDirect), pcctCap/4)
+	fwd.csCapIndirectLimit = max(fwd.Cs().Capacity(cs.ListIndirect), pcctCap/4)
 
 	pcg32.Init(unsafe.Pointer(&fwd.c.sgRng))
 	suppressCfg.CopyToC(unsafe.Pointer(&fwd.c.suppressCfg))
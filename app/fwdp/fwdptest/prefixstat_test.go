@@ -0,0 +1,114 @@
+package fwdptest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/usnistgov/ndn-dpdk/app/fwdp"
+	"github.com/usnistgov/ndn-dpdk/iface/intface"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+)
+
+func TestPrefixStat(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := NewFixture(t, func(cfg *fwdp.Config) {
+		cfg.PrefixStat.Depth = 2
+		cfg.PrefixStat.SampleInterval = 1
+	})
+
+	face1, face2 := intface.MustNew(), intface.MustNew()
+	collect2 := intface.Collect(face2)
+	fixture.SetFibEntry("/P", "multicast", face2.ID)
+
+	for i := range 40 {
+		face1.Tx <- ndn.MakeInterest(fmt.Sprintf("/P/A/%d", i), makeToken().LpL3())
+	}
+	for i := range 10 {
+		face1.Tx <- ndn.MakeInterest(fmt.Sprintf("/P/B/%d", i), makeToken().LpL3())
+	}
+	fixture.StepDelay()
+	require.Equal(50, collect2.Count())
+
+	for i := range 50 {
+		if interest := collect2.Get(i).Interest; interest.Name.Get(1).String() == "B" {
+			face2.Tx <- ndn.MakeData(interest, make([]byte, 100))
+		}
+	}
+	fixture.StepDelay()
+
+	byInterests := fixture.DataPlane.TopPrefixes(10, fwdp.PrefixStatByInterests, 0)
+	require.Len(byInterests, 2)
+	assert.Equal("/P/A", byInterests[0].Name.String())
+	assert.EqualValues(40, byInterests[0].NInterests)
+	assert.EqualValues(0, byInterests[0].NData)
+	assert.InDelta(1.0, byInterests[0].UnsatisfiedRatio(), 0.001)
+	assert.Equal("/P/B", byInterests[1].Name.String())
+	assert.EqualValues(10, byInterests[1].NInterests)
+	assert.EqualValues(10, byInterests[1].NData)
+	assert.Greater(byInterests[1].DataBytes, uint64(1000))
+	assert.InDelta(0.0, byInterests[1].UnsatisfiedRatio(), 0.001)
+
+	byDataBytes := fixture.DataPlane.TopPrefixes(1, fwdp.PrefixStatByDataBytes, 0)
+	require.Len(byDataBytes, 1)
+	assert.Equal("/P/B", byDataBytes[0].Name.String())
+
+	assert.Len(fixture.DataPlane.TopPrefixes(10, fwdp.PrefixStatByUnsatisfiedRatio, 20), 1)
+}
+
+func TestPrefixStatSampled(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := NewFixture(t, func(cfg *fwdp.Config) {
+		cfg.PrefixStat.Depth = 2
+		cfg.PrefixStat.Capacity = 4
+		cfg.PrefixStat.SampleInterval = 4
+	})
+
+	face1, face2 := intface.MustNew(), intface.MustNew()
+	collect2 := intface.Collect(face2)
+	fixture.SetFibEntry("/P", "multicast", face2.ID)
+
+	// Interest counter: /P/A takes samples 4,8,...,40; /P/B takes sample 44
+	for i := range 40 {
+		face1.Tx <- ndn.MakeInterest(fmt.Sprintf("/P/A/%d", i), makeToken().LpL3())
+	}
+	fixture.StepDelay()
+	for i := range 6 {
+		face1.Tx <- ndn.MakeInterest(fmt.Sprintf("/P/B/%d", i), makeToken().LpL3())
+	}
+	fixture.StepDelay()
+	require.Equal(46, collect2.Count())
+
+	// Data counter is separate from Interest counter: /P/B takes sample 4
+	for i := range 46 {
+		if interest := collect2.Get(i).Interest; interest.Name.Get(1).String() == "B" {
+			face2.Tx <- ndn.MakeData(interest, make([]byte, 100))
+		}
+	}
+	fixture.StepDelay()
+
+	byInterests := fixture.DataPlane.TopPrefixes(10, fwdp.PrefixStatByInterests, 0)
+	require.Len(byInterests, 2)
+	assert.Equal("/P/A", byInterests[0].Name.String())
+	assert.EqualValues(40, byInterests[0].NInterests)
+	assert.Zero(byInterests[0].NData)
+	assert.Equal("/P/B", byInterests[1].Name.String())
+	assert.EqualValues(4, byInterests[1].NInterests)
+	assert.EqualValues(4, byInterests[1].NData)
+	assert.InDelta(0.0, byInterests[1].UnsatisfiedRatio(), 0.001)
+
+	// many distinct prefixes exercise eviction through hash table and heap
+	for i := range 400 {
+		face1.Tx <- ndn.MakeInterest(fmt.Sprintf("/P/C%d/%d", i%20, i), makeToken().LpL3())
+	}
+	fixture.StepDelay()
+	all := fixture.DataPlane.TopPrefixes(0, fwdp.PrefixStatByInterests, 0)
+	assert.LessOrEqual(len(all), 8) // capacity 4 in each of 2 forwarding threads
+	nC := 0
+	for _, rec := range all {
+		if comp := rec.Name.Get(1).String(); comp[0] == 'C' {
+			nC++
+			assert.NotZero(rec.NInterests)
+		}
+	}
+	assert.NotZero(nC)
+}
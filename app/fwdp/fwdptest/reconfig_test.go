@@ -0,0 +1,46 @@
+package fwdptest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/app/fwdp"
+	"github.com/usnistgov/ndn-dpdk/container/pit"
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
+)
+
+func TestReconfigure(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := NewFixture(t, func(cfg *fwdp.Config) {
+		cfg.Suppress.Min = nnduration.Nanoseconds(20 * time.Millisecond)
+		cfg.Suppress.Max = nnduration.Nanoseconds(500 * time.Millisecond)
+		cfg.Suppress.Multiplier = 3
+	})
+
+	_, e := fixture.DataPlane.Reconfigure(fwdp.RuntimeConfig{CsMemoryCapacity: 65535})
+	assert.Error(e)
+	_, e = fixture.DataPlane.Reconfigure(fwdp.RuntimeConfig{CsIndirectCapacity: 65535})
+	assert.Error(e)
+
+	list, e := fixture.DataPlane.Reconfigure(fwdp.RuntimeConfig{
+		CsMemoryCapacity: 8192,
+		Suppress: &pit.SuppressConfig{
+			Max: nnduration.Nanoseconds(time.Second),
+		},
+	})
+	require.NoError(e)
+	require.Len(list, 2)
+	for _, rc := range list {
+		assert.Equal(8192, rc.CsMemoryCapacity)
+		assert.Equal(16384, rc.CsIndirectCapacity)
+		assert.InDelta(20e6, float64(rc.Suppress.Min), 1e3)
+		assert.InDelta(1e9, float64(rc.Suppress.Max), 1e3)
+		assert.InDelta(3.0, rc.Suppress.Multiplier, 1e-6)
+	}
+
+	list, e = fixture.DataPlane.Reconfigure(fwdp.RuntimeConfig{CsMemoryCapacity: 16384})
+	require.NoError(e)
+	for _, rc := range list {
+		assert.Equal(16384, rc.CsMemoryCapacity)
+	}
+}
@@ -10,10 +10,14 @@ import (
 	"github.com/usnistgov/ndn-dpdk/container/fib"
 	"github.com/usnistgov/ndn-dpdk/container/pit"
 	"github.com/usnistgov/ndn-dpdk/core/gqlserver"
+	"github.com/usnistgov/ndn-dpdk/core/jsonhelper"
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
 	"github.com/usnistgov/ndn-dpdk/core/rttest"
 	"github.com/usnistgov/ndn-dpdk/core/runningstat"
 	"github.com/usnistgov/ndn-dpdk/dpdk/ealthread"
 	"github.com/usnistgov/ndn-dpdk/iface"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndni"
 )
 
 var (
@@ -51,6 +55,11 @@ var (
 	GqlDispatchCountersType    *graphql.Object
 	GqlFwdCountersType         *graphql.Object
 	GqlFibNexthopRttType       *graphql.Object
+	GqlQueueRuntimeConfigType  *graphql.Object
+	GqlQueueRuntimeConfigInput *graphql.InputObject
+	GqlFwdRuntimeConfigType    *graphql.Object
+	GqlPrefixStatSortByEnum    *graphql.Enum
+	GqlPrefixStatRecordType    *graphql.Object
 )
 
 func init() {
@@ -287,6 +296,99 @@ func init() {
 		},
 	})
 
+	queueRuntimeConfigFieldTypes := gqlserver.FieldTypes{
+		reflect.TypeFor[nnduration.Nanoseconds](): nnduration.GqlNanoseconds,
+	}
+	GqlQueueRuntimeConfigType = graphql.NewObject(graphql.ObjectConfig{
+		Name:   "FwQueueRuntimeConfig",
+		Fields: gqlserver.BindFields[QueueRuntimeConfig](queueRuntimeConfigFieldTypes),
+	})
+	GqlQueueRuntimeConfigInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:   "FwQueueRuntimeConfigInput",
+		Fields: gqlserver.BindInputFields[QueueRuntimeConfig](queueRuntimeConfigFieldTypes),
+	})
+	GqlFwdRuntimeConfigType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "FwFwdRuntimeConfig",
+		Description: "Effective runtime parameters of a forwarding thread.",
+		Fields: gqlserver.BindFields[FwdRuntimeConfig](gqlserver.FieldTypes{
+			reflect.TypeFor[pit.SuppressConfig](): pit.GqlSuppressConfigType,
+			reflect.TypeFor[QueueRuntimeConfig](): GqlQueueRuntimeConfigType,
+		}),
+	})
+	GqlFwdType.Object.AddFieldConfig("runtimeConfig", &graphql.Field{
+		Description: "Runtime parameters.",
+		Type:        graphql.NewNonNull(GqlFwdRuntimeConfigType),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			fwd := p.Source.(*Fwd)
+			return fwd.RuntimeConfig(), nil
+		},
+	})
+
+	gqlserver.AddMutation(&graphql.Field{
+		Name:        "reconfigureFwdp",
+		Description: "Change runtime parameters on every forwarding thread. Omitted parameters retain their current values.",
+		Args: gqlserver.BindArguments[RuntimeConfig](gqlserver.FieldTypes{
+			reflect.TypeFor[pit.SuppressConfig](): pit.GqlSuppressConfigInput,
+			reflect.TypeFor[QueueRuntimeConfig](): GqlQueueRuntimeConfigInput,
+		}),
+		Type: gqlserver.NewListNonNullBoth(GqlFwdRuntimeConfigType),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			if GqlDataPlane == nil {
+				return nil, errNoGqlDataPlane
+			}
+
+			var cfg RuntimeConfig
+			if e := jsonhelper.Roundtrip(p.Args, &cfg); e != nil {
+				return nil, e
+			}
+			return GqlDataPlane.Reconfigure(cfg)
+		},
+	})
+
+	GqlPrefixStatSortByEnum = gqlserver.NewStringEnum("FwPrefixStatSortBy", "Per-prefix traffic accounting sorting criteria.",
+		PrefixStatByInterests, PrefixStatByDataBytes, PrefixStatByUnsatisfiedRatio)
+	GqlPrefixStatRecordType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "FwPrefixStatRecord",
+		Description: "Traffic counters of a name prefix, estimated from sampled packets.",
+		Fields: gqlserver.BindFields[PrefixStatRecord](gqlserver.FieldTypes{
+			reflect.TypeFor[ndn.Name](): ndni.GqlNameType,
+		}),
+	})
+	GqlPrefixStatRecordType.AddFieldConfig("unsatisfiedRatio", &graphql.Field{
+		Description: "Ratio of Interests not satisfied by Data.",
+		Type:        graphql.NewNonNull(graphql.Float),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return p.Source.(PrefixStatRecord).UnsatisfiedRatio(), nil
+		},
+	})
+	GqlDataPlaneType.AddFieldConfig("topPrefixes", &graphql.Field{
+		Description: "Top name prefixes by traffic, aggregated across forwarding threads." +
+			" This requires per-prefix traffic accounting to be enabled in data plane config.",
+		Type: gqlserver.NewListNonNullBoth(GqlPrefixStatRecordType),
+		Args: graphql.FieldConfigArgument{
+			"n": &graphql.ArgumentConfig{
+				Description:  "Maximum number of prefixes.",
+				Type:         graphql.Int,
+				DefaultValue: 10,
+			},
+			"sortBy": &graphql.ArgumentConfig{
+				Description:  "Sorting criteria.",
+				Type:         GqlPrefixStatSortByEnum,
+				DefaultValue: PrefixStatByInterests,
+			},
+			"minInterests": &graphql.ArgumentConfig{
+				Description:  "Exclude prefixes with fewer Interests.",
+				Type:         graphql.Int,
+				DefaultValue: 0,
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			dp := p.Source.(*DataPlane)
+			n, sortBy, minInterests := p.Args["n"].(int), p.Args["sortBy"].(PrefixStatSortBy), p.Args["minInterests"].(int)
+			return dp.TopPrefixes(n, sortBy, uint64(max(minInterests, 0))), nil
+		},
+	})
+
 	GqlFibNexthopRttType = graphql.NewObject(graphql.ObjectConfig{
 		Name:        "FibNexthopRtt",
 		Description: "FIB nexthop and RTT measurements in a forwarding thread.",
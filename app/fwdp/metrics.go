@@ -0,0 +1,55 @@
+package fwdp
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/usnistgov/ndn-dpdk/core/metrics"
+	"github.com/usnistgov/ndn-dpdk/ndni"
+)
+
+var metricsPktTypes = []struct {
+	t    ndni.PktType
+	name string
+}{
+	{ndni.PktInterest, "interest"},
+	{ndni.PktData, "data"},
+	{ndni.PktNack, "nack"},
+}
+
+func init() {
+	metrics.Register("fwdp", func(c *metrics.Collection) {
+		dp := GqlDataPlane
+		if dp == nil {
+			return
+		}
+
+		for _, fwd := range dp.fwds {
+			labels := metrics.Labels{"fwd": strconv.Itoa(fwd.id)}
+			cnt := fwd.Counters()
+			c.AddStruct("fwd_", cnt, labels)
+			c.Add("fwd_input_latency_ns", "Mean latency between packet arrival and dequeuing at forwarding thread, in nanoseconds.",
+				metrics.Gauge, cnt.InputLatency.Mean, labels)
+			c.AddStruct("pit_", fwd.Pit().Counters(), labels)
+			c.AddStruct("cs_", fwd.Cs().Counters(), labels)
+			for _, pt := range metricsPktTypes {
+				c.AddStruct("fwd_queue_", fwd.PktQueueOf(pt.t).Counters(), labels.With("type", pt.name))
+			}
+		}
+
+		for _, th := range dp.dispatch {
+			for _, pt := range metricsPktTypes {
+				demux := th.DemuxOf(pt.t)
+				if demux == nil {
+					continue
+				}
+				for i, fwd := range dp.fwds {
+					labels := metrics.Labels{"thread": fmt.Sprint(th), "type": pt.name, "fwd": strconv.Itoa(fwd.id)}
+					dest := demux.DestCounters(i)
+					c.Add("dispatch_queued", "Packets enqueued toward forwarding thread.", metrics.Counter, float64(dest.NQueued), labels)
+					c.Add("dispatch_dropped", "Packets dropped toward forwarding thread.", metrics.Counter, float64(dest.NDropped), labels)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,196 @@
+package fwdp
+
+/*
+#include "../../csrc/fwdp/fwd.h"
+*/
+import "C"
+import (
+	"cmp"
+	"fmt"
+	"math/bits"
+	"slices"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/usnistgov/ndn-dpdk/core/cptr"
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+)
+
+// PrefixStat limits and defaults.
+const (
+	MaxPrefixStatCapacity     = 4096
+	DefaultPrefixStatCapacity = 256
+	DefaultPrefixStatSample   = 16
+)
+
+// PrefixStatConfig contains per-prefix traffic accounting configuration.
+type PrefixStatConfig struct {
+	// Depth is the name prefix length in components.
+	// Packets are accounted toward their name prefix of this length.
+	// Zero disables per-prefix traffic accounting.
+	Depth int `json:"depth,omitempty"`
+
+	// Capacity is the number of tracked prefixes per forwarding thread.
+	// Default is 256; maximum is 4096.
+	Capacity int `json:"capacity,omitempty"`
+
+	// SampleInterval is the sampling interval; 1-in-SampleInterval packets are accounted.
+	// This is adjusted up to the next power of two.
+	// Default is 16.
+	SampleInterval int `json:"sampleInterval,omitempty"`
+}
+
+func (cfg *PrefixStatConfig) validate() error {
+	if cfg.Depth < 0 {
+		return fmt.Errorf("prefixStat.depth must be non-negative")
+	}
+	if cfg.Capacity <= 0 {
+		cfg.Capacity = DefaultPrefixStatCapacity
+	}
+	if cfg.Capacity > MaxPrefixStatCapacity {
+		return fmt.Errorf("prefixStat.capacity must not exceed %d", MaxPrefixStatCapacity)
+	}
+	if cfg.SampleInterval <= 0 {
+		cfg.SampleInterval = DefaultPrefixStatSample
+	}
+	cfg.SampleInterval = 1 << bits.Len(uint(cfg.SampleInterval-1))
+	return nil
+}
+
+// PrefixStatRecord contains traffic counters of a name prefix.
+// Counters are estimated from sampled packets.
+type PrefixStatRecord struct {
+	Name       ndn.Name `json:"name" gqldesc:"Name prefix."`
+	NInterests uint64   `json:"nInterests" gqldesc:"Incoming Interests."`
+	NData      uint64   `json:"nData" gqldesc:"Data satisfying Interests, including cache hits."`
+	DataBytes  uint64   `json:"dataBytes" gqldesc:"Total length of Data satisfying Interests."`
+	Error      uint64   `json:"error" gqldesc:"Maximum overestimation of packet count, due to heavy hitters approximation."`
+}
+
+// UnsatisfiedRatio returns the ratio of Interests not satisfied by Data.
+func (rec PrefixStatRecord) UnsatisfiedRatio() float64 {
+	if rec.NInterests == 0 || rec.NData >= rec.NInterests {
+		return 0
+	}
+	return 1 - float64(rec.NData)/float64(rec.NInterests)
+}
+
+// PrefixStatSortBy indicates sorting criteria of PrefixStatRecord.
+type PrefixStatSortBy string
+
+// PrefixStatSortBy values.
+const (
+	PrefixStatByInterests        PrefixStatSortBy = "INTERESTS"
+	PrefixStatByDataBytes        PrefixStatSortBy = "DATA_BYTES"
+	PrefixStatByUnsatisfiedRatio PrefixStatSortBy = "UNSATISFIED_RATIO"
+)
+
+func (sortBy PrefixStatSortBy) compare(a, b PrefixStatRecord) int {
+	switch sortBy {
+	case PrefixStatByDataBytes:
+		return cmp.Compare(b.DataBytes, a.DataBytes)
+	case PrefixStatByUnsatisfiedRatio:
+		return cmp.Or(cmp.Compare(b.UnsatisfiedRatio(), a.UnsatisfiedRatio()), cmp.Compare(b.NInterests, a.NInterests))
+	}
+	return cmp.Compare(b.NInterests, a.NInterests)
+}
+
+func (fwd *Fwd) initPrefixStat(cfg PrefixStatConfig, socket eal.NumaSocket) {
+	ps := &fwd.c.prefixStat
+	if cfg.Depth == 0 {
+		return
+	}
+	tableSize := 1 << bits.Len(uint(2*cfg.Capacity-1)) // keep occupancy under 50%
+	ps.entries = eal.Zmalloc[C.PrefixStatEntry]("PrefixStat", C.sizeof_PrefixStatEntry*cfg.Capacity, socket)
+	ps.heap = eal.Zmalloc[C.uint32_t]("PrefixStat", C.sizeof_uint32_t*cfg.Capacity, socket)
+	ps.table = eal.Zmalloc[C.uint32_t]("PrefixStat", C.sizeof_uint32_t*tableSize, socket)
+	ps.tableMask = C.uint32_t(tableSize - 1)
+	ps.capacity = C.uint32_t(cfg.Capacity)
+	ps.sampleI.sampleMask = C.uint32_t(cfg.SampleInterval - 1)
+	ps.sampleD.sampleMask = C.uint32_t(cfg.SampleInterval - 1)
+	C.PrefixStat_Clear(ps)
+	ps.depth = C.uint16_t(cfg.Depth)
+}
+
+func (fwd *Fwd) closePrefixStat() {
+	ps := &fwd.c.prefixStat
+	ps.depth = 0
+	if ps.entries != nil {
+		eal.Free(ps.entries)
+		ps.entries = nil
+	}
+	if ps.heap != nil {
+		eal.Free(ps.heap)
+		ps.heap = nil
+	}
+	if ps.table != nil {
+		eal.Free(ps.table)
+		ps.table = nil
+	}
+}
+
+// PrefixStats reads per-prefix traffic counters of this forwarding thread.
+// Entries whose key is being changed by the forwarding thread are skipped.
+func (fwd *Fwd) PrefixStats() (list []PrefixStatRecord) {
+	ps := &fwd.c.prefixStat
+	if ps.depth == 0 {
+		return nil
+	}
+	scaleI, scaleD := uint64(ps.sampleI.sampleMask)+1, uint64(ps.sampleD.sampleMask)+1
+	entries := unsafe.Slice(ps.entries, ps.capacity)
+	for i := range entries {
+		entry := &entries[i]
+		versionPtr := (*uint32)(unsafe.Pointer(&entry.version))
+		v0 := atomic.LoadUint32(versionPtr)
+		if v0%2 != 0 || entry.nameL == C.UINT16_MAX {
+			continue
+		}
+
+		var rec PrefixStatRecord
+		rec.Name.UnmarshalBinary(cptr.AsByteSlice(entry.nameV[:entry.nameL]))
+		rec.NInterests = uint64(entry.nInterests) * scaleI
+		rec.NData = uint64(entry.nData) * scaleD
+		rec.DataBytes = uint64(entry.dataBytes) * scaleD
+		rec.Error = uint64(entry.error) * max(scaleI, scaleD)
+
+		if atomic.LoadUint32(versionPtr) != v0 {
+			continue
+		}
+		list = append(list, rec)
+	}
+	return list
+}
+
+// TopPrefixes returns top n name prefixes aggregated across forwarding threads.
+// Prefixes with fewer than minInterests Interests are excluded.
+func (dp *DataPlane) TopPrefixes(n int, sortBy PrefixStatSortBy, minInterests uint64) (list []PrefixStatRecord) {
+	m := map[string]*PrefixStatRecord{}
+	for _, fwd := range dp.fwds {
+		for _, rec := range fwd.PrefixStats() {
+			key := rec.Name.String()
+			if agg := m[key]; agg == nil {
+				m[key] = &rec
+			} else {
+				agg.NInterests += rec.NInterests
+				agg.NData += rec.NData
+				agg.DataBytes += rec.DataBytes
+				agg.Error += rec.Error
+			}
+		}
+	}
+
+	list = []PrefixStatRecord{}
+	for _, rec := range m {
+		if rec.NInterests >= minInterests {
+			list = append(list, *rec)
+		}
+	}
+	slices.SortFunc(list, func(a, b PrefixStatRecord) int {
+		return cmp.Or(sortBy.compare(a, b), a.Name.Compare(b.Name))
+	})
+	if n > 0 && len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
@@ -0,0 +1,260 @@
+package fwdp
+
+/*
+#include "../../csrc/fwdp/fwd.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/usnistgov/ndn-dpdk/container/cs"
+	"github.com/usnistgov/ndn-dpdk/container/pit"
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+	"github.com/usnistgov/ndn-dpdk/iface"
+	"github.com/usnistgov/ndn-dpdk/ndni"
+	"go.uber.org/zap"
+)
+
+// ReconfigTimeout is the maximum duration to wait for a forwarding thread to apply runtime reconfiguration.
+var ReconfigTimeout = 5 * time.Second
+
+// QueueRuntimeConfig contains forwarding thread input queue parameters that can be changed at runtime.
+// Zero fields retain their current values.
+type QueueRuntimeConfig struct {
+	DequeueBurstSize int                    `json:"dequeueBurstSize,omitempty" gqldesc:"Dequeue burst size limit."`
+	Delay            nnduration.Nanoseconds `json:"delay,omitempty" gqldesc:"Minimum delay; applicable to queue in delay mode only."`
+	Target           nnduration.Nanoseconds `json:"target,omitempty" gqldesc:"CoDel TARGET parameter; applicable to queue in CoDel mode only."`
+	Interval         nnduration.Nanoseconds `json:"interval,omitempty" gqldesc:"CoDel INTERVAL parameter; applicable to queue in CoDel mode only."`
+}
+
+func (qcfg QueueRuntimeConfig) apply(qc *C.PktQueue, qp *C.FwFwdQueueParams) error {
+	qp.target, qp.interval, qp.dequeueBurstSize = qc.target, qc.interval, qc.dequeueBurstSize
+
+	if qcfg.DequeueBurstSize != 0 {
+		if qcfg.DequeueBurstSize < 1 || qcfg.DequeueBurstSize > iface.MaxBurstSize {
+			return fmt.Errorf("dequeueBurstSize must be between 1 and %d", iface.MaxBurstSize)
+		}
+		qp.dequeueBurstSize = C.uint32_t(qcfg.DequeueBurstSize)
+	}
+
+	switch qc.pop {
+	case C.PktQueuePopActDelay:
+		if qcfg.Target != 0 || qcfg.Interval != 0 {
+			return errors.New("target and interval are not applicable to queue in delay mode")
+		}
+		if qcfg.Delay != 0 {
+			qp.target = C.TscDuration(eal.ToTscDuration(qcfg.Delay.Duration()))
+		}
+	case C.PktQueuePopActCoDel:
+		if qcfg.Delay != 0 {
+			return errors.New("delay is not applicable to queue in CoDel mode")
+		}
+		if qcfg.Target != 0 {
+			qp.target = C.TscDuration(eal.ToTscDuration(qcfg.Target.Duration()))
+		}
+		if qcfg.Interval != 0 {
+			qp.interval = C.TscDuration(eal.ToTscDuration(qcfg.Interval.Duration()))
+		}
+	default:
+		if qcfg.Delay != 0 || qcfg.Target != 0 || qcfg.Interval != 0 {
+			return errors.New("delay, target, and interval are not applicable to queue in plain mode")
+		}
+	}
+	return nil
+}
+
+func readQueueRuntimeConfig(qc *C.PktQueue) (qcfg QueueRuntimeConfig) {
+	qcfg.DequeueBurstSize = int(qc.dequeueBurstSize)
+	target := nnduration.Nanoseconds(eal.FromTscDuration(int64(qc.target)))
+	switch qc.pop {
+	case C.PktQueuePopActDelay:
+		qcfg.Delay = target
+	case C.PktQueuePopActCoDel:
+		qcfg.Target = target
+		qcfg.Interval = nnduration.Nanoseconds(eal.FromTscDuration(int64(qc.interval)))
+	}
+	return
+}
+
+// RuntimeConfig contains forwarder data plane parameters that can be changed at runtime.
+// Zero or nil fields retain their current values, including zero fields within Suppress.
+//
+// Each CS capacity cannot exceed the greater of its initial value and PcctCapacity/4.
+type RuntimeConfig struct {
+	CsMemoryCapacity   int                 `json:"csMemoryCapacity,omitempty" gqldesc:"CS in-memory direct entries capacity, per forwarding thread."`
+	CsIndirectCapacity int                 `json:"csIndirectCapacity,omitempty" gqldesc:"CS indirect entries capacity, per forwarding thread."`
+	Suppress           *pit.SuppressConfig `json:"suppress,omitempty" gqldesc:"PIT suppression configuration."`
+	FwdInterestQueue   *QueueRuntimeConfig `json:"fwdInterestQueue,omitempty" gqldesc:"Interest queue parameters."`
+	FwdDataQueue       *QueueRuntimeConfig `json:"fwdDataQueue,omitempty" gqldesc:"Data queue parameters."`
+	FwdNackQueue       *QueueRuntimeConfig `json:"fwdNackQueue,omitempty" gqldesc:"Nack queue parameters."`
+}
+
+func (cfg RuntimeConfig) validate() error {
+	if cfg.CsMemoryCapacity != 0 && cfg.CsMemoryCapacity < cs.EvictBulk {
+		return fmt.Errorf("csMemoryCapacity must be at least %d", cs.EvictBulk)
+	}
+	if cfg.CsIndirectCapacity != 0 && cfg.CsIndirectCapacity < cs.EvictBulk {
+		return fmt.Errorf("csIndirectCapacity must be at least %d", cs.EvictBulk)
+	}
+	return nil
+}
+
+func (cfg RuntimeConfig) queueOf(t ndni.PktType) *QueueRuntimeConfig {
+	switch t {
+	case ndni.PktInterest:
+		return cfg.FwdInterestQueue
+	case ndni.PktData:
+		return cfg.FwdDataQueue
+	case ndni.PktNack:
+		return cfg.FwdNackQueue
+	}
+	return nil
+}
+
+// FwdRuntimeConfig contains effective runtime parameters of a forwarding thread.
+type FwdRuntimeConfig struct {
+	Fwd                int                `json:"fwd" gqldesc:"Forwarding thread index."`
+	CsMemoryCapacity   int                `json:"csMemoryCapacity" gqldesc:"CS in-memory direct entries capacity."`
+	CsIndirectCapacity int                `json:"csIndirectCapacity" gqldesc:"CS indirect entries capacity."`
+	Suppress           pit.SuppressConfig `json:"suppress" gqldesc:"PIT suppression configuration."`
+	FwdInterestQueue   QueueRuntimeConfig `json:"fwdInterestQueue" gqldesc:"Interest queue parameters."`
+	FwdDataQueue       QueueRuntimeConfig `json:"fwdDataQueue" gqldesc:"Data queue parameters."`
+	FwdNackQueue       QueueRuntimeConfig `json:"fwdNackQueue" gqldesc:"Nack queue parameters."`
+}
+
+// RuntimeConfig reads effective runtime parameters of this forwarding thread.
+func (fwd *Fwd) RuntimeConfig() (rc FwdRuntimeConfig) {
+	rc.Fwd = fwd.id
+	rc.CsMemoryCapacity = fwd.Cs().Capacity(cs.ListDirect)
+	rc.CsIndirectCapacity = fwd.Cs().Capacity(cs.ListIndirect)
+	rc.Suppress = pit.SuppressConfigFromC(unsafe.Pointer(&fwd.c.suppressCfg))
+	rc.FwdInterestQueue = readQueueRuntimeConfig(&fwd.c.queueI)
+	rc.FwdDataQueue = readQueueRuntimeConfig(&fwd.c.queueD)
+	rc.FwdNackQueue = readQueueRuntimeConfig(&fwd.c.queueN)
+	return
+}
+
+// prepareReconfig fills the reconfiguration request from current values and cfg.
+func (fwd *Fwd) prepareReconfig(cfg RuntimeConfig) error {
+	if bool(C.FwFwd_IsReconfigPending(fwd.c)) {
+		return errors.New("previous reconfiguration is still pending")
+	}
+
+	if cfg.CsMemoryCapacity > fwd.csCapMemoryLimit {
+		return fmt.Errorf("csMemoryCapacity must not exceed %d", fwd.csCapMemoryLimit)
+	}
+	if cfg.CsIndirectCapacity > fwd.csCapIndirectLimit {
+		return fmt.Errorf("csIndirectCapacity must not exceed %d", fwd.csCapIndirectLimit)
+	}
+
+	rc := &fwd.c.reconfig
+	rc.suppressCfg = fwd.c.suppressCfg
+	if cfg.Suppress != nil {
+		sc := pit.SuppressConfigFromC(unsafe.Pointer(&fwd.c.suppressCfg))
+		if cfg.Suppress.Min != 0 {
+			sc.Min = cfg.Suppress.Min
+		}
+		if cfg.Suppress.Max != 0 {
+			sc.Max = cfg.Suppress.Max
+		}
+		if cfg.Suppress.Multiplier != 0 {
+			sc.Multiplier = cfg.Suppress.Multiplier
+		}
+		sc.CopyToC(unsafe.Pointer(&rc.suppressCfg))
+	}
+
+	for t, qc := range map[ndni.PktType]*C.PktQueue{
+		ndni.PktInterest: &fwd.c.queueI,
+		ndni.PktData:     &fwd.c.queueD,
+		ndni.PktNack:     &fwd.c.queueN,
+	} {
+		var qcfg QueueRuntimeConfig
+		if q := cfg.queueOf(t); q != nil {
+			qcfg = *q
+		}
+		if e := qcfg.apply(qc, &rc.queue[t]); e != nil {
+			return fmt.Errorf("queue%s: %w", t, e)
+		}
+	}
+
+	rc.csCapMemory = C.uint32_t(fwd.Cs().Capacity(cs.ListDirect))
+	if cfg.CsMemoryCapacity != 0 {
+		rc.csCapMemory = C.uint32_t(cfg.CsMemoryCapacity)
+	}
+	rc.csCapIndirect = C.uint32_t(fwd.Cs().Capacity(cs.ListIndirect))
+	if cfg.CsIndirectCapacity != 0 {
+		rc.csCapIndirect = C.uint32_t(cfg.CsIndirectCapacity)
+	}
+	return nil
+}
+
+// reconfig applies the prepared reconfiguration request.
+// If the forwarding thread is running, the request is applied by the forwarding thread at its next
+// quiescent point; otherwise, it is applied in the calling thread.
+func (fwd *Fwd) reconfig() error {
+	if !fwd.IsRunning() {
+		C.FwFwd_Reconfig(fwd.c)
+		return nil
+	}
+
+	C.FwFwd_RequestReconfig(fwd.c)
+	deadline := time.Now().Add(ReconfigTimeout)
+	for bool(C.FwFwd_IsReconfigPending(fwd.c)) {
+		if !fwd.IsRunning() {
+			C.FwFwd_Reconfig(fwd.c)
+			break
+		}
+		if time.Now().After(deadline) {
+			return errors.New("timeout waiting for forwarding thread to apply reconfiguration")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return nil
+}
+
+var reconfigMutex sync.Mutex
+
+// Reconfigure changes runtime parameters on every forwarding thread.
+// It returns effective parameters of each forwarding thread.
+//
+// Each forwarding thread applies the new parameters at a quiescent point between bursts.
+// Parameters are validated on every forwarding thread before any change is made.
+// If a forwarding thread fails to apply the change, it is reported as error, and other forwarding
+// threads may have applied the change.
+func (dp *DataPlane) Reconfigure(cfg RuntimeConfig) (list []FwdRuntimeConfig, e error) {
+	if e := cfg.validate(); e != nil {
+		return nil, e
+	}
+
+	reconfigMutex.Lock()
+	defer reconfigMutex.Unlock()
+
+	for _, fwd := range dp.fwds {
+		if e := fwd.prepareReconfig(cfg); e != nil {
+			return nil, fmt.Errorf("%s: %w", fwd, e)
+		}
+	}
+
+	var errs []error
+	for _, fwd := range dp.fwds {
+		if e := fwd.reconfig(); e != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", fwd, e))
+		}
+	}
+
+	for _, fwd := range dp.fwds {
+		rc := fwd.RuntimeConfig()
+		logger.Info("reconfigured",
+			zap.Stringer("fwd", fwd),
+			zap.Int("cs-cap-memory", rc.CsMemoryCapacity),
+			zap.Int("cs-cap-indirect", rc.CsIndirectCapacity),
+		)
+		list = append(list, rc)
+	}
+	return list, errors.Join(errs...)
+}
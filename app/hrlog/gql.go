@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/graphql-go/graphql"
+	"github.com/usnistgov/ndn-dpdk/app/hrlog/hrlogreader"
 	"github.com/usnistgov/ndn-dpdk/core/gqlserver"
 	"github.com/usnistgov/ndn-dpdk/core/gqlserver/gqlsingleton"
 	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
@@ -18,10 +19,21 @@ var (
 
 // GraphQL types.
 var (
+	GqlActionEnum *graphql.Enum
 	GqlWriterType *gqlserver.NodeType[*Writer]
 )
 
 func init() {
+	actionValues := graphql.EnumValueConfigMap{}
+	for _, act := range hrlogreader.Actions {
+		actionValues[act.String()] = &graphql.EnumValueConfig{Value: act}
+	}
+	GqlActionEnum = graphql.NewEnum(graphql.EnumConfig{
+		Name:        "HrlogAction",
+		Description: "High resolution log trace point.",
+		Values:      actionValues,
+	})
+
 	GqlWriterType = gqlserver.NewNodeType(graphql.ObjectConfig{
 		Name:        "HrlogWriter",
 		Description: "High resolution log writer.",
@@ -34,6 +46,14 @@ func init() {
 					return w.filename, nil
 				},
 			},
+			"actions": &graphql.Field{
+				Description: "Enabled trace points.",
+				Type:        gqlserver.NewListNonNullBoth(GqlActionEnum),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					w := p.Source.(*Writer)
+					return w.Actions(), nil
+				},
+			},
 			"worker": ealthread.GqlWithWorker(nil),
 		},
 	}, gqlWriter.NodeConfig())
@@ -50,6 +70,10 @@ func init() {
 				Description: "Maximum number of entries. Storage will be pre-allocated.",
 				Type:        graphql.Int,
 			},
+			"actions": &graphql.ArgumentConfig{
+				Description: "Trace points to be collected. Default is OI, OD, OC.",
+				Type:        graphql.NewList(graphql.NewNonNull(GqlActionEnum)),
+			},
 		},
 		Type: graphql.NewNonNull(GqlWriterType.Object),
 		Resolve: gqlWriter.CreateWith(func(p graphql.ResolveParams) (w *Writer, e error) {
@@ -63,6 +87,11 @@ func init() {
 			if count, ok := p.Args["count"]; ok {
 				cfg.Count = count.(int)
 			}
+			if actions, ok := p.Args["actions"].([]any); ok {
+				for _, act := range actions {
+					cfg.Actions = append(cfg.Actions, act.(hrlogreader.Action))
+				}
+			}
 			w, e = NewWriter(cfg)
 			if e != nil {
 				return nil, e
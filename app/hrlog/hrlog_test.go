@@ -3,6 +3,7 @@ package hrlog_test
 import (
 	"math/rand/v2"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
@@ -21,6 +22,7 @@ func TestWriter(t *testing.T) {
 		Filename:     filename,
 		Count:        1000,
 		RingCapacity: 256,
+		Actions:      hrlogreader.Actions,
 	})
 	require.NoError(e)
 	require.NoError(ealthread.AllocLaunch(w))
@@ -44,9 +46,14 @@ func TestWriter(t *testing.T) {
 	count := 0
 	r, e := hrlogreader.Open(filename)
 	require.NoError(e)
+	nMarkers := 0
 	for entry := range r.Read() {
 		count++
-		assert.Contains(entries, entry)
+		if !slices.Contains(entries, entry) {
+			assert.Equal(hrlogreader.ActionTS, hrlogreader.ParseEntry(entry).Action)
+			nMarkers++
+		}
 	}
 	assert.Equal(1000, count)
+	assert.Greater(nMarkers, 0)
 }
@@ -0,0 +1,101 @@
+package hrlogreader
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Action identifies the trace point of a log entry.
+// Values must match HrlogAction in csrc/hrlog/entry.h.
+type Action uint8
+
+// Action values.
+// Except ActionTS, each action is a distinct bit.
+const (
+	ActionTS Action = 0   // time marker, value is TSC time
+	ActionOI Action = 1   // Interest TX since RX
+	ActionOD Action = 2   // retrieved Data TX since RX
+	ActionOC Action = 4   // cached Data TX since Interest RX
+	ActionFQ Action = 8   // forwarding thread dequeue since RX
+	ActionSG Action = 16  // strategy invocation duration
+	ActionCR Action = 32  // Data returned from crypto helper since enqueue
+	ActionDK Action = 64  // Interest returned from disk helper since request
+	ActionTQ Action = 128 // face output queue sojourn
+)
+
+// Actions lists actions that can be enabled, excluding ActionTS.
+var Actions = []Action{ActionOI, ActionOD, ActionOC, ActionFQ, ActionSG, ActionCR, ActionDK, ActionTQ}
+
+// DefaultActions lists actions enabled by default.
+var DefaultActions = []Action{ActionOI, ActionOD, ActionOC}
+
+var actionNames = map[Action]string{
+	ActionTS: "TS",
+	ActionOI: "OI",
+	ActionOD: "OD",
+	ActionOC: "OC",
+	ActionFQ: "FQ",
+	ActionSG: "SG",
+	ActionCR: "CR",
+	ActionDK: "DK",
+	ActionTQ: "TQ",
+}
+
+func (act Action) String() string {
+	if s, ok := actionNames[act]; ok {
+		return s
+	}
+	return strconv.Itoa(int(act))
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (act Action) MarshalText() (text []byte, e error) {
+	return []byte(act.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface.
+func (act *Action) UnmarshalText(text []byte) error {
+	for a, s := range actionNames {
+		if s == string(text) {
+			*act = a
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown hrlog action %s", text)
+}
+
+// ActionMask computes bitmask of a list of actions.
+func ActionMask(actions []Action) (mask uint8) {
+	for _, act := range actions {
+		mask |= uint8(act)
+	}
+	return
+}
+
+// ActionsFromMask lists actions in a bitmask.
+func ActionsFromMask(mask uint8) (actions []Action) {
+	for _, act := range Actions {
+		if mask&uint8(act) != 0 {
+			actions = append(actions, act)
+		}
+	}
+	return
+}
+
+// Entry is a parsed log entry.
+type Entry struct {
+	Action Action
+	LCore  uint8
+
+	// Value is a duration in TSC unit, or TSC time if Action is ActionTS.
+	Value uint64
+}
+
+// ParseEntry parses a log entry.
+func ParseEntry(entry uint64) Entry {
+	return Entry{
+		Action: Action(entry),
+		LCore:  uint8(entry >> 8),
+		Value:  entry >> 16,
+	}
+}
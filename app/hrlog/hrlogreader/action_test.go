@@ -0,0 +1,58 @@
+package hrlogreader_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/usnistgov/ndn-dpdk/app/hrlog/hrlogreader"
+	"github.com/usnistgov/ndn-dpdk/core/testenv"
+)
+
+var makeAR = testenv.MakeAR
+
+func TestActionMask(t *testing.T) {
+	tests := []struct {
+		actions []hrlogreader.Action
+		mask    uint8
+	}{
+		{nil, 0x00},
+		{[]hrlogreader.Action{hrlogreader.ActionOI}, 0x01},
+		{hrlogreader.DefaultActions, 0x07},
+		{[]hrlogreader.Action{hrlogreader.ActionFQ, hrlogreader.ActionTQ}, 0x88},
+		{hrlogreader.Actions, 0xFF},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%02X", tt.mask), func(t *testing.T) {
+			assert, _ := makeAR(t)
+			assert.Equal(tt.mask, hrlogreader.ActionMask(tt.actions))
+			assert.Equal(tt.actions, hrlogreader.ActionsFromMask(tt.mask))
+		})
+	}
+
+	assert, _ := makeAR(t)
+	// ActionTS and duplicates do not affect the mask
+	assert.Equal(uint8(0x11), hrlogreader.ActionMask([]hrlogreader.Action{
+		hrlogreader.ActionTS, hrlogreader.ActionOI, hrlogreader.ActionSG, hrlogreader.ActionOI,
+	}))
+
+	// every mask survives a round trip
+	for mask := range 256 {
+		assert.Equal(uint8(mask), hrlogreader.ActionMask(hrlogreader.ActionsFromMask(uint8(mask))))
+	}
+}
+
+func TestActionText(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	for _, act := range append([]hrlogreader.Action{hrlogreader.ActionTS}, hrlogreader.Actions...) {
+		text, e := act.MarshalText()
+		assert.NoError(e)
+		var decoded hrlogreader.Action
+		assert.NoError(decoded.UnmarshalText(text))
+		assert.Equal(act, decoded)
+	}
+
+	var decoded hrlogreader.Action
+	assert.Error(decoded.UnmarshalText([]byte("XX")))
+	assert.Equal("3", hrlogreader.Action(3).String())
+}
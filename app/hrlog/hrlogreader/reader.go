@@ -12,7 +12,7 @@ import (
 // Header constants.
 const (
 	Magic   = 0x35F0498A
-	Version = 2
+	Version = 3
 )
 
 // Reader represents a reader for high resolution logs.
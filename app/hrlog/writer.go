@@ -6,9 +6,13 @@ package hrlog
 import "C"
 import (
 	"errors"
+	"fmt"
 	"path/filepath"
+	"slices"
+	"time"
 	"unsafe"
 
+	"github.com/usnistgov/ndn-dpdk/app/hrlog/hrlogreader"
 	"github.com/usnistgov/ndn-dpdk/core/cptr"
 	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
 	"github.com/usnistgov/ndn-dpdk/dpdk/ealthread"
@@ -35,6 +39,14 @@ type WriterConfig struct {
 	Count        int
 	RingCapacity int
 	Socket       eal.NumaSocket
+
+	// Actions selects trace points to be collected.
+	// Default is hrlogreader.DefaultActions.
+	Actions []hrlogreader.Action
+
+	// MarkInterval is the interval between time markers.
+	// Default is 1ms.
+	MarkInterval time.Duration
 }
 
 func (cfg *WriterConfig) applyDefaults() {
@@ -46,11 +58,22 @@ func (cfg *WriterConfig) applyDefaults() {
 	if cfg.Socket.IsAny() {
 		cfg.Socket = eal.RandomSocket()
 	}
+	if len(cfg.Actions) == 0 {
+		cfg.Actions = hrlogreader.DefaultActions
+	}
+	if cfg.MarkInterval <= 0 {
+		cfg.MarkInterval = time.Millisecond
+	}
 }
 func (cfg WriterConfig) validate() error {
 	if cfg.Filename == "" {
 		return errors.New("filename is missing")
 	}
+	for _, act := range cfg.Actions {
+		if !slices.Contains(hrlogreader.Actions, act) {
+			return fmt.Errorf("action %s cannot be selected", act)
+		}
+	}
 	return nil
 }
 
@@ -72,6 +95,11 @@ func (Writer) ThreadRole() string {
 	return Role
 }
 
+// Actions returns enabled trace points.
+func (w *Writer) Actions() []hrlogreader.Action {
+	return hrlogreader.ActionsFromMask(uint8(w.c.actions))
+}
+
 // Close releases resources.
 func (w *Writer) Close() error {
 	e := w.Stop()
@@ -107,6 +135,8 @@ func NewWriter(cfg WriterConfig) (w *Writer, e error) {
 	}
 	w.c.filename = C.CString(cfg.Filename)
 	w.c.count = C.int64_t(cfg.Count)
+	w.c.markInterval = C.TscDuration(eal.ToTscDuration(cfg.MarkInterval))
+	w.c.actions = C.uint8_t(hrlogreader.ActionMask(cfg.Actions))
 
 	w.ThreadWithCtrl = ealthread.NewThreadWithCtrl(
 		cptr.Func0.C(C.HrlogWriter_Run, w.c),
@@ -126,6 +156,7 @@ func NewWriter(cfg WriterConfig) (w *Writer, e error) {
 
 	logger.Info("Writer open",
 		zap.String("filename", cfg.Filename),
+		zap.Stringers("actions", cfg.Actions),
 		zap.Uintptr("queue", uintptr(unsafe.Pointer(w.c.queue))),
 	)
 	return w, nil
@@ -24,6 +24,9 @@ const (
 	// MbufTypeSLL indicates mbuf should be written with SLL header.
 	MbufTypeSLL = 0xF0020000
 
+	// StreamChunkSize is the size of each chunk passed from writer thread to a streaming sink.
+	StreamChunkSize = 1 << 16
+
 	_ = "enumgen::Pdump"
 )
 
@@ -31,4 +34,7 @@ const (
 const (
 	MinFileSize     = 1 << 16
 	DefaultFileSize = 1 << 24
+
+	MinStreamBuffer     = 4 * StreamChunkSize
+	DefaultStreamBuffer = 64 * StreamChunkSize
 )
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"time"
 
 	"github.com/graphql-go/graphql"
 	"github.com/usnistgov/ndn-dpdk/core/gqlserver"
@@ -24,10 +25,40 @@ var (
 	gqlWriter gqlsingleton.Singleton[*Writer]
 )
 
+// GqlCreateWriter creates and launches the writer accessible via GraphQL.
+func GqlCreateWriter(cfg WriterConfig) (*Writer, error) {
+	return gqlWriter.Create(func() (w *Writer, e error) {
+		if !GqlLCore.Valid() || GqlLCore.IsBusy() {
+			return nil, fmt.Errorf("no LCore for %s role; check activation parameters and ensure there's no other writer running", Role)
+		}
+
+		if w, e = NewWriter(cfg); e != nil {
+			return nil, e
+		}
+		w.SetLCore(GqlLCore)
+		ealthread.Launch(w)
+		return w, nil
+	})
+}
+
+// GqlGetWriter returns the writer accessible via GraphQL, or nil if it does not exist.
+func GqlGetWriter() *Writer {
+	return gqlWriter.Get()
+}
+
+// GqlCloseWriter closes the writer accessible via GraphQL.
+// Sources attached to the writer must be closed first.
+func GqlCloseWriter(w *Writer) error {
+	return gqlWriter.Delete(w)
+}
+
 // GraphQL types.
 var (
 	GqlDirectionEnum        *graphql.Enum
 	GqlEthGrabEnum          *graphql.Enum
+	GqlSinkEnum             *graphql.Enum
+	GqlWriterCountersType   *graphql.Object
+	GqlPacketRecordType     *graphql.Object
 	GqlNameFilterEntryInput *graphql.InputObject
 	GqlNameFilterEntryType  *graphql.Object
 	GqlWriterType           *gqlserver.NodeType[*Writer]
@@ -39,6 +70,22 @@ var (
 func init() {
 	GqlDirectionEnum = gqlserver.NewStringEnum("PdumpDirection", "Packet dump traffic direction.", DirIncoming, DirOutgoing)
 	GqlEthGrabEnum = gqlserver.NewStringEnum("PdumpEthGrab", "Packet dump Ethernet port grab position.", EthGrabRxUnmatched)
+	GqlSinkEnum = gqlserver.NewStringEnum("PdumpSink", "Packet dump writer output destination.",
+		SinkFile, SinkPipe, SinkTCP, SinkHTTP, SinkGraphQL)
+	GqlWriterCountersType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "PdumpWriterCounters",
+		Description: "Packet dump writer counters.",
+		Fields:      gqlserver.BindFields[WriterCounters](nil),
+	})
+	GqlPacketRecordType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "PdumpPacketRecord",
+		Description: "Decoded fields of a captured packet.",
+		Fields: gqlserver.BindFields[PacketRecord](gqlserver.FieldTypes{
+			reflect.TypeFor[time.Time](): graphql.DateTime,
+			reflect.TypeFor[Direction](): GqlDirectionEnum,
+			reflect.TypeFor[ndn.Name]():  ndni.GqlNameType,
+		}),
+	})
 	GqlNameFilterEntryInput = graphql.NewInputObject(graphql.InputObjectConfig{
 		Name:        "PdumpNameFilterEntryInput",
 		Description: "Packet dump name filter entry.",
@@ -58,14 +105,38 @@ func init() {
 		Name:        "PdumpWriter",
 		Description: "Packet dump writer.",
 		Fields: graphql.Fields{
+			"sink": &graphql.Field{
+				Description: "Output destination kind.",
+				Type:        graphql.NewNonNull(GqlSinkEnum),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					w := p.Source.(*Writer)
+					return w.Sink(), nil
+				},
+			},
 			"filename": &graphql.Field{
-				Description: "Destination filename.",
+				Description: "Destination filename, or named pipe or Unix socket; empty for other sinks.",
 				Type:        gqlserver.NonNullString,
 				Resolve: func(p graphql.ResolveParams) (any, error) {
 					w := p.Source.(*Writer)
 					return w.filename, nil
 				},
 			},
+			"listen": &graphql.Field{
+				Description: "TCP listen address of TCP or HTTP sink.",
+				Type:        graphql.String,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					w := p.Source.(*Writer)
+					return gqlserver.Optional(w.Listen()), nil
+				},
+			},
+			"counters": &graphql.Field{
+				Description: "Writer counters.",
+				Type:        graphql.NewNonNull(GqlWriterCountersType),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					w := p.Source.(*Writer)
+					return w.Counters(), nil
+				},
+			},
 			"worker": ealthread.GqlWithWorker(nil),
 		},
 	}, gqlWriter.NodeConfig())
@@ -74,35 +145,71 @@ func init() {
 		Name:        "createPdumpWriter",
 		Description: "Start packet dump writer.",
 		Args: graphql.FieldConfigArgument{
+			"sink": &graphql.ArgumentConfig{
+				Description:  "Output destination kind.",
+				Type:         GqlSinkEnum,
+				DefaultValue: SinkFile,
+			},
 			"filename": &graphql.ArgumentConfig{
-				Description: "Output file name.",
-				Type:        gqlserver.NonNullString,
+				Description: "Output file name for FILE sink, or named pipe or Unix socket for PIPE sink.",
+				Type:        graphql.String,
+			},
+			"listen": &graphql.ArgumentConfig{
+				Description: "TCP listen address for TCP and HTTP sinks.",
+				Type:        graphql.String,
 			},
 			"maxSize": &graphql.ArgumentConfig{
-				Description: "Maximum output file size in bytes. Storage will be pre-allocated.",
+				Description: "Maximum output file size in bytes for FILE sink, storage will be pre-allocated; stream buffer size for other sinks.",
 				Type:        graphql.Int,
 			},
 		},
 		Type: graphql.NewNonNull(GqlWriterType.Object),
-		Resolve: gqlWriter.CreateWith(func(p graphql.ResolveParams) (w *Writer, e error) {
-			if !GqlLCore.Valid() || GqlLCore.IsBusy() {
-				return nil, fmt.Errorf("no LCore for %s role; check activation parameters and ensure there's no other writer running", Role)
-			}
-
+		Resolve: func(p graphql.ResolveParams) (any, error) {
 			cfg := WriterConfig{
-				Filename: p.Args["filename"].(string),
+				Sink: p.Args["sink"].(SinkKind),
+			}
+			if filename, ok := p.Args["filename"].(string); ok {
+				cfg.Filename = filename
+			}
+			if listen, ok := p.Args["listen"].(string); ok {
+				cfg.Listen = listen
 			}
 			if maxSize, ok := p.Args["maxSize"]; ok {
 				cfg.MaxSize = maxSize.(int)
 			}
-			w, e = NewWriter(cfg)
+			return GqlCreateWriter(cfg)
+		},
+	})
+
+	gqlserver.AddSubscription(&graphql.Field{
+		Name:        "pdumpPackets",
+		Description: "Decoded packets captured by a packet dump writer with GRAPHQL sink.",
+		Args: graphql.FieldConfigArgument{
+			"writer": &graphql.ArgumentConfig{
+				Description: "Packet dump writer.",
+				Type:        gqlserver.NonNullID,
+			},
+		},
+		Type: graphql.NewNonNull(GqlPacketRecordType),
+		Subscribe: func(p graphql.ResolveParams) (any, error) {
+			w := GqlWriterType.Retrieve(p.Args["writer"].(string))
+			if w == nil {
+				return nil, nil
+			}
+			records, e := w.SubscribePackets(p.Context)
 			if e != nil {
 				return nil, e
 			}
-			w.SetLCore(GqlLCore)
-			ealthread.Launch(w)
-			return w, nil
-		}),
+			return gqlserver.PublishChan(func(updates chan<- any) {
+				for rec := range records {
+					select {
+					case updates <- rec:
+					case <-p.Context.Done():
+						return
+					}
+				}
+			})
+		},
 	})
 
 	gqlserver.AddQuery(&graphql.Field{
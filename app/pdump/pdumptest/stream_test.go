@@ -0,0 +1,124 @@
+package pdumptest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gopacket/gopacket/layers"
+	"github.com/gopacket/gopacket/pcapgo"
+	"github.com/usnistgov/ndn-dpdk/app/pdump"
+	"github.com/usnistgov/ndn-dpdk/dpdk/ealthread"
+	"github.com/usnistgov/ndn-dpdk/iface"
+	"github.com/usnistgov/ndn-dpdk/iface/intface"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndni"
+)
+
+func sendInterests(face *intface.IntFace, n int) {
+	for i := range n {
+		iface.TxBurst(face.ID, []*ndni.Packet{makeInterest(fmt.Sprintf("/S/%d", i))})
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestStreamTCP(t *testing.T) {
+	assert, require := makeAR(t)
+
+	w, e := pdump.NewWriter(pdump.WriterConfig{
+		Sink:   pdump.SinkTCP,
+		Listen: "127.0.0.1:0",
+	})
+	require.NoError(e)
+	require.NoError(ealthread.AllocLaunch(w))
+	defer ealthread.AllocFree(w.LCore())
+
+	conn, e := net.Dial("tcp", w.Listen())
+	require.NoError(e)
+	defer conn.Close()
+
+	face := intface.MustNew()
+	defer face.D.Close()
+	go func() {
+		for range face.Rx {
+		}
+	}()
+
+	dumpTx, e := pdump.NewFaceSource(pdump.FaceConfig{
+		Writer: w,
+		Face:   face.D,
+		Dir:    pdump.DirOutgoing,
+		Names:  []pdump.NameFilterEntry{{Name: ndn.ParseName("/"), SampleProbability: 1.0}},
+	})
+	require.NoError(e)
+	go sendInterests(face, 100)
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	r, e := pcapgo.NewNgReader(conn, pcapgo.DefaultNgReaderOptions)
+	require.NoError(e)
+	for range 100 {
+		pkt, ci, e := r.ReadPacketData()
+		require.NoError(e)
+		intf, e := r.Interface(ci.InterfaceIndex)
+		require.NoError(e)
+		assert.Equal(layers.LinkTypeLinuxSLL, intf.LinkType)
+		assert.Greater(len(pkt), 16)
+	}
+
+	assert.NoError(dumpTx.Close())
+	time.Sleep(100 * time.Millisecond)
+	cnt := w.Counters()
+	assert.Equal(1, cnt.NClients)
+	assert.Zero(cnt.NDropped)
+	assert.NoError(w.Close())
+}
+
+func TestStreamGraphQL(t *testing.T) {
+	assert, require := makeAR(t)
+
+	w, e := pdump.NewWriter(pdump.WriterConfig{
+		Sink: pdump.SinkGraphQL,
+	})
+	require.NoError(e)
+	require.NoError(ealthread.AllocLaunch(w))
+	defer ealthread.AllocFree(w.LCore())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	records, e := w.SubscribePackets(ctx)
+	require.NoError(e)
+
+	face := intface.MustNew()
+	defer face.D.Close()
+	go func() {
+		for range face.Rx {
+		}
+	}()
+
+	dumpTx, e := pdump.NewFaceSource(pdump.FaceConfig{
+		Writer: w,
+		Face:   face.D,
+		Dir:    pdump.DirOutgoing,
+		Names:  []pdump.NameFilterEntry{{Name: ndn.ParseName("/"), SampleProbability: 1.0}},
+	})
+	require.NoError(e)
+	go sendInterests(face, 100)
+
+	prefix := ndn.ParseName("/S")
+	for range 100 {
+		rec, ok := <-records
+		require.True(ok)
+		assert.Equal(face.ID, rec.Face)
+		assert.Equal(pdump.DirOutgoing, rec.Dir)
+		assert.Equal("Interest", rec.Type)
+		assert.True(prefix.IsPrefixOf(rec.Name))
+	}
+
+	assert.NoError(dumpTx.Close())
+	time.Sleep(100 * time.Millisecond)
+	assert.NoError(w.Close())
+	_, ok := <-records
+	assert.False(ok)
+}
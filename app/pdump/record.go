@@ -0,0 +1,114 @@
+package pdump
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
+	"github.com/usnistgov/ndn-dpdk/iface"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// PacketRecord contains decoded fields of a captured packet.
+type PacketRecord struct {
+	Time     time.Time `json:"time" gqldesc:"Capture timestamp."`
+	Intf     string    `json:"intf" gqldesc:"Capture interface name, such as face1 or port0."`
+	Face     iface.ID  `json:"face,omitempty" gqldesc:"Face ID, if captured on a face."`
+	Dir      Direction `json:"dir,omitempty" gqldesc:"Traffic direction, if captured on a face."`
+	Length   int       `json:"length" gqldesc:"Captured length in octets."`
+	Type     string    `json:"type,omitempty" gqldesc:"NDN packet type: Interest, Data, Nack, or Fragment; omitted if not decodable."`
+	Name     ndn.Name  `json:"name,omitempty" gqldesc:"Interest or Data name."`
+	PitToken string    `json:"pitToken,omitempty" gqldesc:"PIT token in hexadecimal."`
+}
+
+// decodeChunk decodes enhanced packet blocks in a chunk.
+// intfs is indexed by pcapng interface ID.
+func decodeChunk(chunk []byte, intfs []streamIntf) (records []PacketRecord) {
+	for len(chunk) >= 28 {
+		blockType, totalLength := binary.LittleEndian.Uint32(chunk[0:]), binary.LittleEndian.Uint32(chunk[4:])
+		if totalLength < 28 || int(totalLength) > len(chunk) {
+			break
+		}
+		block := chunk[:totalLength]
+		chunk = chunk[totalLength:]
+
+		intfID, capLen := binary.LittleEndian.Uint32(block[8:]), binary.LittleEndian.Uint32(block[20:])
+		if blockType != NgTypeEPB || int(intfID) >= len(intfs) || 28+int(capLen) > len(block) {
+			continue
+		}
+		intf := intfs[intfID]
+		timeHi, timeLo := binary.LittleEndian.Uint32(block[12:]), binary.LittleEndian.Uint32(block[16:])
+		rec := PacketRecord{
+			Time:   time.Unix(0, int64(uint64(timeHi)<<32|uint64(timeLo))),
+			Intf:   intf.intf.Name,
+			Length: int(capLen),
+		}
+		rec.decode(intf, block[28:28+capLen])
+		records = append(records, rec)
+	}
+	return records
+}
+
+func (rec *PacketRecord) decode(intf streamIntf, wire []byte) {
+	var payload []byte
+	switch intf.intf.LinkType {
+	case layers.LinkTypeLinuxSLL:
+		var sll layers.LinuxSLL
+		if sll.DecodeFromBytes(wire, gopacket.NilDecodeFeedback) != nil {
+			return
+		}
+		rec.Face = iface.ID(intf.id)
+		switch sll.PacketType {
+		case layers.LinuxSLLPacketTypeHost:
+			rec.Dir = DirIncoming
+		case layers.LinuxSLLPacketTypeOutgoing:
+			rec.Dir = DirOutgoing
+		}
+		payload = sll.Payload
+	case layers.LinkTypeEthernet:
+		var eth layers.Ethernet
+		if eth.DecodeFromBytes(wire, gopacket.NilDecodeFeedback) != nil || eth.EthernetType != an.EtherTypeNDN {
+			return
+		}
+		payload = eth.Payload
+	}
+
+	var pkt ndn.Packet
+	if tlv.Decode(payload, &pkt) != nil {
+		return
+	}
+	rec.PitToken = hex.EncodeToString(pkt.Lp.PitToken)
+	switch {
+	case pkt.Fragment != nil:
+		rec.Type = "Fragment"
+	case pkt.Interest != nil:
+		rec.Type, rec.Name = "Interest", pkt.Interest.Name
+	case pkt.Data != nil:
+		rec.Type, rec.Name = "Data", pkt.Data.Name
+	case pkt.Nack != nil:
+		rec.Type, rec.Name = "Nack", pkt.Nack.Interest.Name
+	}
+}
+
+// SubscribePackets receives decoded packet records until ctx is canceled or the writer is closed.
+// This is only available on SinkGraphQL.
+// If the subscriber is too slow, packet records are dropped.
+func (w *Writer) SubscribePackets(ctx context.Context) (<-chan PacketRecord, error) {
+	if w.sink != SinkGraphQL || w.stream == nil {
+		return nil, errors.New("writer is not a GraphQL sink")
+	}
+
+	s := w.stream
+	c := s.subscribeRecords()
+	go func() {
+		<-ctx.Done()
+		s.unsubscribe(c)
+	}()
+	return c.records, nil
+}
@@ -22,3 +22,23 @@ func setSourceRef(ref *C.PdumpSourceRef, expected, newPtr *C.PdumpSource) {
 		)
 	}
 }
+
+// ListFaceSources returns a list of active face sources.
+func ListFaceSources() (list []*FaceSource) {
+	sourcesMutex.Lock()
+	defer sourcesMutex.Unlock()
+	for _, s := range faceSources {
+		list = append(list, s)
+	}
+	return list
+}
+
+// ListEthPortSources returns a list of active Ethernet port sources.
+func ListEthPortSources() (list []*EthPortSource) {
+	sourcesMutex.Lock()
+	defer sourcesMutex.Unlock()
+	for _, s := range ethPortSources {
+		list = append(list, s)
+	}
+	return list
+}
@@ -0,0 +1,379 @@
+package pdump
+
+/*
+#include "../../csrc/pdump/writer.h"
+*/
+import "C"
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/gopacket/gopacket/pcapgo"
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+	"github.com/usnistgov/ndn-dpdk/dpdk/ringbuffer"
+	"go.uber.org/zap"
+)
+
+const (
+	// streamPollInterval is the interval of polling filled chunks when the writer thread is idle.
+	streamPollInterval = time.Millisecond
+
+	// streamReopenInterval is the interval of reopening a named pipe or Unix socket.
+	streamReopenInterval = time.Second
+
+	// streamRecordQueue is the capacity of decoded packet record queue per GraphQL subscriber.
+	streamRecordQueue = 1024
+)
+
+// checkPipe ensures filename refers to a named pipe or a Unix socket.
+func checkPipe(filename string) error {
+	if filename == "" {
+		return errors.New("filename is missing")
+	}
+	st, e := os.Stat(filename)
+	if e != nil {
+		return e
+	}
+	if st.Mode()&(fs.ModeNamedPipe|fs.ModeSocket) == 0 {
+		return fmt.Errorf("%s is neither a named pipe nor a Unix socket", filename)
+	}
+	return nil
+}
+
+// openPipe opens a named pipe for writing, or connects to a Unix socket.
+// Opening a named pipe fails if there is no reader.
+func openPipe(filename string) (io.WriteCloser, error) {
+	st, e := os.Stat(filename)
+	if e != nil {
+		return nil, e
+	}
+	if st.Mode()&fs.ModeSocket != 0 {
+		return net.Dial("unix", filename)
+	}
+	return os.OpenFile(filename, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+}
+
+type streamIntf struct {
+	id   int
+	intf pcapgo.NgInterface
+}
+
+// streamClient is a streaming sink client.
+// Either blocks or records is non-nil.
+type streamClient struct {
+	blocks  chan []byte
+	records chan PacketRecord
+	closer  io.Closer
+}
+
+// streamer passes pcapng blocks from writer thread to streaming sink clients.
+//
+// The writer thread fills chunks with enhanced packet blocks, and the streamer polls filled chunks
+// and sends them to every client through a bounded queue.
+// When a client is too slow, chunks or packet records are dropped instead of blocking the writer.
+// Section header block and interface description blocks are kept in Go and sent to each client as
+// it connects, so that a client can start decoding from any point.
+type streamer struct {
+	chunks     []*C.PdumpChunk
+	chunksMem  *C.PdumpChunk
+	freeChunks *ringbuffer.Ring
+	fullChunks *ringbuffer.Ring
+	listener   net.Listener
+	httpServer *http.Server
+	stop       chan struct{}
+	wg         sync.WaitGroup
+
+	mutex        sync.Mutex
+	headers      [][]byte
+	intfs        []streamIntf
+	clients      map[*streamClient]struct{}
+	nClientDrops uint64
+}
+
+// addHeader records the section header block, and sends it to connected clients.
+func (s *streamer) addHeader(shb []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.sendHeaderLocked(shb)
+}
+
+// addIntf records an interface description block, and sends it to connected clients.
+// Interfaces are numbered in the same order as the writer thread processes IDBs.
+func (s *streamer) addIntf(id int, intf pcapgo.NgInterface, idb []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.intfs = append(s.intfs, streamIntf{id: id, intf: intf})
+	s.sendHeaderLocked(idb)
+}
+
+func (s *streamer) sendHeaderLocked(block []byte) {
+	s.headers = append(s.headers, block)
+	for c := range s.clients {
+		if c.blocks == nil {
+			continue
+		}
+		select {
+		case c.blocks <- block:
+		default: // a client missing a header block cannot decode subsequent packets
+			s.removeLocked(c)
+		}
+	}
+}
+
+func (s *streamer) subscribe(c *streamClient) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if c.blocks != nil && len(s.headers) > 0 {
+		c.blocks <- bytes.Join(s.headers, nil)
+	}
+	s.clients[c] = struct{}{}
+}
+
+func (s *streamer) unsubscribe(c *streamClient) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.removeLocked(c)
+}
+
+func (s *streamer) removeLocked(c *streamClient) {
+	if _, ok := s.clients[c]; !ok {
+		return
+	}
+	delete(s.clients, c)
+	if c.blocks != nil {
+		close(c.blocks)
+	}
+	if c.records != nil {
+		close(c.records)
+	}
+	if c.closer != nil {
+		c.closer.Close()
+	}
+}
+
+// broadcast sends a chunk to connected clients.
+func (s *streamer) broadcast(chunk []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var records []PacketRecord
+	decoded := false
+	for c := range s.clients {
+		if c.blocks != nil {
+			select {
+			case c.blocks <- chunk:
+			default:
+				s.nClientDrops++
+			}
+			continue
+		}
+
+		if !decoded {
+			records, decoded = decodeChunk(chunk, s.intfs), true
+		}
+		for _, rec := range records {
+			select {
+			case c.records <- rec:
+			default:
+				s.nClientDrops++
+			}
+		}
+	}
+}
+
+// drain polls filled chunks from the writer thread.
+func (s *streamer) drain() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(streamPollInterval)
+	defer ticker.Stop()
+
+	chunks := make([]*C.PdumpChunk, 64)
+	for {
+		n := ringbuffer.Dequeue(s.fullChunks, chunks)
+		for _, chunk := range chunks[:n] {
+			s.broadcast(C.GoBytes(unsafe.Pointer(&chunk.data[0]), C.int(chunk.len)))
+			chunk.len = 0
+		}
+		ringbuffer.Enqueue(s.freeChunks, chunks[:n])
+		if n > 0 {
+			continue
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// serve streams pcapng to a client until the client is removed, done is closed, or write fails.
+func (s *streamer) serve(done <-chan struct{}, wr io.Writer, closer io.Closer, flush func()) {
+	c := &streamClient{
+		blocks: make(chan []byte, len(s.chunks)+1),
+		closer: closer,
+	}
+	s.subscribe(c)
+	defer s.unsubscribe(c)
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-done:
+			return
+		case block, ok := <-c.blocks:
+			if !ok {
+				return
+			}
+			if _, e := wr.Write(block); e != nil {
+				return
+			}
+			if flush != nil {
+				flush()
+			}
+		}
+	}
+}
+
+func (s *streamer) servePipe(filename string) {
+	defer s.wg.Done()
+	for {
+		if f, e := openPipe(filename); e == nil {
+			logger.Info("streaming to pipe", zap.String("filename", filename))
+			s.serve(nil, f, f, nil)
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(streamReopenInterval):
+		}
+	}
+}
+
+func (s *streamer) serveTCP() {
+	defer s.wg.Done()
+	for {
+		conn, e := s.listener.Accept()
+		if e != nil {
+			return
+		}
+		logger.Info("streaming to TCP client", zap.Stringer("client", conn.RemoteAddr()))
+		go s.serve(nil, conn, conn, nil)
+	}
+}
+
+func (s *streamer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/x-pcapng")
+	w.WriteHeader(http.StatusOK)
+	flush := func() {}
+	if flusher, ok := w.(http.Flusher); ok {
+		flush = flusher.Flush
+	}
+	logger.Info("streaming to HTTP client", zap.String("client", r.RemoteAddr))
+	s.serve(r.Context().Done(), w, nil, flush)
+}
+
+// subscribeRecords creates a client that receives decoded packet records.
+func (s *streamer) subscribeRecords() *streamClient {
+	c := &streamClient{
+		records: make(chan PacketRecord, streamRecordQueue),
+	}
+	s.subscribe(c)
+	return c
+}
+
+func (s *streamer) counters() (cnt WriterCounters) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	cnt.NClientDrops = s.nClientDrops
+	cnt.NClients = len(s.clients)
+	return
+}
+
+func (s *streamer) close() {
+	close(s.stop)
+	if s.httpServer != nil {
+		s.httpServer.Close()
+	} else if s.listener != nil {
+		s.listener.Close()
+	}
+
+	s.mutex.Lock()
+	for c := range s.clients {
+		s.removeLocked(c)
+	}
+	s.mutex.Unlock()
+	s.wg.Wait()
+
+	if s.freeChunks != nil {
+		s.freeChunks.Close()
+	}
+	if s.fullChunks != nil {
+		s.fullChunks.Close()
+	}
+	if s.chunksMem != nil {
+		eal.Free(s.chunksMem)
+	}
+}
+
+func newStreamer(w *Writer, cfg WriterConfig) (s *streamer, e error) {
+	s = &streamer{
+		stop:    make(chan struct{}),
+		clients: map[*streamClient]struct{}{},
+	}
+	defer func() {
+		if e != nil {
+			s.close()
+		}
+	}()
+
+	nChunks := cfg.MaxSize / StreamChunkSize
+	if s.freeChunks, e = ringbuffer.New(nChunks+1, cfg.Socket, ringbuffer.ProducerSingle, ringbuffer.ConsumerSingle); e != nil {
+		return nil, e
+	}
+	if s.fullChunks, e = ringbuffer.New(nChunks+1, cfg.Socket, ringbuffer.ProducerSingle, ringbuffer.ConsumerSingle); e != nil {
+		return nil, e
+	}
+	s.chunksMem = eal.Zmalloc[C.PdumpChunk]("PdumpChunk", C.sizeof_PdumpChunk*nChunks, cfg.Socket)
+	for i := range nChunks {
+		s.chunks = append(s.chunks, (*C.PdumpChunk)(unsafe.Add(unsafe.Pointer(s.chunksMem), i*C.sizeof_PdumpChunk)))
+	}
+	ringbuffer.Enqueue(s.freeChunks, s.chunks)
+
+	switch cfg.Sink {
+	case SinkPipe:
+		s.wg.Add(1)
+		go s.servePipe(cfg.Filename)
+	case SinkTCP:
+		if s.listener, e = net.Listen("tcp", cfg.Listen); e != nil {
+			return nil, e
+		}
+		s.wg.Add(1)
+		go s.serveTCP()
+	case SinkHTTP:
+		if s.listener, e = net.Listen("tcp", cfg.Listen); e != nil {
+			return nil, e
+		}
+		s.httpServer = &http.Server{Handler: http.HandlerFunc(s.serveHTTP)}
+		go s.httpServer.Serve(s.listener)
+	}
+
+	w.c.stream = true
+	w.c.freeChunks = (*C.struct_rte_ring)(s.freeChunks.Ptr())
+	w.c.fullChunks = (*C.struct_rte_ring)(s.fullChunks.Ptr())
+	s.wg.Add(1)
+	go s.drain()
+	return s, nil
+}
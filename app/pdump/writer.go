@@ -31,18 +31,58 @@ const Role = "PDUMP"
 
 var logger = logging.New("pdump")
 
+// SinkKind indicates writer output destination.
+type SinkKind string
+
+// SinkKind values.
+const (
+	// SinkFile writes a pcapng file on local disk.
+	SinkFile SinkKind = "FILE"
+
+	// SinkPipe streams pcapng to a named pipe, or connects to a Unix socket and streams pcapng.
+	SinkPipe SinkKind = "PIPE"
+
+	// SinkTCP listens on a TCP port and streams pcapng to each connected client.
+	SinkTCP SinkKind = "TCP"
+
+	// SinkHTTP listens on a TCP port and streams pcapng in response to each HTTP request.
+	SinkHTTP SinkKind = "HTTP"
+
+	// SinkGraphQL emits decoded packets via GraphQL subscription.
+	SinkGraphQL SinkKind = "GRAPHQL"
+)
+
 // WriterConfig contains writer configuration.
 type WriterConfig struct {
-	Filename     string
-	MaxSize      int
+	// Sink is the output destination kind.
+	// Default is SinkFile.
+	Sink SinkKind
+
+	// Filename is the output file for SinkFile, or the named pipe or Unix socket for SinkPipe.
+	Filename string
+
+	// Listen is the TCP listen address for SinkTCP and SinkHTTP.
+	Listen string
+
+	// MaxSize is the maximum file size for SinkFile, or the stream buffer size for other sinks.
+	MaxSize int
+
 	RingCapacity int
 	Socket       eal.NumaSocket
 }
 
 func (cfg *WriterConfig) applyDefaults() {
-	cfg.Filename = filepath.Clean(cfg.Filename)
+	if cfg.Sink == "" {
+		cfg.Sink = SinkFile
+	}
+	if cfg.Filename != "" {
+		cfg.Filename = filepath.Clean(cfg.Filename)
+	}
 	if cfg.MaxSize == 0 {
 		cfg.MaxSize = DefaultFileSize
+		if cfg.Sink != SinkFile {
+			cfg.MaxSize = DefaultStreamBuffer
+		}
 	}
 	cfg.RingCapacity = ringbuffer.AlignCapacity(cfg.RingCapacity, 64, 65536)
 	if cfg.Socket.IsAny() {
@@ -52,11 +92,28 @@ func (cfg *WriterConfig) applyDefaults() {
 
 func (cfg WriterConfig) validate() error {
 	errs := []error{}
-	if cfg.Filename == "" {
-		errs = append(errs, errors.New("filename is missing"))
+	switch cfg.Sink {
+	case SinkFile:
+		if cfg.Filename == "" {
+			errs = append(errs, errors.New("filename is missing"))
+		}
+		if cfg.MaxSize < MinFileSize {
+			errs = append(errs, fmt.Errorf("file size is less than %d", MinFileSize))
+		}
+	case SinkPipe:
+		if e := checkPipe(cfg.Filename); e != nil {
+			errs = append(errs, e)
+		}
+	case SinkTCP, SinkHTTP:
+		if cfg.Listen == "" {
+			errs = append(errs, errors.New("listen address is missing"))
+		}
+	case SinkGraphQL:
+	default:
+		errs = append(errs, fmt.Errorf("unknown sink %s", cfg.Sink))
 	}
-	if cfg.MaxSize < MinFileSize {
-		errs = append(errs, fmt.Errorf("file size is less than %d", MinFileSize))
+	if cfg.Sink != SinkFile && cfg.MaxSize < MinStreamBuffer {
+		errs = append(errs, fmt.Errorf("stream buffer size is less than %d", MinStreamBuffer))
 	}
 	return errors.Join(errs...)
 }
@@ -64,10 +121,13 @@ func (cfg WriterConfig) validate() error {
 // Writer is a packet dump writer thread.
 type Writer struct {
 	ealthread.ThreadWithCtrl
+	cfg      WriterConfig
+	sink     SinkKind
 	filename string
 	c        *C.PdumpWriter
 	queue    *ringbuffer.Ring
 	mp       *pktmbuf.Pool
+	stream   *streamer
 
 	nSources atomic.Int32
 	intfs    map[int]pcapgo.NgInterface
@@ -106,9 +166,15 @@ func (w *Writer) defineIntf(id int, intf pcapgo.NgInterface) {
 
 	shb, idb := ngMakeHeader(intf)
 	if !w.hasSHB {
+		if w.stream != nil {
+			w.stream.addHeader(shb)
+		}
 		w.putBlock(shb, NgTypeSHB, math.MaxUint16)
 		w.hasSHB = true
 	}
+	if w.stream != nil {
+		w.stream.addIntf(id, intf, idb)
+	}
 	w.putBlock(idb, NgTypeIDB, uint16(id))
 }
 
@@ -138,6 +204,41 @@ func (*Writer) ThreadRole() string {
 	return Role
 }
 
+// Config returns the configuration used to create this writer, with defaults applied.
+func (w *Writer) Config() WriterConfig {
+	return w.cfg
+}
+
+// Sink returns the output destination kind.
+func (w *Writer) Sink() SinkKind {
+	return w.sink
+}
+
+// Listen returns the TCP listen address of SinkTCP or SinkHTTP, or empty string for other sinks.
+func (w *Writer) Listen() string {
+	if w.stream == nil || w.stream.listener == nil {
+		return ""
+	}
+	return w.stream.listener.Addr().String()
+}
+
+// Counters returns writer counters.
+func (w *Writer) Counters() (cnt WriterCounters) {
+	if w.stream == nil {
+		return
+	}
+	cnt = w.stream.counters()
+	cnt.NDropped = uint64(w.c.nDropped)
+	return
+}
+
+// WriterCounters contains writer counters.
+type WriterCounters struct {
+	NDropped     uint64 `json:"nDropped" gqldesc:"Packets dropped by writer thread because stream buffer is full."`
+	NClientDrops uint64 `json:"nClientDrops" gqldesc:"Chunks or packet records dropped because a sink client is too slow."`
+	NClients     int    `json:"nClients" gqldesc:"Connected sink clients."`
+}
+
 // Close releases resources.
 func (w *Writer) Close() error {
 	if !w.nSources.CompareAndSwap(0, -65536) {
@@ -150,6 +251,11 @@ func (w *Writer) Close() error {
 		zap.Error(e),
 	)
 
+	if w.stream != nil {
+		w.stream.close()
+		w.stream = nil
+	}
+
 	if w.c != nil {
 		C.free(unsafe.Pointer(w.c.filename))
 		eal.Free(w.c)
@@ -180,12 +286,16 @@ func NewWriter(cfg WriterConfig) (w *Writer, e error) {
 	}
 
 	w = &Writer{
+		cfg:      cfg,
+		sink:     cfg.Sink,
 		filename: cfg.Filename,
 		c:        eal.Zmalloc[C.PdumpWriter]("PdumpWriter", C.sizeof_PdumpWriter, cfg.Socket),
 		mp:       pktmbuf.Direct.Get(cfg.Socket),
 		intfs:    map[int]pcapgo.NgInterface{},
 	}
-	w.c.filename = C.CString(cfg.Filename)
+	if cfg.Sink == SinkFile {
+		w.c.filename = C.CString(cfg.Filename)
+	}
 	w.c.maxSize = C.size_t(cfg.MaxSize)
 	for i := range w.c.intf {
 		w.c.intf[i] = math.MaxUint32
@@ -207,8 +317,16 @@ func NewWriter(cfg WriterConfig) (w *Writer, e error) {
 	}
 	w.c.queue = (*C.struct_rte_ring)(w.queue.Ptr())
 
+	if cfg.Sink != SinkFile {
+		if w.stream, e = newStreamer(w, cfg); e != nil {
+			return nil, e
+		}
+	}
+
 	logger.Info("Writer open",
+		zap.String("sink", string(cfg.Sink)),
 		zap.String("filename", cfg.Filename),
+		zap.String("listen", w.Listen()),
 		zap.Uintptr("queue", uintptr(unsafe.Pointer(w.c.queue))),
 	)
 	return w, nil
@@ -0,0 +1,648 @@
+package svcstate
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/suzuki-shunsuke/go-dataeq/dataeq"
+	"github.com/usnistgov/ndn-dpdk/app/pdump"
+	"github.com/usnistgov/ndn-dpdk/app/tg"
+	"github.com/usnistgov/ndn-dpdk/container/fib"
+	"github.com/usnistgov/ndn-dpdk/container/fib/fibdef"
+	"github.com/usnistgov/ndn-dpdk/container/strategycode"
+	"github.com/usnistgov/ndn-dpdk/core/jsonhelper"
+	"github.com/usnistgov/ndn-dpdk/dpdk/ethdev"
+	"github.com/usnistgov/ndn-dpdk/iface"
+	"github.com/usnistgov/ndn-dpdk/iface/ethport"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"go.uber.org/zap"
+)
+
+// Action is the action of an Op.
+type Action string
+
+// Action values.
+const (
+	ActionActivate Action = "activate"
+	ActionCreate   Action = "create"
+	ActionUpdate   Action = "update"
+	ActionDelete   Action = "delete"
+)
+
+// Kind values of an Op.
+const (
+	KindActivation         = "activation"
+	KindEthPort            = "ethPort"
+	KindFace               = "face"
+	KindStrategy           = "strategy"
+	KindFibEntry           = "fibEntry"
+	KindPdumpWriter        = "pdumpWriter"
+	KindPdumpFaceSource    = "pdumpFaceSource"
+	KindPdumpEthPortSource = "pdumpEthPortSource"
+)
+
+// Op is an operation needed to reach the desired State.
+type Op struct {
+	Action Action `json:"action" gqldesc:"Operation action."`
+	Kind   string `json:"kind" gqldesc:"Object kind."`
+	Key    string `json:"key" gqldesc:"Object key, such as face locator or FIB entry name."`
+
+	// Error is the error message if this operation has failed.
+	Error string `json:"error,omitempty" gqldesc:"Error message if this operation has failed."`
+
+	exec func() error
+}
+
+// planner computes operations from desired State and live objects.
+type planner struct {
+	desired State
+
+	// activating indicates the service would be activated before executing the plan.
+	// This is only possible in dry run, where objects created during activation are unknown.
+	activating bool
+
+	// faceIDs maps FaceState.ID to live face ID.
+	// Faces that will be created are added during execution.
+	faceIDs map[iface.ID]iface.ID
+
+	pdumpSourceDeletes []Op
+	pdumpWriterDeletes []Op
+	fibDeletes         []Op
+	faceDeletes        []Op
+	portDeletes        []Op
+	strategyCreates    []Op
+	portCreates        []Op
+	faceCreates        []Op
+	fibUpserts         []Op
+	pdumpWriterCreates []Op
+	pdumpSourceCreates []Op
+	strategyDeletes    []Op
+}
+
+// liveFace resolves a face reference in the desired State to a live face ID.
+// It returns false if the face does not exist yet.
+func (pl *planner) liveFace(id iface.ID) (iface.ID, bool) {
+	if pl.desired.Faces == nil {
+		return id, iface.Get(id) != nil
+	}
+	liveID, ok := pl.faceIDs[id]
+	return liveID, ok
+}
+
+func (pl *planner) checkFaceRef(id iface.ID) error {
+	if pl.desired.Faces == nil {
+		if iface.Get(id) == nil {
+			return fmt.Errorf("face %d does not exist", id)
+		}
+		return nil
+	}
+	if !slices.ContainsFunc(pl.desired.Faces, func(f FaceState) bool { return f.ID == id }) {
+		return fmt.Errorf("face %d is not defined in faces", id)
+	}
+	return nil
+}
+
+func (pl *planner) planEthPorts() error {
+	if pl.desired.EthPorts == nil {
+		return nil
+	}
+
+	ports, live := map[string]*ethport.Port{}, map[string]ethport.Config{}
+	for _, port := range ethport.List() {
+		if cfg := port.Config(); !cfg.AutoClose {
+			name := port.EthDev().Name()
+			ports[name], live[name] = port, cfg
+		}
+	}
+
+	for i, ps := range pl.desired.EthPorts {
+		key := cmp.Or(ps.Name, "ethPorts["+strconv.Itoa(i)+"]")
+		name, matched, e := MatchBest(ps.Config, live)
+		if e != nil {
+			return fmt.Errorf("%s: %w", key, e)
+		}
+		if matched {
+			delete(live, name)
+			continue
+		}
+
+		cfg := ps.Config
+		pl.portCreates = append(pl.portCreates, Op{
+			Action: ActionCreate,
+			Kind:   KindEthPort,
+			Key:    key,
+			exec: func() error {
+				_, e := ethport.New(cfg)
+				return e
+			},
+		})
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(live)) {
+		pl.portDeletes = append(pl.portDeletes, Op{
+			Action: ActionDelete,
+			Kind:   KindEthPort,
+			Key:    name,
+			exec:   ports[name].Close,
+		})
+	}
+	return nil
+}
+
+func (pl *planner) planFaces() error {
+	if pl.desired.Faces == nil {
+		return nil
+	}
+
+	live := map[iface.ID]map[string]any{}
+	for _, face := range iface.List() {
+		live[face.ID()] = locatorMap(face)
+	}
+
+	desired := map[iface.ID]bool{}
+	for _, fs := range pl.desired.Faces {
+		if desired[fs.ID] {
+			return fmt.Errorf("duplicate face %d", fs.ID)
+		}
+		desired[fs.ID] = true
+
+		id, matched, e := MatchBest(fs.Locator, live)
+		if e != nil {
+			return fmt.Errorf("face %d: %w", fs.ID, e)
+		}
+		if matched {
+			pl.faceIDs[fs.ID] = id
+			delete(live, id)
+			continue
+		}
+
+		if !iface.GqlCreateFaceAllowed && !pl.activating {
+			return fmt.Errorf("face %d: face creation is not allowed", fs.ID)
+		}
+		var locw iface.LocatorWrapper
+		if e := jsonhelper.Roundtrip(fs.Locator, &locw, jsonhelper.DisallowUnknownFields); e != nil {
+			return fmt.Errorf("face %d: %w", fs.ID, e)
+		}
+		if e := locw.Locator.Validate(); e != nil {
+			return fmt.Errorf("face %d: %w", fs.ID, e)
+		}
+
+		stateID := fs.ID
+		pl.faceCreates = append(pl.faceCreates, Op{
+			Action: ActionCreate,
+			Kind:   KindFace,
+			Key:    strconv.Itoa(int(stateID)),
+			exec: func() error {
+				face, e := locw.Locator.CreateFace()
+				if e != nil {
+					return e
+				}
+				pl.faceIDs[stateID] = face.ID()
+				return nil
+			},
+		})
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(live)) {
+		if tg.Get(id) != nil { // face is owned by a traffic generator and closed along with it
+			continue
+		}
+		face := iface.Get(id)
+		pl.faceDeletes = append(pl.faceDeletes, Op{
+			Action: ActionDelete,
+			Kind:   KindFace,
+			Key:    strconv.Itoa(int(id)),
+			exec:   face.Close,
+		})
+	}
+	return nil
+}
+
+func (pl *planner) planStrategies() error {
+	if pl.desired.Strategies == nil {
+		return nil
+	}
+
+	desired := map[string]bool{}
+	for _, ss := range pl.desired.Strategies {
+		if desired[ss.Name] {
+			return fmt.Errorf("duplicate strategy %s", ss.Name)
+		}
+		desired[ss.Name] = true
+
+		if sc := strategycode.Find(ss.Name); sc != nil {
+			if ss.ELF != nil && !bytes.Equal(ss.ELF, sc.ELF()) {
+				return fmt.Errorf("strategy %s is loaded with a different ELF program; use a different name", ss.Name)
+			}
+			continue
+		}
+
+		name, elf := ss.Name, ss.ELF
+		pl.strategyCreates = append(pl.strategyCreates, Op{
+			Action: ActionCreate,
+			Kind:   KindStrategy,
+			Key:    name,
+			exec: func() (e error) {
+				if elf == nil {
+					_, e = strategycode.LoadFile(name, "")
+				} else {
+					_, e = strategycode.Load(name, elf)
+				}
+				return
+			},
+		})
+	}
+
+	for _, sc := range strategycode.List() {
+		if desired[sc.Name()] || sc == fib.GqlDefaultStrategy {
+			continue
+		}
+		pl.strategyDeletes = append(pl.strategyDeletes, Op{
+			Action: ActionDelete,
+			Kind:   KindStrategy,
+			Key:    sc.Name(),
+			exec: func() error {
+				sc.Unref()
+				return nil
+			},
+		})
+	}
+	return nil
+}
+
+func (pl *planner) planFib() error {
+	if pl.desired.Fib == nil {
+		return nil
+	}
+	if fib.GqlFib == nil && !pl.activating {
+		return errors.New("FIB is unavailable")
+	}
+
+	desired := map[string]bool{}
+	for _, es := range pl.desired.Fib {
+		key := es.Name.String()
+		if desired[key] {
+			return fmt.Errorf("duplicate FIB entry %s", key)
+		}
+		desired[key] = true
+
+		for _, nh := range es.Nexthops {
+			if e := pl.checkFaceRef(nh); e != nil {
+				return fmt.Errorf("FIB entry %s: %w", key, e)
+			}
+		}
+		if es.Strategy != "" && strategycode.Find(es.Strategy) == nil &&
+			!slices.ContainsFunc(pl.strategyCreates, func(op Op) bool { return op.Key == es.Strategy }) {
+			return fmt.Errorf("FIB entry %s: strategy %s is not loaded", key, es.Strategy)
+		}
+
+		action := ActionCreate
+		if live := pl.liveFibEntry(es.Name); live != nil {
+			if pl.fibEntryEquals(es, live.Entry) {
+				continue
+			}
+			action = ActionUpdate
+		}
+
+		pl.fibUpserts = append(pl.fibUpserts, Op{
+			Action: action,
+			Kind:   KindFibEntry,
+			Key:    key,
+			exec: func() error {
+				entry := fibdef.Entry{Name: es.Name}
+				entry.Params = es.Params
+				for _, nh := range es.Nexthops {
+					id, ok := pl.liveFace(nh)
+					if !ok {
+						return fmt.Errorf("face %d does not exist", nh)
+					}
+					entry.Nexthops = append(entry.Nexthops, id)
+				}
+				sc := fib.GqlDefaultStrategy
+				if es.Strategy != "" {
+					sc = strategycode.Find(es.Strategy)
+				}
+				if sc == nil {
+					return errors.New("strategy not found")
+				}
+				entry.Strategy = sc.ID()
+				return fib.GqlFib.Insert(entry)
+			},
+		})
+	}
+
+	if fib.GqlFib == nil { // activating in dry run
+		return nil
+	}
+	for _, entry := range fib.GqlFib.List() {
+		if key := entry.Name.String(); !desired[key] {
+			name := entry.Name
+			pl.fibDeletes = append(pl.fibDeletes, Op{
+				Action: ActionDelete,
+				Kind:   KindFibEntry,
+				Key:    key,
+				exec:   func() error { return fib.GqlFib.Erase(name) },
+			})
+		}
+	}
+	return nil
+}
+
+func (pl *planner) liveFibEntry(name ndn.Name) *fib.Entry {
+	if fib.GqlFib == nil {
+		return nil
+	}
+	return fib.GqlFib.Find(name)
+}
+
+// fibEntryEquals determines whether a live FIB entry matches desired entry.
+// It returns false if a nexthop or the strategy does not exist yet.
+func (pl *planner) fibEntryEquals(es FibEntryState, live fibdef.Entry) bool {
+	if len(es.Nexthops) != len(live.Nexthops) {
+		return false
+	}
+	for i, nh := range es.Nexthops {
+		if id, ok := pl.liveFace(nh); !ok || id != live.Nexthops[i] {
+			return false
+		}
+	}
+
+	sc := fib.GqlDefaultStrategy
+	if es.Strategy != "" {
+		sc = strategycode.Find(es.Strategy)
+	}
+	if sc == nil || sc.ID() != live.Strategy {
+		return false
+	}
+
+	if len(es.Params) == 0 && len(live.Params) == 0 {
+		return true
+	}
+	eq, e := dataeq.JSON.Equal(es.Params, live.Params)
+	return e == nil && eq
+}
+
+func (pl *planner) planPdump() error {
+	ps := pl.desired.Pdump
+	if ps == nil {
+		return nil
+	}
+
+	for _, fss := range ps.FaceSources {
+		if e := pl.checkFaceRef(fss.Face); e != nil {
+			return fmt.Errorf("pdump face source: %w", e)
+		}
+	}
+	if ps.Writer == nil && (len(ps.FaceSources) > 0 || len(ps.EthPortSources) > 0) {
+		return errors.New("pdump sources require a writer")
+	}
+
+	w := pdump.GqlGetWriter()
+	var liveFaceSources []*pdump.FaceSource
+	var liveEthPortSources []*pdump.EthPortSource
+	if w != nil {
+		for _, s := range pdump.ListFaceSources() {
+			if s.Writer == w {
+				liveFaceSources = append(liveFaceSources, s)
+			}
+		}
+		for _, s := range pdump.ListEthPortSources() {
+			if s.Writer == w {
+				liveEthPortSources = append(liveEthPortSources, s)
+			}
+		}
+	}
+
+	if w == nil || ps.Writer == nil || !MatchJSON(ps.Writer, writerState(w)) {
+		// writer must be (re)created: delete all live sources and create all desired sources
+		if w != nil {
+			pl.deletePdumpSources(liveFaceSources, liveEthPortSources)
+			pl.pdumpWriterDeletes = append(pl.pdumpWriterDeletes, Op{
+				Action: ActionDelete,
+				Kind:   KindPdumpWriter,
+				Key:    string(w.Sink()),
+				exec:   func() error { return pdump.GqlCloseWriter(w) },
+			})
+		}
+		if ps.Writer != nil {
+			cfg := ps.Writer.writerConfig()
+			pl.pdumpWriterCreates = append(pl.pdumpWriterCreates, Op{
+				Action: ActionCreate,
+				Kind:   KindPdumpWriter,
+				Key:    string(cfg.Sink),
+				exec: func() error {
+					_, e := pdump.GqlCreateWriter(cfg)
+					return e
+				},
+			})
+			pl.createPdumpSources(ps.FaceSources, ps.EthPortSources)
+		}
+		return nil
+	}
+
+	var createFaceSources []PdumpFaceSourceState
+	for _, fss := range ps.FaceSources {
+		id, ok := pl.liveFace(fss.Face)
+		i := slices.IndexFunc(liveFaceSources, func(s *pdump.FaceSource) bool {
+			if !ok || s.Face.ID() != id || s.Dir != fss.Dir {
+				return false
+			}
+			eq, e := dataeq.JSON.Equal(fss.Names, s.Names)
+			return e == nil && eq
+		})
+		if i < 0 {
+			createFaceSources = append(createFaceSources, fss)
+		} else {
+			liveFaceSources = slices.Delete(liveFaceSources, i, i+1)
+		}
+	}
+
+	var createEthPortSources []PdumpEthPortSourceState
+	for _, pss := range ps.EthPortSources {
+		i := slices.IndexFunc(liveEthPortSources, func(s *pdump.EthPortSource) bool {
+			return s.Port.EthDev().Name() == pss.Port && s.Grab == pss.Grab
+		})
+		if i < 0 {
+			createEthPortSources = append(createEthPortSources, pss)
+		} else {
+			liveEthPortSources = slices.Delete(liveEthPortSources, i, i+1)
+		}
+	}
+
+	pl.deletePdumpSources(liveFaceSources, liveEthPortSources)
+	pl.createPdumpSources(createFaceSources, createEthPortSources)
+	return nil
+}
+
+func (pl *planner) deletePdumpSources(faceSources []*pdump.FaceSource, ethPortSources []*pdump.EthPortSource) {
+	for _, s := range faceSources {
+		pl.pdumpSourceDeletes = append(pl.pdumpSourceDeletes, Op{
+			Action: ActionDelete,
+			Kind:   KindPdumpFaceSource,
+			Key:    fmt.Sprintf("%d-%s", s.Face.ID(), s.Dir),
+			exec:   s.Close,
+		})
+	}
+	for _, s := range ethPortSources {
+		pl.pdumpSourceDeletes = append(pl.pdumpSourceDeletes, Op{
+			Action: ActionDelete,
+			Kind:   KindPdumpEthPortSource,
+			Key:    s.Port.EthDev().Name(),
+			exec:   s.Close,
+		})
+	}
+}
+
+func (pl *planner) createPdumpSources(faceSources []PdumpFaceSourceState, ethPortSources []PdumpEthPortSourceState) {
+	for _, fss := range faceSources {
+		pl.pdumpSourceCreates = append(pl.pdumpSourceCreates, Op{
+			Action: ActionCreate,
+			Kind:   KindPdumpFaceSource,
+			Key:    fmt.Sprintf("%d-%s", fss.Face, fss.Dir),
+			exec: func() error {
+				id, ok := pl.liveFace(fss.Face)
+				if !ok {
+					return fmt.Errorf("face %d does not exist", fss.Face)
+				}
+				_, e := pdump.NewFaceSource(pdump.FaceConfig{
+					Writer: pdump.GqlGetWriter(),
+					Face:   iface.Get(id),
+					Dir:    fss.Dir,
+					Names:  fss.Names,
+				})
+				return e
+			},
+		})
+	}
+	for _, pss := range ethPortSources {
+		pl.pdumpSourceCreates = append(pl.pdumpSourceCreates, Op{
+			Action: ActionCreate,
+			Kind:   KindPdumpEthPortSource,
+			Key:    pss.Port,
+			exec: func() error {
+				port := ethport.Find(ethdev.FromName(pss.Port))
+				if port == nil {
+					return fmt.Errorf("port %s does not exist", pss.Port)
+				}
+				_, e := pdump.NewEthPortSource(pdump.EthPortConfig{
+					Writer: pdump.GqlGetWriter(),
+					Port:   port,
+					Grab:   pss.Grab,
+				})
+				return e
+			},
+		})
+	}
+}
+
+func (pl *planner) plan() (ops []Op, e error) {
+	for _, f := range []func() error{
+		pl.planEthPorts,
+		pl.planFaces,
+		pl.planStrategies,
+		pl.planFib,
+		pl.planPdump,
+	} {
+		if e := f(); e != nil {
+			return nil, e
+		}
+	}
+
+	return slices.Concat(
+		pl.pdumpSourceDeletes,
+		pl.pdumpWriterDeletes,
+		pl.fibDeletes,
+		pl.faceDeletes,
+		pl.portDeletes,
+		pl.strategyCreates,
+		pl.portCreates,
+		pl.faceCreates,
+		pl.fibUpserts,
+		pl.pdumpWriterCreates,
+		pl.pdumpSourceCreates,
+		pl.strategyDeletes,
+	), nil
+}
+
+var applyMutex sync.Mutex
+
+// Apply brings the service to the desired State.
+// It returns a list of operations needed, in the order they are executed.
+//
+// If dryRun is true, operations are computed but not executed.
+// Otherwise, execution stops at the first failed operation, whose Error field is set; operations
+// after the failed operation are not returned.
+//
+// Activation parameters cannot be changed on an activated service.
+// If the service has not been activated, it is activated with the desired activation parameters
+// before other objects are compared.
+func Apply(desired State, dryRun bool) (ops []Op, e error) {
+	applyMutex.Lock()
+	defer applyMutex.Unlock()
+
+	if len(desired.Activate) > 0 {
+		if len(desired.Activate) != 1 {
+			return nil, errors.New("activate must have exactly one role")
+		}
+		current := getActivation()
+		if current == nil {
+			var role string
+			var args any
+			for r, a := range desired.Activate {
+				role, args = r, a
+			}
+			op := Op{Action: ActionActivate, Kind: KindActivation, Key: role}
+			if !dryRun {
+				if Activator == nil {
+					return nil, errors.New("activation is not supported")
+				}
+				if e := Activator(role, args); e != nil {
+					op.Error = e.Error()
+					return []Op{op}, nil
+				}
+			}
+			ops = append(ops, op)
+		} else if eq, e := dataeq.JSON.Equal(desired.Activate, current); e != nil || !eq {
+			return nil, errors.New("activation parameters differ from the running service; restart the service to change them")
+		}
+	}
+
+	pl := &planner{
+		desired:    desired,
+		activating: len(ops) > 0 && dryRun,
+		faceIDs:    map[iface.ID]iface.ID{},
+	}
+	planned, e := pl.plan()
+	if e != nil {
+		return ops, e
+	}
+	if dryRun {
+		return append(ops, planned...), nil
+	}
+
+	for _, op := range planned {
+		if e := op.exec(); e != nil {
+			logger.Warn("apply error",
+				zap.String("action", string(op.Action)),
+				zap.String("kind", op.Kind),
+				zap.String("key", op.Key),
+				zap.Error(e),
+			)
+			op.Error = e.Error()
+			return append(ops, op), nil
+		}
+		logger.Info("applied",
+			zap.String("action", string(op.Action)),
+			zap.String("kind", op.Kind),
+			zap.String("key", op.Key),
+		)
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
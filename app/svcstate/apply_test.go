@@ -0,0 +1,194 @@
+package svcstate_test
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/usnistgov/ndn-dpdk/app/svcstate"
+	"github.com/usnistgov/ndn-dpdk/iface"
+	"github.com/usnistgov/ndn-dpdk/iface/socketface"
+)
+
+type applyOp struct {
+	Action svcstate.Action
+	Kind   string
+	Key    string
+	Error  bool
+}
+
+func gatherApplyOps(ops []svcstate.Op) (list []applyOp) {
+	for _, op := range ops {
+		list = append(list, applyOp{
+			Action: op.Action,
+			Kind:   op.Kind,
+			Key:    op.Key,
+			Error:  op.Error != "",
+		})
+	}
+	return
+}
+
+func udpLocator(local, remote string) map[string]any {
+	return map[string]any{
+		"scheme": "udp",
+		"local":  local,
+		"remote": remote,
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	assert, _ := makeAR(t)
+	iface.GqlCreateFaceAllowed = true
+	defer func() { iface.GqlCreateFaceAllowed = false }()
+
+	// duplicate IDs are rejected even if neither face exists
+	_, e := svcstate.Apply(svcstate.State{
+		Faces: []svcstate.FaceState{
+			{ID: 1, Locator: udpLocator("127.0.0.1:7201", "127.0.0.1:7202")},
+			{ID: 1, Locator: udpLocator("127.0.0.1:7203", "127.0.0.1:7204")},
+		},
+	}, true)
+	assert.ErrorContains(e, "duplicate face 1")
+
+	_, e = svcstate.Apply(svcstate.State{
+		Faces: []svcstate.FaceState{
+			{ID: 1, Locator: map[string]any{"scheme": "udp", "remote": "not-an-address"}},
+		},
+	}, true)
+	assert.Error(e)
+
+	_, e = svcstate.Apply(svcstate.State{
+		Faces: []svcstate.FaceState{
+			{ID: 1, Locator: udpLocator("127.0.0.1:7201", "127.0.0.1:7202")},
+		},
+		Pdump: &svcstate.PdumpState{
+			FaceSources: []svcstate.PdumpFaceSourceState{{Face: 2}},
+		},
+	}, true)
+	assert.ErrorContains(e, "face 2 is not defined in faces")
+
+	_, e = svcstate.Apply(svcstate.State{
+		Activate: map[string]any{"forwarder": map[string]any{}, "trafficgen": map[string]any{}},
+	}, true)
+	assert.Error(e)
+
+	iface.GqlCreateFaceAllowed = false
+	_, e = svcstate.Apply(svcstate.State{
+		Faces: []svcstate.FaceState{
+			{ID: 1, Locator: udpLocator("127.0.0.1:7201", "127.0.0.1:7202")},
+		},
+	}, true)
+	assert.ErrorContains(e, "face creation is not allowed")
+}
+
+func TestApplyFaces(t *testing.T) {
+	assert, require := makeAR(t)
+	iface.GqlCreateFaceAllowed = true
+	defer func() { iface.GqlCreateFaceAllowed = false }()
+
+	faceKept, e := socketface.New(socketface.Locator{Network: "udp", Local: "127.0.0.1:7301", Remote: "127.0.0.1:7302"})
+	require.NoError(e)
+	faceStale, e := socketface.New(socketface.Locator{Network: "udp", Local: "127.0.0.1:7303", Remote: "127.0.0.1:7304"})
+	require.NoError(e)
+	keptID, staleID := faceKept.ID(), faceStale.ID()
+
+	desired := svcstate.State{
+		Faces: []svcstate.FaceState{
+			{ID: 1, Locator: map[string]any{"scheme": "udp", "remote": "127.0.0.1:7302"}},
+			{ID: 2, Locator: udpLocator("127.0.0.1:7305", "127.0.0.1:7306")},
+		},
+	}
+	expectedOps := []applyOp{
+		{Action: svcstate.ActionDelete, Kind: svcstate.KindFace, Key: strconv.Itoa(int(staleID))},
+		{Action: svcstate.ActionCreate, Kind: svcstate.KindFace, Key: "2"},
+	}
+
+	// dry run computes operations without executing them
+	ops, e := svcstate.Apply(desired, true)
+	require.NoError(e)
+	assert.Equal(expectedOps, gatherApplyOps(ops))
+	assert.NotNil(iface.Get(staleID))
+	assert.Len(iface.List(), 2)
+
+	// apply executes operations in order
+	ops, e = svcstate.Apply(desired, false)
+	require.NoError(e)
+	assert.Equal(expectedOps, gatherApplyOps(ops))
+	assert.Nil(iface.Get(staleID))
+	assert.NotNil(iface.Get(keptID))
+	require.Len(iface.List(), 2)
+
+	dumped := svcstate.Dump()
+	require.Len(dumped.Faces, 2)
+	assert.True(slices.ContainsFunc(dumped.Faces, func(fs svcstate.FaceState) bool {
+		return svcstate.MatchJSON(udpLocator("127.0.0.1:7305", "127.0.0.1:7306"), fs.Locator)
+	}))
+
+	// applying the same State again is a no-op
+	ops, e = svcstate.Apply(desired, false)
+	require.NoError(e)
+	assert.Empty(ops)
+
+	// applying a dumped State is a no-op
+	ops, e = svcstate.Apply(svcstate.State{Faces: dumped.Faces}, false)
+	require.NoError(e)
+	assert.Empty(ops)
+
+	// empty section deletes all faces
+	ops, e = svcstate.Apply(svcstate.State{Faces: []svcstate.FaceState{}}, false)
+	require.NoError(e)
+	assert.Len(ops, 2)
+	assert.Len(iface.List(), 0)
+}
+
+func TestApplyFacesAmbiguous(t *testing.T) {
+	assert, require := makeAR(t)
+	iface.GqlCreateFaceAllowed = true
+	defer func() { iface.GqlCreateFaceAllowed = false }()
+
+	faceA, e := socketface.New(socketface.Locator{Network: "udp", Local: "127.0.0.1:7401", Remote: "127.0.0.1:7402"})
+	require.NoError(e)
+	faceB, e := socketface.New(socketface.Locator{Network: "udp", Local: "127.0.0.1:7403", Remote: "127.0.0.1:7404"})
+	require.NoError(e)
+	faceC, e := socketface.New(socketface.Locator{Network: "udp", Local: "127.0.0.1:7405", Remote: "127.0.0.1:7406"})
+	require.NoError(e)
+	defer func() {
+		svcstate.Apply(svcstate.State{Faces: []svcstate.FaceState{}}, false)
+	}()
+
+	// a desired face matching several existing faces is a plan error
+	_, e = svcstate.Apply(svcstate.State{
+		Faces: []svcstate.FaceState{
+			{ID: 1, Locator: map[string]any{"scheme": "udp"}},
+		},
+	}, true)
+	assert.ErrorContains(e, "face 1")
+	assert.ErrorContains(e, "ambiguous")
+
+	// once other desired faces have claimed their matches, the remaining match is unique
+	ops, e := svcstate.Apply(svcstate.State{
+		Faces: []svcstate.FaceState{
+			{ID: 1, Locator: map[string]any{"scheme": "udp", "remote": "127.0.0.1:7402"}},
+			{ID: 2, Locator: map[string]any{"scheme": "udp", "remote": "127.0.0.1:7404"}},
+			{ID: 3, Locator: map[string]any{"scheme": "udp"}},
+		},
+	}, true)
+	require.NoError(e)
+	assert.Empty(ops)
+
+	// deletions are planned in ascending face ID order
+	ops, e = svcstate.Apply(svcstate.State{
+		Faces: []svcstate.FaceState{
+			{ID: 2, Locator: map[string]any{"scheme": "udp", "remote": "127.0.0.1:7404"}},
+		},
+	}, true)
+	require.NoError(e)
+	ids := []int{int(faceA.ID()), int(faceC.ID())}
+	slices.Sort(ids)
+	assert.Equal([]applyOp{
+		{Action: svcstate.ActionDelete, Kind: svcstate.KindFace, Key: strconv.Itoa(ids[0])},
+		{Action: svcstate.ActionDelete, Kind: svcstate.KindFace, Key: strconv.Itoa(ids[1])},
+	}, gatherApplyOps(ops))
+	assert.NotNil(iface.Get(faceB.ID()))
+}
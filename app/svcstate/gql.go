@@ -0,0 +1,55 @@
+package svcstate
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/usnistgov/ndn-dpdk/core/gqlserver"
+	"github.com/usnistgov/ndn-dpdk/core/jsonhelper"
+)
+
+// GraphQL types.
+var (
+	GqlOpType *graphql.Object
+)
+
+func init() {
+	gqlserver.AddQuery(&graphql.Field{
+		Name:        "dumpState",
+		Description: "Activation parameters and runtime-created objects, as a JSON document that can be passed to 'applyState' mutation.",
+		Type:        gqlserver.NonNullJSON,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return Dump(), nil
+		},
+	})
+
+	GqlOpType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "StateOp",
+		Description: "Operation needed to reach the desired state.",
+		Fields:      gqlserver.BindFields[Op](nil),
+	})
+
+	gqlserver.AddMutation(&graphql.Field{
+		Name: "applyState",
+		Description: "Bring the service to the desired state. " +
+			"Only the needed create, update, and delete operations are executed. " +
+			"Execution stops at the first failed operation, which has non-empty 'error' field.",
+		Args: graphql.FieldConfigArgument{
+			"state": &graphql.ArgumentConfig{
+				Description: "Desired state, in the format returned by 'dumpState' query.",
+				Type:        gqlserver.NonNullJSON,
+			},
+			"dryRun": &graphql.ArgumentConfig{
+				Description:  "If true, return the needed operations without executing them.",
+				Type:         graphql.Boolean,
+				DefaultValue: false,
+			},
+		},
+		Type: gqlserver.NewListNonNullBoth(GqlOpType),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			var desired State
+			if e := jsonhelper.Roundtrip(p.Args["state"], &desired, jsonhelper.DisallowUnknownFields); e != nil {
+				return nil, e
+			}
+			return Apply(desired, p.Args["dryRun"].(bool))
+		},
+	})
+}
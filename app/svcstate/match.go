@@ -0,0 +1,80 @@
+package svcstate
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/usnistgov/ndn-dpdk/core/jsonhelper"
+)
+
+// MatchJSON determines whether every field in want exists in have with the same value.
+// Both arguments are converted to their JSON representations.
+// Objects are matched recursively, so that have may contain additional fields, such as those
+// filled with default values; arrays must have the same length and match element-wise.
+func MatchJSON(want, have any) bool {
+	var w, h any
+	if e := jsonhelper.Roundtrip(want, &w); e != nil {
+		return false
+	}
+	if e := jsonhelper.Roundtrip(have, &h); e != nil {
+		return false
+	}
+	return matchValue(w, h)
+}
+
+func matchValue(w, h any) bool {
+	switch w := w.(type) {
+	case map[string]any:
+		h, ok := h.(map[string]any)
+		if !ok {
+			return false
+		}
+		for k, wv := range w {
+			if hv, ok := h[k]; !ok || !matchValue(wv, hv) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		h, ok := h.([]any)
+		if !ok || len(w) != len(h) {
+			return false
+		}
+		for i := range w {
+			if !matchValue(w[i], h[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(w, h)
+}
+
+// MatchBest finds the candidate that satisfies want.
+// Candidates are considered in sorted key order. A candidate whose JSON representation equals want
+// is preferred; otherwise, want must match exactly one candidate via MatchJSON.
+// It returns ok=false if no candidate matches, or an error if several candidates match equally well.
+func MatchBest[K cmp.Ordered, V any](want any, candidates map[K]V) (key K, ok bool, e error) {
+	var partial []K
+	for _, k := range slices.Sorted(maps.Keys(candidates)) {
+		have := candidates[k]
+		if !MatchJSON(want, have) {
+			continue
+		}
+		if MatchJSON(have, want) {
+			return k, true, nil
+		}
+		partial = append(partial, k)
+	}
+
+	switch len(partial) {
+	case 0:
+		return key, false, nil
+	case 1:
+		return partial[0], true, nil
+	}
+	return key, false, fmt.Errorf("ambiguous match among %v", partial)
+}
@@ -0,0 +1,74 @@
+package svcstate_test
+
+import (
+	"testing"
+
+	"github.com/usnistgov/ndn-dpdk/app/svcstate"
+)
+
+func TestMatchJSON(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	have := map[string]any{
+		"scheme": "udp",
+		"local":  "192.0.2.1:6363",
+		"remote": "192.0.2.2:6363",
+		"mtu":    1500,
+		"vlan":   []int{1, 2},
+		"xdp":    map[string]any{"queue": 0, "zc": true},
+	}
+
+	assert.True(svcstate.MatchJSON(map[string]any{}, have))
+	assert.True(svcstate.MatchJSON(map[string]any{"scheme": "udp", "remote": "192.0.2.2:6363"}, have))
+	assert.True(svcstate.MatchJSON(map[string]any{"mtu": 1500.0}, have))
+	assert.True(svcstate.MatchJSON(map[string]any{"xdp": map[string]any{"zc": true}}, have))
+	assert.True(svcstate.MatchJSON(map[string]any{"vlan": []int{1, 2}}, have))
+
+	assert.False(svcstate.MatchJSON(map[string]any{"scheme": "ether"}, have))
+	assert.False(svcstate.MatchJSON(map[string]any{"port": 6363}, have))
+	assert.False(svcstate.MatchJSON(map[string]any{"xdp": map[string]any{"zc": false}}, have))
+	assert.False(svcstate.MatchJSON(map[string]any{"vlan": []int{1}}, have))
+	assert.False(svcstate.MatchJSON(map[string]any{"mtu": "1500"}, have))
+	assert.False(svcstate.MatchJSON(map[string]any{"scheme": "udp"}, []any{have}))
+}
+
+func TestMatchBest(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	candidates := map[int]map[string]any{
+		1: {"scheme": "udp", "remote": "192.0.2.1:6363", "mtu": 1500},
+		2: {"scheme": "udp", "remote": "192.0.2.1:6363"},
+		3: {"scheme": "udp", "remote": "192.0.2.3:6363", "mtu": 1500},
+		4: {"scheme": "ether", "remote": "02:00:00:00:00:04"},
+	}
+
+	tests := []struct {
+		want      map[string]any
+		key       int
+		ok        bool
+		ambiguous bool
+	}{
+		{want: map[string]any{"scheme": "ether"}, key: 4, ok: true},
+		{want: map[string]any{"scheme": "udp", "remote": "192.0.2.3:6363"}, key: 3, ok: true},
+		{want: map[string]any{"scheme": "udp", "remote": "192.0.2.1:6363"}, key: 2, ok: true},
+		{want: map[string]any{"scheme": "udp", "remote": "192.0.2.1:6363", "mtu": 1500}, key: 1, ok: true},
+		{want: map[string]any{"scheme": "udp", "mtu": 1500}, ambiguous: true},
+		{want: map[string]any{"scheme": "udp"}, ambiguous: true},
+		{want: map[string]any{"scheme": "tcp"}},
+	}
+	for _, tt := range tests {
+		key, ok, e := svcstate.MatchBest(tt.want, candidates)
+		if tt.ambiguous {
+			assert.ErrorContains(e, "ambiguous", tt.want)
+			assert.False(ok, tt.want)
+			continue
+		}
+		assert.NoError(e, tt.want)
+		assert.Equal(tt.ok, ok, tt.want)
+		assert.Equal(tt.key, key, tt.want)
+	}
+
+	_, ok, e := svcstate.MatchBest(map[string]any{}, map[int]any{})
+	assert.NoError(e)
+	assert.False(ok)
+}
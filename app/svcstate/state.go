@@ -0,0 +1,254 @@
+// Package svcstate exports and applies declarative runtime state of NDN-DPDK service.
+package svcstate
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/usnistgov/ndn-dpdk/app/pdump"
+	"github.com/usnistgov/ndn-dpdk/container/fib"
+	"github.com/usnistgov/ndn-dpdk/container/strategycode"
+	"github.com/usnistgov/ndn-dpdk/core/jsonhelper"
+	"github.com/usnistgov/ndn-dpdk/core/logging"
+	"github.com/usnistgov/ndn-dpdk/iface"
+	"github.com/usnistgov/ndn-dpdk/iface/ethport"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+)
+
+var logger = logging.New("svcstate")
+
+var (
+	activationMutex sync.Mutex
+	activation      map[string]any
+)
+
+// SetActivation records activation parameters of the running service.
+// role is the activation role, such as "forwarder"; args is the activation parameters object.
+func SetActivation(role string, args any) {
+	activationMutex.Lock()
+	defer activationMutex.Unlock()
+	activation = map[string]any{role: args}
+}
+
+func getActivation() map[string]any {
+	activationMutex.Lock()
+	defer activationMutex.Unlock()
+	return maps.Clone(activation)
+}
+
+// Activator activates the service with a role and activation parameters.
+// It is assigned by the service, and invoked when applying a State that contains activation
+// parameters on a service that has not been activated.
+var Activator func(role string, args any) error
+
+// State describes activation parameters and runtime-created objects of the service.
+//
+// When a State is applied, a nil section means the corresponding objects are left unchanged,
+// while an empty section means the corresponding objects should be deleted.
+type State struct {
+	// Activate contains activation parameters, keyed by activation role.
+	// This matches the arguments of 'activate' mutation.
+	Activate map[string]any `json:"activate,omitempty"`
+
+	// EthPorts contains Ethernet ports created explicitly.
+	// Ports created automatically during face creation are not included.
+	EthPorts []EthPortState `json:"ethPorts"`
+
+	// Faces contains faces.
+	Faces []FaceState `json:"faces"`
+
+	// Strategies contains forwarding strategies.
+	Strategies []StrategyState `json:"strategies"`
+
+	// Fib contains FIB entries.
+	Fib []FibEntryState `json:"fib"`
+
+	// Pdump contains packet dump writer and sources.
+	Pdump *PdumpState `json:"pdump,omitempty"`
+}
+
+// EthPortState describes an Ethernet port.
+type EthPortState struct {
+	// Name is the EthDev name.
+	// It is informational and ignored when applying a State.
+	Name string `json:"name,omitempty"`
+
+	ethport.Config
+}
+
+// FaceState describes a face.
+type FaceState struct {
+	// ID identifies the face within a State document.
+	// In a dumped State, this is the face ID in the running service.
+	// When applying a State, faces are matched by locator; the face ID may differ.
+	ID iface.ID `json:"id"`
+
+	// Locator is a JSON object that satisfies the schema given in 'locator.schema.json'.
+	// When applying a State, an existing face matches if its locator contains every field given here.
+	Locator map[string]any `json:"locator"`
+}
+
+// StrategyState describes a forwarding strategy.
+type StrategyState struct {
+	// Name is the strategy short name.
+	Name string `json:"name"`
+
+	// ELF is the ELF program, if it was uploaded via 'loadStrategy' mutation.
+	// If omitted, the strategy is loaded from default locations by name.
+	ELF []byte `json:"elf,omitempty"`
+}
+
+// FibEntryState describes a FIB entry.
+type FibEntryState struct {
+	Name ndn.Name `json:"name"`
+
+	// Nexthops refers to FaceState.ID.
+	// If State.Faces is nil, this refers to face IDs in the running service.
+	Nexthops []iface.ID `json:"nexthops"`
+
+	// Strategy is the strategy name.
+	// If omitted, the default strategy is used.
+	Strategy string `json:"strategy,omitempty"`
+
+	Params map[string]any `json:"params,omitempty"`
+}
+
+// PdumpState describes the packet dump writer created via GraphQL and its sources.
+type PdumpState struct {
+	// Writer describes the writer.
+	// If nil, there should be no writer and no sources.
+	Writer *PdumpWriterState `json:"writer"`
+
+	FaceSources    []PdumpFaceSourceState    `json:"faceSources"`
+	EthPortSources []PdumpEthPortSourceState `json:"ethPortSources"`
+}
+
+// PdumpWriterState describes a packet dump writer.
+type PdumpWriterState struct {
+	Sink     pdump.SinkKind `json:"sink"`
+	Filename string         `json:"filename,omitempty"`
+	Listen   string         `json:"listen,omitempty"`
+	MaxSize  int            `json:"maxSize,omitempty"`
+}
+
+func (ws PdumpWriterState) writerConfig() pdump.WriterConfig {
+	return pdump.WriterConfig{
+		Sink:     ws.Sink,
+		Filename: ws.Filename,
+		Listen:   ws.Listen,
+		MaxSize:  ws.MaxSize,
+	}
+}
+
+// PdumpFaceSourceState describes a packet dump source attached to a face.
+type PdumpFaceSourceState struct {
+	// Face refers to FaceState.ID.
+	// If State.Faces is nil, this refers to face ID in the running service.
+	Face  iface.ID                `json:"face"`
+	Dir   pdump.Direction         `json:"dir"`
+	Names []pdump.NameFilterEntry `json:"names"`
+}
+
+// PdumpEthPortSourceState describes a packet dump source attached to an Ethernet port.
+type PdumpEthPortSourceState struct {
+	// Port is the EthDev name.
+	Port string        `json:"port"`
+	Grab pdump.EthGrab `json:"grab"`
+}
+
+// Dump retrieves current State of the service.
+func Dump() (st State) {
+	st.Activate = getActivation()
+
+	st.EthPorts = []EthPortState{}
+	for _, port := range ethport.List() {
+		if cfg := port.Config(); !cfg.AutoClose {
+			st.EthPorts = append(st.EthPorts, EthPortState{
+				Name:   port.EthDev().Name(),
+				Config: cfg,
+			})
+		}
+	}
+	slices.SortFunc(st.EthPorts, func(a, b EthPortState) int { return cmp.Compare(a.Name, b.Name) })
+
+	st.Faces = []FaceState{}
+	for _, face := range iface.List() {
+		st.Faces = append(st.Faces, FaceState{
+			ID:      face.ID(),
+			Locator: locatorMap(face),
+		})
+	}
+	slices.SortFunc(st.Faces, func(a, b FaceState) int { return cmp.Compare(a.ID, b.ID) })
+
+	st.Strategies = []StrategyState{}
+	for _, sc := range strategycode.List() {
+		st.Strategies = append(st.Strategies, StrategyState{
+			Name: sc.Name(),
+			ELF:  sc.ELF(),
+		})
+	}
+	slices.SortFunc(st.Strategies, func(a, b StrategyState) int { return cmp.Compare(a.Name, b.Name) })
+
+	if fib.GqlFib != nil {
+		st.Fib = []FibEntryState{}
+		for _, entry := range fib.GqlFib.List() {
+			es := FibEntryState{
+				Name:     entry.Name,
+				Nexthops: entry.Nexthops,
+				Params:   entry.Params,
+			}
+			if sc := strategycode.Get(entry.Strategy); sc != nil {
+				es.Strategy = sc.Name()
+			}
+			st.Fib = append(st.Fib, es)
+		}
+		slices.SortFunc(st.Fib, func(a, b FibEntryState) int { return a.Name.Compare(b.Name) })
+	}
+
+	st.Pdump = &PdumpState{
+		FaceSources:    []PdumpFaceSourceState{},
+		EthPortSources: []PdumpEthPortSourceState{},
+	}
+	if w := pdump.GqlGetWriter(); w != nil {
+		st.Pdump.Writer = writerState(w)
+		for _, s := range pdump.ListFaceSources() {
+			if s.Writer == w {
+				st.Pdump.FaceSources = append(st.Pdump.FaceSources, PdumpFaceSourceState{
+					Face:  s.Face.ID(),
+					Dir:   s.Dir,
+					Names: s.Names,
+				})
+			}
+		}
+		slices.SortFunc(st.Pdump.FaceSources, func(a, b PdumpFaceSourceState) int {
+			return cmp.Or(cmp.Compare(a.Face, b.Face), cmp.Compare(a.Dir, b.Dir))
+		})
+		for _, s := range pdump.ListEthPortSources() {
+			if s.Writer == w {
+				st.Pdump.EthPortSources = append(st.Pdump.EthPortSources, PdumpEthPortSourceState{
+					Port: s.Port.EthDev().Name(),
+					Grab: s.Grab,
+				})
+			}
+		}
+		slices.SortFunc(st.Pdump.EthPortSources, func(a, b PdumpEthPortSourceState) int { return cmp.Compare(a.Port, b.Port) })
+	}
+	return
+}
+
+func locatorMap(face iface.Face) (m map[string]any) {
+	jsonhelper.Roundtrip(iface.LocatorWrapper{Locator: face.Locator()}, &m)
+	return
+}
+
+func writerState(w *pdump.Writer) *PdumpWriterState {
+	cfg := w.Config()
+	return &PdumpWriterState{
+		Sink:     cfg.Sink,
+		Filename: cfg.Filename,
+		Listen:   cfg.Listen,
+		MaxSize:  cfg.MaxSize,
+	}
+}
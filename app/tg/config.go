@@ -19,6 +19,10 @@ type Config struct {
 	FileServer *fileserver.Config   `json:"fileServer,omitempty"`
 	Consumer   *tgconsumer.Config   `json:"consumer,omitempty"`
 	Fetcher    *fetch.Config        `json:"fetcher,omitempty"`
+
+	// Scenario enables scheduled multi-phase operation.
+	// If omitted, the traffic generator runs with the initial configuration until stopped.
+	Scenario *Scenario `json:"scenario,omitempty"`
 }
 
 // Validate applies defaults and validates the configuration.
@@ -56,6 +60,11 @@ func (cfg *Config) Validate() error {
 	if hasProducer == "" && hasConsumer == "" {
 		errs = append(errs, errors.New("at least one producer or consumer module should be enabled"))
 	}
+	if cfg.Scenario != nil {
+		if e := cfg.Scenario.validate(*cfg); e != nil {
+			errs = append(errs, fmt.Errorf("scenario %w", e))
+		}
+	}
 
 	return errors.Join(errs...)
 }
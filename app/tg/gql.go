@@ -3,6 +3,7 @@ package tg
 import (
 	"errors"
 	"reflect"
+	"time"
 
 	"github.com/graphql-go/graphql"
 	"github.com/usnistgov/ndn-dpdk/app/fetch"
@@ -12,6 +13,7 @@ import (
 	"github.com/usnistgov/ndn-dpdk/app/tgproducer"
 	"github.com/usnistgov/ndn-dpdk/core/gqlserver"
 	"github.com/usnistgov/ndn-dpdk/core/jsonhelper"
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
 	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
 	"github.com/usnistgov/ndn-dpdk/dpdk/ealthread"
 	"github.com/usnistgov/ndn-dpdk/iface"
@@ -27,8 +29,11 @@ var (
 
 // GraphQL types.
 var (
-	GqlTrafficGenType *gqlserver.NodeType[*TrafficGen]
-	GqlCountersType   *graphql.Object
+	GqlTrafficGenType  *gqlserver.NodeType[*TrafficGen]
+	GqlPhaseInput      *graphql.InputObject
+	GqlScenarioInput   *graphql.InputObject
+	GqlCountersType    *graphql.Object
+	GqlPhaseResultType *graphql.Object
 )
 
 func init() {
@@ -97,6 +102,22 @@ func init() {
 		},
 	})
 
+	GqlPhaseInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "TgPhaseInput",
+		Description: "Traffic generator scenario phase.",
+		Fields: gqlserver.BindInputFields[Phase](gqlserver.FieldTypes{
+			reflect.TypeFor[nnduration.Milliseconds](): nnduration.GqlMilliseconds,
+			reflect.TypeFor[nnduration.Nanoseconds]():  nnduration.GqlNanoseconds,
+		}),
+	})
+	GqlScenarioInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "TgScenarioInput",
+		Description: "Traffic generator scenario.",
+		Fields: gqlserver.BindInputFields[Scenario](gqlserver.FieldTypes{
+			reflect.TypeFor[Phase](): GqlPhaseInput,
+		}),
+	})
+
 	gqlserver.AddMutation(&graphql.Field{
 		Name:        "startTrafficGen",
 		Description: "Create and start a traffic generator.",
@@ -106,6 +127,7 @@ func init() {
 			reflect.TypeFor[fileserver.Config]():    fileserver.GqlConfigInput,
 			reflect.TypeFor[tgconsumer.Config]():    tgconsumer.GqlConfigInput,
 			reflect.TypeFor[fetch.Config]():         fetch.GqlConfigInput,
+			reflect.TypeFor[Scenario]():             GqlScenarioInput,
 		}),
 		Type: graphql.NewNonNull(GqlTrafficGenType.Object),
 		Resolve: func(p graphql.ResolveParams) (any, error) {
@@ -140,6 +162,35 @@ func init() {
 		}),
 	})
 
+	GqlPhaseResultType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "TgPhaseResult",
+		Description: "Traffic generator scenario phase result.",
+		Fields: gqlserver.BindFields[PhaseResult](gqlserver.FieldTypes{
+			reflect.TypeFor[time.Time](): graphql.DateTime,
+			reflect.TypeFor[Counters]():  GqlCountersType,
+		}),
+	})
+	GqlTrafficGenType.Object.AddFieldConfig("phaseResults", &graphql.Field{
+		Description: "Counter snapshots of completed scenario phases.",
+		Type:        gqlserver.NewListNonNullElem(GqlPhaseResultType),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			gen := p.Source.(*TrafficGen)
+			return gen.PhaseResults(), nil
+		},
+	})
+
+	GqlTrafficGenType.Object.AddFieldConfig("scenarioError", &graphql.Field{
+		Description: "Error that aborted the scenario, null if the scenario is running or has completed normally.",
+		Type:        graphql.String,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			gen := p.Source.(*TrafficGen)
+			if e := gen.ScenarioError(); e != nil {
+				return e.Error(), nil
+			}
+			return nil, nil
+		},
+	})
+
 	gqlserver.AddCounters(&gqlserver.CountersConfig{
 		Description:  "Traffic generator counters.",
 		Parent:       GqlTrafficGenType.Object,
@@ -0,0 +1,249 @@
+package tg
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
+	"go.uber.org/zap"
+)
+
+// phaseDrainDelay is the duration between stopping consumer TX and RX threads at the end of a phase,
+// so that Data in flight are counted in the phase that requested them.
+const phaseDrainDelay = 100 * time.Millisecond
+
+// Scenario describes a sequence of phases that the traffic generator walks through automatically.
+//
+// At the end of each phase, all modules are stopped. At the start of the next phase, the consumer and
+// producer modules are reconfigured according to the phase, their counters are cleared so that each
+// phase result reflects a single phase, and all modules are launched again.
+// After the last phase, all modules remain stopped.
+type Scenario struct {
+	// Phases is a list of phases.
+	// It must contain at least one phase.
+	Phases []Phase `json:"phases"`
+}
+
+func (sc Scenario) validate(cfg Config) error {
+	if len(sc.Phases) == 0 {
+		return errors.New("no phase specified")
+	}
+	for i, phase := range sc.Phases {
+		if e := phase.validate(cfg); e != nil {
+			return fmt.Errorf("phase %d: %w", i, e)
+		}
+	}
+	return nil
+}
+
+// Phase describes a scenario phase.
+// Except Name and Duration, each omitted field retains the setting of the previous phase.
+type Phase struct {
+	// Name is an optional phase name, included in phase results.
+	Name string `json:"name,omitempty"`
+
+	// Duration is the phase duration.
+	// It must be positive.
+	Duration nnduration.Milliseconds `json:"duration"`
+
+	// Interval changes consumer average Interest interval.
+	Interval nnduration.Nanoseconds `json:"interval,omitempty"`
+
+	// Weights changes consumer pattern weights.
+	// It must have one entry per consumer pattern.
+	// Zero disables a pattern, but at least one weight must be positive.
+	Weights []int `json:"weights,omitempty"`
+
+	// ReplyWeights changes producer reply weights.
+	// It must have one entry per producer pattern, which is either null to retain existing weights,
+	// or has one entry per reply in the pattern.
+	// Zero disables a reply, but at least one weight in each pattern must be positive.
+	ReplyWeights [][]int `json:"replyWeights,omitempty"`
+}
+
+func (phase Phase) validate(cfg Config) error {
+	if phase.Duration <= 0 {
+		return errors.New("duration must be positive")
+	}
+
+	if phase.Interval != 0 || phase.Weights != nil {
+		if cfg.Consumer == nil {
+			return errors.New("interval and weights require consumer")
+		}
+		if phase.Weights != nil {
+			if e := cfg.Consumer.ValidateWeights(phase.Weights); e != nil {
+				return e
+			}
+		}
+	}
+
+	if phase.ReplyWeights != nil {
+		if cfg.Producer == nil {
+			return errors.New("replyWeights require producer")
+		}
+		if e := cfg.Producer.ValidateReplyWeights(phase.ReplyWeights); e != nil {
+			return fmt.Errorf("replyWeights: %w", e)
+		}
+	}
+	return nil
+}
+
+// PhaseResult contains counter snapshot at the end of a scenario phase.
+type PhaseResult struct {
+	Index    int       `json:"index" gqldesc:"Phase index."`
+	Name     string    `json:"name,omitempty" gqldesc:"Phase name."`
+	Start    time.Time `json:"start" gqldesc:"Phase start time."`
+	End      time.Time `json:"end" gqldesc:"Phase end time."`
+	Counters Counters  `json:"counters" gqldesc:"Counters at the end of phase; consumer and producer counters are cleared at the start of phase."`
+}
+
+// scenarioTarget is the part of TrafficGen controlled by scenarioRunner.
+type scenarioTarget interface {
+	applyPhase(phase Phase) error
+	launchModules()
+	stopModules(consumerDelay time.Duration) error
+	Counters() Counters
+}
+
+type scenarioRunner struct {
+	gen    scenarioTarget
+	phases []Phase
+	stop   chan struct{}
+	done   chan struct{}
+
+	mutex   sync.Mutex
+	results []PhaseResult
+	err     error
+}
+
+// apply reconfigures modules for a phase and clears their counters.
+func (r *scenarioRunner) apply(i int) error {
+	return r.gen.applyPhase(r.phases[i])
+}
+
+// fail records an error that aborted the scenario.
+func (r *scenarioRunner) fail(i int, e error) {
+	logger.Error("scenario phase error", zap.Int("phase", i), zap.Error(e))
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.err = fmt.Errorf("phase %d: %w", i, e)
+}
+
+func (r *scenarioRunner) run() {
+	defer close(r.done)
+	gen := r.gen
+	for i, phase := range r.phases {
+		if i > 0 {
+			if e := r.apply(i); e != nil {
+				r.fail(i, e)
+				return
+			}
+			gen.launchModules()
+		}
+
+		start := time.Now()
+		timer := time.NewTimer(phase.Duration.Duration())
+		select {
+		case <-r.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if e := gen.stopModules(phaseDrainDelay); e != nil {
+			r.fail(i, e)
+			return
+		}
+
+		r.mutex.Lock()
+		r.results = append(r.results, PhaseResult{
+			Index:    i,
+			Name:     phase.Name,
+			Start:    start,
+			End:      time.Now(),
+			Counters: gen.Counters(),
+		})
+		r.mutex.Unlock()
+		logger.Info("scenario phase completed", zap.Int("phase", i), zap.String("name", phase.Name))
+	}
+}
+
+// Results returns results of completed phases.
+func (r *scenarioRunner) Results() []PhaseResult {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return slices.Clone(r.results)
+}
+
+// Err returns the error that aborted the scenario, or nil if the scenario is running or has completed normally.
+func (r *scenarioRunner) Err() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.err
+}
+
+// Close stops the scenario and waits for the runner to exit.
+func (r *scenarioRunner) Close() error {
+	select {
+	case <-r.stop:
+	default:
+		close(r.stop)
+	}
+	<-r.done
+	return nil
+}
+
+func newScenarioRunner(gen scenarioTarget, sc Scenario) *scenarioRunner {
+	return &scenarioRunner{
+		gen:    gen,
+		phases: sc.Phases,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// applyPhase reconfigures modules for a scenario phase and clears their counters.
+// Consumer and producer modules must be stopped.
+func (gen *TrafficGen) applyPhase(phase Phase) error {
+	if gen.consumer != nil {
+		if phase.Interval != 0 {
+			gen.consumer.SetInterval(phase.Interval.Duration())
+		}
+		if phase.Weights != nil {
+			if e := gen.consumer.SetWeights(phase.Weights); e != nil {
+				return e
+			}
+		}
+		gen.consumer.ClearCounters()
+	}
+	if gen.producer != nil {
+		if phase.ReplyWeights != nil {
+			if e := gen.producer.SetReplyWeights(phase.ReplyWeights); e != nil {
+				return e
+			}
+		}
+		gen.producer.ClearCounters()
+	}
+	return nil
+}
+
+// PhaseResults returns counter snapshots of completed scenario phases.
+// It returns nil if the traffic generator does not have a scenario.
+func (gen *TrafficGen) PhaseResults() []PhaseResult {
+	if gen.runner == nil {
+		return nil
+	}
+	return gen.runner.Results()
+}
+
+// ScenarioError returns the error that aborted the scenario.
+// It returns nil if the traffic generator does not have a scenario, or the scenario has not failed.
+func (gen *TrafficGen) ScenarioError() error {
+	if gen.runner == nil {
+		return nil
+	}
+	return gen.runner.Err()
+}
@@ -0,0 +1,231 @@
+package tg
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/app/tgconsumer"
+	"github.com/usnistgov/ndn-dpdk/app/tgproducer"
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
+	"github.com/usnistgov/ndn-dpdk/core/testenv"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+)
+
+var makeAR = testenv.MakeAR
+
+func TestScenarioValidate(t *testing.T) {
+	cfgBoth := Config{
+		Consumer: &tgconsumer.Config{
+			Patterns: []tgconsumer.Pattern{{}, {}},
+		},
+		Producer: &tgproducer.Config{
+			Patterns: []tgproducer.Pattern{
+				{Prefix: ndn.ParseName("/A"), Replies: []tgproducer.Reply{{}, {}}},
+				{Prefix: ndn.ParseName("/B"), Replies: []tgproducer.Reply{{}}},
+			},
+		},
+	}
+	cfgNone := Config{}
+
+	const d = nnduration.Milliseconds(100)
+	tests := []struct {
+		cfg   Config
+		sc    Scenario
+		valid bool
+	}{
+		{cfgBoth, Scenario{}, false},
+		{cfgBoth, Scenario{Phases: []Phase{{}}}, false},
+		{cfgBoth, Scenario{Phases: []Phase{{Duration: d}, {Name: "zero-duration"}}}, false},
+		{cfgNone, Scenario{Phases: []Phase{{Duration: d}}}, true},
+		{cfgNone, Scenario{Phases: []Phase{{Duration: d, Interval: 1000}}}, false},
+		{cfgNone, Scenario{Phases: []Phase{{Duration: d, Weights: []int{1, 1}}}}, false},
+		{cfgNone, Scenario{Phases: []Phase{{Duration: d, ReplyWeights: [][]int{nil, nil}}}}, false},
+		{cfgBoth, Scenario{Phases: []Phase{{Duration: d, Interval: 1000}}}, true},
+		{cfgBoth, Scenario{Phases: []Phase{{Duration: d, Weights: []int{1, 0}}}}, true},
+		{cfgBoth, Scenario{Phases: []Phase{{Duration: d, Weights: []int{1}}}}, false},
+		{cfgBoth, Scenario{Phases: []Phase{{Duration: d, Weights: []int{-1, 2}}}}, false},
+		{cfgBoth, Scenario{Phases: []Phase{{Duration: d, Weights: []int{0, 0}}}}, false},
+		{cfgBoth, Scenario{Phases: []Phase{{Duration: d, Weights: []int{tgconsumer.MaxSumWeight, 0}}}}, true},
+		{cfgBoth, Scenario{Phases: []Phase{{Duration: d, Weights: []int{tgconsumer.MaxSumWeight, 1}}}}, false},
+		{cfgBoth, Scenario{Phases: []Phase{{Duration: d, ReplyWeights: [][]int{{0, 1}, nil}}}}, true},
+		{cfgBoth, Scenario{Phases: []Phase{{Duration: d, ReplyWeights: [][]int{{0, 1}}}}}, false},
+		{cfgBoth, Scenario{Phases: []Phase{{Duration: d, ReplyWeights: [][]int{{1}, nil}}}}, false},
+		{cfgBoth, Scenario{Phases: []Phase{{Duration: d, ReplyWeights: [][]int{nil, {0}}}}}, false},
+		{cfgBoth, Scenario{Phases: []Phase{{Duration: d, ReplyWeights: [][]int{{1, -1}, nil}}}}, false},
+		{cfgBoth, Scenario{Phases: []Phase{{Duration: d, ReplyWeights: [][]int{nil, {tgproducer.MaxSumWeight + 1}}}}}, false},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			assert, _ := makeAR(t)
+			e := tt.sc.validate(tt.cfg)
+			if tt.valid {
+				assert.NoError(e)
+			} else {
+				assert.Error(e)
+			}
+		})
+	}
+}
+
+// fakeScenarioTarget records scenarioRunner operations.
+type fakeScenarioTarget struct {
+	events    chan string
+	phase     int
+	failApply int
+	failStop  int
+}
+
+func (ft *fakeScenarioTarget) applyPhase(phase Phase) error {
+	ft.phase++
+	ft.events <- "apply " + phase.Name
+	if ft.phase == ft.failApply {
+		return errors.New("apply failure")
+	}
+	return nil
+}
+
+func (ft *fakeScenarioTarget) launchModules() {
+	ft.events <- "launch"
+}
+
+func (ft *fakeScenarioTarget) stopModules(consumerDelay time.Duration) error {
+	ft.events <- "stop"
+	if ft.phase == ft.failStop {
+		return errors.New("stop failure")
+	}
+	return nil
+}
+
+func (ft *fakeScenarioTarget) Counters() Counters {
+	return Counters{
+		Consumer: &tgconsumer.Counters{NAllocError: uint64(ft.phase)},
+	}
+}
+
+func newFakeScenarioTarget() *fakeScenarioTarget {
+	return &fakeScenarioTarget{
+		events:    make(chan string, 64),
+		phase:     -1,
+		failApply: -1,
+		failStop:  -1,
+	}
+}
+
+// startScenario starts a scenarioRunner in the same way as TrafficGen.Launch.
+func startScenario(t testing.TB, ft *fakeScenarioTarget, phases ...Phase) *scenarioRunner {
+	_, require := makeAR(t)
+	r := newScenarioRunner(ft, Scenario{Phases: phases})
+	require.NoError(r.apply(0))
+	ft.launchModules()
+	go r.run()
+	return r
+}
+
+func collectEvents(ft *fakeScenarioTarget) (events []string) {
+	for {
+		select {
+		case evt := <-ft.events:
+			events = append(events, evt)
+		default:
+			return events
+		}
+	}
+}
+
+func TestScenarioRunner(t *testing.T) {
+	assert, require := makeAR(t)
+
+	ft := newFakeScenarioTarget()
+	r := startScenario(t, ft,
+		Phase{Name: "P0", Duration: 20},
+		Phase{Name: "P1", Duration: 20},
+		Phase{Name: "P2", Duration: 20},
+	)
+	<-r.done
+	assert.NoError(r.Err())
+	assert.NoError(r.Close())
+
+	assert.Equal([]string{
+		"apply P0", "launch", "stop",
+		"apply P1", "launch", "stop",
+		"apply P2", "launch", "stop",
+	}, collectEvents(ft))
+
+	results := r.Results()
+	require.Len(results, 3)
+	for i, res := range results {
+		assert.Equal(i, res.Index)
+		assert.Equal(fmt.Sprintf("P%d", i), res.Name)
+		assert.GreaterOrEqual(res.End.Sub(res.Start), 20*time.Millisecond)
+		if i > 0 {
+			assert.False(res.Start.Before(results[i-1].End))
+		}
+		require.NotNil(res.Counters.Consumer)
+		assert.EqualValues(i, res.Counters.Consumer.NAllocError)
+	}
+}
+
+func TestScenarioRunnerApplyError(t *testing.T) {
+	assert, require := makeAR(t)
+
+	ft := newFakeScenarioTarget()
+	ft.failApply = 1
+	r := startScenario(t, ft,
+		Phase{Name: "P0", Duration: 10},
+		Phase{Name: "P1", Duration: 10},
+		Phase{Name: "P2", Duration: 10},
+	)
+	<-r.done
+	assert.NoError(r.Close())
+
+	// modules are not launched after a failed reconfiguration
+	assert.Equal([]string{"apply P0", "launch", "stop", "apply P1"}, collectEvents(ft))
+	e := r.Err()
+	require.Error(e)
+	assert.ErrorContains(e, "phase 1")
+	assert.ErrorContains(e, "apply failure")
+	assert.Len(r.Results(), 1)
+}
+
+func TestScenarioRunnerStopError(t *testing.T) {
+	assert, require := makeAR(t)
+
+	ft := newFakeScenarioTarget()
+	ft.failStop = 0
+	r := startScenario(t, ft,
+		Phase{Name: "P0", Duration: 10},
+		Phase{Name: "P1", Duration: 10},
+	)
+	<-r.done
+	assert.NoError(r.Close())
+
+	assert.Equal([]string{"apply P0", "launch", "stop"}, collectEvents(ft))
+	e := r.Err()
+	require.Error(e)
+	assert.ErrorContains(e, "phase 0")
+	assert.ErrorContains(e, "stop failure")
+	assert.Empty(r.Results())
+}
+
+func TestScenarioRunnerClose(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	ft := newFakeScenarioTarget()
+	r := startScenario(t, ft,
+		Phase{Name: "P0", Duration: 10},
+		Phase{Name: "P1", Duration: 60000},
+	)
+	time.Sleep(200 * time.Millisecond)
+
+	t0 := time.Now()
+	assert.NoError(r.Close())
+	assert.Less(time.Since(t0), time.Second)
+	assert.NoError(r.Close())
+
+	// the runner exits without stopping modules; TrafficGen.Stop does that
+	assert.Equal([]string{"apply P0", "launch", "stop", "apply P1", "launch"}, collectEvents(ft))
+	assert.NoError(r.Err())
+	assert.Len(r.Results(), 1)
+}
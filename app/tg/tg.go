@@ -7,6 +7,7 @@ import (
 	"io"
 	"reflect"
 	"sync"
+	"time"
 
 	"github.com/usnistgov/ndn-dpdk/app/fetch"
 	"github.com/usnistgov/ndn-dpdk/app/fileserver"
@@ -44,13 +45,15 @@ func saveChooseRxlTxl() (undo func()) {
 type TrafficGen struct {
 	face    iface.Face
 	rxl     []iface.RxLoop
-	txl     iface.TxLoop
+	txl     []iface.TxLoop
 	workers []ealthread.ThreadWithRole
 
 	producer   *tgproducer.Producer
 	fileServer *fileserver.Server
 	consumer   *tgconsumer.Consumer
 	fetcher    *fetch.Fetcher
+	scenario   *Scenario
+	runner     *scenarioRunner
 	exit       chan struct{}
 }
 
@@ -85,12 +88,31 @@ func (gen TrafficGen) Workers() []ealthread.ThreadWithRole {
 }
 
 // Launch launches the traffic generator.
+// If a scenario is configured, it starts from the first phase.
 func (gen *TrafficGen) Launch() error {
-	ealthread.Launch(gen.txl)
+	for _, txl := range gen.txl {
+		ealthread.Launch(txl)
+	}
 	for _, rxl := range gen.rxl {
 		ealthread.Launch(rxl)
 	}
 
+	if gen.scenario == nil {
+		gen.launchModules()
+		return nil
+	}
+
+	runner := newScenarioRunner(gen, *gen.scenario)
+	if e := runner.apply(0); e != nil {
+		return fmt.Errorf("error applying scenario phase 0 %w", e)
+	}
+	gen.launchModules()
+	gen.runner = runner
+	go runner.run()
+	return nil
+}
+
+func (gen *TrafficGen) launchModules() {
 	if gen.producer != nil {
 		gen.producer.Launch()
 	} else if gen.fileServer != nil {
@@ -102,8 +124,6 @@ func (gen *TrafficGen) Launch() error {
 	} else if gen.fetcher != nil {
 		gen.fetcher.Launch()
 	}
-
-	return nil
 }
 
 func (gen *TrafficGen) configureDemux() {
@@ -123,19 +143,33 @@ func (gen *TrafficGen) configureDemux() {
 	}
 }
 
-// Stop stops the traffic generator.
-// It can be launched again.
-func (gen *TrafficGen) Stop() error {
+// stopModules stops every module started by launchModules.
+// Consumer TX thread is stopped first, and consumer RX thread is stopped after consumerDelay.
+func (gen *TrafficGen) stopModules(consumerDelay time.Duration) error {
 	errs := []error{}
-	if gen.producer != nil {
-		errs = append(errs, gen.producer.Stop())
-	}
 	if gen.consumer != nil {
-		errs = append(errs, gen.consumer.Stop())
+		errs = append(errs, gen.consumer.StopDelay(consumerDelay))
 	}
 	if gen.fetcher != nil {
 		errs = append(errs, gen.fetcher.Stop())
 	}
+	if gen.producer != nil {
+		errs = append(errs, gen.producer.Stop())
+	}
+	if gen.fileServer != nil {
+		errs = append(errs, gen.fileServer.Stop())
+	}
+	return errors.Join(errs...)
+}
+
+// Stop stops the traffic generator.
+// It can be launched again.
+func (gen *TrafficGen) Stop() error {
+	errs := []error{}
+	if gen.runner != nil {
+		errs = append(errs, gen.runner.Close())
+	}
+	errs = append(errs, gen.stopModules(0))
 	return errors.Join(errs...)
 }
 
@@ -167,7 +201,9 @@ func (gen *TrafficGen) Close() error {
 	for _, rxl := range gen.rxl {
 		gatherCloseErr(rxl)
 	}
-	gatherCloseErr(gen.txl)
+	for _, txl := range gen.txl {
+		gatherCloseErr(txl)
+	}
 
 	var lcores eal.LCores
 	for _, w := range gen.workers {
@@ -188,7 +224,8 @@ func New(cfg Config) (gen *TrafficGen, e error) {
 	}
 
 	gen = &TrafficGen{
-		exit: make(chan struct{}),
+		scenario: cfg.Scenario,
+		exit:     make(chan struct{}),
 	}
 	defer func(gen *TrafficGen) {
 		if e != nil {
@@ -211,10 +248,11 @@ func New(cfg Config) (gen *TrafficGen, e error) {
 		gen.workers = append(gen.workers, rxl)
 		return rxl
 	}
-	iface.ChooseTxLoop = func(face iface.Face) iface.TxLoop {
-		gen.txl = iface.NewTxLoop(face.NumaSocket())
-		gen.workers = append(gen.workers, gen.txl)
-		return gen.txl
+	iface.ChooseTxLoop = func(face iface.Face, txThread int) iface.TxLoop {
+		txl := iface.NewTxLoop(face.NumaSocket())
+		gen.txl = append(gen.txl, txl)
+		gen.workers = append(gen.workers, txl)
+		return txl
 	}
 
 	if gen.face, e = cfg.Face.CreateFace(); e != nil {
@@ -223,7 +261,7 @@ func New(cfg Config) (gen *TrafficGen, e error) {
 	if len(gen.rxl) == 0 {
 		logger.Warn("face creation did not result in dedicated RxLoop creation; this face is incompatible with traffic generator: results are inaccurate, closing any traffic generator may cause a crash")
 	}
-	if gen.txl == nil {
+	if len(gen.txl) == 0 {
 		logger.Warn("face creation did not result in dedicated TxLoop creation; this face is incompatible with traffic generator: results are inaccurate, closing any traffic generator may cause a crash")
 	}
 
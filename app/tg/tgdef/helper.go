@@ -2,6 +2,7 @@ package tgdef
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/usnistgov/ndn-dpdk/dpdk/ealthread"
 )
@@ -29,3 +30,22 @@ func StopWorkers[W ealthread.Thread](workers []W) error {
 	}
 	return errors.Join(errs...)
 }
+
+// ValidateWeights checks weights of a random choice.
+// Each weight must be non-negative, at least one must be positive, and the sum must not exceed maxSum.
+func ValidateWeights(weights []int, maxSum int) error {
+	sum := 0
+	for _, weight := range weights {
+		if weight < 0 {
+			return errors.New("weight cannot be negative")
+		}
+		sum += weight
+	}
+	if sum == 0 {
+		return errors.New("at least one weight must be positive")
+	}
+	if sum > maxSum {
+		return fmt.Errorf("sum of weight cannot exceed %d", maxSum)
+	}
+	return nil
+}
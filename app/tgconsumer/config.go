@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/usnistgov/ndn-dpdk/app/tg/tgdef"
 	"github.com/usnistgov/ndn-dpdk/core/nnduration"
 	"github.com/usnistgov/ndn-dpdk/iface"
 	"github.com/usnistgov/ndn-dpdk/ndni"
@@ -25,6 +26,9 @@ const (
 	// DigestBurstSize is the number of Data packets to enqueue into crypto device.
 	DigestBurstSize = 64
 
+	// RttBuckets is the number of per-pattern RTT histogram buckets.
+	RttBuckets = 48
+
 	_ = "enumgen::Tgc"
 )
 
@@ -44,9 +48,31 @@ type Config struct {
 	// It must contain between 1 and MaxPatterns entries.
 	Patterns []Pattern `json:"patterns"`
 
+	// Trace enables trace-driven Interest generation.
+	// If specified, the consumer replays Interests from a trace file at the recorded timing, instead of
+	// selecting patterns randomly; Interval and pattern weights are ignored.
+	// Each trace record is assigned to the pattern with the longest matching Prefix among patterns whose
+	// CanBePrefix and MustBeFresh equal the record's flags; the pattern provides other Interest fields.
+	// Records that do not match any pattern are skipped.
+	Trace *TraceConfig `json:"trace,omitempty"`
+
+	// Verify enables verifying mode.
+	// If specified, the consumer verifies every Data packet and counts network faults.
+	// This cannot be used together with Trace.
+	Verify *VerifyConfig `json:"verify,omitempty"`
+
 	nWeights, nDigestPatterns int
 }
 
+// ValidateWeights checks new pattern weights for SetWeights.
+// weights must have one entry per pattern; zero disables a pattern, but at least one weight must be positive.
+func (cfg Config) ValidateWeights(weights []int) error {
+	if len(weights) != len(cfg.Patterns) {
+		return fmt.Errorf("expect %d weights", len(cfg.Patterns))
+	}
+	return tgdef.ValidateWeights(weights, MaxSumWeight)
+}
+
 // Validate applies defaults and validates the configuration.
 func (cfg *Config) Validate() error {
 	cfg.RxQueue.DisableCoDel = true
@@ -65,8 +91,20 @@ func (cfg *Config) Validate() error {
 		patterns = append(patterns, pattern)
 		nWeights += pattern.Weight
 		if pattern.Digest != nil {
+			if pattern.Digest.Signing != nil {
+				return errors.New("Digest pattern cannot have Signing")
+			}
 			nDigestPatterns++
 		}
+		if pattern.Popularity != nil {
+			if pattern.Digest != nil || pattern.SeqNumOffset != 0 {
+				return errors.New("pattern cannot have Popularity together with Digest or SeqNumOffset")
+			}
+			pattern.Popularity.applyDefaults()
+			if e := pattern.Popularity.validate(); e != nil {
+				return fmt.Errorf("pattern %d: %w", i, e)
+			}
+		}
 		if pattern.SeqNumOffset != 0 {
 			if pattern.Digest != nil {
 				return errors.New("pattern cannot have both Digest and SeqNumOffset")
@@ -76,6 +114,28 @@ func (cfg *Config) Validate() error {
 			}
 		}
 	}
+	if cfg.Trace != nil {
+		cfg.Trace.applyDefaults()
+		if e := cfg.Trace.validate(); e != nil {
+			return e
+		}
+		if nDigestPatterns > 0 {
+			return errors.New("trace cannot be used with Digest patterns")
+		}
+		for _, pattern := range patterns {
+			if pattern.SeqNumOffset != 0 || pattern.Popularity != nil {
+				return errors.New("trace cannot be used with SeqNumOffset or Popularity patterns")
+			}
+		}
+	}
+	if cfg.Verify != nil {
+		if cfg.Trace != nil {
+			return errors.New("verify cannot be used with trace")
+		}
+		if e := cfg.Verify.validate(nDigestPatterns); e != nil {
+			return fmt.Errorf("verify: %w", e)
+		}
+	}
 	if nWeights > MaxSumWeight {
 		return fmt.Errorf("sum of weight cannot exceed %d", MaxSumWeight)
 	}
@@ -98,6 +158,15 @@ type Pattern struct {
 	// The consumer derives sequence number by subtracting SeqNumOffset from the previous pattern's
 	// sequence number. Sufficient CS capacity is necessary for Data to actually come from CS.
 	SeqNumOffset int `json:"seqNumOffset,omitempty"`
+
+	// If specified, choose names from a catalog according to a popularity distribution,
+	// instead of incrementing sequence number.
+	Popularity *PopularityConfig `json:"popularity,omitempty"`
+
+	// If non-zero, Data with RTT not exceeding this threshold are counted as Content Store hits.
+	// This should be set between the RTT of a cache hit at the nearest forwarder and the RTT to the producer.
+	// If zero, the threshold is inferred from the RTT histogram, see RttHistogram.InferCsHitRtt.
+	CsHitRtt nnduration.Nanoseconds `json:"csHitRtt,omitempty"`
 }
 
 func (pattern *Pattern) applyDefaults() {
@@ -6,6 +6,7 @@ package tgconsumer
 #include "../../csrc/tgconsumer/tx.h"
 
 static_assert(offsetof(TgcTxPattern, digest) == offsetof(TgcTxPattern, seqNumOffset), "");
+static_assert(offsetof(TgcTxPattern, digest) == offsetof(TgcTxPattern, popularity), "");
 enum { c_offsetof_TgcTxPattern_DigestSeqNumOffset = offsetof(TgcTxPattern, digest) };
 */
 import "C"
@@ -19,6 +20,7 @@ import (
 
 	"github.com/usnistgov/ndn-dpdk/app/tg/tgdef"
 	"github.com/usnistgov/ndn-dpdk/core/cptr"
+	"github.com/usnistgov/ndn-dpdk/core/logging"
 	"github.com/usnistgov/ndn-dpdk/core/nnduration"
 	"github.com/usnistgov/ndn-dpdk/core/pcg32"
 	"github.com/usnistgov/ndn-dpdk/dpdk/cryptodev"
@@ -33,6 +35,8 @@ import (
 	"go4.org/must"
 )
 
+var logger = logging.New("tgconsumer")
+
 type worker struct {
 	ealthread.ThreadWithCtrl
 }
@@ -58,6 +62,8 @@ type Consumer struct {
 
 	digestCrypto *cryptodev.CryptoDev
 	dPatterns    []*C.TgcTxDigestPattern
+	popularities []*C.TgcTxPopularity
+	verifies     []*C.TgcRxVerify
 }
 
 var _ tgdef.Consumer = &Consumer{}
@@ -71,6 +77,9 @@ func (c *Consumer) initPatterns() (e error) {
 	if e := c.prepareDigest(c.cfg.nDigestPatterns); e != nil {
 		return fmt.Errorf("prepareDigest %w", e)
 	}
+	if e := c.initVerify(); e != nil {
+		return fmt.Errorf("initVerify %w", e)
+	}
 	var dataGenVec pktmbuf.Vector
 	if c.cfg.nDigestPatterns > 0 {
 		payloadMp := ndni.PayloadMempool.Get(c.socket)
@@ -81,18 +90,41 @@ func (c *Consumer) initPatterns() (e error) {
 	}
 
 	c.rxC.nPatterns = C.uint8_t(len(c.cfg.Patterns))
-	c.txC.nWeights = C.uint32_t(c.cfg.nWeights)
-	w := 0
 	for i, pattern := range c.cfg.Patterns {
 		c.assignPattern(i, pattern, dataGenVec.Take)
+		c.clearCounter(i)
+	}
+	c.assignWeights()
+	return nil
+}
 
+func (c *Consumer) assignWeights() {
+	w := 0
+	for i, pattern := range c.cfg.Patterns {
 		for range pattern.Weight {
 			c.txC.weight[w] = C.uint8_t(i)
 			w++
 		}
+	}
+	c.txC.nWeights = C.uint32_t(w)
+}
 
-		c.clearCounter(i)
+// SetWeights changes pattern weights.
+// weights must have one entry per pattern; zero disables a pattern, but at least one weight must be positive.
+// Both RX and TX threads should be stopped before calling this, otherwise race conditions may occur.
+func (c *Consumer) SetWeights(weights []int) error {
+	if e := c.cfg.ValidateWeights(weights); e != nil {
+		return e
+	}
+
+	patterns, nWeights := make([]Pattern, len(c.cfg.Patterns)), 0
+	for i, pattern := range c.cfg.Patterns {
+		pattern.Weight = weights[i]
+		patterns[i] = pattern
+		nWeights += pattern.Weight
 	}
+	c.cfg.Patterns, c.cfg.nWeights = patterns, nWeights
+	c.assignWeights()
 	return nil
 }
 
@@ -100,6 +132,7 @@ func (c *Consumer) assignPattern(i int, pattern Pattern, takeDataGenMbuf func()
 	rxP := &c.rxC.pattern[i]
 	*rxP = C.TgcRxPattern{
 		prefixLen: C.uint16_t(pattern.Prefix.Length()),
+		csHitRtt:  C.TscDuration(eal.ToTscDuration(pattern.CsHitRtt.Duration())),
 	}
 
 	txP := &c.txC.pattern[i]
@@ -111,10 +144,16 @@ func (c *Consumer) assignPattern(i int, pattern Pattern, takeDataGenMbuf func()
 		digestL: ndni.ImplicitDigestLength,
 	}
 	pattern.InterestTemplateConfig.Apply(ndni.InterestTemplateFromPtr(unsafe.Pointer(&txP.tpl)))
+	if c.cfg.Verify != nil {
+		c.newVerify(pattern, rxP, txP)
+	}
 
 	switch {
 	case pattern.Digest != nil:
-		c.assignDigestPattern(pattern, txP, takeDataGenMbuf)
+		c.assignDigestPattern(pattern, txP, rxP.verify, takeDataGenMbuf)
+	case pattern.Popularity != nil:
+		txP.makeSuffix = pattern.Popularity.makeSuffix()
+		*(**C.TgcTxPopularity)(unsafe.Add(unsafe.Pointer(txP), C.c_offsetof_TgcTxPattern_DigestSeqNumOffset)) = c.newPopularity(*pattern.Popularity)
 	case pattern.SeqNumOffset != 0:
 		txP.makeSuffix = C.TgcTxPattern_MakeSuffix(C.TgcTxPattern_MakeSuffix_Offset)
 		*(*C.uint64_t)(unsafe.Add(unsafe.Pointer(txP), C.c_offsetof_TgcTxPattern_DigestSeqNumOffset)) = C.uint64_t(pattern.SeqNumOffset)
@@ -123,7 +162,7 @@ func (c *Consumer) assignPattern(i int, pattern Pattern, takeDataGenMbuf func()
 	}
 }
 
-func (c *Consumer) assignDigestPattern(pattern Pattern, txP *C.TgcTxPattern, takeDataGenMbuf func() *pktmbuf.Packet) {
+func (c *Consumer) assignDigestPattern(pattern Pattern, txP *C.TgcTxPattern, verify *C.TgcRxVerify, takeDataGenMbuf func() *pktmbuf.Packet) {
 	txP.makeSuffix = C.TgcTxPattern_MakeSuffix(C.TgcTxPattern_MakeSuffix_Digest)
 
 	var seqNumV [8]byte
@@ -143,6 +182,7 @@ func (c *Consumer) assignDigestPattern(pattern Pattern, txP *C.TgcTxPattern, tak
 	copy(unsafe.Slice((*byte)(nameVC), len(nameV)), nameV)
 	dp.prefix.value = (*C.uint8_t)(nameVC)
 	dp.prefix.length = C.uint16_t(len(nameV))
+	assignVerifyDigests(dp, verify)
 
 	*(**C.TgcTxDigestPattern)(unsafe.Add(unsafe.Pointer(txP), C.c_offsetof_TgcTxPattern_DigestSeqNumOffset)) = dp
 	c.dPatterns = append(c.dPatterns, dp)
@@ -170,6 +210,13 @@ func (c Consumer) Interval() time.Duration {
 	return eal.FromTscDuration(int64(c.txC.burstInterval)) / iface.MaxBurstSize
 }
 
+// SetInterval changes average Interest interval.
+// TX thread should be stopped before calling this.
+func (c *Consumer) SetInterval(interval time.Duration) {
+	c.cfg.Interval = nnduration.Nanoseconds(interval)
+	c.txC.burstInterval = C.TscDuration(eal.ToTscDuration(interval * iface.MaxBurstSize))
+}
+
 // Face returns the associated face.
 func (c Consumer) Face() iface.Face {
 	return iface.Get(iface.ID(c.txC.face))
@@ -218,6 +265,9 @@ func (c *Consumer) StopDelay(delay time.Duration) error {
 func (c *Consumer) Close() error {
 	c.Stop()
 	c.closeDigest()
+	c.closePopularity()
+	c.closeTrace()
+	c.closeVerify()
 	must.Close(c.rxQueue())
 	eal.Free(c.rxC)
 	eal.Free(c.txC)
@@ -274,12 +324,17 @@ func New(face iface.Face, cfg Config) (c *Consumer, e error) {
 		),
 	}
 
-	c.txC.burstInterval = C.TscDuration(eal.ToTscDuration(
-		cfg.Interval.DurationOr(nnduration.Nanoseconds(defaultInterval)) * iface.MaxBurstSize))
+	c.SetInterval(cfg.Interval.DurationOr(nnduration.Nanoseconds(defaultInterval)))
 	if e := c.initPatterns(); e != nil {
 		must.Close(c)
 		return nil, fmt.Errorf("error setting patterns %w", e)
 	}
+	if cfg.Trace != nil {
+		if e := c.loadTrace(); e != nil {
+			must.Close(c)
+			return nil, fmt.Errorf("error loading trace %w", e)
+		}
+	}
 
 	c.ClearCounters()
 	return c, nil
@@ -6,6 +6,7 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
 	"github.com/usnistgov/ndn-dpdk/core/runningstat"
 	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
 )
@@ -49,16 +50,33 @@ func (cnt PacketCounters) String() string {
 // PatternCounters contains per-pattern counters.
 type PatternCounters struct {
 	PacketCounters
-	Rtt runningstat.Snapshot `json:"rtt" gqldesc:"RTT in nanoseconds."`
+	VerifyCounters
+	NCsHits      uint64                 `json:"nCsHits" gqldesc:"Data with RTT within csHitRtt, presumably from Content Store."`
+	CsHitRtt     nnduration.Nanoseconds `json:"csHitRtt" gqldesc:"RTT threshold of Content Store hits, configured or inferred; zero if unknown."`
+	Rtt          runningstat.Snapshot   `json:"rtt" gqldesc:"RTT in nanoseconds."`
+	RttHistogram RttHistogram           `json:"rttHistogram" gqldesc:"RTT histogram."`
+}
+
+// CsHitRatio returns NCsHits/NData.
+func (cnt PatternCounters) CsHitRatio() float64 {
+	return float64(cnt.NCsHits) / float64(cnt.NData)
 }
 
 func (cnt PatternCounters) String() string {
-	return fmt.Sprintf("%s rtt=%s", cnt.PacketCounters, formatRttCounters(cnt.Rtt))
+	s := fmt.Sprintf("%s rtt=%s", cnt.PacketCounters, formatRttCounters(cnt.Rtt))
+	if cnt.NCsHits > 0 {
+		s += fmt.Sprintf(" cs-hit=%d(%0.2f%%)", cnt.NCsHits, cnt.CsHitRatio()*100.0)
+	}
+	if !cnt.VerifyCounters.IsZero() {
+		s += " " + cnt.VerifyCounters.String()
+	}
+	return s
 }
 
 // Counters contains consumer counters.
 type Counters struct {
 	PacketCounters
+	VerifyCounters
 	NAllocError uint64               `json:"nAllocError"`
 	Rtt         runningstat.Snapshot `json:"rtt" gqldesc:"RTT in nanoseconds."`
 	PerPattern  []PatternCounters    `json:"perPattern"`
@@ -66,6 +84,9 @@ type Counters struct {
 
 func (cnt Counters) String() string {
 	s := fmt.Sprintf("%s %dalloc-error rtt=%s", cnt.PacketCounters, cnt.NAllocError, formatRttCounters(cnt.Rtt))
+	if !cnt.VerifyCounters.IsZero() {
+		s += " " + cnt.VerifyCounters.String()
+	}
 	for i, pcnt := range cnt.PerPattern {
 		s += fmt.Sprintf(", pattern(%d) %s", i, pcnt)
 	}
@@ -84,14 +105,23 @@ func (c *Consumer) Counters() (cnt Counters) {
 		pcnt.NData = rtt.Count
 		pcnt.NNacks = uint64(crP.nNacks)
 		pcnt.Rtt = rtt
+		pcnt.RttHistogram = newRttHistogram(unsafe.Slice((*uint64)(unsafe.Pointer(&crP.rttHist[0])), len(crP.rttHist)))
+		if csHitRtt := c.cfg.Patterns[i].CsHitRtt; csHitRtt > 0 {
+			pcnt.NCsHits, pcnt.CsHitRtt = uint64(crP.nCsHits), csHitRtt
+		} else if csHitRtt, ok := pcnt.RttHistogram.InferCsHitRtt(); ok {
+			pcnt.NCsHits, pcnt.CsHitRtt = pcnt.RttHistogram.countBelow(csHitRtt), csHitRtt
+		}
+		pcnt.VerifyCounters = c.readVerifyCounters(i)
 		cnt.PerPattern = append(cnt.PerPattern, pcnt)
 
 		cnt.NInterests += pcnt.NInterests
 		cnt.NData += pcnt.NData
 		cnt.NNacks += pcnt.NNacks
 		cnt.Rtt = cnt.Rtt.Add(rtt)
+		cnt.VerifyCounters = cnt.VerifyCounters.Add(pcnt.VerifyCounters)
 	}
 
+	cnt.NUnsolicited += uint64(c.rxC.nUnsolicited)
 	cnt.NAllocError = uint64(c.txC.nAllocError)
 	return cnt
 }
@@ -106,11 +136,15 @@ func (c *Consumer) ClearCounters() {
 	for i := range int(c.rxC.nPatterns) {
 		c.clearCounter(i)
 	}
+	c.rxC.nUnsolicited = 0
 	c.txC.nAllocError = 0
 }
 
 func (c *Consumer) clearCounter(index int) {
 	c.rxC.pattern[index].nNacks = 0
+	c.rxC.pattern[index].nCsHits = 0
 	c.rttStat(index).Init(0)
+	clear(c.rxC.pattern[index].rttHist[:])
+	c.clearVerifyCounters(index)
 	c.txC.pattern[index].nInterests = 0
 }
@@ -19,21 +19,44 @@ var GqlRetrieveByFaceID func(id iface.ID) *Consumer
 
 // GraphQL types.
 var (
+	GqlPopularityInput     *graphql.InputObject
 	GqlPatternInput        *graphql.InputObject
+	GqlTraceInput          *graphql.InputObject
+	GqlVerifyInput         *graphql.InputObject
 	GqlConfigInput         *graphql.InputObject
+	GqlRttBucketType       *graphql.Object
 	GqlPatternCountersType *graphql.Object
 	GqlCountersType        *graphql.Object
 	GqlConsumerType        *gqlserver.NodeType[*Consumer]
 )
 
 func init() {
+	GqlPopularityInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "TgcPopularityInput",
+		Description: "Traffic generator consumer popularity-based name generation config.",
+		Fields:      gqlserver.BindInputFields[PopularityConfig](nil),
+	})
 	GqlPatternInput = graphql.NewInputObject(graphql.InputObjectConfig{
 		Name:        "TgcPatternInput",
 		Description: "Traffic generator consumer pattern definition.",
 		Fields: gqlserver.BindInputFields[Pattern](gqlserver.FieldTypes{
 			reflect.TypeFor[ndn.Name]():                gqlserver.NonNullString,
 			reflect.TypeFor[nnduration.Milliseconds](): nnduration.GqlMilliseconds,
+			reflect.TypeFor[nnduration.Nanoseconds]():  nnduration.GqlNanoseconds,
 			reflect.TypeFor[ndni.DataGenConfig]():      ndni.GqlDataGenInput,
+			reflect.TypeFor[PopularityConfig]():        GqlPopularityInput,
+		}),
+	})
+	GqlTraceInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "TgcTraceInput",
+		Description: "Traffic generator consumer trace replay config.",
+		Fields:      gqlserver.BindInputFields[TraceConfig](nil),
+	})
+	GqlVerifyInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "TgcVerifyInput",
+		Description: "Traffic generator consumer verifying mode config.",
+		Fields: gqlserver.BindInputFields[VerifyConfig](gqlserver.FieldTypes{
+			reflect.TypeFor[ndni.SigningConfig](): ndni.GqlSigningInput,
 		}),
 	})
 	GqlConfigInput = graphql.NewInputObject(graphql.InputObjectConfig{
@@ -43,13 +66,23 @@ func init() {
 			reflect.TypeFor[iface.PktQueueConfig]():   iface.GqlPktQueueInput,
 			reflect.TypeFor[nnduration.Nanoseconds](): nnduration.GqlNanoseconds,
 			reflect.TypeFor[Pattern]():                GqlPatternInput,
+			reflect.TypeFor[TraceConfig]():            GqlTraceInput,
+			reflect.TypeFor[VerifyConfig]():           GqlVerifyInput,
 		}),
 	})
 
+	GqlRttBucketType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "TgcRttBucket",
+		Fields: gqlserver.BindFields[RttBucket](gqlserver.FieldTypes{
+			reflect.TypeFor[nnduration.Nanoseconds](): nnduration.GqlNanoseconds,
+		}),
+	})
 	GqlPatternCountersType = graphql.NewObject(graphql.ObjectConfig{
 		Name: "TgcPatternCounters",
 		Fields: gqlserver.BindFields[PatternCounters](gqlserver.FieldTypes{
-			reflect.TypeFor[runningstat.Snapshot](): runningstat.GqlSnapshotType,
+			reflect.TypeFor[nnduration.Nanoseconds](): nnduration.GqlNanoseconds,
+			reflect.TypeFor[runningstat.Snapshot]():   runningstat.GqlSnapshotType,
+			reflect.TypeFor[RttBucket]():              GqlRttBucketType,
 		}),
 	})
 	GqlCountersType = graphql.NewObject(graphql.ObjectConfig{
@@ -0,0 +1,167 @@
+package tgconsumer
+
+/*
+#include "../../csrc/tgconsumer/tx.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"unsafe"
+
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+)
+
+// Limits of PopularityConfig.
+const (
+	// MaxCatalogSize is the maximum catalog size.
+	MaxCatalogSize = math.MaxUint32
+
+	// MaxZipfCatalogSize is the maximum catalog size in Zipf distribution.
+	// Each catalog item occupies 4 octets in the cumulative probability table.
+	MaxZipfCatalogSize = 1 << 22
+)
+
+// Defaults of PopularityConfig.
+const (
+	DefaultZipfAlpha      = 1.0
+	DefaultHotProbability = 0.8
+)
+
+// PopularityDistribution indicates the popularity distribution of names.
+type PopularityDistribution string
+
+// PopularityDistribution values.
+const (
+	// PopularityZipf selects catalog item of rank k with probability proportional to 1/k^Alpha.
+	PopularityZipf PopularityDistribution = "zipf"
+
+	// PopularityUniform selects every catalog item with equal probability.
+	PopularityUniform PopularityDistribution = "uniform"
+
+	// PopularityHotCold selects from the hot set with HotProbability, otherwise from the cold set.
+	// Items within each set are selected with equal probability.
+	PopularityHotCold PopularityDistribution = "hotcold"
+)
+
+// PopularityConfig configures popularity-based name generation.
+//
+// The catalog contains CatalogSize names, each ending with a distinct sequence number.
+// Every time the pattern is selected, a catalog item is chosen according to the distribution.
+type PopularityConfig struct {
+	Distribution PopularityDistribution `json:"distribution"`
+
+	// CatalogSize is the number of catalog items.
+	// It must be between 1 and MaxCatalogSize, or MaxZipfCatalogSize in Zipf distribution.
+	CatalogSize int `json:"catalogSize"`
+
+	// Alpha is the exponent of Zipf distribution.
+	// Default is DefaultZipfAlpha.
+	Alpha float64 `json:"alpha,omitempty"`
+
+	// HotSize is the number of items in the hot set, in HotCold distribution.
+	// It must be less than CatalogSize.
+	// Default is 20% of CatalogSize.
+	HotSize int `json:"hotSize,omitempty"`
+
+	// HotProbability is the probability of selecting from the hot set, in HotCold distribution.
+	// Default is DefaultHotProbability.
+	HotProbability float64 `json:"hotProbability,omitempty"`
+}
+
+func (cfg *PopularityConfig) applyDefaults() {
+	switch cfg.Distribution {
+	case PopularityZipf:
+		if cfg.Alpha <= 0 {
+			cfg.Alpha = DefaultZipfAlpha
+		}
+	case PopularityHotCold:
+		if cfg.HotSize <= 0 {
+			cfg.HotSize = max(1, cfg.CatalogSize/5)
+		}
+		if cfg.HotProbability <= 0 {
+			cfg.HotProbability = DefaultHotProbability
+		}
+	}
+}
+
+func (cfg PopularityConfig) validate() error {
+	maxCatalogSize := MaxCatalogSize
+	switch cfg.Distribution {
+	case PopularityZipf:
+		maxCatalogSize = MaxZipfCatalogSize
+	case PopularityUniform:
+	case PopularityHotCold:
+		if cfg.HotSize >= cfg.CatalogSize {
+			return errors.New("HotSize must be less than CatalogSize")
+		}
+		if cfg.HotProbability > 1 {
+			return errors.New("HotProbability must not exceed 1")
+		}
+	default:
+		return fmt.Errorf("unknown popularity distribution %s", cfg.Distribution)
+	}
+
+	if cfg.CatalogSize < 1 || cfg.CatalogSize > maxCatalogSize {
+		return fmt.Errorf("CatalogSize must be between 1 and %d", maxCatalogSize)
+	}
+	return nil
+}
+
+// makeSuffix returns the C function that generates name suffix.
+func (cfg PopularityConfig) makeSuffix() C.TgcTxPattern_MakeSuffix {
+	switch cfg.Distribution {
+	case PopularityZipf:
+		return C.TgcTxPattern_MakeSuffix(C.TgcTxPattern_MakeSuffix_Zipf)
+	case PopularityHotCold:
+		return C.TgcTxPattern_MakeSuffix(C.TgcTxPattern_MakeSuffix_HotCold)
+	default:
+		return C.TgcTxPattern_MakeSuffix(C.TgcTxPattern_MakeSuffix_Uniform)
+	}
+}
+
+// fillZipfCdf computes cumulative probabilities of Zipf distribution in UINT32_MAX scale.
+func fillZipfCdf(cdf []C.uint32_t, alpha float64) {
+	sum := 0.0
+	for k := range cdf {
+		sum += math.Pow(float64(k+1), -alpha)
+	}
+
+	acc := 0.0
+	for k := range cdf {
+		acc += math.Pow(float64(k+1), -alpha)
+		cdf[k] = C.uint32_t(min(acc/sum*(1<<32), math.MaxUint32))
+	}
+}
+
+// newPopularity allocates and initializes TgcTxPopularity.
+func (c *Consumer) newPopularity(cfg PopularityConfig) (pop *C.TgcTxPopularity) {
+	size := C.sizeof_TgcTxPopularity
+	if cfg.Distribution == PopularityZipf {
+		size += cfg.CatalogSize * C.sizeof_uint32_t
+	}
+	pop = eal.Zmalloc[C.TgcTxPopularity]("TgcTxPopularity", size, c.socket)
+	pop.seqNumBase = C.uint64_t(rand.Uint64())
+	pop.catalogSize = C.uint32_t(cfg.CatalogSize)
+
+	switch cfg.Distribution {
+	case PopularityZipf:
+		cdf := unsafe.Slice((*C.uint32_t)(unsafe.Add(unsafe.Pointer(pop), C.sizeof_TgcTxPopularity)), cfg.CatalogSize)
+		fillZipfCdf(cdf, cfg.Alpha)
+	case PopularityHotCold:
+		pop.hotSize = C.uint32_t(cfg.HotSize)
+		pop.hotThreshold = C.uint32_t(min(cfg.HotProbability*(1<<32), math.MaxUint32))
+	}
+
+	c.popularities = append(c.popularities, pop)
+	return pop
+}
+
+func (c *Consumer) closePopularity() {
+	for _, pop := range c.popularities {
+		eal.Free(pop)
+	}
+	c.popularities = nil
+}
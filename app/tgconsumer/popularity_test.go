@@ -0,0 +1,165 @@
+package tgconsumer_test
+
+import (
+	"encoding/binary"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/app/tg/tgtestenv"
+	"github.com/usnistgov/ndn-dpdk/app/tgconsumer"
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
+	"github.com/usnistgov/ndn-dpdk/iface/intface"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndni"
+)
+
+func TestPopularity(t *testing.T) {
+	assert, require := makeAR(t)
+
+	face := intface.MustNew()
+	defer face.D.Close()
+
+	nameZ, nameU, nameH := ndn.ParseName("/Z"), ndn.ParseName("/U"), ndn.ParseName("/H")
+	cfg := tgconsumer.Config{
+		Interval: nnduration.Nanoseconds(100 * time.Microsecond),
+		Patterns: []tgconsumer.Pattern{
+			{
+				InterestTemplateConfig: ndni.InterestTemplateConfig{
+					Prefix: nameZ,
+				},
+				Popularity: &tgconsumer.PopularityConfig{
+					Distribution: tgconsumer.PopularityZipf,
+					CatalogSize:  100,
+					Alpha:        1.2,
+				},
+				CsHitRtt: nnduration.Nanoseconds(time.Hour),
+			},
+			{
+				InterestTemplateConfig: ndni.InterestTemplateConfig{
+					Prefix: nameU,
+				},
+				Popularity: &tgconsumer.PopularityConfig{
+					Distribution: tgconsumer.PopularityUniform,
+					CatalogSize:  50,
+				},
+			},
+			{
+				InterestTemplateConfig: ndni.InterestTemplateConfig{
+					Prefix: nameH,
+				},
+				Popularity: &tgconsumer.PopularityConfig{
+					Distribution:   tgconsumer.PopularityHotCold,
+					CatalogSize:    1000,
+					HotSize:        10,
+					HotProbability: 0.9,
+				},
+			},
+		},
+	}
+
+	c, e := tgconsumer.New(face.D, cfg)
+	require.NoError(e)
+	defer c.Close()
+	tgtestenv.Open(t, c)
+
+	seqNums := map[string]map[uint64]int{
+		nameZ.String(): {},
+		nameU.String(): {},
+		nameH.String(): {},
+	}
+	go func() {
+		for packet := range face.Rx {
+			require.NotNil(packet.Interest)
+			interest := *packet.Interest
+			if assert.Len(interest.Name, 2) {
+				seqNums[interest.Name.GetPrefix(1).String()][binary.LittleEndian.Uint64(interest.Name[1].Value)]++
+			}
+			face.Tx <- ndn.MakeData(interest)
+		}
+	}()
+
+	c.Launch()
+	time.Sleep(900 * time.Millisecond)
+	e = c.StopDelay(100 * time.Millisecond)
+	assert.NoError(e)
+
+	// returns number of distinct names, total count, and counts sorted in descending order
+	analyze := func(m map[uint64]int) (distinct, total int, counts []int) {
+		for _, cnt := range m {
+			total += cnt
+			counts = append(counts, cnt)
+		}
+		slices.SortFunc(counts, func(a, b int) int { return b - a })
+		return len(m), total, counts
+	}
+
+	distinctZ, totalZ, countsZ := analyze(seqNums[nameZ.String()])
+	require.Greater(totalZ, 2000)
+	assert.LessOrEqual(distinctZ, 100)
+	assert.InDelta(0.28, float64(countsZ[0])/float64(totalZ), 0.05)
+	assert.Greater(countsZ[0], 2*countsZ[1])
+
+	distinctU, totalU, countsU := analyze(seqNums[nameU.String()])
+	require.Greater(totalU, 2000)
+	assert.Equal(50, distinctU)
+	assert.InDelta(float64(totalU)/50, countsU[0], float64(totalU)/50)
+
+	distinctH, totalH, countsH := analyze(seqNums[nameH.String()])
+	require.Greater(totalH, 2000)
+	assert.LessOrEqual(distinctH, 1000)
+	nHot := 0
+	for _, cnt := range countsH[:10] {
+		nHot += cnt
+	}
+	assert.InDelta(0.9, float64(nHot)/float64(totalH), 0.05)
+
+	cnt := c.Counters()
+	require.Len(cnt.PerPattern, 3)
+	assert.InDelta(totalZ, cnt.PerPattern[0].NData, 100)
+	assert.Equal(cnt.PerPattern[0].NData, cnt.PerPattern[0].NCsHits)
+	assert.InDelta(1.0, cnt.PerPattern[0].CsHitRatio(), 0.001)
+	assert.EqualValues(time.Hour, cnt.PerPattern[0].CsHitRtt)
+	for _, pcnt := range cnt.PerPattern {
+		var nHist uint64
+		for _, b := range pcnt.RttHistogram {
+			assert.Less(b.Lower, b.Upper)
+			nHist += b.Count
+		}
+		assert.Equal(pcnt.NData, nHist)
+		assert.LessOrEqual(pcnt.NCsHits, pcnt.NData)
+	}
+}
+
+func TestPopularityConfig(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	makeCfg := func(pop tgconsumer.PopularityConfig) tgconsumer.Config {
+		return tgconsumer.Config{
+			Patterns: []tgconsumer.Pattern{{
+				InterestTemplateConfig: ndni.InterestTemplateConfig{Prefix: ndn.ParseName("/P")},
+				Popularity:             &pop,
+			}},
+		}
+	}
+
+	cfg := makeCfg(tgconsumer.PopularityConfig{Distribution: tgconsumer.PopularityHotCold, CatalogSize: 100})
+	if assert.NoError(cfg.Validate()) {
+		assert.Equal(20, cfg.Patterns[0].Popularity.HotSize)
+		assert.Equal(tgconsumer.DefaultHotProbability, cfg.Patterns[0].Popularity.HotProbability)
+	}
+
+	cfg = makeCfg(tgconsumer.PopularityConfig{Distribution: tgconsumer.PopularityZipf, CatalogSize: 100})
+	if assert.NoError(cfg.Validate()) {
+		assert.Equal(tgconsumer.DefaultZipfAlpha, cfg.Patterns[0].Popularity.Alpha)
+	}
+
+	cfg = makeCfg(tgconsumer.PopularityConfig{Distribution: tgconsumer.PopularityZipf, CatalogSize: tgconsumer.MaxZipfCatalogSize + 1})
+	assert.Error(cfg.Validate())
+	cfg = makeCfg(tgconsumer.PopularityConfig{Distribution: tgconsumer.PopularityUniform})
+	assert.Error(cfg.Validate())
+	cfg = makeCfg(tgconsumer.PopularityConfig{Distribution: tgconsumer.PopularityHotCold, CatalogSize: 10, HotSize: 10})
+	assert.Error(cfg.Validate())
+	cfg = makeCfg(tgconsumer.PopularityConfig{Distribution: "pareto", CatalogSize: 10})
+	assert.Error(cfg.Validate())
+}
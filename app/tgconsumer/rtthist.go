@@ -0,0 +1,125 @@
+package tgconsumer
+
+import (
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+)
+
+// Minimum fraction of Data in each RTT class for CsHitRtt inference.
+const csHitMinClassRatio = 0.05
+
+// Maximum ratio between the valley and the smaller peak for CsHitRtt inference.
+const csHitMaxValleyRatio = 0.25
+
+// RttBucket is a bucket in RttHistogram.
+type RttBucket struct {
+	Lower nnduration.Nanoseconds `json:"lower" gqldesc:"RTT lower bound, inclusive."`
+	Upper nnduration.Nanoseconds `json:"upper" gqldesc:"RTT upper bound, exclusive."`
+	Count uint64                 `json:"count"`
+}
+
+// RttHistogram is a histogram of Data RTT.
+// Bucket boundaries are powers of two in TSC units.
+// It contains buckets between the lowest and highest non-empty buckets, in ascending order.
+type RttHistogram []RttBucket
+
+func newRttHistogram(counts []uint64) (h RttHistogram) {
+	first, last := -1, -1
+	for i, n := range counts {
+		if n == 0 {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		last = i
+	}
+	if first < 0 {
+		return nil
+	}
+
+	toNanos := func(tsc uint64) nnduration.Nanoseconds {
+		return nnduration.Nanoseconds(float64(tsc) * eal.TscNanos)
+	}
+	for i := first; i <= last; i++ {
+		b := RttBucket{Upper: toNanos(uint64(1) << i), Count: counts[i]}
+		if i > 0 {
+			b.Lower = toNanos(uint64(1) << (i - 1))
+		}
+		h = append(h, b)
+	}
+	return h
+}
+
+// InferCsHitRtt infers an RTT threshold that separates Content Store hits from other Data.
+//
+// The histogram is expected to be bimodal: Data retrieved from the Content Store of a nearby
+// forwarder form the lower-RTT mode, while Data retrieved from the producer form the higher-RTT mode.
+// The histogram is divided into two classes with Otsu's method over bucket indices, which is on
+// logarithmic scale of RTT. The threshold is the lower bound of the emptiest bucket between the
+// peaks of the two classes.
+//
+// ok is false if the histogram is not clearly bimodal, including when either class has fewer than
+// 5% of Data, or the valley between the peaks is not deeper than a quarter of the smaller peak.
+// In this case, Content Store hits and misses cannot be distinguished, and csHitRtt should be
+// configured on the pattern.
+func (h RttHistogram) InferCsHitRtt() (threshold nnduration.Nanoseconds, ok bool) {
+	var total, sumIndex float64
+	for i, b := range h {
+		total += float64(b.Count)
+		sumIndex += float64(i) * float64(b.Count)
+	}
+
+	split, bestVar := 0, 0.0
+	var w0, sum0 float64
+	for s := 1; s < len(h); s++ {
+		w0 += float64(h[s-1].Count)
+		sum0 += float64(s-1) * float64(h[s-1].Count)
+		w1 := total - w0
+		if w0 < total*csHitMinClassRatio || w1 < total*csHitMinClassRatio {
+			continue
+		}
+		mean0, mean1 := sum0/w0, (sumIndex-sum0)/w1
+		if v := w0 * w1 * (mean0 - mean1) * (mean0 - mean1); v > bestVar {
+			split, bestVar = s, v
+		}
+	}
+	if split == 0 {
+		return 0, false
+	}
+
+	argmax := func(first, last int) (index int) {
+		index = first
+		for i := first; i < last; i++ {
+			if h[i].Count > h[index].Count {
+				index = i
+			}
+		}
+		return index
+	}
+	peakLo, peakHi := argmax(0, split), argmax(split, len(h))
+	if peakHi-peakLo < 2 {
+		return 0, false
+	}
+
+	valley := peakLo + 1
+	for i := valley; i < peakHi; i++ {
+		if h[i].Count < h[valley].Count {
+			valley = i
+		}
+	}
+	if float64(h[valley].Count) > csHitMaxValleyRatio*float64(min(h[peakLo].Count, h[peakHi].Count)) {
+		return 0, false
+	}
+	return h[valley].Lower, true
+}
+
+// countBelow returns the number of Data in buckets whose upper bound does not exceed threshold.
+func (h RttHistogram) countBelow(threshold nnduration.Nanoseconds) (n uint64) {
+	for _, b := range h {
+		if b.Upper <= threshold {
+			n += b.Count
+		}
+	}
+	return n
+}
@@ -0,0 +1,44 @@
+package tgconsumer_test
+
+import (
+	"testing"
+
+	"github.com/usnistgov/ndn-dpdk/app/tgconsumer"
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
+)
+
+func makeRttHistogram(counts ...uint64) (h tgconsumer.RttHistogram) {
+	for i, n := range counts {
+		h = append(h, tgconsumer.RttBucket{
+			Lower: nnduration.Nanoseconds(1000 << i),
+			Upper: nnduration.Nanoseconds(2000 << i),
+			Count: n,
+		})
+	}
+	return h
+}
+
+func TestRttHistogramInfer(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	threshold, ok := makeRttHistogram(5, 300, 600, 20, 0, 3, 200, 800, 150).InferCsHitRtt()
+	if assert.True(ok) {
+		assert.EqualValues(1000<<4, threshold)
+	}
+
+	threshold, ok = makeRttHistogram(900, 50, 0, 0, 0, 60).InferCsHitRtt()
+	if assert.True(ok) {
+		assert.EqualValues(1000<<2, threshold)
+	}
+
+	_, ok = makeRttHistogram().InferCsHitRtt()
+	assert.False(ok, "empty")
+	_, ok = makeRttHistogram(100, 800, 120).InferCsHitRtt()
+	assert.False(ok, "unimodal")
+	_, ok = makeRttHistogram(500, 600).InferCsHitRtt()
+	assert.False(ok, "adjacent peaks")
+	_, ok = makeRttHistogram(500, 300, 400).InferCsHitRtt()
+	assert.False(ok, "shallow valley")
+	_, ok = makeRttHistogram(990, 0, 0, 0, 0, 0, 0, 10).InferCsHitRtt()
+	assert.False(ok, "long tail")
+}
@@ -0,0 +1,304 @@
+package tgconsumer
+
+/*
+#include "../../csrc/tgconsumer/tx.h"
+*/
+import "C"
+import (
+	"cmp"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/pcapgo"
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/ndnlayer"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+	"github.com/usnistgov/ndn-dpdk/ndni"
+	"go.uber.org/zap"
+)
+
+// TraceFormat indicates trace file format.
+type TraceFormat string
+
+// TraceFormat values.
+const (
+	// TraceCSV is a CSV file with columns: timestamp in seconds, Interest name in URI format,
+	// and optional flags ("P" for CanBePrefix, "F" for MustBeFresh).
+	// Lines starting with "#" are comments.
+	TraceCSV TraceFormat = "csv"
+
+	// TracePcapng is a pcapng file, such as one written by package pdump.
+	// Every Interest packet in the file is replayed, regardless of capture interface and direction.
+	TracePcapng TraceFormat = "pcapng"
+)
+
+// TraceConfig configures trace-driven Interest generation.
+type TraceConfig struct {
+	// Filename is the trace file name.
+	Filename string `json:"filename"`
+
+	// Format is the trace file format.
+	// Default is TracePcapng if Filename ends with ".pcapng", otherwise TraceCSV.
+	Format TraceFormat `json:"format,omitempty"`
+
+	// Speedup is the speed-up factor applied to recorded timing.
+	// For example, 2.0 replays the trace in half of the recorded duration.
+	// Default is 1.0.
+	Speedup float64 `json:"speedup,omitempty"`
+
+	// Loop enables looping: after the last record, the trace is restarted from the first record.
+	Loop bool `json:"loop,omitempty"`
+}
+
+func (cfg *TraceConfig) applyDefaults() {
+	if cfg.Format == "" {
+		if strings.EqualFold(filepath.Ext(cfg.Filename), ".pcapng") {
+			cfg.Format = TracePcapng
+		} else {
+			cfg.Format = TraceCSV
+		}
+	}
+	if cfg.Speedup <= 0 {
+		cfg.Speedup = 1.0
+	}
+}
+
+func (cfg TraceConfig) validate() error {
+	if cfg.Filename == "" {
+		return errors.New("trace filename is missing")
+	}
+	if cfg.Format != TraceCSV && cfg.Format != TracePcapng {
+		return fmt.Errorf("unknown trace format %s", cfg.Format)
+	}
+	return nil
+}
+
+// TraceRecord is an Interest in a trace.
+type TraceRecord struct {
+	// Time is the transmission time relative to the first record, before applying speed-up factor.
+	Time time.Duration
+	Name ndn.Name
+
+	CanBePrefix bool
+	MustBeFresh bool
+}
+
+// ReadTrace reads trace records from a file.
+// Records are sorted by time, and Time fields are relative to the first record.
+func ReadTrace(cfg TraceConfig) (records []TraceRecord, e error) {
+	cfg.applyDefaults()
+	if e = cfg.validate(); e != nil {
+		return nil, e
+	}
+
+	f, e := os.Open(cfg.Filename)
+	if e != nil {
+		return nil, e
+	}
+	defer f.Close()
+
+	switch cfg.Format {
+	case TraceCSV:
+		records, e = readTraceCSV(f)
+	case TracePcapng:
+		records, e = readTracePcapng(f)
+	}
+	if e != nil {
+		return nil, fmt.Errorf("%s: %w", cfg.Filename, e)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s: trace is empty", cfg.Filename)
+	}
+
+	slices.SortStableFunc(records, func(a, b TraceRecord) int { return cmp.Compare(a.Time, b.Time) })
+	t0 := records[0].Time
+	for i := range records {
+		records[i].Time -= t0
+	}
+	return records, nil
+}
+
+func readTraceCSV(r io.Reader) (records []TraceRecord, e error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	for {
+		row, e := cr.Read()
+		if errors.Is(e, io.EOF) {
+			return records, nil
+		}
+		if e != nil {
+			return nil, e
+		}
+		if len(row) < 2 {
+			line, _ := cr.FieldPos(0)
+			return nil, fmt.Errorf("line %d: expect timestamp,name[,flags]", line)
+		}
+
+		ts, e := strconv.ParseFloat(row[0], 64)
+		if e != nil {
+			line, _ := cr.FieldPos(0)
+			return nil, fmt.Errorf("line %d: bad timestamp: %w", line, e)
+		}
+		rec := TraceRecord{
+			Time: time.Duration(ts * float64(time.Second)),
+			Name: ndn.ParseName(row[1]),
+		}
+		if len(row) >= 3 {
+			rec.CanBePrefix = strings.ContainsRune(row[2], 'P')
+			rec.MustBeFresh = strings.ContainsRune(row[2], 'F')
+		}
+		records = append(records, rec)
+	}
+}
+
+func readTracePcapng(r io.Reader) (records []TraceRecord, e error) {
+	nr, e := pcapgo.NewNgReader(r, pcapgo.DefaultNgReaderOptions)
+	if e != nil {
+		return nil, e
+	}
+
+	var t0 time.Time
+	for {
+		wire, ci, e := nr.ReadPacketData()
+		if errors.Is(e, io.EOF) {
+			return records, nil
+		}
+		if e != nil {
+			return nil, e
+		}
+
+		intf, e := nr.Interface(ci.InterfaceIndex)
+		if e != nil {
+			return nil, e
+		}
+		pkt := gopacket.NewPacket(wire, intf.LinkType, gopacket.NoCopy)
+		l, ok := pkt.Layer(ndnlayer.LayerTypeNDN).(*ndnlayer.NDN)
+		if !ok || l.Packet.Interest == nil {
+			continue
+		}
+
+		if t0.IsZero() {
+			t0 = ci.Timestamp
+		}
+		interest := l.Packet.Interest
+		records = append(records, TraceRecord{
+			Time:        ci.Timestamp.Sub(t0),
+			Name:        interest.Name,
+			CanBePrefix: interest.CanBePrefix,
+			MustBeFresh: interest.MustBeFresh,
+		})
+	}
+}
+
+// matchTracePattern finds the pattern with longest prefix that matches a trace record.
+// Pattern CanBePrefix and MustBeFresh must equal record flags.
+// Returns -1 if no pattern matches.
+func matchTracePattern(patterns []Pattern, rec TraceRecord) (id int) {
+	id = -1
+	for i, pattern := range patterns {
+		if pattern.CanBePrefix == rec.CanBePrefix && pattern.MustBeFresh == rec.MustBeFresh &&
+			pattern.Prefix.IsPrefixOf(rec.Name) && (id < 0 || len(pattern.Prefix) > len(patterns[id].Prefix)) {
+			id = i
+		}
+	}
+	return id
+}
+
+// loadTrace reads the trace file and prepares TgcTrace for the TX thread.
+func (c *Consumer) loadTrace() error {
+	cfg := *c.cfg.Trace
+	records, e := ReadTrace(cfg)
+	if e != nil {
+		return e
+	}
+
+	type prepared struct {
+		rec     C.TgcTraceRecord
+		suffixV []byte
+	}
+	entries := []prepared{}
+	nUnmatched, suffixesLen := 0, 0
+	for _, rec := range records {
+		id := matchTracePattern(c.cfg.Patterns, rec)
+		if id < 0 {
+			nUnmatched++
+			continue
+		}
+
+		suffixV, _ := tlv.EncodeValueOnly(rec.Name[len(c.cfg.Patterns[id].Prefix):].Field())
+		if prefixV, _ := tlv.EncodeValueOnly(c.cfg.Patterns[id].Prefix.Field()); len(prefixV)+len(suffixV) > ndni.NameMaxLength {
+			return fmt.Errorf("trace name %s too long", rec.Name)
+		}
+
+		offset := time.Duration(float64(rec.Time) / cfg.Speedup)
+		entries = append(entries, prepared{
+			rec: C.TgcTraceRecord{
+				offset:       C.TscDuration(eal.ToTscDuration(offset)),
+				suffixOffset: C.uint32_t(suffixesLen),
+				suffixL:      C.uint16_t(len(suffixV)),
+				patternID:    C.uint8_t(id),
+			},
+			suffixV: suffixV,
+		})
+		suffixesLen += len(suffixV)
+	}
+	if len(entries) == 0 {
+		return errors.New("no trace record matches any pattern")
+	}
+	if len(entries) > math.MaxUint32 || suffixesLen > math.MaxUint32 {
+		return errors.New("trace too large")
+	}
+
+	recordsLen := len(entries) * C.sizeof_TgcTraceRecord
+	trace := eal.Zmalloc[C.TgcTrace]("TgcTrace", C.sizeof_TgcTrace+recordsLen+suffixesLen, c.socket)
+	recordsC := unsafe.Slice((*C.TgcTraceRecord)(unsafe.Add(unsafe.Pointer(trace), C.sizeof_TgcTrace)), len(entries))
+	suffixesC := unsafe.Slice((*byte)(unsafe.Add(unsafe.Pointer(trace), C.sizeof_TgcTrace+recordsLen)), suffixesLen)
+	for i, entry := range entries {
+		recordsC[i] = entry.rec
+		copy(suffixesC[entry.rec.suffixOffset:], entry.suffixV)
+	}
+	trace.suffixes = (*C.uint8_t)(unsafe.SliceData(suffixesC))
+	trace.nRecords = C.uint32_t(len(entries))
+	trace.loop = C.bool(cfg.Loop)
+
+	// one iteration lasts until the last record plus the average gap between records
+	last := entries[len(entries)-1].rec.offset
+	if len(entries) > 1 {
+		trace.period = last + last/C.TscDuration(len(entries)-1)
+	} else {
+		trace.period = C.TscDuration(eal.ToTscDuration(defaultInterval))
+	}
+	trace.period = max(trace.period, 1)
+
+	c.txC.trace = trace
+	c.rxC.anySuffix = true
+
+	logger.Info("trace loaded",
+		zap.String("filename", cfg.Filename),
+		zap.Int("records", len(entries)),
+		zap.Int("unmatched", nUnmatched),
+		zap.Duration("duration", eal.FromTscDuration(int64(last))),
+	)
+	return nil
+}
+
+func (c *Consumer) closeTrace() {
+	if c.txC.trace != nil {
+		eal.Free(c.txC.trace)
+		c.txC.trace = nil
+	}
+}
@@ -0,0 +1,116 @@
+package tgconsumer_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/app/tg/tgtestenv"
+	"github.com/usnistgov/ndn-dpdk/app/tgconsumer"
+	"github.com/usnistgov/ndn-dpdk/iface/intface"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndni"
+)
+
+func writeTestTrace(t testing.TB, n int) (filename string) {
+	var b strings.Builder
+	b.WriteString("# timestamp,name,flags\n")
+	for i := range n {
+		flags := ""
+		if i%4 == 0 {
+			flags = "F"
+		}
+		fmt.Fprintf(&b, "%0.6f,/A/%d,%s\n", 1000.0+float64(i)*0.001, i, flags)
+	}
+	fmt.Fprintf(&b, "%0.6f,/Z/0\n", 1000.0+float64(n)*0.001)
+
+	filename = filepath.Join(t.TempDir(), "trace.csv")
+	if e := os.WriteFile(filename, []byte(b.String()), 0o644); e != nil {
+		t.Fatal(e)
+	}
+	return filename
+}
+
+func TestReadTrace(t *testing.T) {
+	assert, require := makeAR(t)
+
+	records, e := tgconsumer.ReadTrace(tgconsumer.TraceConfig{Filename: writeTestTrace(t, 20)})
+	require.NoError(e)
+	require.Len(records, 21)
+	assert.Zero(records[0].Time)
+	assert.InDelta(5*time.Millisecond, records[5].Time, float64(time.Microsecond))
+	nameEqual(assert, "/A/4", records[4].Name)
+	assert.True(records[4].MustBeFresh)
+	assert.False(records[5].MustBeFresh)
+	assert.False(records[5].CanBePrefix)
+	nameEqual(assert, "/Z/0", records[20].Name)
+
+	_, e = tgconsumer.ReadTrace(tgconsumer.TraceConfig{Filename: filepath.Join(t.TempDir(), "missing.csv")})
+	assert.Error(e)
+}
+
+func TestConsumerTrace(t *testing.T) {
+	assert, require := makeAR(t)
+
+	face := intface.MustNew()
+	defer face.D.Close()
+
+	nameA := ndn.ParseName("/A")
+	cfg := tgconsumer.Config{
+		Patterns: []tgconsumer.Pattern{
+			{
+				InterestTemplateConfig: ndni.InterestTemplateConfig{
+					Prefix: nameA,
+				},
+			},
+			{
+				InterestTemplateConfig: ndni.InterestTemplateConfig{
+					Prefix:      nameA,
+					MustBeFresh: true,
+				},
+			},
+		},
+		Trace: &tgconsumer.TraceConfig{
+			Filename: writeTestTrace(t, 100),
+			Speedup:  2,
+			Loop:     true,
+		},
+	}
+
+	c, e := tgconsumer.New(face.D, cfg)
+	require.NoError(e)
+	defer c.Close()
+	tgtestenv.Open(t, c)
+
+	nInterests, nFresh := 0, 0
+	go func() {
+		for packet := range face.Rx {
+			require.NotNil(packet.Interest)
+			interest := *packet.Interest
+			if assert.Len(interest.Name, 2) && nameA.IsPrefixOf(interest.Name) {
+				nInterests++
+				if interest.MustBeFresh {
+					nFresh++
+				}
+			}
+			face.Tx <- ndn.MakeData(interest)
+		}
+	}()
+
+	c.Launch()
+	time.Sleep(500 * time.Millisecond)
+	e = c.StopDelay(100 * time.Millisecond)
+	assert.NoError(e)
+
+	// each iteration has 100 records in 50.5ms
+	assert.InDelta(1000, nInterests, 200)
+	assert.InDelta(float64(nInterests)/4, nFresh, 20)
+
+	cnt := c.Counters()
+	require.Len(cnt.PerPattern, 2)
+	assert.InDelta(nInterests-nFresh, cnt.PerPattern[0].NData, 10)
+	assert.InDelta(nFresh, cnt.PerPattern[1].NData, 10)
+}
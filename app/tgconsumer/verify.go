@@ -0,0 +1,191 @@
+package tgconsumer
+
+/*
+#include "../../csrc/tgconsumer/rx.h"
+#include "../../csrc/tgconsumer/tx.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+	"github.com/usnistgov/ndn-dpdk/dpdk/ringbuffer"
+	"github.com/usnistgov/ndn-dpdk/ndni"
+)
+
+// Limits and defaults of VerifyConfig.
+const (
+	MinVerifyWindow     = 64
+	MaxVerifyWindow     = 1 << 22
+	DefaultVerifyWindow = 1 << 16
+)
+
+// VerifyConfig configures verifying mode.
+//
+// In verifying mode, the consumer checks every Data packet and counts network faults:
+//   - unsolicited Data, which do not carry a PIT token generated by the consumer, or whose sequence
+//     number has not been requested;
+//   - duplicate Data, whose sequence number has been received within the window;
+//   - wrong names, which do not start with the pattern prefix, do not contain a sequence number,
+//     or have a name suffix despite CanBePrefix being unset;
+//   - digest mismatches, whose implicit digest differs from the Interest name;
+//   - signature failures, which do not pass signature verification.
+//
+// Data packets that fail any verification are excluded from Data counter and RTT measurement.
+// Unsolicited and duplicate Data can only be detected on patterns that increment sequence numbers,
+// i.e. without SeqNumOffset or Popularity.
+type VerifyConfig struct {
+	// WindowSize is the number of sequence numbers tracked per pattern.
+	// It should exceed the number of Interests sent under a pattern within the maximum RTT.
+	// This is adjusted to a power of two between MinVerifyWindow and MaxVerifyWindow.
+	// Default is DefaultVerifyWindow.
+	WindowSize int `json:"windowSize,omitempty"`
+
+	// Signing enables Data signature verification.
+	// Type must be the same as the producer's signing config.
+	// KeyFile should contain the public key or certificate that corresponds to the producer's private key,
+	// or the same secret as the producer for HMAC.
+	// This cannot be used together with Digest patterns.
+	Signing *ndni.VerifyingConfig `json:"signing,omitempty"`
+}
+
+func (cfg *VerifyConfig) validate(nDigestPatterns int) error {
+	cfg.WindowSize = ringbuffer.AlignCapacity(cfg.WindowSize, MinVerifyWindow, DefaultVerifyWindow, MaxVerifyWindow)
+
+	if cfg.Signing == nil {
+		return nil
+	}
+	if nDigestPatterns > 0 {
+		return errors.New("signature verification cannot be used with Digest patterns")
+	}
+	return cfg.Signing.Validate()
+}
+
+// VerifyCounters contains verifying mode counters.
+type VerifyCounters struct {
+	NDuplicates       uint64 `json:"nDuplicates" gqldesc:"Duplicate Data, verifying mode only."`
+	NUnsolicited      uint64 `json:"nUnsolicited" gqldesc:"Unsolicited Data, verifying mode only."`
+	NWrongNames       uint64 `json:"nWrongNames" gqldesc:"Data with wrong names, verifying mode only."`
+	NDigestMismatches uint64 `json:"nDigestMismatches" gqldesc:"Data with mismatched implicit digest, verifying mode only."`
+	NSigFailures      uint64 `json:"nSigFailures" gqldesc:"Data failing signature verification, verifying mode only."`
+}
+
+// Add combines counters.
+func (cnt VerifyCounters) Add(other VerifyCounters) VerifyCounters {
+	return VerifyCounters{
+		NDuplicates:       cnt.NDuplicates + other.NDuplicates,
+		NUnsolicited:      cnt.NUnsolicited + other.NUnsolicited,
+		NWrongNames:       cnt.NWrongNames + other.NWrongNames,
+		NDigestMismatches: cnt.NDigestMismatches + other.NDigestMismatches,
+		NSigFailures:      cnt.NSigFailures + other.NSigFailures,
+	}
+}
+
+// IsZero determines whether all counters are zero.
+func (cnt VerifyCounters) IsZero() bool {
+	return cnt == VerifyCounters{}
+}
+
+func (cnt VerifyCounters) String() string {
+	return fmt.Sprintf("%ddup %dunsolicited %dwrong-name %ddigest-mismatch %dsig-fail",
+		cnt.NDuplicates, cnt.NUnsolicited, cnt.NWrongNames, cnt.NDigestMismatches, cnt.NSigFailures)
+}
+
+func (c *Consumer) readVerifyCounters(index int) VerifyCounters {
+	crP := &c.rxC.pattern[index]
+	return VerifyCounters{
+		NDuplicates:       uint64(crP.nDuplicates),
+		NUnsolicited:      uint64(crP.nUnsolicited),
+		NWrongNames:       uint64(crP.nWrongNames),
+		NDigestMismatches: uint64(crP.nDigestMismatches),
+		NSigFailures:      uint64(crP.nSigFailures),
+	}
+}
+
+func (c *Consumer) clearVerifyCounters(index int) {
+	crP := &c.rxC.pattern[index]
+	crP.nDuplicates, crP.nUnsolicited, crP.nWrongNames, crP.nDigestMismatches, crP.nSigFailures = 0, 0, 0, 0, 0
+}
+
+// initVerify creates DataSigners for signature verification and implicit digest computation.
+func (c *Consumer) initVerify() (e error) {
+	cfg := c.cfg.Verify
+	if cfg == nil {
+		return nil
+	}
+	c.rxC.verifying = true
+
+	if cfg.Signing != nil {
+		verifier, e := ndni.NewDataVerifier(cfg.Signing, c.socket)
+		if e != nil {
+			return e
+		}
+		c.rxC.verifier = (*C.DataSigner)(verifier.Ptr())
+	}
+
+	if c.cfg.nDigestPatterns > 0 {
+		digestCfg := ndni.SigningConfig{Type: ndni.SigningSha256}
+		if e := digestCfg.Validate(); e != nil {
+			return e
+		}
+		digester, e := ndni.NewDataSigner(&digestCfg, c.socket)
+		if e != nil {
+			return e
+		}
+		c.rxC.digester = (*C.DataSigner)(digester.Ptr())
+	}
+	return nil
+}
+
+// newVerify allocates per-pattern verification state.
+func (c *Consumer) newVerify(pattern Pattern, rxP *C.TgcRxPattern, txP *C.TgcTxPattern) {
+	windowSize := c.cfg.Verify.WindowSize
+	v := eal.Zmalloc[C.TgcRxVerify]("TgcRxVerify", C.sizeof_TgcRxVerify+windowSize/8, c.socket)
+	v.hiSeqNum = txP.seqNumV
+	v.windowMask = C.uint32_t(windowSize - 1)
+
+	prefixV, _ := pattern.Prefix.MarshalBinary()
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(&v.prefixV[0])), len(v.prefixV)), prefixV)
+	if !pattern.CanBePrefix {
+		v.nameL = C.uint16_t(len(prefixV) + C.TgcSeqNumSize)
+	}
+
+	if pattern.SeqNumOffset == 0 && pattern.Popularity == nil {
+		v.txSeqNum = (*C.uint64_t)(unsafe.Pointer(&txP.seqNumV))
+	}
+	if pattern.Digest != nil {
+		v.digests = eal.Zmalloc[[ndni.ImplicitDigestLength]C.uint8_t]("TgcRxVerify.digests",
+			windowSize*ndni.ImplicitDigestLength, c.socket)
+	}
+
+	rxP.verify = v
+	c.verifies = append(c.verifies, v)
+}
+
+// assignVerifyDigests lets the digest pattern record implicit digests for verification.
+func assignVerifyDigests(dp *C.TgcTxDigestPattern, v *C.TgcRxVerify) {
+	if v == nil {
+		return
+	}
+	dp.digests = v.digests
+	dp.digestsMask = v.windowMask
+}
+
+func (c *Consumer) closeVerify() {
+	for _, v := range c.verifies {
+		if v.digests != nil {
+			eal.Free(v.digests)
+		}
+		eal.Free(v)
+	}
+	c.verifies = nil
+
+	for _, ptr := range []**C.DataSigner{&c.rxC.verifier, &c.rxC.digester} {
+		if *ptr != nil {
+			ndni.DataSignerFromPtr(unsafe.Pointer(*ptr)).Close()
+			*ptr = nil
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/usnistgov/ndn-dpdk/app/tg/tgdef"
 	"github.com/usnistgov/ndn-dpdk/iface"
 	"github.com/usnistgov/ndn-dpdk/ndn"
 	"github.com/usnistgov/ndn-dpdk/ndn/an"
@@ -43,6 +44,27 @@ type Config struct {
 	nDataGen int
 }
 
+// ValidateReplyWeights checks new reply weights for SetReplyWeights.
+// weights must have one entry per pattern, which is either nil to retain existing weights, or has one
+// entry per reply; zero disables a reply, but at least one weight in each pattern must be positive.
+func (cfg Config) ValidateReplyWeights(weights [][]int) error {
+	if len(weights) != len(cfg.Patterns) {
+		return fmt.Errorf("expect %d patterns", len(cfg.Patterns))
+	}
+	for i, pattern := range cfg.Patterns {
+		if weights[i] == nil {
+			continue
+		}
+		if len(weights[i]) != len(pattern.Replies) {
+			return fmt.Errorf("pattern %d: expect %d weights", i, len(pattern.Replies))
+		}
+		if e := tgdef.ValidateWeights(weights[i], MaxSumWeight); e != nil {
+			return fmt.Errorf("pattern %d: %w", i, e)
+		}
+	}
+	return nil
+}
+
 // Validate applies defaults and validates the configuration.
 func (cfg *Config) Validate() error {
 	cfg.NThreads = max(1, cfg.NThreads)
@@ -65,6 +87,11 @@ func (cfg *Config) Validate() error {
 		if pattern.Prefix.Length() > ndni.NameMaxLength {
 			return ErrPrefixTooLong
 		}
+		for i := range pattern.Replies {
+			if e := pattern.Replies[i].DataGenConfig.Validate(); e != nil {
+				return e
+			}
+		}
 		patterns = append(patterns, pattern)
 	}
 
@@ -3,6 +3,9 @@ package tgproducer
 import (
 	"fmt"
 	"strconv"
+	"unsafe"
+
+	"github.com/usnistgov/ndn-dpdk/ndni"
 )
 
 // PatternCounters contains per-pattern counters.
@@ -27,14 +30,18 @@ func (cnt PatternCounters) String() string {
 
 // Counters contains producer counters.
 type Counters struct {
-	PerPattern  []PatternCounters `json:"perPattern"`
-	NInterests  uint64            `json:"nInterests"`
-	NNoMatch    uint64            `json:"nNoMatch"`
-	NAllocError uint64            `json:"nAllocError"`
+	PerPattern  []PatternCounters   `json:"perPattern"`
+	NInterests  uint64              `json:"nInterests"`
+	NNoMatch    uint64              `json:"nNoMatch"`
+	NAllocError uint64              `json:"nAllocError"`
+	Signing     ndni.SignerCounters `json:"signing"`
 }
 
 func (cnt Counters) String() string {
 	s := fmt.Sprintf("%dI %dno-match %dalloc-error", cnt.NInterests, cnt.NNoMatch, cnt.NAllocError)
+	if cnt.Signing.Latency.Count > 0 || cnt.Signing.NErrors > 0 {
+		s += ", signing " + cnt.Signing.String()
+	}
 	for i, pcnt := range cnt.PerPattern {
 		s += fmt.Sprintf(", pattern(%d) %s", i, pcnt)
 	}
@@ -48,6 +55,9 @@ func (w *worker) accumulateCounters(cnt *Counters) {
 			replyC := patternC.reply[j]
 			pcnt.PerReply[j] += uint64(replyC.nInterests)
 			pcnt.NInterests += uint64(replyC.nInterests)
+			if signer := ndni.DataGenFromPtr(unsafe.Pointer(&replyC.dataGen)).Signer(); signer != nil {
+				cnt.Signing = cnt.Signing.Add(signer.Counters())
+			}
 		}
 		cnt.NInterests += pcnt.NInterests
 	}
@@ -67,3 +77,26 @@ func (p Producer) Counters() (cnt Counters) {
 	}
 	return cnt
 }
+
+// ClearCounters clears counters.
+// Workers should be stopped before calling this, otherwise race conditions may occur.
+func (p *Producer) ClearCounters() {
+	for _, w := range p.workers {
+		w.clearCounters()
+	}
+}
+
+func (w *worker) clearCounters() {
+	for i := range int(w.c.nPatterns) {
+		patternC := &w.c.pattern[i]
+		for j := range int(patternC.nReplies) {
+			replyC := &patternC.reply[j]
+			replyC.nInterests = 0
+			if signer := ndni.DataGenFromPtr(unsafe.Pointer(&replyC.dataGen)).Signer(); signer != nil {
+				signer.ClearCounters()
+			}
+		}
+	}
+	w.c.nNoMatch = 0
+	w.c.nAllocError = 0
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/usnistgov/ndn-dpdk/core/gqlserver"
 	"github.com/usnistgov/ndn-dpdk/iface"
 	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndni"
 )
 
 // GqlRetrieveByFaceID returns *Producer associated with a face.
@@ -29,7 +30,8 @@ func init() {
 		Name:        "TgpReplyInput",
 		Description: "Traffic generator producer reply definition.",
 		Fields: gqlserver.BindInputFields[Reply](gqlserver.FieldTypes{
-			reflect.TypeFor[ndn.Name](): gqlserver.NonNullString,
+			reflect.TypeFor[ndn.Name]():           gqlserver.NonNullString,
+			reflect.TypeFor[ndni.SigningConfig](): ndni.GqlSigningInput,
 		}),
 	})
 	GqlPatternInput = graphql.NewInputObject(graphql.InputObjectConfig{
@@ -56,7 +58,8 @@ func init() {
 	GqlCountersType = graphql.NewObject(graphql.ObjectConfig{
 		Name: "TgpCounters",
 		Fields: gqlserver.BindFields[Counters](gqlserver.FieldTypes{
-			reflect.TypeFor[PatternCounters](): GqlPatternCountersType,
+			reflect.TypeFor[PatternCounters]():     GqlPatternCountersType,
+			reflect.TypeFor[ndni.SignerCounters](): ndni.GqlSignerCountersType,
 		}),
 	})
 
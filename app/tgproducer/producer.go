@@ -4,6 +4,7 @@ package tgproducer
 import (
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/usnistgov/ndn-dpdk/app/tg/tgdef"
 	"github.com/usnistgov/ndn-dpdk/dpdk/ealthread"
@@ -38,6 +39,34 @@ func (p *Producer) initPatterns() error {
 	return nil
 }
 
+// SetReplyWeights changes reply weights.
+// weights must have one entry per pattern, which is either nil to retain existing weights, or has one
+// entry per reply; zero disables a reply, but at least one weight in each pattern must be positive.
+// Workers should be stopped before calling this, otherwise race conditions may occur.
+func (p *Producer) SetReplyWeights(weights [][]int) error {
+	if e := p.cfg.ValidateReplyWeights(weights); e != nil {
+		return e
+	}
+
+	patterns := make([]Pattern, len(p.cfg.Patterns))
+	for i, pattern := range p.cfg.Patterns {
+		patterns[i] = pattern
+		if weights[i] == nil {
+			continue
+		}
+		patterns[i].Replies = slices.Clone(pattern.Replies)
+		for k, weight := range weights[i] {
+			patterns[i].Replies[k].Weight = weight
+		}
+	}
+
+	p.cfg.Patterns = patterns
+	for _, w := range p.workers {
+		w.setWeights(patterns)
+	}
+	return nil
+}
+
 // Face returns the associated face.
 func (p Producer) Face() iface.Face {
 	return p.workers[0].face()
@@ -183,3 +183,59 @@ func TestDataProducer(t *testing.T) {
 	e = p.Stop()
 	assert.NoError(e)
 }
+
+func TestSetReplyWeights(t *testing.T) {
+	assert, require := makeAR(t)
+
+	face := intface.MustNew()
+	defer face.D.Close()
+
+	cfg := tgproducer.Config{
+		Patterns: []tgproducer.Pattern{
+			{
+				Prefix: ndn.ParseName("/A"),
+				Replies: []tgproducer.Reply{
+					{},
+					{Nack: an.NackNoRoute},
+				},
+			},
+		},
+	}
+
+	p, e := tgproducer.New(face.D, cfg)
+	require.NoError(e)
+	defer p.Close()
+	tgtestenv.Open(t, p)
+
+	assert.Error(p.SetReplyWeights(nil))
+	assert.Error(p.SetReplyWeights([][]int{{1}}))
+	assert.Error(p.SetReplyWeights([][]int{{0, 0}}))
+	assert.Error(p.SetReplyWeights([][]int{{-1, 2}}))
+	require.NoError(p.SetReplyWeights([][]int{{0, 1}}))
+	assert.Equal(0, p.Patterns()[0].Replies[0].Weight)
+	assert.Equal(1, p.Patterns()[0].Replies[1].Weight)
+
+	nData, nNacks := 0, 0
+	go func() {
+		for packet := range face.Rx {
+			switch {
+			case packet.Data != nil:
+				nData++
+			case packet.Nack != nil:
+				nNacks++
+			}
+		}
+	}()
+
+	p.Launch()
+	for i := range 100 {
+		face.Tx <- ndn.MakeInterest(fmt.Sprintf("/A/%d", i))
+		time.Sleep(50 * time.Microsecond)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	e = p.Stop()
+	assert.NoError(e)
+	assert.Zero(nData)
+	assert.Equal(100, nNacks)
+}
@@ -59,6 +59,12 @@ func (w *worker) setPatterns(patterns []Pattern, takeDataGenMbuf func() *pktmbuf
 	}
 }
 
+func (w *worker) setWeights(patterns []Pattern) {
+	for i, pattern := range patterns {
+		pattern.assignWeights(&w.c.pattern[i])
+	}
+}
+
 func (w *worker) close() error {
 	w.freeDataGen()
 	e := w.rxQueue().Close()
@@ -101,10 +107,15 @@ func (pattern Pattern) assign(c *C.TgpPattern, takeDataGenMbuf func() *pktmbuf.P
 		nReplies: C.uint8_t(len(pattern.Replies)),
 	}
 
-	w := 0
 	for k, reply := range pattern.Replies {
 		reply.assign(&c.reply[k], takeDataGenMbuf)
+	}
+	pattern.assignWeights(c)
+}
 
+func (pattern Pattern) assignWeights(c *C.TgpPattern) {
+	w := 0
+	for k, reply := range pattern.Replies {
 		for range reply.Weight {
 			c.weight[w] = C.TgpReplyID(k)
 			w++
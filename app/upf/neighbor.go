@@ -0,0 +1,90 @@
+package upf
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+
+	"github.com/usnistgov/ndn-dpdk/core/macaddr"
+	"github.com/vishvananda/netlink"
+)
+
+// ErrMacPending indicates that the MAC address of an N3 peer is being resolved.
+// The caller should retry later.
+var ErrMacPending = errors.New("MAC address resolution in progress")
+
+// Neighbor entry states that contain a usable MAC address.
+const neighValidStates = netlink.NUD_REACHABLE | netlink.NUD_STALE | netlink.NUD_DELAY | netlink.NUD_PROBE |
+	netlink.NUD_PERMANENT | netlink.NUD_NOARP
+
+// NeighborTable resolves N3 peer MAC addresses via the kernel neighbor table.
+//
+// Netifs are normally the TAP netifs of pass-through faces on N3 Ethernet ports.
+// ARP packets received on the Ethernet port are delivered to the TAP netif, so that the kernel can learn peer MAC addresses.
+type NeighborTable struct {
+	Netifs []string
+}
+
+// ResolveMAC returns the MAC address of the next hop toward an N3 peer.
+// If the neighbor entry is missing or failed, it asks the kernel to send ARP requests and returns ErrMacPending.
+// If the neighbor entry is stale, it asks the kernel to re-confirm the MAC address, so that a MAC address change would be noticed.
+func (nt NeighborTable) ResolveMAC(ip netip.Addr) (net.HardwareAddr, error) {
+	routes, e := netlink.RouteGet(ip.AsSlice())
+	if e != nil {
+		return nil, fmt.Errorf("netlink.RouteGet(%s): %w", ip, e)
+	}
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("no route to peer %s", ip)
+	}
+	route := routes[0]
+
+	link, e := netlink.LinkByIndex(route.LinkIndex)
+	if e != nil {
+		return nil, fmt.Errorf("netlink.LinkByIndex(%d): %w", route.LinkIndex, e)
+	}
+	if ifname := link.Attrs().Name; !slices.Contains(nt.Netifs, ifname) {
+		return nil, fmt.Errorf("route to peer %s goes through netif %s, which is not an N3 netif", ip, ifname)
+	}
+
+	nexthop := ip
+	if gw, ok := netip.AddrFromSlice(route.Gw); ok && !gw.IsUnspecified() {
+		nexthop = gw.Unmap()
+	}
+
+	neighs, e := netlink.NeighList(route.LinkIndex, netlink.FAMILY_V4)
+	if e != nil {
+		return nil, fmt.Errorf("netlink.NeighList(%d): %w", route.LinkIndex, e)
+	}
+	i := slices.IndexFunc(neighs, func(neigh netlink.Neigh) bool {
+		addr, _ := netip.AddrFromSlice(neigh.IP)
+		return addr.Unmap() == nexthop && neigh.State&neighValidStates != 0 && macaddr.IsUnicast(neigh.HardwareAddr)
+	})
+	if i < 0 {
+		if e := nt.probe(route.LinkIndex, nexthop); e != nil {
+			return nil, e
+		}
+		return nil, ErrMacPending
+	}
+
+	neigh := neighs[i]
+	if neigh.State&netlink.NUD_STALE != 0 {
+		nt.probe(route.LinkIndex, nexthop)
+	}
+	return neigh.HardwareAddr, nil
+}
+
+// probe asks the kernel to resolve or re-confirm a neighbor, as if the neighbor entry is used for transmission.
+func (NeighborTable) probe(linkIndex int, nexthop netip.Addr) error {
+	e := netlink.NeighSet(&netlink.Neigh{
+		LinkIndex: linkIndex,
+		Family:    netlink.FAMILY_V4,
+		IP:        nexthop.AsSlice(),
+		Flags:     netlink.NTF_USE,
+	})
+	if e != nil {
+		return fmt.Errorf("netlink.NeighSet(%s,NTF_USE): %w", nexthop, e)
+	}
+	return nil
+}
@@ -1,9 +1,12 @@
 package upf
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"maps"
 	"net/netip"
+	"slices"
 
 	"github.com/wmnsk/go-pfcp/ie"
 	"github.com/wmnsk/go-pfcp/message"
@@ -15,108 +18,224 @@ type SessionLocatorFields struct {
 	DlTEID        uint32     `json:"dlTEID"`
 	UlQFI         uint8      `json:"ulQFI"`
 	DlQFI         uint8      `json:"dlQFI"`
+	LocalIP       netip.Addr `json:"localIP"`
 	RemoteIP      netip.Addr `json:"remoteIP"`
 	InnerRemoteIP netip.Addr `json:"innerRemoteIP"`
 }
 
-const (
-	spHaveUlTEID = 1 << iota
-	spHaveDlTEID
-	spHaveUlQFI
-	spHaveDlQFI
-	spHaveUlQERID
-	spHaveDlQERID
-	spHaveRemoteIP
-	spHaveInnerRemoteIP
-	spHaveNeeded = spHaveUlTEID | spHaveDlTEID | spHaveUlQFI | spHaveDlQFI | spHaveRemoteIP | spHaveInnerRemoteIP
-)
+// SessionQoS contains QoS enforcement parameters extracted from PFCP session.
+type SessionQoS struct {
+	// UlMBR is the uplink maximum bitrate in bits per second.
+	// Zero means unlimited.
+	UlMBR uint64 `json:"ulMBR,omitempty"`
+
+	// DlMBR is the downlink maximum bitrate in bits per second.
+	// Zero means unlimited.
+	DlMBR uint64 `json:"dlMBR,omitempty"`
+}
+
+// sessionPDR contains recognized fields of a Packet Detection Rule.
+type sessionPDR struct {
+	SourceInterface uint8
+	Precedence      uint32
+	TEID            uint32
+	LocalIP         netip.Addr
+	UEIP            netip.Addr
+	PdiQFI          uint8
+	HasPdiQFI       bool
+	FARID           uint32
+	QERIDs          []uint32
+	URRIDs          []uint32
+}
+
+// sessionFAR contains recognized fields of a Forwarding Action Rule.
+type sessionFAR struct {
+	DlTEID   uint32
+	RemoteIP netip.Addr
+	HasOHC   bool
+}
+
+// sessionQER contains recognized fields of a QoS Enforcement Rule.
+type sessionQER struct {
+	QFI          uint8
+	HasQFI       bool
+	UlMBR, DlMBR uint64 // kbps
+}
 
 // SessionParser parses PFCP messages to construct GTP-U face locator.
 type SessionParser struct {
 	// Handle F-TEID with CH flag, returns updated F-TEID for CreatedPDR.
-	ChooseTeid func(fTEID *ie.FTEIDFields) *ie.FTEIDFields
+	// networkInstance is the Network Instance in the PDI, or empty string if absent.
+	ChooseTeid func(fTEID *ie.FTEIDFields, networkInstance string) *ie.FTEIDFields
 
-	loc              SessionLocatorFields
-	ulQERID, dlQERID uint32
-	have             uint32
+	pdrs map[uint16]*sessionPDR
+	fars map[uint32]*sessionFAR
+	qers map[uint32]*sessionQER
+	urrs map[uint32]*UsageRule
+}
+
+func (sp *SessionParser) init() {
+	if sp.pdrs != nil {
+		return
+	}
+	sp.pdrs = map[uint16]*sessionPDR{}
+	sp.fars = map[uint32]*sessionFAR{}
+	sp.qers = map[uint32]*sessionQER{}
+	sp.urrs = map[uint32]*UsageRule{}
+}
+
+// sessionRules contains rules IEs within a SessionEstablishmentRequest or SessionModificationRequest message.
+type sessionRules struct {
+	RemovePDR, RemoveFAR, RemoveQER, RemoveURR []*ie.IE
+	CreatePDR, CreateFAR, CreateQER, CreateURR []*ie.IE
+	UpdatePDR, UpdateFAR, UpdateQER, UpdateURR []*ie.IE
 }
 
 // EstablishmentRequest handles a SessionEstablishmentRequest message.
 func (sp *SessionParser) EstablishmentRequest(req *message.SessionEstablishmentRequest, rspIEs []*ie.IE) ([]*ie.IE, error) {
-	return sp.emRequest(req.CreatePDR, req.CreateFAR, nil, req.CreateQER, rspIEs)
+	return sp.emRequest(sessionRules{
+		CreatePDR: req.CreatePDR,
+		CreateFAR: req.CreateFAR,
+		CreateQER: req.CreateQER,
+		CreateURR: req.CreateURR,
+	}, rspIEs)
 }
 
 // ModificationRequest handles a SessionModificationRequest message.
 func (sp *SessionParser) ModificationRequest(req *message.SessionModificationRequest, rspIEs []*ie.IE) ([]*ie.IE, error) {
-	return sp.emRequest(req.CreatePDR, req.CreateFAR, req.UpdateFAR, req.CreateQER, rspIEs)
+	return sp.emRequest(sessionRules{
+		RemovePDR: req.RemovePDR,
+		RemoveFAR: req.RemoveFAR,
+		RemoveQER: req.RemoveQER,
+		RemoveURR: req.RemoveURR,
+		CreatePDR: req.CreatePDR,
+		CreateFAR: req.CreateFAR,
+		CreateQER: req.CreateQER,
+		CreateURR: req.CreateURR,
+		UpdatePDR: req.UpdatePDR,
+		UpdateFAR: req.UpdateFAR,
+		UpdateQER: req.UpdateQER,
+		UpdateURR: req.UpdateURR,
+	}, rspIEs)
 }
 
 // emRequest handles a SessionEstablishmentRequest or SessionModificationRequest message.
-func (sp *SessionParser) emRequest(createPDR, createFAR, updateFAR, createQER []*ie.IE, rspIEs []*ie.IE) (rspIEsRet []*ie.IE, e error) {
+func (sp *SessionParser) emRequest(rules sessionRules, rspIEs []*ie.IE) (rspIEsRet []*ie.IE, e error) {
+	sp.init()
 	var errs []error
-	for i, pdr := range createPDR {
-		if rspIEs, e = sp.createPDR(pdr, rspIEs); e != nil {
-			errs = append(errs, fmt.Errorf("CreatePDR[%d]: %w", i, e))
-		}
-	}
-	for i, far := range createFAR {
-		if e = sp.createFAR(far); e != nil {
-			errs = append(errs, fmt.Errorf("CreateFAR[%d]: %w", i, e))
-		}
-	}
-	for i, far := range updateFAR {
-		if e = sp.updateFAR(far); e != nil {
-			errs = append(errs, fmt.Errorf("UpdateFAR[%d]: %w", i, e))
-		}
-	}
-	for i, qer := range createQER {
-		if e = sp.createQER(qer); e != nil {
-			errs = append(errs, fmt.Errorf("CreateQER[%d]: %w", i, e))
+	each := func(title string, list []*ie.IE, f func(item *ie.IE) error) {
+		for i, item := range list {
+			if e := f(item); e != nil {
+				errs = append(errs, fmt.Errorf("%s[%d]: %w", title, i, e))
+			}
 		}
 	}
+
+	each("RemovePDR", rules.RemovePDR, sp.removePDR)
+	each("RemoveFAR", rules.RemoveFAR, sp.removeFAR)
+	each("RemoveQER", rules.RemoveQER, sp.removeQER)
+	each("RemoveURR", rules.RemoveURR, sp.removeURR)
+	each("CreatePDR", rules.CreatePDR, func(pdr *ie.IE) (e error) {
+		rspIEs, e = sp.cuPDR(pdr.CreatePDR, ie.NewCreatedPDR, rspIEs)
+		return e
+	})
+	each("CreateFAR", rules.CreateFAR, sp.createFAR)
+	each("CreateQER", rules.CreateQER, func(qer *ie.IE) error { return sp.cuQER(qer.CreateQER) })
+	each("CreateURR", rules.CreateURR, func(urr *ie.IE) error { return sp.cuURR(urr.CreateURR) })
+	each("UpdatePDR", rules.UpdatePDR, func(pdr *ie.IE) (e error) {
+		rspIEs, e = sp.cuPDR(pdr.UpdatePDR, ie.NewUpdatedPDR, rspIEs)
+		return e
+	})
+	each("UpdateFAR", rules.UpdateFAR, sp.updateFAR)
+	each("UpdateQER", rules.UpdateQER, func(qer *ie.IE) error { return sp.cuQER(qer.UpdateQER) })
+	each("UpdateURR", rules.UpdateURR, func(urr *ie.IE) error { return sp.cuURR(urr.UpdateURR) })
 	return rspIEs, errors.Join(errs...)
 }
 
-// createPDR handles a CreatePDR IE.
-func (sp *SessionParser) createPDR(pdr *ie.IE, rspIEs []*ie.IE) ([]*ie.IE, error) {
-	si, e := pdr.SourceInterface()
+// cuPDR handles a CreatePDR or UpdatePDR IE.
+func (sp *SessionParser) cuPDR(children func() ([]*ie.IE, error), makeRsp func(ies ...*ie.IE) *ie.IE, rspIEs []*ie.IE) ([]*ie.IE, error) {
+	ies, e := children()
 	if e != nil {
-		return rspIEs, fmt.Errorf("SourceInterface: %w", e)
+		return rspIEs, e
 	}
 
-	pdrID, e := pdr.PDRID()
+	pdrID, e := FindIE(ie.PDRID).Within(ies, nil).PDRID()
 	if e != nil {
 		return rspIEs, fmt.Errorf("PDRID: %w", e)
 	}
-	createdPdr := []*ie.IE{
+	pdr := sp.pdrs[pdrID]
+	if pdr == nil {
+		pdr = &sessionPDR{}
+	}
+	rsp := []*ie.IE{
 		ie.NewPDRID(pdrID),
 	}
 
-	isAccess := false
-	switch si {
-	case ie.SrcInterfaceAccess:
-		isAccess = true
-		fallthrough
-	case ie.SrcInterfaceCPFunction:
-		fTEID, e := sp.createPDRWithFTEID(pdr, isAccess)
+	if precedence, e := FindIE(ie.Precedence).Within(ies, nil).Precedence(); e == nil {
+		pdr.Precedence = precedence
+	}
+	if farID, e := FindIE(ie.FARID).Within(ies, nil).FARID(); e == nil {
+		pdr.FARID = farID
+	}
+	if qerIDs, e := findIDs(ies, ie.QERID, (*ie.IE).QERID); e != nil {
+		return rspIEs, fmt.Errorf("QERID: %w", e)
+	} else if qerIDs != nil {
+		pdr.QERIDs = qerIDs
+	}
+	if urrIDs, e := findIDs(ies, ie.URRID, (*ie.IE).URRID); e != nil {
+		return rspIEs, fmt.Errorf("URRID: %w", e)
+	} else if urrIDs != nil {
+		pdr.URRIDs = urrIDs
+	}
+
+	if pdi := FindIE(ie.PDI).Within(ies, nil); pdi.Type != 0 {
+		fTEID, e := sp.parsePDI(pdr, pdi)
 		if e != nil {
 			return rspIEs, e
 		}
-		createdPdr = append(createdPdr, encodeFTEID(*fTEID))
-	case ie.SrcInterfaceCore:
-		return rspIEs, sp.createPDRCore(pdr)
-	default:
-		return rspIEs, fmt.Errorf("SourceInterface %d unknown", si)
+		if fTEID != nil {
+			rsp = append(rsp, encodeFTEID(*fTEID))
+		}
 	}
 
-	rspIEs = append(rspIEs, ie.NewCreatedPDR(createdPdr...))
+	sp.pdrs[pdrID] = pdr
+	if len(rsp) > 1 {
+		rspIEs = append(rspIEs, makeRsp(rsp...))
+	}
 	return rspIEs, nil
 }
 
-// createPDRWithFTEID handles a CreatePDR IE expected to contain PDI.
-func (sp *SessionParser) createPDRWithFTEID(pdr *ie.IE, isAccess bool) (*ie.FTEIDFields, error) {
-	pdi := FindIE(ie.PDI).Within(pdr.CreatePDR())
-	fTEID, e := pdi.FTEID()
+// parsePDI handles a PDI IE within CreatePDR or UpdatePDR.
+// Returns F-TEID to be included in CreatedPDR or UpdatedPDR, or nil if not applicable.
+func (sp *SessionParser) parsePDI(pdr *sessionPDR, pdi *ie.IE) (*ie.FTEIDFields, error) {
+	ies, e := pdi.PDI()
+	if e != nil {
+		return nil, fmt.Errorf("PDI: %w", e)
+	}
+
+	si, e := FindIE(ie.SourceInterface).Within(ies, nil).SourceInterface()
+	if e != nil {
+		return nil, fmt.Errorf("SourceInterface: %w", e)
+	}
+	pdr.SourceInterface = si
+
+	if qfi, e := FindIE(ie.QFI).Within(ies, nil).QFI(); e == nil {
+		// OAI-CN5G-SMF v2.0.1 does not send CreateQER, but QFI is available in the PDI.
+		pdr.PdiQFI, pdr.HasPdiQFI = qfi, true
+	}
+
+	switch si {
+	case ie.SrcInterfaceAccess, ie.SrcInterfaceCPFunction:
+		return sp.parsePDIWithFTEID(pdr, ies)
+	case ie.SrcInterfaceCore:
+		return nil, sp.parsePDICore(pdr, ies)
+	}
+	return nil, fmt.Errorf("SourceInterface %d unknown", si)
+}
+
+// parsePDIWithFTEID handles a PDI IE expected to contain F-TEID.
+func (sp *SessionParser) parsePDIWithFTEID(pdr *sessionPDR, ies []*ie.IE) (*ie.FTEIDFields, error) {
+	fTEID, e := FindIE(ie.FTEID).Within(ies, nil).FTEID()
 	if e != nil {
 		return nil, fmt.Errorf("FTEID: %w", e)
 	}
@@ -125,34 +244,18 @@ func (sp *SessionParser) createPDRWithFTEID(pdr *ie.IE, isAccess bool) (*ie.FTEI
 	}
 
 	if fTEID.HasCh() {
-		fTEID = sp.ChooseTeid(fTEID)
-	}
-
-	sp.loc.UlTEID = fTEID.TEID
-	sp.have |= spHaveUlTEID
-
-	sp.ulQERID, e = pdr.QERID()
-	if e == nil {
-		sp.have |= spHaveUlQERID
-	} else if errors.Is(e, ie.ErrIENotFound) {
-		// OAI-CN5G-SMF v2.0.1 does not send CreateQER, but QFI is available in the PDI.
-		// UlQFI and DlQFI are assumed to be the same.
-		sp.loc.UlQFI, e = FindIE(ie.QFI).Within(pdi.PDI()).QFI()
-		if e != nil {
-			return nil, fmt.Errorf("QFI: %w", e)
-		}
-		sp.loc.DlQFI = sp.loc.UlQFI
-		sp.have |= spHaveUlQFI | spHaveDlQFI
-	} else {
-		return nil, fmt.Errorf("QERID: %w", e)
+		ni, _ := FindIE(ie.NetworkInstance).Within(ies, nil).NetworkInstanceHeuristic()
+		fTEID = sp.ChooseTeid(fTEID, ni)
 	}
 
+	pdr.TEID = fTEID.TEID
+	pdr.LocalIP, _ = netip.AddrFromSlice(fTEID.IPv4Address)
 	return fTEID, nil
 }
 
-// createPDRCore handles a CreatePDR IE with SourceInterface=core.
-func (sp *SessionParser) createPDRCore(pdr *ie.IE) error {
-	ueIP, e := pdr.UEIPAddress()
+// parsePDICore handles a PDI IE with SourceInterface=core.
+func (sp *SessionParser) parsePDICore(pdr *sessionPDR, ies []*ie.IE) error {
+	ueIP, e := FindIE(ie.UEIPAddress).Within(ies, nil).UEIPAddress()
 	if e != nil {
 		return fmt.Errorf("UEIPAddress: %w", e)
 	}
@@ -162,21 +265,28 @@ func (sp *SessionParser) createPDRCore(pdr *ie.IE) error {
 		return fmt.Errorf("UEIPAddress is not IPv4")
 	}
 
-	sp.loc.InnerRemoteIP = ip
-	sp.have |= spHaveInnerRemoteIP
+	pdr.UEIP = ip
+	return nil
+}
 
-	sp.dlQERID, e = pdr.QERID()
-	if e == nil {
-		sp.have |= spHaveDlQERID
-	} else if !errors.Is(e, ie.ErrIENotFound) {
-		return fmt.Errorf("QERID: %w", e)
+// removePDR handles a RemovePDR IE.
+func (sp *SessionParser) removePDR(pdr *ie.IE) error {
+	pdrID, e := pdr.PDRID()
+	if e != nil {
+		return fmt.Errorf("PDRID: %w", e)
 	}
-
+	delete(sp.pdrs, pdrID)
 	return nil
 }
 
 // createFAR handles a CreateFAR IE.
 func (sp *SessionParser) createFAR(far *ie.IE) error {
+	farID, e := far.FARID()
+	if e != nil {
+		return fmt.Errorf("FARID: %w", e)
+	}
+	sp.fars[farID] = &sessionFAR{}
+
 	if !far.HasFORW() {
 		return nil
 	}
@@ -185,20 +295,25 @@ func (sp *SessionParser) createFAR(far *ie.IE) error {
 	if e != nil {
 		return fmt.Errorf("ForwardingParameters: %w", e)
 	}
-	return sp.cuFAR(fps)
+	return sp.cuFAR(farID, fps)
 }
 
 // updateFAR handles an UpdateFAR IE.
 func (sp *SessionParser) updateFAR(far *ie.IE) error {
+	farID, e := FindIE(ie.FARID).Within(far.UpdateFAR()).FARID()
+	if e != nil {
+		return fmt.Errorf("FARID: %w", e)
+	}
+
 	fps, e := far.UpdateForwardingParameters()
 	if e != nil {
 		return fmt.Errorf("UpdateForwardingParameters: %w", e)
 	}
-	return sp.cuFAR(fps)
+	return sp.cuFAR(farID, fps)
 }
 
 // cuFAR handles a CreateFAR or UpdateFAR IE.
-func (sp *SessionParser) cuFAR(fps []*ie.IE) error {
+func (sp *SessionParser) cuFAR(farID uint32, fps []*ie.IE) error {
 	if len(fps) == 0 {
 		return errors.New("ForwardingParameters or UpdateForwardingParameters empty")
 	}
@@ -210,7 +325,7 @@ func (sp *SessionParser) cuFAR(fps []*ie.IE) error {
 
 	switch di {
 	case ie.DstInterfaceAccess:
-		return sp.cuFARAccess(fps)
+		return sp.cuFARAccess(farID, fps)
 	case ie.DstInterfaceCore, ie.DstInterfaceCPFunction:
 		return nil
 	}
@@ -218,7 +333,7 @@ func (sp *SessionParser) cuFAR(fps []*ie.IE) error {
 }
 
 // cuFARAccess handles a CreateFAR or UpdateFAR IE with DestinationInterface=access.
-func (sp *SessionParser) cuFARAccess(fps []*ie.IE) error {
+func (sp *SessionParser) cuFARAccess(farID uint32, fps []*ie.IE) error {
 	ohcFound := FindIE(ie.OuterHeaderCreation).Within(fps, nil)
 	if ohcFound.Type == 0 {
 		return nil
@@ -229,41 +344,211 @@ func (sp *SessionParser) cuFARAccess(fps []*ie.IE) error {
 		return fmt.Errorf("OuterHeaderCreation: %w", e)
 	}
 
-	sp.loc.DlTEID = ohc.TEID
-	sp.loc.RemoteIP, _ = netip.AddrFromSlice(ohc.IPv4Address)
-	sp.have |= spHaveDlTEID | spHaveRemoteIP
+	far := sp.fars[farID]
+	if far == nil {
+		far = &sessionFAR{}
+		sp.fars[farID] = far
+	}
+	far.DlTEID = ohc.TEID
+	far.RemoteIP, _ = netip.AddrFromSlice(ohc.IPv4Address)
+	far.HasOHC = true
+	return nil
+}
+
+// removeFAR handles a RemoveFAR IE.
+func (sp *SessionParser) removeFAR(far *ie.IE) error {
+	farID, e := FindIE(ie.FARID).Within(far.RemoveFAR()).FARID()
+	if e != nil {
+		return fmt.Errorf("FARID: %w", e)
+	}
+	delete(sp.fars, farID)
 	return nil
 }
 
-// createQER handles a CreateQER IE.
-func (sp *SessionParser) createQER(qer *ie.IE) error {
-	qerID, e := qer.QERID()
+// cuQER handles a CreateQER or UpdateQER IE.
+func (sp *SessionParser) cuQER(children func() ([]*ie.IE, error)) error {
+	ies, e := children()
+	if e != nil {
+		return e
+	}
+
+	qerID, e := FindIE(ie.QERID).Within(ies, nil).QERID()
 	if e != nil {
 		return fmt.Errorf("QERID: %w", e)
 	}
+	qer := sp.qers[qerID]
+	if qer == nil {
+		qer = &sessionQER{}
+		sp.qers[qerID] = qer
+	}
 
-	for _, c := range []struct {
-		MustHave   uint32
-		MatchQERID uint32
-		SetQFI     *uint8
-		SetHave    uint32
-	}{
-		{spHaveUlQERID, sp.ulQERID, &sp.loc.UlQFI, spHaveUlQFI},
-		{spHaveDlQERID, sp.dlQERID, &sp.loc.DlQFI, spHaveDlQFI},
-	} {
-		if sp.have&c.MustHave != 0 && qerID == c.MatchQERID {
-			*c.SetQFI, e = qer.QFI()
-			if e != nil {
-				return fmt.Errorf("QFI: %w", e)
-			}
-			sp.have |= c.SetHave
+	if qfiFound := FindIE(ie.QFI).Within(ies, nil); qfiFound.Type != 0 {
+		if qer.QFI, e = qfiFound.QFI(); e != nil {
+			return fmt.Errorf("QFI: %w", e)
+		}
+		qer.HasQFI = true
+	}
+
+	if mbrFound := FindIE(ie.MBR).Within(ies, nil); mbrFound.Type != 0 {
+		if qer.UlMBR, e = mbrFound.MBRUL(); e != nil {
+			return fmt.Errorf("MBR: %w", e)
 		}
+		if qer.DlMBR, e = mbrFound.MBRDL(); e != nil {
+			return fmt.Errorf("MBR: %w", e)
+		}
+	}
+	return nil
+}
+
+// removeQER handles a RemoveQER IE.
+func (sp *SessionParser) removeQER(qer *ie.IE) error {
+	qerID, e := FindIE(ie.QERID).Within(qer.RemoveQER()).QERID()
+	if e != nil {
+		return fmt.Errorf("QERID: %w", e)
 	}
+	delete(sp.qers, qerID)
 	return nil
 }
 
+// cuURR handles a CreateURR or UpdateURR IE.
+func (sp *SessionParser) cuURR(children func() ([]*ie.IE, error)) error {
+	ies, e := children()
+	if e != nil {
+		return e
+	}
+
+	urrID, e := FindIE(ie.URRID).Within(ies, nil).URRID()
+	if e != nil {
+		return fmt.Errorf("URRID: %w", e)
+	}
+	urr := sp.urrs[urrID]
+	if urr == nil {
+		urr = &UsageRule{URRID: urrID}
+		sp.urrs[urrID] = urr
+	}
+	return urr.update(ies)
+}
+
+// removeURR handles a RemoveURR IE.
+func (sp *SessionParser) removeURR(urr *ie.IE) error {
+	urrID, e := FindIE(ie.URRID).Within(urr.RemoveURR()).URRID()
+	if e != nil {
+		return fmt.Errorf("URRID: %w", e)
+	}
+	delete(sp.urrs, urrID)
+	return nil
+}
+
+// primaryPDR returns the PDR with lowest precedence among PDRs with the given SourceInterface.
+// When several PDRs match the same direction, the lowest precedence one is normally the
+// catch-all rule of the default QoS flow.
+func (sp SessionParser) primaryPDR(si uint8) (pdr *sessionPDR) {
+	ids := slices.Sorted(maps.Keys(sp.pdrs))
+	for _, id := range ids {
+		p := sp.pdrs[id]
+		if p.SourceInterface == si && (pdr == nil || p.Precedence > pdr.Precedence) {
+			pdr = p
+		}
+	}
+	return pdr
+}
+
+// pdrQFI determines QFI of a PDR.
+// It prefers a non-zero QFI from a linked QER, and falls back to the QFI in the PDI.
+func (sp SessionParser) pdrQFI(pdr *sessionPDR) (qfi uint8, ok bool) {
+	for _, qerID := range pdr.QERIDs {
+		if qer := sp.qers[qerID]; qer != nil && qer.HasQFI && (!ok || qfi == 0) {
+			qfi, ok = qer.QFI, true
+		}
+	}
+	if !ok && pdr.HasPdiQFI {
+		qfi, ok = pdr.PdiQFI, true
+	}
+	return
+}
+
 // LocatorFields returns GTP-U locator fields extracted from PFCP session.
 // ok indicates whether the locator is valid.
 func (sp SessionParser) LocatorFields() (loc SessionLocatorFields, ok bool) {
-	return sp.loc, sp.have&spHaveNeeded == spHaveNeeded
+	ul, dl := sp.primaryPDR(ie.SrcInterfaceAccess), sp.primaryPDR(ie.SrcInterfaceCore)
+	if ul == nil || dl == nil {
+		return loc, false
+	}
+	far := sp.fars[dl.FARID]
+	if far == nil || !far.HasOHC {
+		return loc, false
+	}
+
+	loc.UlTEID, loc.LocalIP = ul.TEID, ul.LocalIP
+	loc.DlTEID, loc.RemoteIP = far.DlTEID, far.RemoteIP
+	loc.InnerRemoteIP = dl.UEIP
+
+	var ulOk, dlOk bool
+	loc.UlQFI, ulOk = sp.pdrQFI(ul)
+	if loc.DlQFI, dlOk = sp.pdrQFI(dl); !dlOk {
+		// UlQFI and DlQFI are assumed to be the same.
+		loc.DlQFI = loc.UlQFI
+	}
+	return loc, ulOk
+}
+
+// QoS returns QoS enforcement parameters extracted from PFCP session.
+// Each MBR is the minimum among QERs linked to PDRs of the same direction.
+func (sp SessionParser) QoS() (qos SessionQoS) {
+	minMBR := func(dst *uint64, kbps uint64) {
+		if bps := kbps * 1000; bps > 0 && (*dst == 0 || bps < *dst) {
+			*dst = bps
+		}
+	}
+	for _, pdr := range sp.pdrs {
+		for _, qerID := range pdr.QERIDs {
+			qer := sp.qers[qerID]
+			if qer == nil {
+				continue
+			}
+			switch pdr.SourceInterface {
+			case ie.SrcInterfaceAccess:
+				minMBR(&qos.UlMBR, qer.UlMBR)
+			case ie.SrcInterfaceCore:
+				minMBR(&qos.DlMBR, qer.DlMBR)
+			}
+		}
+	}
+	return
+}
+
+// UsageRules returns Usage Reporting Rules in PFCP session, sorted by URR ID.
+// Each rule measures uplink and/or downlink traffic depending on which PDRs refer to it.
+func (sp SessionParser) UsageRules() (list []UsageRule) {
+	for _, urr := range sp.urrs {
+		r := *urr
+		r.Ul, r.Dl = false, false
+		for _, pdr := range sp.pdrs {
+			if !slices.Contains(pdr.URRIDs, r.URRID) {
+				continue
+			}
+			switch pdr.SourceInterface {
+			case ie.SrcInterfaceAccess:
+				r.Ul = true
+			case ie.SrcInterfaceCore:
+				r.Dl = true
+			}
+		}
+		list = append(list, r)
+	}
+	slices.SortFunc(list, func(a, b UsageRule) int { return cmp.Compare(a.URRID, b.URRID) })
+	return list
+}
+
+// findIDs collects all rule IDs of the desired type.
+// Returns nil if the IE type is absent.
+func findIDs(ies []*ie.IE, typ uint16, get func(*ie.IE) (uint32, error)) (ids []uint32, e error) {
+	for item := range FindIE(typ).IterWithin(ies) {
+		id, e := get(item)
+		if e != nil {
+			return nil, e
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
 }
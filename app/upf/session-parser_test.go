@@ -3,6 +3,7 @@ package upf_test
 import (
 	"net/netip"
 	"testing"
+	"time"
 
 	"github.com/usnistgov/ndn-dpdk/app/upf"
 	"github.com/wmnsk/go-pfcp/ie"
@@ -39,7 +40,7 @@ func parseSession(t *testing.T, est, mod string) (sess upf.SessionParser, estRsp
 	var e error
 
 	nChosenTeids := 0
-	sess.ChooseTeid = func(fTEID *ie.FTEIDFields) *ie.FTEIDFields {
+	sess.ChooseTeid = func(fTEID *ie.FTEIDFields, networkInstance string) *ie.FTEIDFields {
 		assert.True(fTEID.HasCh())
 
 		teid := uint32(0x8F000000)
@@ -75,6 +76,9 @@ func TestSessionParserPhoenix(t *testing.T) {
 	assert.EqualValues(1, loc.DlQFI)
 	assert.EqualValues(netip.MustParseAddr("172.25.196.14"), loc.RemoteIP)
 	assert.EqualValues(netip.MustParseAddr("10.141.0.2"), loc.InnerRemoteIP)
+
+	assert.Equal(upf.SessionQoS{}, sess.QoS())
+	assert.Len(sess.UsageRules(), 0)
 }
 
 func TestSessionParserFree5gc(t *testing.T) {
@@ -88,8 +92,30 @@ func TestSessionParserFree5gc(t *testing.T) {
 	assert.EqualValues(0x00000002, loc.DlTEID)
 	assert.EqualValues(1, loc.UlQFI)
 	assert.EqualValues(1, loc.DlQFI)
+	assert.EqualValues(netip.MustParseAddr("172.25.194.7"), loc.LocalIP)
 	assert.EqualValues(netip.MustParseAddr("172.25.194.20"), loc.RemoteIP)
 	assert.EqualValues(netip.MustParseAddr("10.141.0.1"), loc.InnerRemoteIP)
+
+	// session-AMBR QER is linked to both PDRs
+	assert.Equal(upf.SessionQoS{
+		UlMBR: 200000000,
+		DlMBR: 100000000,
+	}, sess.QoS())
+
+	// both URRs are linked to both PDRs
+	expectedRule := upf.UsageRule{
+		MeasureVolume:   true,
+		Period:          10 * time.Second,
+		VolumeThreshold: upf.UsageVolume{Ul: 1000, Dl: 1000},
+		Ul:              true,
+		Dl:              true,
+	}
+	rules := sess.UsageRules()
+	require.Len(rules, 2)
+	for i, rule := range rules {
+		expectedRule.URRID = uint32(1 + i)
+		assert.Equal(expectedRule, rule)
+	}
 }
 
 func TestSessionParserOai(t *testing.T) {
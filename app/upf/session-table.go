@@ -1,33 +1,126 @@
 package upf
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"net"
 	"net/netip"
 	"os"
+	"slices"
+	"time"
 
 	"github.com/usnistgov/ndn-dpdk/core/uintalloc"
 	"github.com/wmnsk/go-pfcp/ie"
 	"github.com/wmnsk/go-pfcp/message"
+	"go.uber.org/zap"
 )
 
+// SessionFaces manipulates GTP-U faces on behalf of SessionTable.
+type SessionFaces interface {
+	// ResolveMAC determines the MAC address of an N3 peer.
+	// It returns ErrMacPending if the MAC address is being resolved.
+	ResolveMAC(ctx context.Context, ip netip.Addr) (net.HardwareAddr, error)
+
+	// CreateFace creates a GTP-U face.
+	CreateFace(ctx context.Context, loc SessionLocatorFields, remote net.HardwareAddr, qos SessionQoS) (id string, e error)
+
+	// UpdateFaceQoS changes QoS enforcement on an existing face.
+	UpdateFaceQoS(ctx context.Context, id string, qos SessionQoS) error
+
+	// DestroyFace destroys a face.
+	DestroyFace(ctx context.Context, id string) error
+
+	// ReadFaceVolume reads cumulative traffic counters of a face.
+	ReadFaceVolume(ctx context.Context, id string) (FaceVolume, error)
+}
+
+// ErrUlMBR indicates that a session requests an uplink MBR, which cannot be enforced by the GTP-U face.
+var ErrUlMBR = errors.New("uplink MBR cannot be enforced")
+
+// N3Addrs maps PDI Network Instance to UPF N3 IPv4 address.
+// The empty key is the default N3 address, used when Network Instance is absent or unmatched.
+type N3Addrs map[string]netip.Addr
+
 // Session represents a PFCP session and the associated face.
 type Session struct {
 	CpSEID, UpSEID uint64
 	Parser         SessionParser
 	FaceID         string
 
-	sct SessionTeids
+	faceLoc SessionLocatorFields
+	faceMAC net.HardwareAddr
+	faceQoS SessionQoS
+	volBase FaceVolume // cumulative traffic volume of destroyed faces
+	volSeq  uint64     // incremented when face counters are read
+	meters  map[uint32]*usageMeter
+	sct     SessionTeids
+}
+
+// syncMeters creates usage meters for new URRs and deletes usage meters for removed URRs.
+func (sess *Session) syncMeters(now time.Time, vol FaceVolume) (rules []UsageRule) {
+	rules = sess.Parser.UsageRules()
+	meters := map[uint32]*usageMeter{}
+	for _, r := range rules {
+		m := sess.meters[r.URRID]
+		if m == nil {
+			m = newUsageMeter(r, now, vol)
+		}
+		meters[r.URRID] = m
+	}
+	sess.meters = meters
+	return rules
+}
+
+// usageReports checks Usage Reporting Rules and constructs Usage Report IEs.
+//
+//	urrIDs: if non-nil, only check these URRs.
+//	trigger: if non-nil, report unconditionally with this Usage Report Trigger.
+func (sess *Session) usageReports(now time.Time, vol FaceVolume, typ uint16, urrIDs []uint32, trigger []uint8) (reports []*ie.IE) {
+	for _, r := range sess.syncMeters(now, vol) {
+		if urrIDs != nil && !slices.Contains(urrIDs, r.URRID) {
+			continue
+		}
+
+		m, t := sess.meters[r.URRID], trigger
+		if t == nil {
+			t = m.check(r, now, vol)
+		}
+		if t != nil {
+			reports = append(reports, m.report(r, now, vol, typ, t))
+		}
+	}
+	return reports
+}
+
+// SessionReport contains usage reports of a session, to be sent in a SessionReportRequest.
+type SessionReport struct {
+	Session      *Session
+	UsageReports []*ie.IE
 }
 
 // SessionTable stores PFCP sessions and instructs face creation.
 type SessionTable struct {
-	n3ipv4      net.IP              // UPF N3 IPv4
+	// IgnoreUlMBR allows sessions with uplink MBR, which is then not enforced.
+	// If false, such sessions are rejected with ErrUlMBR.
+	IgnoreUlMBR bool
+
+	n3          N3Addrs
 	table       map[uint64]*Session // UpSEID => Session
 	teidChooser *TeidChooser
-	createFace  func(ctx context.Context, loc SessionLocatorFields) (id string, e error)
-	destroyFace func(ctx context.Context, id string) error
+	faces       SessionFaces
+}
+
+// n3ipv4 returns UPF N3 IPv4 address for a Network Instance.
+func (st *SessionTable) n3ipv4(networkInstance string) net.IP {
+	ip, ok := st.n3[networkInstance]
+	if !ok {
+		ip = st.n3[""]
+	}
+	b := ip.As4()
+	return b[:]
 }
 
 // EstablishmentRequest handles a SessionEstablishmentRequest message.
@@ -42,9 +135,9 @@ func (st *SessionTable) EstablishmentRequest(ctx context.Context, req *message.S
 		UpSEID: uintalloc.Alloc64(st.table),
 		sct:    SessionTeids{},
 	}
-	sess.Parser.ChooseTeid = func(fTEID *ie.FTEIDFields) *ie.FTEIDFields {
+	sess.Parser.ChooseTeid = func(fTEID *ie.FTEIDFields, networkInstance string) *ie.FTEIDFields {
 		teid := st.teidChooser.Alloc(fTEID, sess.sct)
-		rsp := ie.NewFTEIDFields(0, teid, st.n3ipv4, nil, 0)
+		rsp := ie.NewFTEIDFields(0, teid, st.n3ipv4(networkInstance), nil, 0)
 		rsp.SetIPv4Flag()
 		return rsp
 	}
@@ -53,7 +146,12 @@ func (st *SessionTable) EstablishmentRequest(ctx context.Context, req *message.S
 	if rspIEs, e = sess.Parser.EstablishmentRequest(req, rspIEs); e != nil {
 		return sess, rspIEs, e
 	}
-	return sess, rspIEs, st.createFaceWhenReady(ctx, sess)
+	if _, e = st.qos(sess); e != nil {
+		st.teidChooser.Free(sess.sct)
+		delete(st.table, sess.UpSEID)
+		return sess, rspIEs, e
+	}
+	return sess, rspIEs, st.syncFace(ctx, sess)
 }
 
 // ModificationRequest handles a SessionModificationRequest message.
@@ -65,27 +163,252 @@ func (st *SessionTable) ModificationRequest(ctx context.Context, req *message.Se
 	if rspIEs, e = sess.Parser.ModificationRequest(req, rspIEs); e != nil {
 		return sess, rspIEs, e
 	}
-	return sess, rspIEs, st.createFaceWhenReady(ctx, sess)
+	if _, e = st.qos(sess); e != nil {
+		return sess, rspIEs, e
+	}
+	if e = st.syncFace(ctx, sess); e != nil {
+		return sess, rspIEs, e
+	}
+	if e = st.startMeters(ctx, sess); e != nil {
+		return sess, rspIEs, e
+	}
+
+	if len(req.QueryURR) > 0 {
+		var urrIDs []uint32
+		for _, q := range req.QueryURR {
+			urrID, e := FindIE(ie.URRID).Within(q.QueryURR()).URRID()
+			if e != nil {
+				return sess, rspIEs, fmt.Errorf("QueryURR: %w", e)
+			}
+			urrIDs = append(urrIDs, urrID)
+		}
+
+		vol, e := st.volume(ctx, sess)
+		if e != nil {
+			return sess, rspIEs, e
+		}
+		rspIEs = append(rspIEs, sess.usageReports(time.Now(), vol, ie.UsageReportWithinSessionModificationResponse,
+			urrIDs, []uint8{urtIMMER, 0, 0})...)
+	}
+	return sess, rspIEs, nil
+}
+
+// qos returns QoS enforcement parameters of the face of a session.
+func (st *SessionTable) qos(sess *Session) (qos SessionQoS, e error) {
+	qos = sess.Parser.QoS()
+	if qos.UlMBR != 0 {
+		if !st.IgnoreUlMBR {
+			return qos, ErrUlMBR
+		}
+		qos.UlMBR = 0
+	}
+	return qos, nil
 }
 
-func (st *SessionTable) createFaceWhenReady(ctx context.Context, sess *Session) error {
+// syncFace creates, updates, or re-creates the face to match current session state.
+// If the N3 peer MAC address is being resolved, face creation is deferred until RefreshFaces.
+func (st *SessionTable) syncFace(ctx context.Context, sess *Session) error {
 	loc, ok := sess.Parser.LocatorFields()
 	if !ok {
 		return nil
 	}
+	qos, e := st.qos(sess)
+	if e != nil {
+		return e
+	}
+
+	mac, e := st.faces.ResolveMAC(ctx, loc.RemoteIP)
+	pending := errors.Is(e, ErrMacPending)
+	if e != nil && !pending {
+		return e
+	}
+
+	if sess.FaceID != "" {
+		// keep using the last known MAC address while it is being re-resolved
+		if loc == sess.faceLoc && (pending || bytes.Equal(mac, sess.faceMAC)) {
+			if qos == sess.faceQoS {
+				return nil
+			}
+			if e := st.faces.UpdateFaceQoS(ctx, sess.FaceID, qos); e != nil {
+				return e
+			}
+			sess.faceQoS = qos
+			return nil
+		}
+
+		if e := st.destroyFace(ctx, sess); e != nil {
+			return e
+		}
+	}
 
-	id, e := st.createFace(ctx, loc)
+	if pending {
+		logger.Debug("face creation deferred until peer MAC address is resolved",
+			zap.Uint64("up-seid", sess.UpSEID), zap.Stringer("peer", loc.RemoteIP))
+		return nil
+	}
+
+	id, e := st.faces.CreateFace(ctx, loc, mac, qos)
+	if e != nil {
+		return e
+	}
+	sess.FaceID, sess.faceLoc, sess.faceMAC, sess.faceQoS = id, loc, mac, qos
+
+	// start usage measurement on new URRs, so that traffic on the new face is measured from now
+	vol, e := st.volume(ctx, sess)
 	if e != nil {
 		return e
 	}
-	sess.FaceID = id
+	sess.syncMeters(time.Now(), vol)
+	return nil
+}
+
+// startMeters starts usage measurement on URRs created by a modification, so that traffic is measured from now.
+func (st *SessionTable) startMeters(ctx context.Context, sess *Session) error {
+	for urrID := range sess.Parser.urrs {
+		if sess.meters[urrID] != nil {
+			continue
+		}
+
+		vol, e := st.volume(ctx, sess)
+		if e != nil {
+			return e
+		}
+		sess.syncMeters(time.Now(), vol)
+		return nil
+	}
 	return nil
 }
 
+// volume returns cumulative traffic volume of a session.
+func (st *SessionTable) volume(ctx context.Context, sess *Session) (vol FaceVolume, e error) {
+	vol = sess.volBase
+	if sess.FaceID == "" || len(sess.Parser.urrs) == 0 {
+		return vol, nil
+	}
+
+	faceVol, e := st.faces.ReadFaceVolume(ctx, sess.FaceID)
+	if e != nil {
+		return vol, fmt.Errorf("ReadFaceVolume(%s): %w", sess.FaceID, e)
+	}
+	sess.volSeq++
+	return vol.Add(faceVol), nil
+}
+
+// destroyFace destroys the face of a session, preserving its traffic volume for usage reporting.
+func (st *SessionTable) destroyFace(ctx context.Context, sess *Session) error {
+	vol, eVol := st.volume(ctx, sess)
+	if e := st.faces.DestroyFace(ctx, sess.FaceID); e != nil {
+		return e
+	}
+	sess.FaceID, sess.faceLoc, sess.faceMAC, sess.faceQoS = "", SessionLocatorFields{}, nil, SessionQoS{}
+
+	if eVol != nil {
+		logger.Warn("traffic volume of destroyed face is lost", zap.Uint64("up-seid", sess.UpSEID), zap.Error(eVol))
+	} else {
+		sess.volBase = vol
+	}
+	return nil
+}
+
+// RefreshFaces re-resolves N3 peer MAC addresses of all sessions.
+// It creates faces that were deferred, and re-creates faces whose peer MAC address has changed.
+func (st *SessionTable) RefreshFaces(ctx context.Context) error {
+	var errs []error
+	for _, upSEID := range slices.Sorted(maps.Keys(st.table)) {
+		if e := st.syncFace(ctx, st.table[upSEID]); e != nil {
+			errs = append(errs, fmt.Errorf("session %d: %w", upSEID, e))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// UsageSnapshot contains faces whose counters are needed for checking Usage Reporting Rules.
+// It allows reading face counters without holding the lock that protects the SessionTable.
+type UsageSnapshot struct {
+	faces   SessionFaces
+	entries map[uint64]*usageSnapshotEntry // UpSEID => entry
+}
+
+type usageSnapshotEntry struct {
+	faceID string
+	volSeq uint64
+	vol    FaceVolume
+	e      error
+}
+
+// ReadVolumes reads face counters.
+// This may be invoked without holding the lock that protects the SessionTable.
+func (s *UsageSnapshot) ReadVolumes(ctx context.Context) {
+	for upSEID, ent := range s.entries {
+		if ent.faceID == "" {
+			continue
+		}
+		if ent.vol, ent.e = s.faces.ReadFaceVolume(ctx, ent.faceID); ent.e != nil {
+			ent.e = fmt.Errorf("session %d: ReadFaceVolume(%s): %w", upSEID, ent.faceID, ent.e)
+		}
+	}
+}
+
+// PrepareUsage records faces of sessions that have Usage Reporting Rules.
+// The caller should invoke ReadVolumes on the returned snapshot, and then pass it to UsageReportsFrom.
+func (st *SessionTable) PrepareUsage() *UsageSnapshot {
+	s := &UsageSnapshot{
+		faces:   st.faces,
+		entries: map[uint64]*usageSnapshotEntry{},
+	}
+	for upSEID, sess := range st.table {
+		if len(sess.Parser.urrs) == 0 {
+			continue
+		}
+		s.entries[upSEID] = &usageSnapshotEntry{
+			faceID: sess.FaceID,
+			volSeq: sess.volSeq,
+		}
+	}
+	return s
+}
+
+// UsageReportsFrom checks Usage Reporting Rules of sessions in a snapshot.
+// Returns usage reports that should be sent to the SMF in SessionReportRequest messages.
+//
+// A session is skipped if its face was changed, or its face counters were read by another operation, after PrepareUsage.
+// Such a session is checked in the next snapshot.
+func (st *SessionTable) UsageReportsFrom(now time.Time, s *UsageSnapshot) (list []SessionReport, e error) {
+	var errs []error
+	for _, upSEID := range slices.Sorted(maps.Keys(s.entries)) {
+		ent, sess := s.entries[upSEID], st.table[upSEID]
+		if sess == nil || sess.FaceID != ent.faceID || sess.volSeq != ent.volSeq {
+			continue
+		}
+		if ent.e != nil {
+			errs = append(errs, ent.e)
+			continue
+		}
+
+		vol := sess.volBase.Add(ent.vol)
+		if reports := sess.usageReports(now, vol, ie.UsageReportWithinSessionReportRequest, nil, nil); len(reports) > 0 {
+			list = append(list, SessionReport{
+				Session:      sess,
+				UsageReports: reports,
+			})
+		}
+	}
+	return list, errors.Join(errs...)
+}
+
+// UsageReports checks Usage Reporting Rules of all sessions.
+// This is equivalent to PrepareUsage, ReadVolumes, and UsageReportsFrom without releasing the lock in between.
+func (st *SessionTable) UsageReports(ctx context.Context, now time.Time) (list []SessionReport, e error) {
+	s := st.PrepareUsage()
+	s.ReadVolumes(ctx)
+	return st.UsageReportsFrom(now, s)
+}
+
 // DeletionRequest handles a SessionDeletionRequest message.
-func (st *SessionTable) DeletionRequest(ctx context.Context, req *message.SessionDeletionRequest) (sess *Session, e error) {
+// Final usage reports are appended to rspIEs.
+func (st *SessionTable) DeletionRequest(ctx context.Context, req *message.SessionDeletionRequest, rspIEs []*ie.IE) (sess *Session, rspIEsRet []*ie.IE, e error) {
 	if sess = st.table[req.SEID()]; sess == nil {
-		return nil, os.ErrNotExist
+		return nil, rspIEs, os.ErrNotExist
 	}
 	defer func() {
 		st.teidChooser.Free(sess.sct)
@@ -93,23 +416,21 @@ func (st *SessionTable) DeletionRequest(ctx context.Context, req *message.Sessio
 	}()
 
 	if sess.FaceID != "" {
-		e = st.destroyFace(ctx, sess.FaceID)
+		if e = st.destroyFace(ctx, sess); e != nil {
+			return
+		}
 	}
-	return
+	rspIEs = append(rspIEs, sess.usageReports(time.Now(), sess.volBase, ie.UsageReportWithinSessionDeletionResponse,
+		nil, []uint8{0, urtTERMR, 0})...)
+	return sess, rspIEs, nil
 }
 
 // NewSessionTable constructs SessionTable.
-func NewSessionTable(
-	n3ipv4 netip.Addr,
-	createFace func(ctx context.Context, sloc SessionLocatorFields) (id string, e error),
-	destroyFace func(ctx context.Context, id string) error,
-) *SessionTable {
-	n3ipv4b := n3ipv4.As4()
+func NewSessionTable(n3 N3Addrs, faces SessionFaces) *SessionTable {
 	return &SessionTable{
-		n3ipv4:      n3ipv4b[:],
+		n3:          n3,
 		table:       map[uint64]*Session{},
 		teidChooser: NewTeidChooser(),
-		createFace:  createFace,
-		destroyFace: destroyFace,
+		faces:       faces,
 	}
 }
@@ -3,77 +3,160 @@ package upf_test
 import (
 	"context"
 	"math/rand/v2"
+	"net"
 	"net/netip"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/usnistgov/ndn-dpdk/app/upf"
+	"github.com/wmnsk/go-pfcp/ie"
 	"github.com/wmnsk/go-pfcp/message"
 )
 
-type testSessionTableFaceCreator struct {
-	t     testing.TB
-	ctx   context.Context
-	Table map[string]upf.SessionLocatorFields
+type testSessionFaces struct {
+	t        testing.TB
+	ctx      context.Context
+	Peers    map[netip.Addr]net.HardwareAddr // if nil, every peer has testPeerMAC
+	NCreated int
+	Table    map[string]upf.SessionLocatorFields
+	Remote   map[string]net.HardwareAddr
+	QoS      map[string]upf.SessionQoS
+	Volume   map[string]upf.FaceVolume
 }
 
-func (fc *testSessionTableFaceCreator) CreateFace(ctx context.Context, loc upf.SessionLocatorFields) (id string, e error) {
+var testPeerMAC = net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01}
+
+func (fc *testSessionFaces) ResolveMAC(ctx context.Context, ip netip.Addr) (net.HardwareAddr, error) {
+	assert, _ := makeAR(fc.t)
+	assert.Same(fc.ctx, ctx)
+
+	if fc.Peers == nil {
+		return testPeerMAC, nil
+	}
+	if mac, ok := fc.Peers[ip]; ok {
+		return mac, nil
+	}
+	return nil, upf.ErrMacPending
+}
+
+func (fc *testSessionFaces) CreateFace(ctx context.Context, loc upf.SessionLocatorFields, remote net.HardwareAddr, qos upf.SessionQoS) (id string, e error) {
 	assert, _ := makeAR(fc.t)
 	assert.Same(fc.ctx, ctx)
 
 	id = loc.InnerRemoteIP.String()
+	assert.NotContains(fc.Table, id)
+	fc.NCreated++
 	fc.Table[id] = loc
+	fc.Remote[id] = remote
+	fc.QoS[id] = qos
+	fc.Volume[id] = upf.FaceVolume{}
 	return id, nil
 }
 
-func (fc *testSessionTableFaceCreator) DestroyFace(ctx context.Context, id string) error {
+func (fc *testSessionFaces) UpdateFaceQoS(ctx context.Context, id string, qos upf.SessionQoS) error {
+	assert, _ := makeAR(fc.t)
+	assert.Same(fc.ctx, ctx)
+
+	assert.Contains(fc.Table, id)
+	fc.QoS[id] = qos
+	return nil
+}
+
+func (fc *testSessionFaces) DestroyFace(ctx context.Context, id string) error {
 	assert, _ := makeAR(fc.t)
 	assert.Same(fc.ctx, ctx)
 
 	delete(fc.Table, id)
+	delete(fc.Remote, id)
+	delete(fc.QoS, id)
+	delete(fc.Volume, id)
 	return nil
 }
 
-func TestSessionTable(t *testing.T) {
-	assert, require := makeAR(t)
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+func (fc *testSessionFaces) ReadFaceVolume(ctx context.Context, id string) (upf.FaceVolume, error) {
+	assert, _ := makeAR(fc.t)
+	assert.Same(fc.ctx, ctx)
+
+	vol, ok := fc.Volume[id]
+	if !ok {
+		return vol, os.ErrNotExist
+	}
+	return vol, nil
+}
 
-	fc := &testSessionTableFaceCreator{
-		t:     t,
-		ctx:   ctx,
-		Table: map[string]upf.SessionLocatorFields{},
+func newTestSessionFaces(t testing.TB, ctx context.Context) *testSessionFaces {
+	return &testSessionFaces{
+		t:      t,
+		ctx:    ctx,
+		Table:  map[string]upf.SessionLocatorFields{},
+		Remote: map[string]net.HardwareAddr{},
+		QoS:    map[string]upf.SessionQoS{},
+		Volume: map[string]upf.FaceVolume{},
 	}
-	st := upf.NewSessionTable(netip.MustParseAddr("192.168.3.2"), fc.CreateFace, fc.DestroyFace)
+}
+
+type testSessionTable struct {
+	*upf.SessionTable
+	t   testing.TB
+	ctx context.Context
+}
 
-	establishment := func(wireHex string) *upf.Session {
-		sess, _, e := st.EstablishmentRequest(ctx, parsePFCP(wireHex).(*message.SessionEstablishmentRequest), nil)
+func (st testSessionTable) Establishment(wireHex string) *upf.Session {
+	_, require := makeAR(st.t)
+	sess, _, e := st.EstablishmentRequest(st.ctx, parsePFCP(wireHex).(*message.SessionEstablishmentRequest), nil)
+	require.NoError(e)
+	require.NotNil(sess)
+	return sess
+}
+
+func (st testSessionTable) Modification(sess *upf.Session, msg *message.SessionModificationRequest, expectNotExist bool) (rspIEs []*ie.IE) {
+	assert, require := makeAR(st.t)
+	msg.Header.SEID = sess.UpSEID
+	sessFound, rspIEs, e := st.ModificationRequest(st.ctx, msg, nil)
+	if expectNotExist {
+		assert.True(os.IsNotExist(e))
+		assert.Nil(sessFound)
+	} else {
 		require.NoError(e)
-		require.NotNil(sess)
-		return sess
+		assert.Same(sess, sessFound)
 	}
+	return rspIEs
+}
+
+func (st testSessionTable) Deletion(sess *upf.Session, expectNotExist bool) (rspIEs []*ie.IE) {
+	assert, require := makeAR(st.t)
+	msg := message.NewSessionDeletionRequest(0, 0, sess.UpSEID, rand.Uint32(), 0)
+	sessFound, rspIEs, e := st.DeletionRequest(st.ctx, msg, nil)
+	if expectNotExist {
+		assert.True(os.IsNotExist(e))
+		assert.Nil(sessFound)
+	} else {
+		require.NoError(e)
+		assert.Same(sess, sessFound)
+	}
+	return rspIEs
+}
+
+func newTestSessionTable(t testing.TB, n3 upf.N3Addrs) (st testSessionTable, fc *testSessionFaces, cancel context.CancelFunc) {
+	st.t = t
+	st.ctx, cancel = context.WithCancel(context.Background())
+	fc = newTestSessionFaces(t, st.ctx)
+	st.SessionTable = upf.NewSessionTable(n3, fc)
+	return
+}
+
+func TestSessionTable(t *testing.T) {
+	assert, _ := makeAR(t)
+	st, fc, cancel := newTestSessionTable(t, upf.N3Addrs{"": netip.MustParseAddr("192.168.3.2")})
+	defer cancel()
+
+	establishment := st.Establishment
 	modification := func(sess *upf.Session, wireHex string, expectNotExist bool) {
-		msg := parsePFCP(wireHex).(*message.SessionModificationRequest)
-		msg.Header.SEID = sess.UpSEID
-		sessFound, _, e := st.ModificationRequest(ctx, msg, nil)
-		if expectNotExist {
-			assert.True(os.IsNotExist(e))
-			assert.Nil(sessFound)
-		} else {
-			require.NoError(e)
-			assert.Same(sess, sessFound)
-		}
+		st.Modification(sess, parsePFCP(wireHex).(*message.SessionModificationRequest), expectNotExist)
 	}
 	deletion := func(sess *upf.Session, expectNotExist bool) {
-		msg := message.NewSessionDeletionRequest(0, 0, sess.UpSEID, rand.Uint32(), 0)
-		sessFound, e := st.DeletionRequest(ctx, msg)
-		if expectNotExist {
-			assert.True(os.IsNotExist(e))
-			assert.Nil(sessFound)
-		} else {
-			require.NoError(e)
-			assert.Same(sess, sessFound)
-		}
+		st.Deletion(sess, expectNotExist)
 	}
 
 	assert.Len(fc.Table, 0)
@@ -99,6 +182,7 @@ func TestSessionTable(t *testing.T) {
 		DlTEID:        0x00000003,
 		UlQFI:         1,
 		DlQFI:         1,
+		LocalIP:       netip.MustParseAddr("172.25.196.7"),
 		RemoteIP:      netip.MustParseAddr("172.25.196.14"),
 		InnerRemoteIP: netip.MustParseAddr("10.141.0.2"),
 	}, fc.Table["10.141.0.2"])
@@ -108,6 +192,7 @@ func TestSessionTable(t *testing.T) {
 		DlTEID:        0x00000004,
 		UlQFI:         1,
 		DlQFI:         1,
+		LocalIP:       netip.MustParseAddr("172.25.196.7"),
 		RemoteIP:      netip.MustParseAddr("172.25.196.14"),
 		InnerRemoteIP: netip.MustParseAddr("10.141.0.3"),
 	}, fc.Table["10.141.0.3"])
@@ -117,6 +202,7 @@ func TestSessionTable(t *testing.T) {
 		DlTEID:        0x00000007,
 		UlQFI:         1,
 		DlQFI:         1,
+		LocalIP:       netip.MustParseAddr("172.25.196.7"),
 		RemoteIP:      netip.MustParseAddr("172.25.196.14"),
 		InnerRemoteIP: netip.MustParseAddr("10.141.0.1"),
 	}, fc.Table["10.141.0.1"])
@@ -126,6 +212,7 @@ func TestSessionTable(t *testing.T) {
 		DlTEID:        0x00000009,
 		UlQFI:         1,
 		DlQFI:         1,
+		LocalIP:       netip.MustParseAddr("172.25.196.7"),
 		RemoteIP:      netip.MustParseAddr("172.25.196.14"),
 		InnerRemoteIP: netip.MustParseAddr("10.141.0.4"),
 	}, fc.Table["10.141.0.4"])
@@ -142,3 +229,283 @@ func TestSessionTable(t *testing.T) {
 	modification(sess3, pfcpPhoenixMod3, true)
 	deletion(sess3, true)
 }
+
+func TestSessionTableFree5gc(t *testing.T) {
+	assert, require := makeAR(t)
+	st, fc, cancel := newTestSessionTable(t, upf.N3Addrs{"": netip.MustParseAddr("192.168.3.2")})
+	defer cancel()
+	st.IgnoreUlMBR = true
+	t0 := time.Now()
+
+	sess := st.Establishment(pfcpFree5gcEst)
+	assert.Len(fc.Table, 0)
+	st.Modification(sess, parsePFCP(pfcpFree5gcMod).(*message.SessionModificationRequest), false)
+	require.Len(fc.Table, 1)
+	assert.Equal(1, fc.NCreated)
+	faceID := sess.FaceID
+	assert.Equal(upf.SessionQoS{DlMBR: 100000000}, fc.QoS[faceID])
+
+	usageReports := func(now time.Time) (list []usageReport) {
+		reports, e := st.UsageReports(st.ctx, now)
+		require.NoError(e)
+		for _, r := range reports {
+			assert.Same(sess, r.Session)
+			list = append(list, gatherUsageReports(t, ie.UsageReportWithinSessionReportRequest, r.UsageReports)...)
+		}
+		return
+	}
+
+	// below thresholds
+	fc.Volume[faceID] = upf.FaceVolume{UlOctets: 400, DlOctets: 900}
+	assert.Len(usageReports(t0.Add(time.Second)), 0)
+
+	// downlink volume threshold reached
+	fc.Volume[faceID] = upf.FaceVolume{UlOctets: 600, DlOctets: 1500}
+	assert.Equal([]usageReport{
+		{URRID: 1, URSEQN: 0, Trigger: []byte{0x02, 0, 0}, UlOctets: 600, DlOctets: 1500},
+		{URRID: 2, URSEQN: 0, Trigger: []byte{0x02, 0, 0}, UlOctets: 600, DlOctets: 1500},
+	}, usageReports(t0.Add(2*time.Second)))
+	assert.Len(usageReports(t0.Add(3*time.Second)), 0)
+
+	// measurement period elapsed
+	assert.Equal([]usageReport{
+		{URRID: 1, URSEQN: 1, Trigger: []byte{0x01, 0, 0}},
+		{URRID: 2, URSEQN: 1, Trigger: []byte{0x01, 0, 0}},
+	}, usageReports(t0.Add(11*time.Second)))
+
+	// QER update changes face QoS without re-creating the face
+	rspIEs := st.Modification(sess, message.NewSessionModificationRequest(0, 0, 0, rand.Uint32(), 0,
+		ie.NewUpdateQER(ie.NewQERID(1), ie.NewMBR(50000, 20000)),
+		ie.NewQueryURR(ie.NewURRID(2)),
+	), false)
+	assert.Equal(1, fc.NCreated)
+	assert.Equal(faceID, sess.FaceID)
+	assert.Equal(upf.SessionQoS{DlMBR: 20000000}, fc.QoS[faceID])
+	assert.Equal([]usageReport{
+		{URRID: 2, URSEQN: 2, Trigger: []byte{0x80, 0, 0}},
+	}, gatherUsageReports(t, ie.UsageReportWithinSessionModificationResponse, rspIEs))
+
+	// handover to another gNB re-creates the face, traffic volume is preserved
+	fc.Volume[faceID] = upf.FaceVolume{UlOctets: 700, DlOctets: 1600}
+	st.Modification(sess, message.NewSessionModificationRequest(0, 0, 0, rand.Uint32(), 0,
+		ie.NewUpdateFAR(
+			ie.NewFARID(2),
+			ie.NewApplyAction(0x02),
+			ie.NewUpdateForwardingParameters(
+				ie.NewDestinationInterface(ie.DstInterfaceAccess),
+				ie.NewOuterHeaderCreation(0x0100, 0x00000003, "172.25.194.21", "", 0, 0, 0),
+			),
+		),
+	), false)
+	assert.Equal(2, fc.NCreated)
+	require.Len(fc.Table, 1)
+	assert.EqualValues(3, fc.Table[sess.FaceID].DlTEID)
+	assert.Equal(netip.MustParseAddr("172.25.194.21"), fc.Table[sess.FaceID].RemoteIP)
+	assert.Equal(upf.SessionQoS{DlMBR: 20000000}, fc.QoS[sess.FaceID])
+	fc.Volume[sess.FaceID] = upf.FaceVolume{UlOctets: 10, DlOctets: 20}
+
+	// final usage reports upon deletion
+	rspIEs = st.Deletion(sess, false)
+	assert.Len(fc.Table, 0)
+	assert.Equal([]usageReport{
+		{URRID: 1, URSEQN: 2, Trigger: []byte{0, 0x08, 0}, UlOctets: 110, DlOctets: 120},
+		{URRID: 2, URSEQN: 3, Trigger: []byte{0, 0x08, 0}, UlOctets: 110, DlOctets: 120},
+	}, gatherUsageReports(t, ie.UsageReportWithinSessionDeletionResponse, rspIEs))
+}
+
+// pfcpFree5gcModQoS is a QoS and charging update following the free5GC SMF message layout, encoded with go-pfcp.
+// It is applied after pfcpFree5gcMod and contains:
+//
+//	UpdatePDR 1 and 2: link URR 1, 2, 3 and QER 2, 1.
+//	UpdateQER 1: MBR ul=50000 dl=20000 kbps.
+//	UpdateURR 2: volume threshold total=4000 octets, no periodic reporting.
+//	CreateURR 3: time threshold 30 seconds.
+const pfcpFree5gcModQoS = "213400f20000000000000001000009c00006001b0051000400000003003e000103002500020400002000040000001e0009003e003800020001001d0004000000ff006c000400000001005100040000000100510004000000020051000400000003006d000400000002006d0004000000010009003e003800020002001d0004000000ff006c000400000002005100040000000100510004000000020051000400000003006d000400000002006d000400000001000d00200051000400000002003e000102002500020200001f0009010000000000000fa0000e001b006d0004000000010019000100001a000a000000c3500000004e20"
+
+func TestSessionTableFree5gcQoS(t *testing.T) {
+	assert, require := makeAR(t)
+	st, fc, cancel := newTestSessionTable(t, upf.N3Addrs{"": netip.MustParseAddr("192.168.3.2")})
+	defer cancel()
+	st.IgnoreUlMBR = true
+	t0 := time.Now()
+
+	sess := st.Establishment(pfcpFree5gcEst)
+	st.Modification(sess, parsePFCP(pfcpFree5gcMod).(*message.SessionModificationRequest), false)
+	require.Len(fc.Table, 1)
+	faceID := sess.FaceID
+	assert.Equal(upf.SessionQoS{DlMBR: 100000000}, fc.QoS[faceID])
+
+	// rejected if uplink MBR is not allowed, face QoS is unchanged
+	st.IgnoreUlMBR = false
+	msg := parsePFCP(pfcpFree5gcModQoS).(*message.SessionModificationRequest)
+	msg.Header.SEID = sess.UpSEID
+	_, _, e := st.ModificationRequest(st.ctx, msg, nil)
+	assert.ErrorIs(e, upf.ErrUlMBR)
+	assert.Equal(upf.SessionQoS{DlMBR: 100000000}, fc.QoS[faceID])
+
+	// QER and URR updates are applied without re-creating the face
+	st.IgnoreUlMBR = true
+	st.Modification(sess, parsePFCP(pfcpFree5gcModQoS).(*message.SessionModificationRequest), false)
+	assert.Equal(1, fc.NCreated)
+	assert.Equal(faceID, sess.FaceID)
+	assert.Equal(upf.SessionQoS{DlMBR: 20000000}, fc.QoS[faceID])
+
+	rules := sess.Parser.UsageRules()
+	require.Len(rules, 3)
+	assert.Zero(rules[1].Period)
+	assert.Equal(upf.UsageVolume{Total: 4000}, rules[1].VolumeThreshold)
+	assert.Equal(30*time.Second, rules[2].TimeThreshold)
+	assert.True(rules[2].Ul)
+	assert.True(rules[2].Dl)
+
+	usageReports := func(now time.Time) (list []usageReport) {
+		reports, e := st.UsageReports(st.ctx, now)
+		require.NoError(e)
+		for _, r := range reports {
+			list = append(list, gatherUsageReports(t, ie.UsageReportWithinSessionReportRequest, r.UsageReports)...)
+		}
+		return
+	}
+
+	// below thresholds
+	fc.Volume[faceID] = upf.FaceVolume{UlOctets: 300, DlOctets: 600}
+	assert.Len(usageReports(t0.Add(time.Second)), 0)
+
+	// URR 1 reaches its uplink and downlink thresholds, URR 2 reaches the new total threshold
+	fc.Volume[faceID] = upf.FaceVolume{UlOctets: 1500, DlOctets: 3000}
+	assert.Equal([]usageReport{
+		{URRID: 1, URSEQN: 0, Trigger: []byte{0x02, 0, 0}, UlOctets: 1500, DlOctets: 3000},
+		{URRID: 2, URSEQN: 0, Trigger: []byte{0x02, 0, 0}, UlOctets: 1500, DlOctets: 3000},
+	}, usageReports(t0.Add(2*time.Second)))
+
+	// URR 1 measurement period elapsed, URR 3 time threshold reached; URR 2 no longer reports periodically
+	assert.Equal([]usageReport{
+		{URRID: 1, URSEQN: 1, Trigger: []byte{0x01, 0, 0}},
+		{URRID: 3, URSEQN: 0, Trigger: []byte{0x04, 0, 0}, UlOctets: 1500, DlOctets: 3000},
+	}, usageReports(t0.Add(31*time.Second)))
+
+	st.Deletion(sess, false)
+	assert.Len(fc.Table, 0)
+}
+
+func TestSessionTableUlMBR(t *testing.T) {
+	assert, require := makeAR(t)
+	st, fc, cancel := newTestSessionTable(t, upf.N3Addrs{"": netip.MustParseAddr("192.168.3.2")})
+	defer cancel()
+
+	sess, _, e := st.EstablishmentRequest(st.ctx, parsePFCP(pfcpFree5gcEst).(*message.SessionEstablishmentRequest), nil)
+	assert.ErrorIs(e, upf.ErrUlMBR)
+	require.NotNil(sess)
+	assert.Len(fc.Table, 0)
+
+	// rejected session is not retained
+	st.Modification(sess, parsePFCP(pfcpFree5gcMod).(*message.SessionModificationRequest), true)
+	assert.Len(fc.Table, 0)
+}
+
+func TestSessionTableUsageSnapshot(t *testing.T) {
+	assert, require := makeAR(t)
+	st, fc, cancel := newTestSessionTable(t, upf.N3Addrs{"": netip.MustParseAddr("192.168.3.2")})
+	defer cancel()
+	st.IgnoreUlMBR = true
+	t0 := time.Now()
+
+	sess := st.Establishment(pfcpFree5gcEst)
+	st.Modification(sess, parsePFCP(pfcpFree5gcMod).(*message.SessionModificationRequest), false)
+	require.Len(fc.Table, 1)
+	faceID := sess.FaceID
+
+	// snapshot is used when the session is unchanged
+	snapshot := st.PrepareUsage()
+	fc.Volume[faceID] = upf.FaceVolume{UlOctets: 600, DlOctets: 1500}
+	snapshot.ReadVolumes(st.ctx)
+	fc.Volume[faceID] = upf.FaceVolume{UlOctets: 9000, DlOctets: 9000}
+	reports, e := st.UsageReportsFrom(t0.Add(time.Second), snapshot)
+	require.NoError(e)
+	require.Len(reports, 1)
+	assert.Equal([]usageReport{
+		{URRID: 1, URSEQN: 0, Trigger: []byte{0x02, 0, 0}, UlOctets: 600, DlOctets: 1500},
+		{URRID: 2, URSEQN: 0, Trigger: []byte{0x02, 0, 0}, UlOctets: 600, DlOctets: 1500},
+	}, gatherUsageReports(t, ie.UsageReportWithinSessionReportRequest, reports[0].UsageReports))
+
+	// snapshot is discarded when face counters were read by a modification in between
+	snapshot = st.PrepareUsage()
+	snapshot.ReadVolumes(st.ctx)
+	st.Modification(sess, message.NewSessionModificationRequest(0, 0, 0, rand.Uint32(), 0,
+		ie.NewQueryURR(ie.NewURRID(1)),
+	), false)
+	reports, e = st.UsageReportsFrom(t0.Add(2*time.Second), snapshot)
+	require.NoError(e)
+	assert.Len(reports, 0)
+
+	// snapshot is discarded when the session is deleted in between
+	snapshot = st.PrepareUsage()
+	snapshot.ReadVolumes(st.ctx)
+	st.Deletion(sess, false)
+	reports, e = st.UsageReportsFrom(t0.Add(3*time.Second), snapshot)
+	require.NoError(e)
+	assert.Len(reports, 0)
+}
+
+func TestSessionTableMultiN3(t *testing.T) {
+	assert, require := makeAR(t)
+	st, fc, cancel := newTestSessionTable(t, upf.N3Addrs{
+		"":    netip.MustParseAddr("192.168.3.2"),
+		"a00": netip.MustParseAddr("192.168.4.2"),
+	})
+	defer cancel()
+	st.IgnoreUlMBR = true
+
+	sess := st.Establishment(pfcpOpen5gsEst)
+	st.Modification(sess, parsePFCP(pfcpOpen5gsMod).(*message.SessionModificationRequest), false)
+	require.Len(fc.Table, 1)
+	loc := fc.Table[sess.FaceID]
+	assert.Equal(netip.MustParseAddr("192.168.4.2"), loc.LocalIP)
+	assert.Equal(netip.MustParseAddr("172.25.195.15"), loc.RemoteIP)
+}
+
+func TestSessionTableNeighbor(t *testing.T) {
+	assert, require := makeAR(t)
+	st, fc, cancel := newTestSessionTable(t, upf.N3Addrs{"": netip.MustParseAddr("192.168.3.2")})
+	defer cancel()
+	st.IgnoreUlMBR = true
+	gnb := netip.MustParseAddr("172.25.194.20")
+	fc.Peers = map[netip.Addr]net.HardwareAddr{}
+
+	// face creation is deferred while gNB MAC address is unknown
+	sess := st.Establishment(pfcpFree5gcEst)
+	st.Modification(sess, parsePFCP(pfcpFree5gcMod).(*message.SessionModificationRequest), false)
+	assert.Len(fc.Table, 0)
+	assert.Empty(sess.FaceID)
+	require.NoError(st.RefreshFaces(st.ctx))
+	assert.Len(fc.Table, 0)
+
+	// face is created after gNB MAC address is learned
+	mac0 := net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0xC0, 0x00}
+	fc.Peers[gnb] = mac0
+	require.NoError(st.RefreshFaces(st.ctx))
+	require.Len(fc.Table, 1)
+	assert.Equal(1, fc.NCreated)
+	assert.Equal(gnb, fc.Table[sess.FaceID].RemoteIP)
+	assert.Equal(mac0, fc.Remote[sess.FaceID])
+	require.NoError(st.RefreshFaces(st.ctx))
+	assert.Equal(1, fc.NCreated)
+
+	// face is kept while gNB MAC address is being re-resolved
+	delete(fc.Peers, gnb)
+	require.NoError(st.RefreshFaces(st.ctx))
+	assert.Equal(1, fc.NCreated)
+	assert.Len(fc.Table, 1)
+
+	// face is re-created when gNB MAC address changes
+	mac1 := net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0xC0, 0x01}
+	fc.Peers[gnb] = mac1
+	require.NoError(st.RefreshFaces(st.ctx))
+	assert.Equal(2, fc.NCreated)
+	require.Len(fc.Table, 1)
+	assert.Equal(mac1, fc.Remote[sess.FaceID])
+	assert.Equal(upf.SessionQoS{DlMBR: 100000000}, fc.QoS[sess.FaceID])
+
+	st.Deletion(sess, false)
+	assert.Len(fc.Table, 0)
+}
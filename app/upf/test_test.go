@@ -49,3 +49,30 @@ func gatherCreatedPDRs(t testing.TB, rsp []*ie.IE) []createdPDR {
 		return
 	}))
 }
+
+type usageReport struct {
+	URRID    uint32
+	URSEQN   uint32
+	Trigger  []byte
+	UlOctets uint64
+	DlOctets uint64
+}
+
+func gatherUsageReports(t testing.TB, typ uint16, rsp []*ie.IE) []usageReport {
+	assert, _ := makeAR(t)
+	return slices.Collect(seqs.Map(upf.FindIE(typ).IterWithin(rsp), func(item *ie.IE) (res usageReport) {
+		ies, e := item.UsageReport()
+		assert.NoError(e)
+
+		res.URRID, e = upf.FindIE(ie.URRID).Within(ies, nil).URRID()
+		assert.NoError(e)
+		res.URSEQN, e = upf.FindIE(ie.URSEQN).Within(ies, nil).URSEQN()
+		assert.NoError(e)
+		res.Trigger, e = upf.FindIE(ie.UsageReportTrigger).Within(ies, nil).UsageReportTrigger()
+		assert.NoError(e)
+		if vm, e := upf.FindIE(ie.VolumeMeasurement).Within(ies, nil).VolumeMeasurement(); assert.NoError(e) {
+			res.UlOctets, res.DlOctets = vm.UplinkVolume, vm.DownlinkVolume
+		}
+		return
+	}))
+}
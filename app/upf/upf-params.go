@@ -3,7 +3,10 @@ package upf
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/netip"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,24 +15,40 @@ import (
 	"github.com/wmnsk/go-pfcp/ie"
 )
 
-// UpfLocatorFields contains GTP-U locator fields not related to a PFCP session.
+// UpfLocatorFields contains GTP-U locator fields not related to a PFCP session or an N3 interface.
 type UpfLocatorFields struct {
-	Scheme       string       `json:"scheme"`
-	Local        macaddr.Flag `json:"local"`
-	VLAN         int          `json:"vlan,omitempty"`
-	LocalIP      netip.Addr   `json:"localIP"`
-	InnerLocalIP netip.Addr   `json:"innerLocalIP"`
+	Scheme       string     `json:"scheme"`
+	InnerLocalIP netip.Addr `json:"innerLocalIP"`
+}
+
+// N3Interface describes a UPF N3 interface.
+type N3Interface struct {
+	// NetworkInstance is the PDI Network Instance that selects this interface.
+	// It is empty on the default N3 interface.
+	NetworkInstance string
+
+	IP   netip.Addr
+	MAC  macaddr.Flag
+	VLAN int
 }
 
 // UpfParams contains UPF parameters.
 type UpfParams struct {
-	SmfN4   netip.Addr
-	UpfN4   netip.Addr
-	Locator UpfLocatorFields
-	MapN3   map[netip.Addr]macaddr.Flag
+	SmfN4     netip.Addr
+	UpfN4     netip.Addr
+	Locator   UpfLocatorFields
+	N3        []N3Interface // first entry is the default N3 interface
+	MapN3     map[netip.Addr]macaddr.Flag
+	Neighbors NeighborTable // learn N3 peer MAC addresses not in MapN3
+
+	// IgnoreUlMBR accepts sessions with uplink MBR without enforcing it.
+	// If false, such sessions are rejected, because the GTP-U face cannot shape received traffic.
+	IgnoreUlMBR bool
 
 	RecoveryTimestamp *ie.IE
 	UpfNodeID         *ie.IE
+
+	defaultN3 N3Interface
 }
 
 // DefineFlags appends CLI flags.
@@ -51,23 +70,35 @@ func (p *UpfParams) DefineFlags(flags []cli.Flag) []cli.Flag {
 			Name:     "upf-n3",
 			Usage:    "UPF N3 IPv4 `address`",
 			Required: true,
-			Action:   p.saveIPv4(&p.Locator.LocalIP),
+			Action:   p.saveIPv4(&p.defaultN3.IP),
 		},
 		&cli.GenericFlag{
 			Name:        "upf-mac",
 			Usage:       "UPF N3 MAC `address`",
 			Required:    true,
-			Destination: &p.Locator.Local,
+			Destination: &p.defaultN3.MAC,
 		},
 		&cli.IntFlag{
 			Name:        "upf-vlan",
 			Usage:       "UPF N3 `VLAN ID`",
-			Destination: &p.Locator.VLAN,
+			Destination: &p.defaultN3.VLAN,
 		},
 		&cli.StringSliceFlag{
-			Name:     "n3",
-			Usage:    "N3 `ip=mac` tuple",
-			Required: true,
+			Name:  "upf-n3-ni",
+			Usage: "additional UPF N3 interface `ni=ip,mac[,vlan]` tuple selected by PDI Network Instance",
+		},
+		&cli.StringSliceFlag{
+			Name:  "n3",
+			Usage: "N3 `ip=mac` tuple",
+		},
+		&cli.StringSliceFlag{
+			Name:  "n3-netif",
+			Usage: "kernel `netif` for learning N3 peer MAC addresses, normally the TAP netif of a pass-through face",
+		},
+		&cli.BoolFlag{
+			Name:        "ignore-ul-mbr",
+			Usage:       "accept sessions with uplink MBR without enforcing it",
+			Destination: &p.IgnoreUlMBR,
 		},
 		&cli.StringFlag{
 			Name:     "dn",
@@ -90,9 +121,26 @@ func (UpfParams) saveIPv4(d *netip.Addr) func(c *cli.Context, v string) error {
 // ProcessFlags validates and stores CLI flags.
 func (p *UpfParams) ProcessFlags(c *cli.Context) error {
 	p.Locator.Scheme = "gtp"
-	if !macaddr.IsUnicast(p.Locator.Local.HardwareAddr) {
+	if !macaddr.IsUnicast(p.defaultN3.MAC.HardwareAddr) {
 		return errors.New("upf-mac is not unicast MAC address")
 	}
+	if p.defaultN3.VLAN != 0 && !macaddr.IsVLAN(p.defaultN3.VLAN) {
+		return macaddr.ErrVLAN
+	}
+
+	p.N3 = []N3Interface{p.defaultN3}
+	for i, line := range c.StringSlice("upf-n3-ni") {
+		n3, e := parseN3Interface(line)
+		if e != nil {
+			return fmt.Errorf("upf-n3-ni[%d] is invalid: %w", i, e)
+		}
+		if slices.ContainsFunc(p.N3, func(item N3Interface) bool {
+			return item.NetworkInstance == n3.NetworkInstance || item.IP == n3.IP
+		}) {
+			return fmt.Errorf("upf-n3-ni[%d] duplicates Network Instance or IP address", i)
+		}
+		p.N3 = append(p.N3, n3)
+	}
 
 	p.MapN3 = map[netip.Addr]macaddr.Flag{}
 	for i, line := range c.StringSlice("n3") {
@@ -111,25 +159,100 @@ func (p *UpfParams) ProcessFlags(c *cli.Context) error {
 		p.MapN3[n3ip] = n3mac
 	}
 
+	p.Neighbors.Netifs = c.StringSlice("n3-netif")
+	if len(p.MapN3) == 0 && len(p.Neighbors.Netifs) == 0 {
+		return errors.New("either n3 or n3-netif is required")
+	}
+
 	p.RecoveryTimestamp = ie.NewRecoveryTimeStamp(time.Now())
 	p.UpfNodeID = ie.NewNodeID(p.UpfN4.String(), "", "")
 	return nil
 }
 
-// MakeLocator constructs GTP-U face locator.
-func (p UpfParams) MakeLocator(sloc SessionLocatorFields) (loc any, e error) {
-	remote, ok := p.MapN3[sloc.RemoteIP]
-	if !ok {
-		return nil, fmt.Errorf("unknown MAC address for peer %s", sloc.RemoteIP)
+// parseN3Interface parses ni=ip,mac[,vlan] tuple.
+func parseN3Interface(line string) (n3 N3Interface, e error) {
+	ni, tuple, ok := strings.Cut(line, "=")
+	tokens := strings.Split(tuple, ",")
+	if !ok || ni == "" || len(tokens) < 2 || len(tokens) > 3 {
+		return n3, errors.New("syntax error")
+	}
+	n3.NetworkInstance = ni
+
+	if n3.IP, e = netip.ParseAddr(tokens[0]); e != nil || !n3.IP.Is4() {
+		return n3, fmt.Errorf("'%s' is not an IPv4 address", tokens[0])
 	}
+	if e := n3.MAC.Set(tokens[1]); e != nil || !macaddr.IsUnicast(n3.MAC.HardwareAddr) {
+		return n3, fmt.Errorf("'%s' is not a unicast MAC address", tokens[1])
+	}
+	if len(tokens) == 3 {
+		if n3.VLAN, e = strconv.Atoi(tokens[2]); e != nil || !macaddr.IsVLAN(n3.VLAN) {
+			return n3, fmt.Errorf("'%s' is not a VLAN ID", tokens[2])
+		}
+	}
+	return n3, nil
+}
 
+// N3Addrs returns N3 IPv4 addresses keyed by Network Instance.
+func (p UpfParams) N3Addrs() N3Addrs {
+	m := N3Addrs{}
+	for _, n3 := range p.N3 {
+		m[n3.NetworkInstance] = n3.IP
+	}
+	return m
+}
+
+// FindN3 returns the N3 interface that has the given IP address.
+// If none matches, returns the default N3 interface.
+func (p UpfParams) FindN3(ip netip.Addr) N3Interface {
+	if i := slices.IndexFunc(p.N3, func(n3 N3Interface) bool { return n3.IP == ip }); i >= 0 {
+		return p.N3[i]
+	}
+	return p.N3[0]
+}
+
+// upfTxShaper contains iface.TxShaperConfig fields set from QoS Enforcement Rules.
+type upfTxShaper struct {
+	BitRate uint64 `json:"bitRate"`
+}
+
+// makeTxShaper constructs TxShaper configuration that enforces downlink MBR.
+// Returns nil if downlink MBR is unlimited.
+func makeTxShaper(qos SessionQoS) *upfTxShaper {
+	if qos.DlMBR == 0 {
+		return nil
+	}
+	return &upfTxShaper{BitRate: qos.DlMBR}
+}
+
+// ResolveMAC determines the MAC address of an N3 peer.
+// Static entries in MapN3 take priority, otherwise the MAC address is learned from kernel neighbor table.
+func (p UpfParams) ResolveMAC(ip netip.Addr) (net.HardwareAddr, error) {
+	if mac, ok := p.MapN3[ip]; ok {
+		return mac.HardwareAddr, nil
+	}
+	if len(p.Neighbors.Netifs) == 0 {
+		return nil, fmt.Errorf("unknown MAC address for peer %s", ip)
+	}
+	return p.Neighbors.ResolveMAC(ip)
+}
+
+// MakeLocator constructs GTP-U face locator.
+func (p UpfParams) MakeLocator(sloc SessionLocatorFields, remote net.HardwareAddr, qos SessionQoS) (loc any, e error) {
+	n3 := p.FindN3(sloc.LocalIP)
+	sloc.LocalIP = n3.IP
 	return struct {
 		SessionLocatorFields
 		UpfLocatorFields
-		Remote macaddr.Flag `json:"remote"`
+		Local    macaddr.Flag `json:"local"`
+		VLAN     int          `json:"vlan,omitempty"`
+		Remote   macaddr.Flag `json:"remote"`
+		TxShaper *upfTxShaper `json:"txShaper,omitempty"`
 	}{
 		SessionLocatorFields: sloc,
 		UpfLocatorFields:     p.Locator,
-		Remote:               remote,
+		Local:                n3.MAC,
+		VLAN:                 n3.VLAN,
+		Remote:               macaddr.Flag{HardwareAddr: remote},
+		TxShaper:             makeTxShaper(qos),
 	}, nil
 }
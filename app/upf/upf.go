@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"net"
 	"net/netip"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/usnistgov/ndn-dpdk/core/logging"
 	"github.com/wmnsk/go-pfcp/ie"
@@ -16,10 +19,67 @@ import (
 
 var logger = logging.New("upf")
 
+// UsageReportInterval is the interval of checking Usage Reporting Rules.
+const UsageReportInterval = time.Second
+
+// FaceRefreshInterval is the interval of re-resolving N3 peer MAC addresses learned from kernel neighbor table.
+const FaceRefreshInterval = time.Second
+
+// FaceClient manipulates faces on NDN-DPDK forwarder.
+type FaceClient interface {
+	// CreateFace creates a face with a JSON locator.
+	CreateFace(ctx context.Context, loc any) (id string, e error)
+
+	// UpdateFace changes face configuration with a JSON object of iface.UpdateConfig type.
+	UpdateFace(ctx context.Context, id string, cfg any) error
+
+	// DestroyFace destroys a face.
+	DestroyFace(ctx context.Context, id string) error
+
+	// FaceVolume reads face counters.
+	FaceVolume(ctx context.Context, id string) (FaceVolume, error)
+}
+
+// upfFaces implements SessionFaces.
+type upfFaces struct {
+	params UpfParams
+	client FaceClient
+}
+
+func (f upfFaces) ResolveMAC(ctx context.Context, ip netip.Addr) (net.HardwareAddr, error) {
+	return f.params.ResolveMAC(ip)
+}
+
+func (f upfFaces) CreateFace(ctx context.Context, sloc SessionLocatorFields, remote net.HardwareAddr, qos SessionQoS) (id string, e error) {
+	loc, e := f.params.MakeLocator(sloc, remote, qos)
+	if e != nil {
+		return "", e
+	}
+	return f.client.CreateFace(ctx, loc)
+}
+
+func (f upfFaces) UpdateFaceQoS(ctx context.Context, id string, qos SessionQoS) error {
+	cfg := map[string]any{"disableTxShaper": true}
+	if txShaper := makeTxShaper(qos); txShaper != nil {
+		cfg = map[string]any{"txShaper": txShaper}
+	}
+	return f.client.UpdateFace(ctx, id, cfg)
+}
+
+func (f upfFaces) DestroyFace(ctx context.Context, id string) error {
+	return f.client.DestroyFace(ctx, id)
+}
+
+func (f upfFaces) ReadFaceVolume(ctx context.Context, id string) (FaceVolume, error) {
+	return f.client.FaceVolume(ctx, id)
+}
+
 // UPF represents a User Plane Function.
 type UPF struct {
+	mutex  sync.Mutex
 	st     *SessionTable
 	params UpfParams
+	seq    atomic.Uint32
 }
 
 // Listen listens for PFCP messages.
@@ -31,6 +91,13 @@ func (upf *UPF) Listen(ctx context.Context) error {
 	}
 	defer conn.Close()
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go upf.reportUsage(ctx, conn)
+	if len(upf.params.Neighbors.Netifs) > 0 {
+		go upf.refreshFaces(ctx)
+	}
+
 	buf := make([]byte, 9000)
 	for {
 		n, raddr, e := conn.ReadFromUDPAddrPort(buf)
@@ -56,13 +123,73 @@ func (upf *UPF) Listen(ctx context.Context) error {
 			logEntry.Warn("cannot handle message", zap.Error(e))
 		} else if rsp != nil {
 			logEntry = logEntry.With(zap.String("rsp-type", rsp.MessageTypeName()))
-			wire := make([]byte, rsp.MarshalLen())
-			if e := rsp.MarshalTo(wire); e != nil {
-				logEntry.Warn("cannot encode response", zap.Error(e))
-			} else if _, e = conn.WriteToUDPAddrPort(wire, raddr); e != nil {
-				logEntry.Warn("cannot send response", zap.Error(e), zap.Binary("wire", wire))
-			} else {
-				logEntry.Debug("sent response")
+			upf.send(logEntry, conn, rsp, raddr)
+		}
+	}
+}
+
+// send transmits a PFCP message.
+func (upf *UPF) send(logEntry *zap.Logger, conn *net.UDPConn, msg message.Message, raddr netip.AddrPort) {
+	wire := make([]byte, msg.MarshalLen())
+	if e := msg.MarshalTo(wire); e != nil {
+		logEntry.Warn("cannot encode message", zap.Error(e))
+	} else if _, e = conn.WriteToUDPAddrPort(wire, raddr); e != nil {
+		logEntry.Warn("cannot send message", zap.Error(e), zap.Binary("wire", wire))
+	} else {
+		logEntry.Debug("sent message")
+	}
+}
+
+// reportUsage periodically checks Usage Reporting Rules and sends SessionReportRequest messages.
+func (upf *UPF) reportUsage(ctx context.Context, conn *net.UDPConn) {
+	smf := netip.AddrPortFrom(upf.params.SmfN4, 8805)
+	ticker := time.NewTicker(UsageReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			// face counters are read without holding the mutex, so that PFCP messages are not delayed by GraphQL queries
+			upf.mutex.Lock()
+			snapshot := upf.st.PrepareUsage()
+			upf.mutex.Unlock()
+			snapshot.ReadVolumes(ctx)
+			upf.mutex.Lock()
+			reports, e := upf.st.UsageReportsFrom(now, snapshot)
+			upf.mutex.Unlock()
+			if e != nil {
+				logger.Warn("cannot check usage reporting rules", zap.Error(e))
+			}
+
+			for _, r := range reports {
+				req := message.NewSessionReportRequest(
+					0, 0, r.Session.CpSEID, upf.seq.Add(1)&0xFFFFFF, 0,
+					append([]*ie.IE{ie.NewReportType(0, 0, 1, 0)}, r.UsageReports...)...,
+				)
+				logEntry := logger.With(zap.String("req-type", req.MessageTypeName()), zap.Uint32("req-seq", req.Sequence()),
+					zap.Uint64("up-seid", r.Session.UpSEID), zap.Int("usage-reports", len(r.UsageReports)))
+				upf.send(logEntry, conn, req, smf)
+			}
+		}
+	}
+}
+
+// refreshFaces periodically re-resolves N3 peer MAC addresses.
+// This creates faces that were deferred due to unresolved MAC addresses, and re-creates faces whose peer MAC address has changed.
+func (upf *UPF) refreshFaces(ctx context.Context) {
+	ticker := time.NewTicker(FaceRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			upf.mutex.Lock()
+			e := upf.st.RefreshFaces(ctx)
+			upf.mutex.Unlock()
+			if e != nil {
+				logger.Warn("cannot refresh faces", zap.Error(e))
 			}
 		}
 	}
@@ -72,6 +199,9 @@ func (upf *UPF) Listen(ctx context.Context) error {
 //
 //	rsp: the response message, may be nil.
 func (upf *UPF) ServePFCP(ctx context.Context, req message.Message) (rsp message.Message, e error) {
+	upf.mutex.Lock()
+	defer upf.mutex.Unlock()
+
 	switch req := req.(type) {
 	case *message.HeartbeatRequest:
 		return upf.HeartbeatRequest(ctx, req)
@@ -83,6 +213,8 @@ func (upf *UPF) ServePFCP(ctx context.Context, req message.Message) (rsp message
 		return upf.SessionModificationRequest(ctx, req)
 	case *message.SessionDeletionRequest:
 		return upf.SessionDeletionRequest(ctx, req)
+	case *message.SessionReportResponse:
+		return nil, upf.SessionReportResponse(ctx, req)
 	}
 	return nil, errors.New("unhandled message type")
 }
@@ -120,6 +252,10 @@ func (upf *UPF) SessionEstablishmentRequest(ctx context.Context, req *message.Se
 	}
 
 	sess, rspIEs, e := upf.st.EstablishmentRequest(ctx, req, rspIEs)
+	if cause := rejectCause(sess, e); cause != nil {
+		return message.NewSessionEstablishmentResponse(0, 0, sess.CpSEID, req.SequenceNumber, 0,
+			upf.params.UpfNodeID, cause), nil
+	}
 	if sess == nil || e != nil {
 		return nil, e
 	}
@@ -135,6 +271,9 @@ func (upf *UPF) SessionModificationRequest(ctx context.Context, req *message.Ses
 	}
 
 	sess, rspIEs, e := upf.st.ModificationRequest(ctx, req, rspIEs)
+	if cause := rejectCause(sess, e); cause != nil {
+		return message.NewSessionModificationResponse(0, 0, sess.CpSEID, req.SequenceNumber, 0, cause), nil
+	}
 	if sess == nil {
 		return nil, e
 	}
@@ -144,34 +283,50 @@ func (upf *UPF) SessionModificationRequest(ctx context.Context, req *message.Ses
 
 // SessionDeletionRequest handles a SessionDeletionRequest message.
 func (upf *UPF) SessionDeletionRequest(ctx context.Context, req *message.SessionDeletionRequest) (rsp *message.SessionDeletionResponse, e error) {
-	sess, e := upf.st.DeletionRequest(ctx, req)
+	rspIEs := []*ie.IE{
+		ie.NewCause(ie.CauseRequestAccepted),
+	}
+
+	sess, rspIEs, e := upf.st.DeletionRequest(ctx, req, rspIEs)
 	if sess == nil {
 		return nil, e
 	}
-	return message.NewSessionDeletionResponse(
-		0, 0, sess.CpSEID, req.SequenceNumber, 0,
-		ie.NewCause(ie.CauseRequestAccepted),
-	), e
+	return message.NewSessionDeletionResponse(0, 0, sess.CpSEID, req.SequenceNumber, 0, rspIEs...), e
+}
+
+// rejectCause returns a Cause IE if a session request should be rejected with a response, otherwise nil.
+func rejectCause(sess *Session, e error) *ie.IE {
+	if sess == nil || !errors.Is(e, ErrUlMBR) {
+		return nil
+	}
+	logger.Warn("session request rejected", zap.Uint64("up-seid", sess.UpSEID), zap.Error(e))
+	return ie.NewCause(ie.CauseRuleCreationModificationFailure)
+}
+
+// SessionReportResponse handles a SessionReportResponse message.
+func (upf *UPF) SessionReportResponse(ctx context.Context, rsp *message.SessionReportResponse) error {
+	if rsp.Cause == nil {
+		return errors.New("Cause missing")
+	}
+	cause, e := rsp.Cause.Cause()
+	if e != nil {
+		return fmt.Errorf("Cause: %w", e)
+	}
+	if cause != ie.CauseRequestAccepted {
+		return fmt.Errorf("session report rejected with cause %d", cause)
+	}
+	return nil
 }
 
 // NewUPF constructs UPF.
-func NewUPF(
-	params UpfParams,
-	createFace func(ctx context.Context, loc any) (id string, e error),
-	destroyFace func(ctx context.Context, id string) error,
-) *UPF {
+func NewUPF(params UpfParams, client FaceClient) *UPF {
+	st := NewSessionTable(params.N3Addrs(), upfFaces{
+		params: params,
+		client: client,
+	})
+	st.IgnoreUlMBR = params.IgnoreUlMBR
 	return &UPF{
-		st: NewSessionTable(
-			params.Locator.LocalIP,
-			func(ctx context.Context, sloc SessionLocatorFields) (id string, e error) {
-				loc, e := params.MakeLocator(sloc)
-				if e != nil {
-					return "", e
-				}
-				return createFace(ctx, loc)
-			},
-			destroyFace,
-		),
+		st:     st,
 		params: params,
 	}
 }
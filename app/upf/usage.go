@@ -0,0 +1,220 @@
+package upf
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wmnsk/go-pfcp/ie"
+)
+
+// UsageVolume contains traffic volume in octets.
+type UsageVolume struct {
+	Total uint64 `json:"total,omitempty"`
+	Ul    uint64 `json:"ul,omitempty"`
+	Dl    uint64 `json:"dl,omitempty"`
+}
+
+// UsageRule contains Usage Reporting Rule fields recognized by the UPF.
+type UsageRule struct {
+	URRID uint32 `json:"urrID"`
+
+	// MeasureVolume and MeasureDuration reflect the Measurement Method.
+	MeasureVolume   bool `json:"measureVolume,omitempty"`
+	MeasureDuration bool `json:"measureDuration,omitempty"`
+
+	// Period is the Measurement Period for periodic reporting.
+	// Zero means periodic reporting is disabled.
+	Period time.Duration `json:"period,omitempty"`
+
+	// VolumeThreshold contains volume thresholds.
+	// Zero fields mean the corresponding threshold is disabled.
+	VolumeThreshold UsageVolume `json:"volumeThreshold"`
+
+	// TimeThreshold is the duration threshold.
+	// Zero means the time threshold is disabled.
+	TimeThreshold time.Duration `json:"timeThreshold,omitempty"`
+
+	// Ul and Dl indicate whether uplink and downlink traffic is measured.
+	// They are determined by which PDRs refer to this URR.
+	Ul bool `json:"ul,omitempty"`
+	Dl bool `json:"dl,omitempty"`
+}
+
+// update applies fields in a CreateURR or UpdateURR IE.
+func (r *UsageRule) update(ies []*ie.IE) (e error) {
+	if mmFound := FindIE(ie.MeasurementMethod).Within(ies, nil); mmFound.Type != 0 {
+		r.MeasureVolume, r.MeasureDuration = mmFound.HasVOLUM(), mmFound.HasDURAT()
+	}
+
+	rtFound := FindIE(ie.ReportingTriggers).Within(ies, nil)
+	if rtFound.Type == 0 {
+		return nil
+	}
+	if _, e = rtFound.ReportingTriggers(); e != nil {
+		return fmt.Errorf("ReportingTriggers: %w", e)
+	}
+
+	r.Period = 0
+	if rtFound.HasPERIO() {
+		if r.Period, e = FindIE(ie.MeasurementPeriod).Within(ies, nil).MeasurementPeriod(); e != nil {
+			return fmt.Errorf("MeasurementPeriod: %w", e)
+		}
+	}
+
+	r.VolumeThreshold = UsageVolume{}
+	if rtFound.HasVOLTH() {
+		vt, e := FindIE(ie.VolumeThreshold).Within(ies, nil).VolumeThreshold()
+		if e != nil {
+			return fmt.Errorf("VolumeThreshold: %w", e)
+		}
+		if vt.HasTOVOL() {
+			r.VolumeThreshold.Total = vt.TotalVolume
+		}
+		if vt.HasULVOL() {
+			r.VolumeThreshold.Ul = vt.UplinkVolume
+		}
+		if vt.HasDLVOL() {
+			r.VolumeThreshold.Dl = vt.DownlinkVolume
+		}
+	}
+
+	r.TimeThreshold = 0
+	if rtFound.HasTIMTH() {
+		if r.TimeThreshold, e = FindIE(ie.TimeThreshold).Within(ies, nil).TimeThreshold(); e != nil {
+			return fmt.Errorf("TimeThreshold: %w", e)
+		}
+	}
+	return nil
+}
+
+// FaceVolume contains cumulative traffic counters of a GTP-U face.
+// Uplink is received by the face; downlink is transmitted by the face.
+type FaceVolume struct {
+	UlOctets  uint64 `json:"rxOctets"`
+	UlPackets uint64 `json:"rxFrames"`
+	DlOctets  uint64 `json:"txOctets"`
+	DlPackets uint64 `json:"txFrames"`
+}
+
+// Add returns the sum of two FaceVolume.
+func (v FaceVolume) Add(w FaceVolume) FaceVolume {
+	return FaceVolume{
+		UlOctets:  v.UlOctets + w.UlOctets,
+		UlPackets: v.UlPackets + w.UlPackets,
+		DlOctets:  v.DlOctets + w.DlOctets,
+		DlPackets: v.DlPackets + w.DlPackets,
+	}
+}
+
+// Sub returns the difference of two FaceVolume.
+func (v FaceVolume) Sub(w FaceVolume) FaceVolume {
+	return FaceVolume{
+		UlOctets:  v.UlOctets - w.UlOctets,
+		UlPackets: v.UlPackets - w.UlPackets,
+		DlOctets:  v.DlOctets - w.DlOctets,
+		DlPackets: v.DlPackets - w.DlPackets,
+	}
+}
+
+// Usage Report Trigger flags, octet 5 and octet 6.
+const (
+	urtPERIO = 1 << 0
+	urtVOLTH = 1 << 1
+	urtTIMTH = 1 << 2
+	urtIMMER = 1 << 7
+
+	urtTERMR = 1 << 3
+)
+
+// Volume Measurement flags.
+const (
+	vmTOVOL = 1 << iota
+	vmULVOL
+	vmDLVOL
+	vmTONOP
+	vmULNOP
+	vmDLNOP
+)
+
+// usageMeter tracks measurement state of a Usage Reporting Rule.
+type usageMeter struct {
+	seq        uint32
+	start      time.Time
+	base       FaceVolume
+	nextPeriod time.Time
+}
+
+func newUsageMeter(r UsageRule, now time.Time, vol FaceVolume) *usageMeter {
+	m := &usageMeter{
+		start: now,
+		base:  vol,
+	}
+	if r.Period > 0 {
+		m.nextPeriod = now.Add(r.Period)
+	}
+	return m
+}
+
+// measure returns volume measured since the start of current measurement.
+func (m *usageMeter) measure(r UsageRule, vol FaceVolume) (d FaceVolume) {
+	d = vol.Sub(m.base)
+	if !r.Ul {
+		d.UlOctets, d.UlPackets = 0, 0
+	}
+	if !r.Dl {
+		d.DlOctets, d.DlPackets = 0, 0
+	}
+	return d
+}
+
+// check determines Usage Report Trigger octets for threshold and periodic reporting.
+// Returns nil if no reporting is needed.
+func (m *usageMeter) check(r UsageRule, now time.Time, vol FaceVolume) (trigger []uint8) {
+	var o5 uint8
+	if r.Period > 0 && !now.Before(m.nextPeriod) {
+		o5 |= urtPERIO
+		for !now.Before(m.nextPeriod) {
+			m.nextPeriod = m.nextPeriod.Add(r.Period)
+		}
+	}
+
+	d, vt := m.measure(r, vol), r.VolumeThreshold
+	if r.MeasureVolume && ((vt.Total > 0 && d.UlOctets+d.DlOctets >= vt.Total) ||
+		(vt.Ul > 0 && d.UlOctets >= vt.Ul) || (vt.Dl > 0 && d.DlOctets >= vt.Dl)) {
+		o5 |= urtVOLTH
+	}
+
+	if r.MeasureDuration && r.TimeThreshold > 0 && now.Sub(m.start) >= r.TimeThreshold {
+		o5 |= urtTIMTH
+	}
+
+	if o5 == 0 {
+		return nil
+	}
+	return []uint8{o5, 0, 0}
+}
+
+// report constructs a Usage Report IE and starts a new measurement.
+func (m *usageMeter) report(r UsageRule, now time.Time, vol FaceVolume, typ uint16, trigger []uint8) *ie.IE {
+	d := m.measure(r, vol)
+	ies := []*ie.IE{
+		ie.NewURRID(r.URRID),
+		ie.NewURSEQN(m.seq),
+		ie.NewUsageReportTrigger(trigger...),
+		ie.NewStartTime(m.start),
+		ie.NewEndTime(now),
+	}
+	if r.MeasureVolume {
+		ies = append(ies, ie.NewVolumeMeasurement(vmTOVOL|vmULVOL|vmDLVOL|vmTONOP|vmULNOP|vmDLNOP,
+			d.UlOctets+d.DlOctets, d.UlOctets, d.DlOctets,
+			d.UlPackets+d.DlPackets, d.UlPackets, d.DlPackets))
+	}
+	if r.MeasureDuration {
+		ies = append(ies, ie.NewDurationMeasurement(now.Sub(m.start)))
+	}
+
+	m.seq++
+	m.start = now
+	m.base = vol
+	return ie.NewUsageReport(typ, ies...)
+}
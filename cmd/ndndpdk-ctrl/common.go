@@ -248,6 +248,9 @@ func (r request) Print() error {
 	if r.isSubscription() {
 		gqArgs[0] = strings.Replace(gqlCfg.WebSocketUri, "ws", "http", 1)
 	}
+	if gqlCfg.BearerToken != "" {
+		gqArgs = append(gqArgs, "-H", "Authorization: Bearer "+gqlCfg.BearerToken)
+	}
 	if r.Vars != nil {
 		j, e := json.MarshalIndent(r.Vars, "", "  ")
 		if e != nil {
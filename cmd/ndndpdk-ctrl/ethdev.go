@@ -87,12 +87,40 @@ func init() {
 				Usage:       "enable RxFlow with specified number of `queues`",
 				DefaultText: "disable RxFlow",
 			},
+			&cli.UintFlag{
+				Name:        "tx-queues",
+				Usage:       "set number of hardware TX `queues`",
+				DefaultText: "1",
+			},
+			&cli.StringSliceFlag{
+				Name:  "bond-pci",
+				Usage: "create bonded port with PCI driver on `PCI addresses` (repeatable)",
+			},
+			&cli.StringFlag{
+				Name:        "bond-mode",
+				Usage:       "link aggregation `mode`: ACTIVE_BACKUP, BALANCE, LACP",
+				DefaultText: "ACTIVE_BACKUP",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			vars := map[string]any{
 				"driver": "AF_PACKET",
 			}
 			switch {
+			case c.IsSet("bond-pci"):
+				vars["driver"] = "PCI"
+				members := []map[string]any{}
+				for _, addr := range c.StringSlice("bond-pci") {
+					members = append(members, map[string]any{
+						"driver":  "PCI",
+						"pciAddr": addr,
+					})
+				}
+				bond := map[string]any{"members": members}
+				if c.IsSet("bond-mode") {
+					bond["mode"] = c.String("bond-mode")
+				}
+				vars["bond"] = bond
 			case c.IsSet("pci"):
 				vars["driver"] = "PCI"
 				vars["pciAddr"] = c.String("pci")
@@ -103,7 +131,7 @@ func init() {
 				if c.IsSet("netif") {
 					vars["netif"] = c.String("netif")
 				} else {
-					return errors.New("either --pci or --netif must be set")
+					return errors.New("one of --pci, --netif, --bond-pci must be set")
 				}
 			}
 			if c.IsSet("mtu") {
@@ -112,6 +140,9 @@ func init() {
 			if c.IsSet("rx-flow") {
 				vars["rxFlowQueues"] = c.Uint("rx-flow")
 			}
+			if c.IsSet("tx-queues") {
+				vars["txQueues"] = c.Uint("tx-queues")
+			}
 
 			return clientDoPrint(c.Context, `
 				mutation createEthPort(
@@ -120,6 +151,8 @@ func init() {
 					$netif: String
 					$mtu: Int
 					$rxFlowQueues: Int
+					$txQueues: Int
+					$bond: EthBondConfigInput
 				) {
 					createEthPort(
 						driver: $driver
@@ -127,6 +160,8 @@ func init() {
 						netif: $netif
 						mtu: $mtu
 						rxFlowQueues: $rxFlowQueues
+						txQueues: $txQueues
+						bond: $bond
 					) {`+gqlEthDevFields+`}
 				}
 			`, vars, "createEthPort")
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+func init() {
+	var types cli.StringSlice
+	var face string
+	defineCommand(&cli.Command{
+		Category: "events",
+		Name:     "watch",
+		Usage:    "Watch operational events",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:        "type",
+				Usage:       "event `TYPE`, such as FACE_DOWN (repeatable)",
+				Destination: &types,
+			},
+			&cli.StringFlag{
+				Name:        "face",
+				Usage:       "face `ID`",
+				Destination: &face,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			vars := map[string]any{}
+			if list := types.Value(); len(list) > 0 {
+				for i, t := range list {
+					list[i] = strings.ToUpper(t)
+				}
+				vars["types"] = list
+			}
+			if face != "" {
+				vars["face"] = face
+			}
+			return clientDoPrint(c.Context, `
+				subscription watchEvents($types: [EventType!], $face: ID) {
+					events(types: $types, face: $face) {
+						seq
+						time
+						type
+						face
+						subject
+						details
+					}
+				}
+			`, vars, "events")
+		},
+	})
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"net"
 	"net/netip"
 
@@ -92,6 +94,76 @@ func init() {
 	})
 }
 
+func init() {
+	var id string
+	var cfg struct {
+		MTU                 int             `json:"mtu,omitempty"`
+		ReassemblerCapacity int             `json:"reassemblerCapacity,omitempty"`
+		TxShaper            json.RawMessage `json:"txShaper,omitempty"`
+		DisableTxShaper     bool            `json:"disableTxShaper,omitempty"`
+		AdminDown           *bool           `json:"adminDown,omitempty"`
+	}
+	defineCommand(&cli.Command{
+		Category: "face",
+		Name:     "update-face",
+		Usage:    "Change face configuration",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "id",
+				Usage:       "face `ID`",
+				Destination: &id,
+				Required:    true,
+			},
+			&cli.IntFlag{
+				Name:        "mtu",
+				Usage:       "face `MTU` (excluding all headers)",
+				Destination: &cfg.MTU,
+			},
+			&cli.IntFlag{
+				Name:        "reass-capacity",
+				Usage:       "reassembler `capacity`",
+				Destination: &cfg.ReassemblerCapacity,
+			},
+			&cli.StringFlag{
+				Name:  "tx-shaper",
+				Usage: "enable or change traffic shaper, including CoDel, with TxShaperConfig `JSON`",
+				Action: func(c *cli.Context, s string) error {
+					if !json.Valid([]byte(s)) {
+						return errors.New("--tx-shaper is not valid JSON")
+					}
+					cfg.TxShaper = json.RawMessage(s)
+					return nil
+				},
+			},
+			&cli.BoolFlag{
+				Name:        "no-tx-shaper",
+				Usage:       "disable traffic shaper",
+				Destination: &cfg.DisableTxShaper,
+			},
+			&cli.BoolFlag{
+				Name:  "down",
+				Usage: "set administrative state (--down=true or --down=false)",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.IsSet("down") {
+				down := c.Bool("down")
+				cfg.AdminDown = &down
+			}
+			return clientDoPrint(c.Context, `
+				mutation updateFace($id: ID!, $config: JSON!) {
+					updateFace(id: $id, config: $config) {
+						id
+					}
+				}
+			`, map[string]any{
+				"id":     id,
+				"config": cfg,
+			}, "updateFace")
+		},
+	})
+}
+
 func init() {
 	defineStdinJSONCommand(stdinJSONCommand{
 		Category:   "face",
@@ -21,7 +21,7 @@ var app = &cli.App{
 	Version:              version.V.String(),
 	Usage:                "Control NDN-DPDK service.",
 	EnableBashCompletion: true,
-	Flags: []cli.Flag{
+	Flags: gqlCfg.DefineFlags([]cli.Flag{
 		&cli.StringFlag{
 			Name:        "gqlserver",
 			Usage:       "GraphQL `endpoint` of NDN-DPDK service",
@@ -34,8 +34,11 @@ var app = &cli.App{
 			Value:       false,
 			Destination: &cmdout,
 		},
-	},
-	Before: func(*cli.Context) (e error) {
+	}),
+	Before: func(c *cli.Context) (e error) {
+		if e := gqlCfg.ProcessFlags(c); e != nil {
+			return e
+		}
 		if e := gqlCfg.Validate(); e != nil {
 			return e
 		}
@@ -10,7 +10,7 @@ import (
 )
 
 func init() {
-	var filename, name string
+	var sink, filename, listen, name string
 	var maxSize int
 	var faces, ports flagz.Flagz
 	var wantRX, wantTX, wantRxUnmatched bool
@@ -25,9 +25,11 @@ func init() {
 	createWriter := func(c *cli.Context) error {
 		var result withID
 		if e := clientDoPrint(c.Context, `
-			mutation createPdumpWriter($filename: String!, $maxSize: Int!) {
-				createPdumpWriter(filename: $filename, maxSize: $maxSize) {
+			mutation createPdumpWriter($sink: PdumpSink!, $filename: String, $listen: String, $maxSize: Int!) {
+				createPdumpWriter(sink: $sink, filename: $filename, listen: $listen, maxSize: $maxSize) {
+					sink
 					filename
+					listen
 					id
 					worker {
 						id
@@ -37,7 +39,9 @@ func init() {
 				}
 			}
 		`, map[string]any{
+			"sink":     sink,
 			"filename": filename,
+			"listen":   listen,
 			"maxSize":  maxSize,
 		}, "createPdumpWriter", &result); e != nil {
 			return e
@@ -126,15 +130,25 @@ func init() {
 	}
 
 	commonFlags := []cli.Flag{
+		&cli.StringFlag{
+			Name:        "sink",
+			Usage:       "destination `kind`: FILE, PIPE, TCP, HTTP, or GRAPHQL",
+			Value:       "FILE",
+			Destination: &sink,
+		},
 		&cli.StringFlag{
 			Name:        "filename",
-			Usage:       "destination `filename`",
+			Usage:       "destination `filename`, or named pipe or Unix socket for PIPE sink",
 			Destination: &filename,
-			Required:    true,
+		},
+		&cli.StringFlag{
+			Name:        "listen",
+			Usage:       "listen `address` for TCP and HTTP sinks",
+			Destination: &listen,
 		},
 		&cli.IntFlag{
 			Name:        "max-size",
-			Usage:       "maximum file `size`",
+			Usage:       "maximum file `size`, or stream buffer size for sinks other than FILE",
 			Destination: &maxSize,
 		},
 		&cli.DurationFlag{
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+)
+
+func init() {
+	defineCommand(&cli.Command{
+		Category: "state",
+		Name:     "dump-state",
+		Usage:    "Dump activation parameters and runtime-created objects",
+		Action: func(c *cli.Context) error {
+			return clientDoPrint(c.Context, `
+				query dumpState {
+					dumpState
+				}
+			`, nil, "dumpState")
+		},
+	})
+}
+
+func init() {
+	var dryRun bool
+	defineCommand(&cli.Command{
+		Category: "state",
+		Name:     "apply",
+		Usage:    "Bring the service to a desired state (pass state document from dump-state via stdin)",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        "dry-run",
+				Usage:       "show needed operations without executing them",
+				Destination: &dryRun,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			var state map[string]any
+			if e := json.NewDecoder(os.Stdin).Decode(&state); e != nil {
+				return e
+			}
+
+			var ops []struct {
+				Error string `json:"error"`
+			}
+			if e := clientDoPrint(c.Context, `
+				mutation applyState($state: JSON!, $dryRun: Boolean) {
+					applyState(state: $state, dryRun: $dryRun) {
+						action
+						kind
+						key
+						error
+					}
+				}
+			`, map[string]any{
+				"state":  state,
+				"dryRun": dryRun,
+			}, "applyState", &ops); e != nil {
+				return e
+			}
+
+			for i, op := range ops {
+				if op.Error != "" {
+					return fmt.Errorf("operation %d failed: %s", i, op.Error)
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -133,6 +133,8 @@ func init() {
 	var segmentBegin, segmentEnd uint64
 	var fileSize int64
 	var segmentLen int
+	var discover, resume bool
+	var cc, sha256 string
 	defineCommand(&cli.Command{
 		Category: "trafficgen",
 		Name:     "start-fetch",
@@ -150,6 +152,17 @@ func init() {
 				Destination: &name,
 				Required:    true,
 			},
+			&cli.BoolFlag{
+				Name:        "discover",
+				Usage:       "discover version and segment information via RDR metadata",
+				Destination: &discover,
+			},
+			&cli.StringFlag{
+				Name:        "cc",
+				Usage:       "congestion control `algorithm`: cubic, aimd, delay, fixed",
+				DefaultText: "fetcher default",
+				Destination: &cc,
+			},
 			&cli.Uint64Flag{
 				Name:        "segment-begin",
 				Usage:       "first segment `number` (inclusive)",
@@ -178,11 +191,28 @@ func init() {
 				Usage:       "segment length `octets`",
 				Destination: &segmentLen,
 			},
+			&cli.BoolFlag{
+				Name:        "resume",
+				Usage:       "resume from sidecar bitmap of written segments",
+				Destination: &resume,
+			},
+			&cli.StringFlag{
+				Name:        "sha256",
+				Usage:       "expected SHA-256 `digest` of output file",
+				DefaultText: "from metadata or not verifying",
+				Destination: &sha256,
+			},
 		},
 		Action: func(c *cli.Context) error {
 			task := map[string]any{
 				"prefix": name,
 			}
+			if discover {
+				task["discover"] = true
+			}
+			if cc != "" {
+				task["congestionControl"] = cc
+			}
 			if c.IsSet("segment-begin") {
 				task["segmentBegin"] = segmentBegin
 			}
@@ -191,8 +221,18 @@ func init() {
 			}
 			if filename != "" {
 				task["filename"] = filename
-				task["fileSize"] = fileSize
-				task["segmentLen"] = segmentLen
+				if c.IsSet("file-size") {
+					task["fileSize"] = fileSize
+				}
+				if c.IsSet("segment-len") {
+					task["segmentLen"] = segmentLen
+				}
+				if resume {
+					task["resume"] = true
+				}
+				if sha256 != "" {
+					task["sha256"] = sha256
+				}
 			}
 			return clientDoPrint(c.Context, `
 				mutation fetch($fetcher: ID!, $task: FetchTaskDefInput!) {
@@ -202,11 +242,14 @@ func init() {
 							prefix
 							interestLifetime
 							hopLimit
+							congestionControl
 							segmentBegin
 							segmentEnd
 							filename
 							fileSize
 							segmentLen
+							resume
+							sha256
 						}
 						worker {
 							id
@@ -287,3 +330,148 @@ func init() {
 		},
 	})
 }
+
+func init() {
+	var fetcher, name, outputDir, cc string
+	var concurrency, maxDepth int
+	defineCommand(&cli.Command{
+		Category: "trafficgen",
+		Name:     "start-fetch-dir",
+		Usage:    "Start fetching a directory recursively",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "fetcher",
+				Usage:       "fetcher `ID`",
+				Destination: &fetcher,
+				Required:    true,
+			},
+			&cli.StringFlag{
+				Name:        "name",
+				Usage:       "unversioned directory name `prefix`",
+				Destination: &name,
+				Required:    true,
+			},
+			&cli.StringFlag{
+				Name:        "output-dir",
+				Usage:       "output `directory`",
+				Destination: &outputDir,
+				Required:    true,
+			},
+			&cli.IntFlag{
+				Name:        "concurrency",
+				Usage:       "maximum simultaneous file tasks",
+				Destination: &concurrency,
+				DefaultText: "4",
+			},
+			&cli.IntFlag{
+				Name:        "max-depth",
+				Usage:       "maximum subdirectory depth",
+				Destination: &maxDepth,
+				DefaultText: "32",
+			},
+			&cli.StringFlag{
+				Name:        "cc",
+				Usage:       "congestion control `algorithm`: cubic, aimd, delay, fixed",
+				DefaultText: "fetcher default",
+				Destination: &cc,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			task := map[string]any{
+				"prefix":    name,
+				"outputDir": outputDir,
+			}
+			if concurrency > 0 {
+				task["concurrency"] = concurrency
+			}
+			if maxDepth > 0 {
+				task["maxDepth"] = maxDepth
+			}
+			if cc != "" {
+				task["congestionControl"] = cc
+			}
+			return clientDoPrint(c.Context, `
+				mutation fetchDirectory($fetcher: ID!, $task: FetchDirDefInput!) {
+					fetchDirectory(fetcher: $fetcher, task: $task) {
+						id
+						task {
+							prefix
+							outputDir
+							concurrency
+							maxDepth
+							congestionControl
+						}
+					}
+				}
+			`, map[string]any{
+				"fetcher": fetcher,
+				"task":    task,
+			}, "fetchDirectory")
+		},
+	})
+}
+
+func init() {
+	defineDeleteCommand("trafficgen", "stop-fetch-dir", "Stop fetching a directory", "recursive fetch task")
+}
+
+func init() {
+	var id string
+	var interval time.Duration
+	var autoStop bool
+	defineCommand(&cli.Command{
+		Category: "trafficgen",
+		Name:     "watch-fetch-dir",
+		Usage:    "Watch recursive fetch progress and failures",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "id",
+				Usage:       "recursive fetch task `ID`",
+				Destination: &id,
+				Required:    true,
+			},
+			&cli.DurationFlag{
+				Name:        "interval",
+				Usage:       "update `interval`",
+				Destination: &interval,
+				Value:       time.Second,
+			},
+			&cli.BoolFlag{
+				Name:        "auto-stop",
+				Usage:       "automatically stop recursive fetch task upon finishing",
+				Destination: &autoStop,
+				Value:       false,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			handleUpdate := func(update struct {
+				Finished *time.Duration `json:"finished"`
+			}) bool {
+				return update.Finished == nil
+			}
+			if !autoStop {
+				handleUpdate = nil
+			}
+
+			if e := clientDoPrint(c.Context, `
+				subscription watchFetchDir($id: ID!, $interval: NNNanoseconds!) {
+					fetchDirCounters(id: $id, interval: $interval)
+				}
+			`, map[string]any{
+				"id":       id,
+				"interval": interval.Nanoseconds(),
+			}, "fetchDirCounters", handleUpdate); e != nil {
+				return e
+			}
+			if !autoStop {
+				return nil
+			}
+
+			if cmdout {
+				fmt.Println("# wait until .finished becomes non-null, continue below to stop")
+				fmt.Println()
+			}
+			return runDeleteCommand(c, id)
+		},
+	})
+}
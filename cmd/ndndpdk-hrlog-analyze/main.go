@@ -0,0 +1,168 @@
+// Command ndndpdk-hrlog-analyze computes per-action latency statistics from high resolution per-packet logs.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/app/hrlog/hrlogreader"
+)
+
+var (
+	filename     = flag.String("f", "", "input .hrlog filename")
+	windowFlag   = flag.Duration("window", 0, "time window duration, 0 means entire file")
+	pctFlag      = flag.String("percentiles", "50,90,99,99.9", "comma separated percentiles")
+	byLCore      = flag.Bool("lcore", false, "include per-lcore breakdown")
+	formatFlag   = flag.String("format", "json", "output format: json or csv")
+	actionsFlag  = flag.String("actions", "", "comma separated actions to include, empty means all")
+	percentiles  []float64
+	percentNames []string
+	actionFilter = map[hrlogreader.Action]bool{}
+)
+
+func parseFlags() {
+	flag.Parse()
+
+	for _, token := range strings.Split(*pctFlag, ",") {
+		if token = strings.TrimSpace(token); token == "" {
+			continue
+		}
+		p, e := strconv.ParseFloat(token, 64)
+		if e != nil || p <= 0 || p > 100 {
+			log.Fatalf("bad percentile %s", token)
+		}
+		percentiles = append(percentiles, p)
+		percentNames = append(percentNames, "p"+token)
+	}
+
+	for _, token := range strings.Split(*actionsFlag, ",") {
+		if token = strings.TrimSpace(token); token == "" {
+			continue
+		}
+		var act hrlogreader.Action
+		if e := act.UnmarshalText([]byte(token)); e != nil {
+			log.Fatal(e)
+		}
+		actionFilter[act] = true
+	}
+
+	switch *formatFlag {
+	case "json", "csv":
+	default:
+		log.Fatalf("unknown format %s", *formatFlag)
+	}
+}
+
+type output interface {
+	Write(rows []row)
+	Close()
+}
+
+type jsonOutput struct {
+	enc *json.Encoder
+}
+
+func (o jsonOutput) Write(rows []row) {
+	for _, r := range rows {
+		o.enc.Encode(r)
+	}
+}
+
+func (jsonOutput) Close() {}
+
+type csvOutput struct {
+	w *csv.Writer
+}
+
+func newCsvOutput() (o csvOutput) {
+	o.w = csv.NewWriter(os.Stdout)
+	header := []string{"window", "start", "action", "lcore", "count", "min", "mean"}
+	header = append(header, percentNames...)
+	header = append(header, "max")
+	o.w.Write(header)
+	return o
+}
+
+func (o csvOutput) Write(rows []row) {
+	formatFloat := func(f float64) string { return strconv.FormatFloat(f, 'f', 3, 64) }
+	for _, r := range rows {
+		lcore := "all"
+		if r.LCore != nil {
+			lcore = strconv.Itoa(*r.LCore)
+		}
+		record := []string{
+			strconv.Itoa(r.Window), formatFloat(r.Start), r.Action.String(), lcore,
+			strconv.Itoa(r.Count), formatFloat(r.Min), formatFloat(r.Mean),
+		}
+		for _, name := range percentNames {
+			record = append(record, formatFloat(r.Percentiles[name]))
+		}
+		record = append(record, formatFloat(r.Max))
+		o.w.Write(record)
+	}
+}
+
+func (o csvOutput) Close() {
+	o.w.Flush()
+}
+
+func main() {
+	parseFlags()
+
+	r, e := hrlogreader.Open(*filename)
+	if e != nil {
+		log.Fatal(e)
+	}
+	tscMul := float64(time.Second) / float64(time.Microsecond) / float64(r.TscHz)
+	windowTsc := uint64(windowFlag.Seconds() * float64(r.TscHz))
+
+	var out output = jsonOutput{enc: json.NewEncoder(os.Stdout)}
+	if *formatFlag == "csv" {
+		out = newCsvOutput()
+	}
+	defer out.Close()
+
+	var (
+		clock = windowClock{WindowTsc: windowTsc}
+		index int
+		win   window
+	)
+	flush := func() {
+		start := float64(uint64(index)*windowTsc) / float64(r.TscHz)
+		out.Write(win.Rows(index, start, tscMul, percentiles, percentNames))
+	}
+
+	for u := range r.Read() {
+		entry := hrlogreader.ParseEntry(u)
+		if entry.Action == hrlogreader.ActionTS {
+			if i := clock.Mark(entry.Value); i != index {
+				flush()
+				index = i
+			}
+			continue
+		}
+
+		if len(actionFilter) > 0 && !actionFilter[entry.Action] {
+			continue
+		}
+		win.Add(groupKey{entry.Action, -1}, entry.Value)
+		if *byLCore {
+			win.Add(groupKey{entry.Action, int(entry.LCore)}, entry.Value)
+		}
+	}
+	flush()
+}
+
+func init() {
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: ndndpdk-hrlog-analyze -f INPUT.hrlog [OPTIONS]")
+		flag.PrintDefaults()
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"math"
+	"slices"
+
+	"github.com/usnistgov/ndn-dpdk/app/hrlog/hrlogreader"
+)
+
+const tscMask = 1<<48 - 1
+
+// windowClock assigns log entries to time windows according to time markers.
+type windowClock struct {
+	WindowTsc uint64 // window duration in TSC unit, 0 means entire file
+
+	hasMarker  bool
+	lastMarker uint64
+	elapsed    uint64 // TSC duration since first marker
+}
+
+// Mark processes a time marker, and returns the index of the window that starts or continues at this marker.
+// A window includes its start time and excludes its end time.
+// TSC values are 48-bit and may wrap around between markers.
+func (c *windowClock) Mark(tsc uint64) int {
+	if c.hasMarker {
+		c.elapsed += (tsc - c.lastMarker) & tscMask
+	}
+	c.hasMarker, c.lastMarker = true, tsc
+	if c.WindowTsc == 0 {
+		return 0
+	}
+	return int(c.elapsed / c.WindowTsc)
+}
+
+// groupKey identifies a group of samples within a time window.
+type groupKey struct {
+	Act   hrlogreader.Action
+	LCore int // -1 means all lcores
+}
+
+// row is a line of output.
+type row struct {
+	Window      int                `json:"window"`
+	Start       float64            `json:"start"` // seconds since first time marker
+	Action      hrlogreader.Action `json:"action"`
+	LCore       *int               `json:"lcore,omitempty"` // nil means all lcores
+	Count       int                `json:"count"`
+	Min         float64            `json:"min"` // microseconds
+	Mean        float64            `json:"mean"`
+	Max         float64            `json:"max"`
+	Percentiles map[string]float64 `json:"percentiles"`
+}
+
+// window collects samples in a time window.
+type window struct {
+	groups map[groupKey][]uint64
+}
+
+func (w *window) Add(key groupKey, value uint64) {
+	if w.groups == nil {
+		w.groups = map[groupKey][]uint64{}
+	}
+	w.groups[key] = append(w.groups[key], value)
+}
+
+// Rows computes statistics of each group, sorted by action and lcore.
+func (w *window) Rows(index int, start float64, tscMul float64, percentiles []float64, percentileNames []string) (rows []row) {
+	keys := make([]groupKey, 0, len(w.groups))
+	for key := range w.groups {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b groupKey) int {
+		if a.Act != b.Act {
+			return int(a.Act) - int(b.Act)
+		}
+		return a.LCore - b.LCore
+	})
+
+	for _, key := range keys {
+		values := w.groups[key]
+		slices.Sort(values)
+
+		r := row{
+			Window:      index,
+			Start:       start,
+			Action:      key.Act,
+			Count:       len(values),
+			Min:         float64(values[0]) * tscMul,
+			Max:         float64(values[len(values)-1]) * tscMul,
+			Percentiles: map[string]float64{},
+		}
+		if key.LCore >= 0 {
+			r.LCore = &key.LCore
+		}
+
+		sum := 0.0
+		for _, v := range values {
+			sum += float64(v)
+		}
+		r.Mean = sum / float64(len(values)) * tscMul
+
+		for i, p := range percentiles {
+			r.Percentiles[percentileNames[i]] = float64(values[nearestRank(len(values), p)]) * tscMul
+		}
+		rows = append(rows, r)
+	}
+
+	w.groups = nil
+	return rows
+}
+
+// nearestRank returns the index of the p-th percentile in a sorted list of n samples, where n is positive.
+func nearestRank(n int, p float64) int {
+	// tolerance absorbs rounding errors of decimal percentiles, e.g. 99.9/100*1000 is slightly above 999
+	rank := int(math.Ceil(p*float64(n)/100 - 1e-9))
+	return min(max(rank-1, 0), n-1)
+}
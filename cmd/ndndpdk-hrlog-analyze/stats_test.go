@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/usnistgov/ndn-dpdk/app/hrlog/hrlogreader"
+	"github.com/usnistgov/ndn-dpdk/core/testenv"
+)
+
+var makeAR = testenv.MakeAR
+
+func TestNearestRank(t *testing.T) {
+	tests := []struct {
+		n     int
+		p     float64
+		index int
+	}{
+		{1, 0, 0},
+		{1, 50, 0},
+		{1, 100, 0},
+		{2, 50, 0},
+		{2, 50.1, 1},
+		{2, 100, 1},
+		{4, 25, 0},
+		{4, 75, 2},
+		{5, 50, 2},
+		{10, 90, 8},
+		{10, 99, 9},
+		{100, 99, 98},
+		{100, 100, 99},
+		{1000, 99.9, 998},
+		{1001, 99.9, 999},
+		{10, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d-%g", tt.n, tt.p), func(t *testing.T) {
+			assert, _ := makeAR(t)
+			assert.Equal(tt.index, nearestRank(tt.n, tt.p))
+		})
+	}
+}
+
+func TestWindowRows(t *testing.T) {
+	assert, require := makeAR(t)
+	percentiles, percentileNames := []float64{0, 50, 100}, []string{"0", "50", "100"}
+
+	var w window
+	assert.Empty(w.Rows(0, 0, 1, percentiles, percentileNames))
+
+	w.Add(groupKey{hrlogreader.ActionOD, -1}, 7)
+	for _, v := range []uint64{40, 10, 30, 20} {
+		w.Add(groupKey{hrlogreader.ActionOI, 1}, v)
+		w.Add(groupKey{hrlogreader.ActionOI, -1}, v)
+	}
+	rows := w.Rows(3, 1.5, 0.5, percentiles, percentileNames)
+	require.Len(rows, 3)
+
+	// sorted by action, then lcore with all lcores first
+	assert.Equal(hrlogreader.ActionOI, rows[0].Action)
+	assert.Nil(rows[0].LCore)
+	assert.Equal(hrlogreader.ActionOI, rows[1].Action)
+	if assert.NotNil(rows[1].LCore) {
+		assert.Equal(1, *rows[1].LCore)
+	}
+	assert.Equal(hrlogreader.ActionOD, rows[2].Action)
+
+	r := rows[0]
+	assert.Equal(3, r.Window)
+	assert.Equal(1.5, r.Start)
+	assert.Equal(4, r.Count)
+	assert.Equal(5.0, r.Min)
+	assert.Equal(12.5, r.Mean)
+	assert.Equal(20.0, r.Max)
+	assert.Equal(map[string]float64{"0": 5, "50": 10, "100": 20}, r.Percentiles)
+
+	// a single sample is every percentile
+	r = rows[2]
+	assert.Equal(1, r.Count)
+	assert.Equal(map[string]float64{"0": 3.5, "50": 3.5, "100": 3.5}, r.Percentiles)
+
+	// samples are cleared after computing rows
+	assert.Empty(w.Rows(4, 2, 0.5, percentiles, percentileNames))
+}
+
+func TestWindowClock(t *testing.T) {
+	tests := []struct {
+		windowTsc uint64
+		markers   []uint64
+		indices   []int
+	}{
+		// entire file
+		{0, []uint64{100, 5000, 90000}, []int{0, 0, 0}},
+		// first marker starts window 0 regardless of its absolute value
+		{1000, []uint64{123456}, []int{0}},
+		// window includes its start and excludes its end
+		{1000, []uint64{5000, 5999, 6000, 6001, 6999, 7000}, []int{0, 0, 1, 1, 1, 2}},
+		// windows without markers are skipped
+		{1000, []uint64{0, 500, 3500, 3999, 4000}, []int{0, 0, 3, 3, 4}},
+		// TSC wraps around at 48 bits
+		{1000, []uint64{tscMask - 499, tscMask, 0, 499, 500}, []int{0, 0, 0, 0, 1}},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			assert, _ := makeAR(t)
+			c := windowClock{WindowTsc: tt.windowTsc}
+			indices := []int{}
+			for _, m := range tt.markers {
+				indices = append(indices, c.Mark(m))
+			}
+			assert.Equal(tt.indices, indices)
+		})
+	}
+}
@@ -22,14 +22,17 @@ func main() {
 	tscMul := float64(time.Second) / float64(time.Microsecond) / float64(r.TscHz)
 
 	hists := map[uint16]*histogram{}
-	for entry := range r.Read() {
-		lcoreAct := uint16(entry)
-		value := entry >> 16
-		microseconds := float64(value) * tscMul
+	for u := range r.Read() {
+		entry := hrlogreader.ParseEntry(u)
+		if entry.Action == hrlogreader.ActionTS {
+			continue
+		}
+		lcoreAct := uint16(u)
+		microseconds := float64(entry.Value) * tscMul
 
 		hist := hists[lcoreAct]
 		if hist == nil {
-			hist = newHistogram(uint8(lcoreAct), uint8(lcoreAct>>8))
+			hist = newHistogram(uint8(entry.Action), entry.LCore)
 			hists[lcoreAct] = hist
 		}
 		hist.Add(int(microseconds))
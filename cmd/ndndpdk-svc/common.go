@@ -11,6 +11,7 @@ import (
 	"github.com/usnistgov/ndn-dpdk/dpdk/ealinit"
 	"github.com/usnistgov/ndn-dpdk/dpdk/ealthread"
 	"github.com/usnistgov/ndn-dpdk/dpdk/ethdev/ethnetif"
+	"github.com/usnistgov/ndn-dpdk/dpdk/mempool"
 	"github.com/usnistgov/ndn-dpdk/dpdk/pktmbuf"
 	"github.com/usnistgov/ndn-dpdk/iface"
 	"github.com/usnistgov/ndn-dpdk/iface/socketface"
@@ -22,10 +23,11 @@ import (
 
 // CommonArgs contains arguments shared between forwarder and traffic generator.
 type CommonArgs struct {
-	Eal        ealconfig.Config        `json:"eal,omitempty"`
-	Mempool    pktmbuf.TemplateUpdates `json:"mempool,omitempty"`
-	LCoreAlloc ealthread.Config        `json:"lcoreAlloc,omitempty"`
-	SocketFace socketface.GlobalConfig `json:"socketFace,omitempty"`
+	Eal             ealconfig.Config        `json:"eal,omitempty"`
+	Mempool         pktmbuf.TemplateUpdates `json:"mempool,omitempty"`
+	LCoreAlloc      ealthread.Config        `json:"lcoreAlloc,omitempty"`
+	SocketFace      socketface.GlobalConfig `json:"socketFace,omitempty"`
+	ResourceMonitor mempool.MonitorConfig   `json:"resourceMonitor,omitempty"`
 }
 
 func (a *CommonArgs) apply() error {
@@ -38,6 +40,7 @@ func (a *CommonArgs) apply() error {
 	}
 
 	a.Mempool.Apply()
+	mempool.StartMonitor(a.ResourceMonitor)
 
 	lcoreAlloc, e := a.LCoreAlloc.Extract(map[string]int{hrlog.Role: 0, pdump.Role: 0})
 	if e != nil {
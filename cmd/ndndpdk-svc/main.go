@@ -5,6 +5,7 @@ package main
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
@@ -14,10 +15,12 @@ import (
 	"github.com/coreos/go-systemd/v22/daemon"
 	"github.com/graphql-go/graphql"
 	"github.com/urfave/cli/v2"
+	"github.com/usnistgov/ndn-dpdk/app/svcstate"
 	"github.com/usnistgov/ndn-dpdk/core/gqlclient"
 	"github.com/usnistgov/ndn-dpdk/core/gqlserver"
 	"github.com/usnistgov/ndn-dpdk/core/jsonhelper"
 	"github.com/usnistgov/ndn-dpdk/core/logging"
+	"github.com/usnistgov/ndn-dpdk/core/metrics"
 	"github.com/usnistgov/ndn-dpdk/core/version"
 	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
 	"github.com/usnistgov/ndn-dpdk/dpdk/spdkenv"
@@ -25,20 +28,62 @@ import (
 	"golang.org/x/sys/unix"
 )
 
-var logger = logging.New("main")
+var (
+	logger  = logging.New("main")
+	authCfg gqlserver.AuthConfig
+)
 
 func init() {
 	http.HandleFunc("/robots.txt", func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Add("Content-Type", "text/plain")
 		w.Write([]byte("User-Agent: *\nDisallow: /\n"))
 	})
+	http.Handle("/metrics", metrics.NewHandler())
 }
 
-func init() {
-	type activator interface {
-		Activate() error
+type activator interface {
+	Activate() error
+}
+
+var (
+	isActivated atomic.Bool
+	activators  = map[string]func() activator{
+		"forwarder":  func() activator { return &fwArgs{} },
+		"trafficgen": func() activator { return &genArgs{} },
+		"fileserver": func() activator { return &fileServerArgs{} },
+	}
+)
+
+// activate activates the service with a role and activation parameters.
+func activate(role string, a any) (e error) {
+	newArg, ok := activators[role]
+	if !ok {
+		return fmt.Errorf("unknown activation role %s", role)
+	}
+	arg := newArg()
+	if e = jsonhelper.Roundtrip(a, arg, jsonhelper.DisallowUnknownFields); e != nil {
+		return e
+	}
+
+	if !isActivated.CompareAndSwap(false, true) {
+		return errors.New("ndndpdk-svc is already activated")
+	}
+
+	initXDPProgram()
+
+	logEntry := logger.With(zap.String("role", role))
+	logEntry.Info("activate start")
+	if e = arg.Activate(); e != nil {
+		delayedShutdown(func() { logEntry.Fatal("activate error", zap.Error(e)) })
+		return e
 	}
-	var isActivated atomic.Bool
+	svcstate.SetActivation(role, a)
+	logEntry.Info("activate success")
+	return nil
+}
+
+func init() {
+	svcstate.Activator = activate
 
 	gqlserver.AddMutation(&graphql.Field{
 		Name: "activate",
@@ -62,41 +107,16 @@ func init() {
 			},
 		},
 		Type: gqlserver.NonNullBoolean,
-		Resolve: func(p graphql.ResolveParams) (result any, e error) {
+		Resolve: func(p graphql.ResolveParams) (any, error) {
 			if len(p.Args) != 1 {
 				return nil, errors.New("exactly one activate argument should be specified")
 			}
-			result = true
-
-			tryActivate := func(role string, arg activator) {
-				a, ok := p.Args[role]
-				if !ok {
-					return
-				}
-				if e = jsonhelper.Roundtrip(a, arg, jsonhelper.DisallowUnknownFields); e != nil {
-					return
-				}
-
-				if !isActivated.CompareAndSwap(false, true) {
-					e = errors.New("ndndpdk-svc is already activated")
-					return
-				}
-
-				initXDPProgram()
-
-				logEntry := logger.With(zap.String("role", role))
-				logEntry.Info("activate start")
-				if e = arg.Activate(); e != nil {
-					delayedShutdown(func() { logEntry.Fatal("activate error", zap.Error(e)) })
-					return
+			for role, a := range p.Args {
+				if e := activate(role, a); e != nil {
+					return nil, e
 				}
-				logEntry.Info("activate success")
 			}
-
-			tryActivate("forwarder", &fwArgs{})
-			tryActivate("trafficgen", &genArgs{})
-			tryActivate("fileserver", &fileServerArgs{})
-			return
+			return true, nil
 		},
 	})
 }
@@ -139,9 +159,45 @@ var app = &cli.App{
 			Usage: "GraphQL HTTP server base URI",
 			Value: "http://127.0.0.1:3030/",
 		},
+		&cli.StringFlag{
+			Name:        "tls-cert",
+			Usage:       "GraphQL HTTPS server certificate `file`",
+			Destination: &authCfg.TLSCert,
+		},
+		&cli.StringFlag{
+			Name:        "tls-key",
+			Usage:       "GraphQL HTTPS server private key `file`",
+			Destination: &authCfg.TLSKey,
+		},
+		&cli.StringFlag{
+			Name:        "client-ca",
+			Usage:       "CA certificates `file` for admin client certificates",
+			Destination: &authCfg.ClientCA,
+		},
+		&cli.StringFlag{
+			Name:        "readonly-client-ca",
+			Usage:       "CA certificates `file` for read-only client certificates",
+			Destination: &authCfg.ReadOnlyClientCA,
+		},
+		&cli.StringFlag{
+			Name:  "token-file",
+			Usage: "bearer tokens `file`, each line has a role (admin or readonly) and a token",
+		},
 	},
 	Action: func(c *cli.Context) (e error) {
-		listen, e := gqlclient.MakeListenAddress(c.String("gqlserver"))
+		listen, useTLS, e := gqlclient.MakeListenAddress(c.String("gqlserver"))
+		if e != nil {
+			return cli.Exit(e, 1)
+		}
+		if useTLS != authCfg.EnableTLS() {
+			return cli.Exit("GraphQL server URI should have 'https' scheme if and only if --tls-cert is specified", 1)
+		}
+		if tokenFile := c.String("token-file"); tokenFile != "" {
+			if e := authCfg.LoadTokenFile(tokenFile); e != nil {
+				return cli.Exit(e, 1)
+			}
+		}
+		tlsCfg, e := authCfg.TLSConfig()
 		if e != nil {
 			return cli.Exit(e, 1)
 		}
@@ -157,8 +213,20 @@ var app = &cli.App{
 		go systemdNotify()
 
 		gqlserver.Prepare()
-		logger.Info("GraphQL HTTP server starting", zap.String("listen", listen))
-		return cli.Exit(http.ListenAndServe(listen, nil), 1)
+		server := &http.Server{
+			Addr:      listen,
+			Handler:   authCfg.Handler(http.DefaultServeMux),
+			TLSConfig: tlsCfg,
+		}
+		logger.Info("GraphQL HTTP server starting",
+			zap.String("listen", listen),
+			zap.Bool("tls", useTLS),
+			zap.Bool("auth", authCfg.EnableAuth()),
+		)
+		if useTLS {
+			return cli.Exit(server.ListenAndServeTLS("", ""), 1)
+		}
+		return cli.Exit(server.ListenAndServe(), 1)
 	},
 }
 
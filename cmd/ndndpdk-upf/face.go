@@ -3,10 +3,16 @@ package main
 import (
 	"context"
 
+	"github.com/usnistgov/ndn-dpdk/app/upf"
 	"go.uber.org/zap"
 )
 
-func createFace(ctx context.Context, loc any) (id string, e error) {
+// gqlFaceClient implements upf.FaceClient via GraphQL.
+type gqlFaceClient struct{}
+
+var _ upf.FaceClient = gqlFaceClient{}
+
+func (gqlFaceClient) CreateFace(ctx context.Context, loc any) (id string, e error) {
 	var reply struct {
 		ID string `json:"id"`
 	}
@@ -26,7 +32,28 @@ func createFace(ctx context.Context, loc any) (id string, e error) {
 	return reply.ID, nil
 }
 
-func destroyFace(ctx context.Context, id string) error {
+func (gqlFaceClient) UpdateFace(ctx context.Context, id string, cfg any) error {
+	var reply struct {
+		ID string `json:"id"`
+	}
+	e := client.Do(ctx, `
+		mutation updateFace($id: ID!, $config: JSON!) {
+			updateFace(id: $id, config: $config) {
+				id
+			}
+		}
+	`, map[string]any{
+		"id":     id,
+		"config": cfg,
+	}, "updateFace", &reply)
+	if e != nil {
+		return e
+	}
+	logger.Info("face updated", zap.String("face-id", id), zap.Any("config", cfg))
+	return nil
+}
+
+func (gqlFaceClient) DestroyFace(ctx context.Context, id string) error {
 	deleted, e := client.Delete(ctx, id)
 	if e != nil {
 		return e
@@ -34,3 +61,23 @@ func destroyFace(ctx context.Context, id string) error {
 	logger.Info("face destroyed", zap.Bool("deleted", deleted), zap.String("face-id", id))
 	return nil
 }
+
+func (gqlFaceClient) FaceVolume(ctx context.Context, id string) (vol upf.FaceVolume, e error) {
+	var reply struct {
+		Counters upf.FaceVolume `json:"counters"`
+	}
+	e = client.Do(ctx, `
+		query faceCounters($id: ID!) {
+			face: node(id: $id) {
+				... on Face {
+					counters {
+						rxFrames rxOctets txFrames txOctets
+					}
+				}
+			}
+		}
+	`, map[string]any{
+		"id": id,
+	}, "face", &reply)
+	return reply.Counters, e
+}
@@ -25,15 +25,18 @@ var (
 var app = &cli.App{
 	Version: version.V.String(),
 	Usage:   "Use NDN-DPDK as a UPF.",
-	Flags: upfParams.DefineFlags([]cli.Flag{
+	Flags: upfParams.DefineFlags(gqlCfg.DefineFlags([]cli.Flag{
 		&cli.StringFlag{
 			Name:        "gqlserver",
 			Usage:       "GraphQL `endpoint` of NDN-DPDK service",
 			Value:       "http://127.0.0.1:3030/",
 			Destination: &gqlCfg.HTTPUri,
 		},
-	}),
+	})),
 	Action: func(c *cli.Context) (e error) {
+		if e = gqlCfg.ProcessFlags(c); e != nil {
+			return e
+		}
 		if client, e = gqlclient.New(gqlCfg); e != nil {
 			return e
 		}
@@ -41,7 +44,7 @@ var app = &cli.App{
 			return e
 		}
 
-		theUPF = upf.NewUPF(upfParams, createFace, destroyFace)
+		theUPF = upf.NewUPF(upfParams, gqlFaceClient{})
 		return theUPF.Listen(c.Context)
 	},
 }
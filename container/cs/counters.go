@@ -18,10 +18,10 @@ var ReadMissCounter func(pcct *pcct.Pcct) uint64
 
 // Counters contains CS counters.
 type Counters struct {
-	DirectEntries    int `json:"directEntries" gqldesc:"Direct entries." subtract:"-"`
-	DirectCapacity   int `json:"directCapacity" gqldesc:"Direct capacity." subtract:"-"`
-	IndirectEntries  int `json:"indirectEntries" gqldesc:"Indirect entries." subtract:"-"`
-	IndirectCapacity int `json:"indirectCapacity" gqldesc:"Indirect capacity." subtract:"-"`
+	DirectEntries    int `json:"directEntries" gqldesc:"Direct entries." subtract:"-" metric:"gauge"`
+	DirectCapacity   int `json:"directCapacity" gqldesc:"Direct capacity." subtract:"-" metric:"gauge"`
+	IndirectEntries  int `json:"indirectEntries" gqldesc:"Indirect entries." subtract:"-" metric:"gauge"`
+	IndirectCapacity int `json:"indirectCapacity" gqldesc:"Indirect capacity." subtract:"-" metric:"gauge"`
 
 	NHitMemory   uint64 `json:"nHitMemory" gqldesc:"Lookup hits on memory entry."`
 	NHitDisk     uint64 `json:"nHitDisk" gqldesc:"Lookup hits on disk entry."`
@@ -37,6 +37,13 @@ func (cs *Cs) Capacity(list ListID) int {
 	return int(C.Cs_GetCapacity(cs.ptr(), C.CsListID(list)))
 }
 
+// SetCapacity changes in-memory direct entries capacity and indirect entries capacity.
+// Excess entries are evicted immediately.
+// This must be invoked in the thread that owns the CS, or when that thread is stopped.
+func (cs *Cs) SetCapacity(capMemory, capIndirect int) {
+	C.Cs_SetCapacity(cs.ptr(), C.uint32_t(max(capMemory, EvictBulk)), C.uint32_t(max(capIndirect, EvictBulk)))
+}
+
 // CountEntries returns number of entries in the specified list.
 func (cs *Cs) CountEntries(list ListID) int {
 	return int(C.Cs_CountEntries(cs.ptr(), C.CsListID(list)))
@@ -156,3 +156,37 @@ func TestIndirectDep(t *testing.T) {
 	assert.GreaterOrEqual(fixture.Cs.CountEntries(cs.ListDirect), 100)
 	assert.GreaterOrEqual(fixture.Cs.CountEntries(cs.ListIndirect), 0)
 }
+
+func TestSetCapacity(t *testing.T) {
+	assert, _ := makeAR(t)
+	fixture := NewFixture(t, pcct.Config{
+		CsMemoryCapacity:   400,
+		CsIndirectCapacity: 400,
+	})
+
+	// insert 1-400 and use 201-400, T1=[1..200], T2=[201..400]
+	assert.Equal(400, fixture.InsertBulk(1, 400, "/N/%d", "/N/%d", ndn.MustBeFreshFlag))
+	assert.Equal(200, fixture.FindBulk(201, 400, "/N/%d", ndn.MustBeFreshFlag))
+	assert.Equal(400, fixture.Cs.CountEntries(cs.ListDirect))
+
+	// shrink: excess entries are evicted immediately
+	fixture.Cs.SetCapacity(100, 100)
+	assert.Equal(100, fixture.Cs.Capacity(cs.ListDirect))
+	assert.Equal(100, fixture.Cs.Capacity(cs.ListIndirect))
+	assert.LessOrEqual(fixture.Cs.CountEntries(cs.ListDirect), 100)
+	assert.LessOrEqual(fixture.Cs.CountEntries(cs.ListIndirect), 100)
+	assert.LessOrEqual(fixture.Cs.CountEntries(cs.ListDirectT1)+fixture.Cs.CountEntries(cs.ListDirectB1), 100)
+	assert.LessOrEqual(fixture.Cs.CountEntries(cs.ListDirectB2), 100)
+	nArc := 0
+	for _, l := range []cs.ListID{cs.ListDirectT1, cs.ListDirectB1, cs.ListDirectT2, cs.ListDirectB2} {
+		nArc += fixture.Cs.CountEntries(l)
+	}
+	assert.LessOrEqual(nArc, 200) // ghost entries are trimmed to 2c
+	assert.Zero(fixture.Cs.CountEntries(cs.ListDirectDel))
+
+	// grow: more entries can be inserted
+	fixture.Cs.SetCapacity(800, 800)
+	assert.Equal(800, fixture.Cs.Capacity(cs.ListDirect))
+	assert.Equal(600, fixture.InsertBulk(1001, 1600, "/N/%d", "/N/%d", ndn.MustBeFreshFlag))
+	assert.Equal(600, fixture.FindBulk(1001, 1600, "/N/%d", ndn.MustBeFreshFlag))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/usnistgov/ndn-dpdk/container/fib/fibreplica"
 	"github.com/usnistgov/ndn-dpdk/container/fib/fibtree"
 	"github.com/usnistgov/ndn-dpdk/container/strategycode"
+	"github.com/usnistgov/ndn-dpdk/core/events"
 	"github.com/usnistgov/ndn-dpdk/core/urcu"
 	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
 	"github.com/usnistgov/ndn-dpdk/ndn"
@@ -76,7 +77,8 @@ func (fib *Fib) Insert(entry fibdef.Entry) (e error) {
 		return fmt.Errorf("entry.Validate: %w", e)
 	}
 
-	if sc := strategycode.Get(entry.Strategy); sc == nil {
+	sc := strategycode.Get(entry.Strategy)
+	if sc == nil {
 		return errors.New("entry.Strategy not found")
 	} else if e := sc.ValidateParams(entry.Params); e != nil {
 		return fmt.Errorf("entry.Strategy.ValidateParams: %w", e)
@@ -85,6 +87,16 @@ func (fib *Fib) Insert(entry fibdef.Entry) (e error) {
 	eal.CallMain(func() {
 		e = fib.doUpdate(fib.tree.Insert(entry))
 	})
+	if e == nil {
+		events.Publish(events.Event{
+			Type:    events.TypeFibInsert,
+			Subject: entry.Name.String(),
+			Details: map[string]any{
+				"nexthops": entry.Nexthops,
+				"strategy": sc.Name(),
+			},
+		})
+	}
 	return e
 }
 
@@ -93,6 +105,9 @@ func (fib *Fib) Erase(name ndn.Name) (e error) {
 	eal.CallMain(func() {
 		e = fib.doUpdate(fib.tree.Erase(name))
 	})
+	if e == nil {
+		events.Publish(events.Event{Type: events.TypeFibErase, Subject: name.String()})
+	}
 	return e
 }
 
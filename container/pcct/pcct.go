@@ -7,8 +7,10 @@ package pcct
 import "C"
 import (
 	"fmt"
+	"sync"
 	"unsafe"
 
+	"github.com/usnistgov/ndn-dpdk/core/events"
 	"github.com/usnistgov/ndn-dpdk/core/logging"
 	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
 	"github.com/usnistgov/ndn-dpdk/dpdk/mempool"
@@ -17,6 +19,26 @@ import (
 
 var logger = logging.New("pcct")
 
+var (
+	livePcctsLock sync.Mutex
+	livePccts     = map[*Pcct]bool{}
+)
+
+func init() {
+	mempool.AddMonitorSource(mempool.MonitorSource{
+		Kind:           "PCCT",
+		PressureEvent:  events.TypePcctPressure,
+		ExhaustedEvent: events.TypePcctPressure,
+		Walk: func(cb func(name string, mp *mempool.Mempool)) {
+			livePcctsLock.Lock()
+			defer livePcctsLock.Unlock()
+			for pcct := range livePccts {
+				cb(pcct.String(), pcct.AsMempool())
+			}
+		},
+	})
+}
+
 // PIT and CS initialization functions.
 // These are assigned during package pit and package cs initialization.
 var InitPit, InitCs func(cfg Config, pcct *Pcct)
@@ -54,6 +76,10 @@ func (pcct *Pcct) String() string {
 
 // Close destroys the PCCT.
 func (pcct *Pcct) Close() error {
+	livePcctsLock.Lock()
+	delete(livePccts, pcct)
+	livePcctsLock.Unlock()
+
 	C.Pcct_Clear((*C.Pcct)(pcct))
 	return pcct.AsMempool().Close()
 }
@@ -94,5 +120,9 @@ func New(cfg Config, socket eal.NumaSocket) (pcct *Pcct, e error) {
 
 	InitPit(cfg, pcct)
 	InitCs(cfg, pcct)
+
+	livePcctsLock.Lock()
+	livePccts[pcct] = true
+	livePcctsLock.Unlock()
 	return pcct, nil
 }
@@ -5,17 +5,20 @@ package pit
 */
 import "C"
 import (
+	"reflect"
 	"unsafe"
 
+	"github.com/graphql-go/graphql"
+	"github.com/usnistgov/ndn-dpdk/core/gqlserver"
 	"github.com/usnistgov/ndn-dpdk/core/nnduration"
 	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
 )
 
 // SuppressConfig contains PIT suppression configuration.
 type SuppressConfig struct {
-	Min        nnduration.Nanoseconds `json:"min,omitempty"`
-	Max        nnduration.Nanoseconds `json:"max,omitempty"`
-	Multiplier float64                `json:"multiplier,omitempty"`
+	Min        nnduration.Nanoseconds `json:"min,omitempty" gqldesc:"Initial suppression duration."`
+	Max        nnduration.Nanoseconds `json:"max,omitempty" gqldesc:"Maximum suppression duration."`
+	Multiplier float64                `json:"multiplier,omitempty" gqldesc:"Suppression duration multiplier."`
 }
 
 // CopyToC copies this configuration to *C.PitSuppressConfig.
@@ -29,3 +32,28 @@ func (sc SuppressConfig) CopyToC(ptr unsafe.Pointer) {
 		c.multiplier = 2.0
 	}
 }
+
+// SuppressConfigFromC reads configuration from *C.PitSuppressConfig.
+func SuppressConfigFromC(ptr unsafe.Pointer) (sc SuppressConfig) {
+	c := (*C.PitSuppressConfig)(ptr)
+	sc.Min = nnduration.Nanoseconds(eal.FromTscDuration(int64(c.min)))
+	sc.Max = nnduration.Nanoseconds(eal.FromTscDuration(int64(c.max)))
+	sc.Multiplier = float64(c.multiplier)
+	return
+}
+
+var suppressConfigFieldTypes = gqlserver.FieldTypes{
+	reflect.TypeFor[nnduration.Nanoseconds](): nnduration.GqlNanoseconds,
+}
+
+// GraphQL types.
+var (
+	GqlSuppressConfigType = graphql.NewObject(graphql.ObjectConfig{
+		Name:   "PitSuppressConfig",
+		Fields: gqlserver.BindFields[SuppressConfig](suppressConfigFieldTypes),
+	})
+	GqlSuppressConfigInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:   "PitSuppressConfigInput",
+		Fields: gqlserver.BindInputFields[SuppressConfig](suppressConfigFieldTypes),
+	})
+)
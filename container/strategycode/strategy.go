@@ -16,6 +16,7 @@ import (
 	"unsafe"
 
 	"github.com/usnistgov/ndn-dpdk/bpf"
+	"github.com/usnistgov/ndn-dpdk/core/events"
 	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
 	"github.com/xeipuuv/gojsonschema"
 	"go.uber.org/zap"
@@ -43,6 +44,7 @@ type Strategy struct {
 	c      *C.StrategyCode
 	id     int
 	name   string
+	elf    []byte
 	init   C.StrategyCodeProg
 	schema *gojsonschema.Schema
 }
@@ -62,6 +64,12 @@ func (sc *Strategy) Name() string {
 	return sc.name
 }
 
+// ELF returns the ELF object if the strategy was loaded via Load.
+// It returns nil if the strategy was loaded from a file.
+func (sc *Strategy) ELF() []byte {
+	return sc.elf
+}
+
 // ValidateParams validates JSON parameters.
 func (sc *Strategy) ValidateParams(params map[string]any) error {
 	if sc.schema == nil {
@@ -141,7 +149,11 @@ func Load(name string, elf []byte) (sc *Strategy, e error) {
 	}
 	file.Close()
 
-	return LoadFile(name, filename)
+	if sc, e = LoadFile(name, filename); e != nil {
+		return nil, e
+	}
+	sc.elf = elf
+	return sc, nil
 }
 
 // LoadFile loads a strategy BPF program from ELF file.
@@ -204,6 +216,11 @@ func LoadFile(name, filename string) (sc *Strategy, e error) {
 	sc.c.id = C.int(sc.id)
 	sc.c.goHandle = C.uintptr_t(cgo.NewHandle(sc))
 	C.StrategyCode_Ref(sc.c)
+	events.Publish(events.Event{
+		Type:    events.TypeStrategyLoad,
+		Subject: name,
+		Details: map[string]any{"id": sc.id},
+	})
 	return sc, nil
 }
 
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// Type identifies the kind of an operational event.
+type Type string
+
+// Type values.
+const (
+	TypeFaceCreated      Type = "FACE_CREATED"
+	TypeFaceUp           Type = "FACE_UP"
+	TypeFaceDown         Type = "FACE_DOWN"
+	TypeFaceDestroyed    Type = "FACE_DESTROYED"
+	TypeFibInsert        Type = "FIB_INSERT"
+	TypeFibErase         Type = "FIB_ERASE"
+	TypeStrategyLoad     Type = "STRATEGY_LOAD"
+	TypeEthLinkUp        Type = "ETHDEV_LINK_UP"
+	TypeEthLinkDown      Type = "ETHDEV_LINK_DOWN"
+	TypeMempoolPressure  Type = "MEMPOOL_PRESSURE"
+	TypeMempoolExhausted Type = "MEMPOOL_EXHAUSTED"
+	TypePcctPressure     Type = "PCCT_PRESSURE"
+)
+
+// Types lists known event types.
+var Types = []Type{
+	TypeFaceCreated, TypeFaceUp, TypeFaceDown, TypeFaceDestroyed,
+	TypeFibInsert, TypeFibErase, TypeStrategyLoad,
+	TypeEthLinkUp, TypeEthLinkDown,
+	TypeMempoolPressure, TypeMempoolExhausted, TypePcctPressure,
+}
+
+// Event describes an operational event.
+type Event struct {
+	Seq     uint64         `json:"seq" gqldesc:"Sequence number."`
+	Time    time.Time      `json:"time" gqldesc:"Event timestamp."`
+	Type    Type           `json:"type" gqldesc:"Event type."`
+	Face    int            `json:"face,omitempty" gqldesc:"Numeric face ID, or zero if the event is not face-related."`
+	Subject string         `json:"subject,omitempty" gqldesc:"Related object, such as name prefix, strategy name, port name, or mempool name."`
+	Details map[string]any `json:"details,omitempty" gqldesc:"Type-specific information."`
+}
+
+var (
+	bus    = NewEmitter()
+	busSeq atomic.Uint64
+)
+
+const evtBus = "Event"
+
+// Publish publishes an event to the event bus.
+// This assigns evt.Seq, and assigns evt.Time if it is zero.
+// Subscriber callbacks are invoked synchronously.
+func Publish(evt Event) {
+	evt.Seq = busSeq.Add(1)
+	if evt.Time.IsZero() {
+		evt.Time = time.Now()
+	}
+	bus.Emit(evtBus, evt)
+}
+
+// Subscribe registers a callback when an event is published.
+// The callback must not block, and should drop events if it cannot keep up.
+// Returns a function that cancels the callback registration.
+func Subscribe(cb func(Event)) (cancel func()) {
+	return bus.On(evtBus, cb)
+}
+
+// Filter selects events of interest.
+type Filter struct {
+	// Types restricts event types. If empty, all types are accepted.
+	Types []Type `json:"types,omitempty"`
+	// Face restricts to events related to a face. If zero, events of all faces and non-face events are accepted.
+	Face int `json:"face,omitempty"`
+}
+
+// Match determines whether an event is accepted by the filter.
+func (f Filter) Match(evt Event) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, evt.Type) {
+		return false
+	}
+	return f.Face == 0 || evt.Face == f.Face
+}
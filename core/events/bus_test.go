@@ -0,0 +1,40 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/usnistgov/ndn-dpdk/core/events"
+)
+
+func TestBus(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	var received []events.Event
+	filter := events.Filter{
+		Types: []events.Type{events.TypeFaceUp, events.TypeFaceDown},
+		Face:  2,
+	}
+	cancel := events.Subscribe(func(evt events.Event) {
+		if filter.Match(evt) {
+			received = append(received, evt)
+		}
+	})
+
+	events.Publish(events.Event{Type: events.TypeFaceUp, Face: 1})
+	events.Publish(events.Event{Type: events.TypeFaceUp, Face: 2})
+	events.Publish(events.Event{Type: events.TypeFaceCreated, Face: 2})
+	events.Publish(events.Event{Type: events.TypeFibInsert, Subject: "/A"})
+	events.Publish(events.Event{Type: events.TypeFaceDown, Face: 2})
+	cancel()
+	events.Publish(events.Event{Type: events.TypeFaceUp, Face: 2})
+
+	if assert.Len(received, 2) {
+		assert.Equal(events.TypeFaceUp, received[0].Type)
+		assert.Equal(events.TypeFaceDown, received[1].Type)
+		assert.Greater(received[1].Seq, received[0].Seq)
+		assert.False(received[0].Time.IsZero())
+	}
+
+	assert.True(events.Filter{}.Match(events.Event{Type: events.TypeFibErase}))
+	assert.False(events.Filter{Face: 1}.Match(events.Event{Type: events.TypeFibErase}))
+}
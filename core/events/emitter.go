@@ -1,4 +1,4 @@
-// Package events provides a simple event emitter.
+// Package events provides a simple event emitter and an operational event bus.
 package events
 
 import (
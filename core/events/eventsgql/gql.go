@@ -0,0 +1,90 @@
+// Package eventsgql allows subscribing to operational events via GraphQL.
+package eventsgql
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/usnistgov/ndn-dpdk/core/events"
+	"github.com/usnistgov/ndn-dpdk/core/gqlserver"
+)
+
+// RetrieveFace converts face GraphQL ID to numeric face ID.
+// This is assigned by package iface.
+var RetrieveFace = func(id string) (nid int, ok bool) { return 0, false }
+
+// GraphQL types.
+var (
+	GqlEventTypeEnum *graphql.Enum
+	GqlEventType     *graphql.Object
+)
+
+func init() {
+	GqlEventTypeEnum = gqlserver.NewStringEnum("EventType", "Operational event type.", events.Types...)
+	GqlEventType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Event",
+		Description: "Operational event.",
+		Fields: gqlserver.BindFields[events.Event](gqlserver.FieldTypes{
+			reflect.TypeFor[time.Time]():      graphql.DateTime,
+			reflect.TypeFor[events.Type]():    GqlEventTypeEnum,
+			reflect.TypeFor[map[string]any](): gqlserver.JSON,
+		}),
+	})
+
+	gqlserver.AddSubscription(&graphql.Field{
+		Name:        "events",
+		Description: "Operational events, such as face state changes, FIB updates, and resource pressure.",
+		Args: graphql.FieldConfigArgument{
+			"types": &graphql.ArgumentConfig{
+				Description: "Event types. If omitted, report events of all types.",
+				Type:        graphql.NewList(graphql.NewNonNull(GqlEventTypeEnum)),
+			},
+			"face": &graphql.ArgumentConfig{
+				Description: "Face ID. If omitted, report events of all faces and events unrelated to faces.",
+				Type:        graphql.ID,
+			},
+		},
+		Type: graphql.NewNonNull(GqlEventType),
+		Subscribe: func(p graphql.ResolveParams) (any, error) {
+			var filter events.Filter
+			if types, ok := p.Args["types"].([]any); ok {
+				for _, t := range types {
+					filter.Types = append(filter.Types, t.(events.Type))
+				}
+			}
+			if id, ok := p.Args["face"].(string); ok {
+				if filter.Face, ok = RetrieveFace(id); !ok {
+					return nil, nil
+				}
+			}
+
+			ch := make(chan events.Event, 64)
+			cancel := events.Subscribe(func(evt events.Event) {
+				if !filter.Match(evt) {
+					return
+				}
+				select {
+				case ch <- evt:
+				default: // subscriber is too slow, drop event
+				}
+			})
+
+			return gqlserver.PublishChan(func(updates chan<- any) {
+				defer cancel()
+				for {
+					select {
+					case <-p.Context.Done():
+						return
+					case evt := <-ch:
+						select {
+						case updates <- evt:
+						case <-p.Context.Done():
+							return
+						}
+					}
+				}
+			})
+		},
+	})
+}
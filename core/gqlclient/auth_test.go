@@ -0,0 +1,232 @@
+package gqlclient_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/core/gqlclient"
+	"github.com/usnistgov/ndn-dpdk/core/gqlserver"
+)
+
+func TestAuth(t *testing.T) {
+	assert, require := makeAR(t)
+
+	authCfg := gqlserver.AuthConfig{
+		Tokens: map[string]gqlserver.Role{
+			"admin-token":    gqlserver.RoleAdmin,
+			"readonly-token": gqlserver.RoleReadOnly,
+		},
+	}
+	server := httptest.NewServer(authCfg.Handler(http.DefaultServeMux))
+	defer server.Close()
+
+	newClient := func(token string) *gqlclient.Client {
+		c, e := gqlclient.New(gqlclient.Config{
+			HTTPUri:     server.URL,
+			BearerToken: token,
+		})
+		require.NoError(e)
+		return c
+	}
+	query := func(c *gqlclient.Client) error {
+		var version any
+		return c.Do(context.Background(), `{ version { version } }`, nil, "version", &version)
+	}
+
+	anonymous := newClient("")
+	defer anonymous.Close()
+	assert.Error(query(anonymous))
+
+	wrong := newClient("wrong-token")
+	defer wrong.Close()
+	assert.Error(query(wrong))
+
+	readonly := newClient("readonly-token")
+	defer readonly.Close()
+	assert.NoError(query(readonly))
+	_, e := readonly.Delete(context.Background(), "x")
+	assert.ErrorContains(e, "not permitted")
+
+	admin := newClient("admin-token")
+	defer admin.Close()
+	assert.NoError(query(admin))
+	_, e = admin.Delete(context.Background(), "x")
+	assert.NoError(e)
+
+	// authentication applies only to requests passing through the handler
+	plain, e := gqlclient.New(serverConfig)
+	require.NoError(e)
+	defer plain.Close()
+	_, e = plain.Delete(context.Background(), "x")
+	assert.NoError(e)
+}
+
+// testCA is a certificate authority for TLS tests.
+type testCA struct {
+	t    testing.TB
+	dir  string
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	File string // CA certificate PEM file
+}
+
+var testSerial int64
+
+// issueCert creates a certificate signed by ca, or a self-signed CA certificate if ca is nil.
+// It writes the certificate and private key PEM files into dir.
+func issueCert(t testing.TB, dir string, ca *testCA, tpl *x509.Certificate) (cert *x509.Certificate, key *ecdsa.PrivateKey, certFile, keyFile string) {
+	_, require := makeAR(t)
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(e)
+
+	testSerial++
+	tpl.SerialNumber = big.NewInt(testSerial)
+	tpl.NotBefore, tpl.NotAfter = time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	parent, signer := tpl, key
+	if ca != nil {
+		parent, signer = ca.cert, ca.key
+	}
+	der, e := x509.CreateCertificate(rand.Reader, tpl, parent, &key.PublicKey, signer)
+	require.NoError(e)
+	cert, e = x509.ParseCertificate(der)
+	require.NoError(e)
+	keyDer, e := x509.MarshalECPrivateKey(key)
+	require.NoError(e)
+
+	name := tpl.Subject.CommonName
+	certFile, keyFile = filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	require.NoError(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return
+}
+
+func newTestCA(t testing.TB, dir, name string) *testCA {
+	ca := &testCA{t: t, dir: dir}
+	ca.cert, ca.key, ca.File, _ = issueCert(t, dir, nil, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: name},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+	return ca
+}
+
+// Server issues a server certificate for 127.0.0.1.
+func (ca *testCA) Server(name string) (certFile, keyFile string) {
+	_, _, certFile, keyFile = issueCert(ca.t, ca.dir, ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: name},
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	return
+}
+
+// Client issues a client certificate.
+func (ca *testCA) Client(name string) (certFile, keyFile string) {
+	_, _, certFile, keyFile = issueCert(ca.t, ca.dir, ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: name},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	return
+}
+
+func TestAuthTLS(t *testing.T) {
+	assert, require := makeAR(t)
+	dir := t.TempDir()
+
+	serverCA := newTestCA(t, dir, "server-ca")
+	adminCA := newTestCA(t, dir, "admin-ca")
+	readonlyCA := newTestCA(t, dir, "readonly-ca")
+	otherCA := newTestCA(t, dir, "other-ca")
+
+	authCfg := gqlserver.AuthConfig{
+		ClientCA:         adminCA.File,
+		ReadOnlyClientCA: readonlyCA.File,
+		Tokens: map[string]gqlserver.Role{
+			"admin-token": gqlserver.RoleAdmin,
+		},
+	}
+	authCfg.TLSCert, authCfg.TLSKey = serverCA.Server("server")
+	tlsCfg, e := authCfg.TLSConfig()
+	require.NoError(e)
+	require.NotNil(tlsCfg)
+
+	server := httptest.NewUnstartedServer(authCfg.Handler(http.DefaultServeMux))
+	server.TLS = tlsCfg
+	server.StartTLS()
+	defer server.Close()
+
+	newClient := func(ca *testCA, token string) *gqlclient.Client {
+		var certFile, keyFile string
+		if ca != nil {
+			certFile, keyFile = ca.Client("client-of-" + filepath.Base(ca.File))
+		}
+		clientTLS, e := gqlclient.LoadClientTLS(serverCA.File, certFile, keyFile)
+		require.NoError(e)
+		c, e := gqlclient.New(gqlclient.Config{
+			HTTPUri:         server.URL,
+			TLSClientConfig: clientTLS,
+			BearerToken:     token,
+		})
+		require.NoError(e)
+		t.Cleanup(func() { c.Close() })
+		return c
+	}
+	query := func(c *gqlclient.Client) error {
+		var version any
+		return c.Do(context.Background(), `{ version { version } }`, nil, "version", &version)
+	}
+	mutate := func(c *gqlclient.Client) error {
+		_, e := c.Delete(context.Background(), "x")
+		return e
+	}
+
+	// no client certificate
+	anonymous := newClient(nil, "")
+	assert.Error(query(anonymous))
+
+	// client certificate signed by an unknown CA is rejected in TLS handshake
+	other := newClient(otherCA, "")
+	assert.Error(query(other))
+
+	// client certificate signed by ClientCA grants RoleAdmin
+	admin := newClient(adminCA, "")
+	assert.NoError(query(admin))
+	assert.NoError(mutate(admin))
+
+	// client certificate signed by ReadOnlyClientCA grants RoleReadOnly
+	readonly := newClient(readonlyCA, "")
+	assert.NoError(query(readonly))
+	assert.ErrorContains(mutate(readonly), "not permitted")
+
+	// the higher role of all valid credentials is granted
+	readonlyWithToken := newClient(readonlyCA, "admin-token")
+	assert.NoError(mutate(readonlyWithToken))
+
+	// client certificate authentication requires TLS
+	_, e = (&gqlserver.AuthConfig{ClientCA: adminCA.File}).TLSConfig()
+	assert.Error(e)
+
+	// CA file without certificate is rejected
+	badCA := filepath.Join(dir, "bad-ca.crt")
+	require.NoError(os.WriteFile(badCA, []byte("not a certificate"), 0o600))
+	badCfg := authCfg
+	badCfg.ReadOnlyClientCA = badCA
+	_, e = badCfg.TLSConfig()
+	assert.Error(e)
+}
@@ -1,11 +1,14 @@
 package gqlclient
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
 
 	gqlws "github.com/korylprince/go-graphql-ws"
@@ -23,6 +26,13 @@ type Config struct {
 	WebSocketUri string
 
 	WebSocketDialer *gqlws.Dialer
+
+	// BearerToken is sent in the Authorization header of every request, if non-empty.
+	BearerToken string
+
+	// TLSClientConfig is the TLS configuration for HTTPS and WSS connections.
+	// It is used only if HTTPClient or WebSocketDialer is not specified.
+	TLSClientConfig *tls.Config
 }
 
 // Validate applies defaults and validates the configuration.
@@ -50,38 +60,90 @@ func (cfg *Config) Validate() error {
 	}
 
 	if cfg.HTTPClient == nil {
-		cfg.HTTPClient = http.DefaultClient
+		if cfg.TLSClientConfig == nil {
+			cfg.HTTPClient = http.DefaultClient
+		} else {
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			transport.TLSClientConfig = cfg.TLSClientConfig
+			cfg.HTTPClient = &http.Client{Transport: transport}
+		}
 	}
 
 	if cfg.WebSocketDialer == nil {
-		dialer := *gqlws.DefaultDialer
-		dialer.Subprotocols = []string{"graphql-ws"}
-		cfg.WebSocketDialer = &dialer
+		wsDialer := *gqlws.DefaultDialer.Dialer
+		wsDialer.Subprotocols = []string{"graphql-ws"}
+		wsDialer.TLSClientConfig = cfg.TLSClientConfig
+		cfg.WebSocketDialer = &gqlws.Dialer{Dialer: &wsDialer}
 	}
 
 	return nil
 }
 
+// Header returns HTTP request headers for authentication.
+func (cfg Config) Header() http.Header {
+	h := http.Header{}
+	if cfg.BearerToken != "" {
+		h.Set("Authorization", "Bearer "+cfg.BearerToken)
+	}
+	return h
+}
+
 // Listen constructs server listen string.
 func (cfg Config) Listen() (hostport string, e error) {
+	hostport, _, e = cfg.listen()
+	return
+}
+
+func (cfg Config) listen() (hostport string, useTLS bool, e error) {
 	uri, e := url.Parse(cfg.HTTPUri)
 	if e != nil {
-		return "", e
+		return "", false, e
 	}
 
-	if uri.Scheme != "http" || strings.TrimPrefix(uri.Path, "/") != "" {
-		return "", errors.New("GraphQL server URI should have 'http' scheme and '/' path")
+	if (uri.Scheme != "http" && uri.Scheme != "https") || strings.TrimPrefix(uri.Path, "/") != "" {
+		return "", false, errors.New("GraphQL server URI should have 'http' or 'https' scheme and '/' path")
 	}
 
+	useTLS = uri.Scheme == "https"
 	host, port := uri.Hostname(), uri.Port()
 	if port == "" {
 		port = "80"
+		if useTLS {
+			port = "443"
+		}
 	}
-	return net.JoinHostPort(host, port), nil
+	return net.JoinHostPort(host, port), useTLS, nil
 }
 
 // MakeListenAddress constructs server listen string.
-func MakeListenAddress(uri string) (hostport string, e error) {
+// useTLS indicates whether the URI has 'https' scheme.
+func MakeListenAddress(uri string) (hostport string, useTLS bool, e error) {
 	cfg := Config{HTTPUri: uri}
-	return cfg.Listen()
+	return cfg.listen()
+}
+
+// LoadClientTLS constructs client TLS configuration from PEM files.
+//
+//	caFile: CA certificates that sign the server certificate; if empty, use system roots.
+//	certFile, keyFile: client certificate and private key; if empty, no client certificate is presented.
+func LoadClientTLS(caFile, certFile, keyFile string) (*tls.Config, error) {
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, e := os.ReadFile(caFile)
+		if e != nil {
+			return nil, e
+		}
+		tlsCfg.RootCAs = x509.NewCertPool()
+		if !tlsCfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s: no certificate found", caFile)
+		}
+	}
+	if certFile != "" {
+		cert, e := tls.LoadX509KeyPair(certFile, keyFile)
+		if e != nil {
+			return nil, e
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return tlsCfg, nil
 }
@@ -0,0 +1,41 @@
+package gqlclient
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+// DefineFlags appends CLI flags for GraphQL server authentication.
+// The GraphQL server endpoint flag should be defined by the caller.
+func (cfg *Config) DefineFlags(flags []cli.Flag) []cli.Flag {
+	return append(flags,
+		&cli.StringFlag{
+			Name:        "gqlserver-token",
+			Usage:       "GraphQL server bearer `token`",
+			EnvVars:     []string{"NDNDPDK_GQLSERVER_TOKEN"},
+			Destination: &cfg.BearerToken,
+		},
+		&cli.StringFlag{
+			Name:  "gqlserver-ca",
+			Usage: "CA certificates `file` for verifying HTTPS GraphQL server",
+		},
+		&cli.StringFlag{
+			Name:  "gqlserver-cert",
+			Usage: "client certificate `file` for HTTPS GraphQL server",
+		},
+		&cli.StringFlag{
+			Name:  "gqlserver-key",
+			Usage: "client private key `file` for HTTPS GraphQL server",
+		},
+	)
+}
+
+// ProcessFlags loads TLS files specified in CLI flags.
+// This should be called before Validate.
+func (cfg *Config) ProcessFlags(c *cli.Context) (e error) {
+	caFile, certFile, keyFile := c.String("gqlserver-ca"), c.String("gqlserver-cert"), c.String("gqlserver-key")
+	if caFile == "" && certFile == "" {
+		return nil
+	}
+	cfg.TLSClientConfig, e = LoadClientTLS(caFile, certFile, keyFile)
+	return e
+}
@@ -64,6 +64,9 @@ func (c *Client) Do(ctx context.Context, query string, vars map[string]any, key
 	defer c.wg.Done()
 
 	request := graphql.NewRequest(query)
+	for key, values := range c.cfg.Header() {
+		request.Header[key] = values
+	}
 	for key, value := range vars {
 		request.Var(key, value)
 	}
@@ -167,7 +170,7 @@ func (c *Client) wsConnect() (conn *gqlws.Conn, e error) {
 	if c.wsConn == nil && c.wsConnErr == nil {
 		c.wg.Add(1)
 		defer c.wg.Done()
-		c.wsConn, _, c.wsConnErr = c.cfg.WebSocketDialer.Dial(c.cfg.WebSocketUri, nil, &gqlws.MessagePayloadConnectionInit{})
+		c.wsConn, _, c.wsConnErr = c.cfg.WebSocketDialer.Dial(c.cfg.WebSocketUri, c.cfg.Header(), &gqlws.MessagePayloadConnectionInit{})
 		if c.wsConnErr == nil {
 			c.wsConn.SetCloseHandler(func(int, string) {
 				close(c.wsClosed)
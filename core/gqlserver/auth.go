@@ -0,0 +1,237 @@
+package gqlserver
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Role is the access level of a GraphQL client.
+type Role string
+
+// Role values.
+const (
+	// RoleAdmin can perform queries, mutations, and subscriptions.
+	RoleAdmin Role = "admin"
+
+	// RoleReadOnly can perform queries and subscriptions, but not mutations.
+	RoleReadOnly Role = "readonly"
+)
+
+// CanMutate determines whether the role can perform mutations.
+func (role Role) CanMutate() bool {
+	return role == RoleAdmin
+}
+
+func parseRole(s string) (Role, error) {
+	switch role := Role(s); role {
+	case RoleAdmin, RoleReadOnly:
+		return role, nil
+	}
+	return "", fmt.Errorf("unknown role %s", s)
+}
+
+type roleCtxKey struct{}
+
+// RoleFromContext retrieves the role of an authenticated client.
+// It returns false if the request did not pass through AuthConfig.Handler.
+func RoleFromContext(ctx context.Context) (role Role, ok bool) {
+	role, ok = ctx.Value(roleCtxKey{}).(Role)
+	return
+}
+
+// checkMutationRole rejects a mutation unless the client may perform mutations.
+// A request that did not pass through AuthConfig.Handler carries no role and is not restricted.
+func checkMutationRole(ctx context.Context) error {
+	if role, ok := RoleFromContext(ctx); ok && !role.CanMutate() {
+		return errors.New("mutation not permitted for this client")
+	}
+	return nil
+}
+
+// AuthConfig contains GraphQL server TLS and authentication settings.
+//
+// If neither Tokens nor a client CA is specified, every client is granted RoleAdmin.
+// Otherwise, each request must carry a bearer token in the Authorization header, or present a client
+// certificate signed by a client CA; the client is granted the higher role of all valid credentials.
+type AuthConfig struct {
+	// TLSCert is the server certificate PEM file.
+	// If non-empty, the server accepts HTTPS only.
+	TLSCert string
+
+	// TLSKey is the server private key PEM file.
+	TLSKey string
+
+	// ClientCA is a PEM file of CA certificates that sign client certificates for RoleAdmin.
+	// This requires TLS.
+	ClientCA string
+
+	// ReadOnlyClientCA is a PEM file of CA certificates that sign client certificates for RoleReadOnly.
+	// This requires TLS.
+	ReadOnlyClientCA string
+
+	// Tokens maps bearer tokens to roles.
+	Tokens map[string]Role
+
+	adminPool    *x509.CertPool
+	readOnlyPool *x509.CertPool
+}
+
+// EnableTLS determines whether TLS is enabled.
+func (cfg AuthConfig) EnableTLS() bool {
+	return cfg.TLSCert != ""
+}
+
+// EnableAuth determines whether client authentication is enabled.
+func (cfg AuthConfig) EnableAuth() bool {
+	return len(cfg.Tokens) > 0 || cfg.ClientCA != "" || cfg.ReadOnlyClientCA != ""
+}
+
+// LoadTokenFile reads bearer tokens from a file into Tokens.
+// Each non-empty line that does not start with '#' contains a role and a token separated by whitespace.
+func (cfg *AuthConfig) LoadTokenFile(filename string) error {
+	file, e := os.Open(filename)
+	if e != nil {
+		return e
+	}
+	defer file.Close()
+
+	if cfg.Tokens == nil {
+		cfg.Tokens = map[string]Role{}
+	}
+	scanner := bufio.NewScanner(file)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return fmt.Errorf("%s:%d: expect role and token", filename, lineno)
+		}
+		role, e := parseRole(fields[0])
+		if e != nil {
+			return fmt.Errorf("%s:%d: %w", filename, lineno, e)
+		}
+		cfg.Tokens[fields[1]] = role
+	}
+	return scanner.Err()
+}
+
+// TLSConfig constructs server TLS configuration.
+// It returns nil if TLS is disabled.
+func (cfg *AuthConfig) TLSConfig() (*tls.Config, error) {
+	if !cfg.EnableTLS() {
+		if cfg.ClientCA != "" || cfg.ReadOnlyClientCA != "" {
+			return nil, errors.New("client certificate authentication requires TLS")
+		}
+		return nil, nil
+	}
+
+	cert, e := tls.LoadX509KeyPair(cfg.TLSCert, cfg.TLSKey)
+	if e != nil {
+		return nil, e
+	}
+	tlsCfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if cfg.ClientCA == "" && cfg.ReadOnlyClientCA == "" {
+		return tlsCfg, nil
+	}
+	tlsCfg.ClientCAs = x509.NewCertPool()
+	for _, ca := range []struct {
+		filename string
+		pool     **x509.CertPool
+	}{
+		{cfg.ClientCA, &cfg.adminPool},
+		{cfg.ReadOnlyClientCA, &cfg.readOnlyPool},
+	} {
+		if ca.filename == "" {
+			continue
+		}
+		pem, e := os.ReadFile(ca.filename)
+		if e != nil {
+			return nil, e
+		}
+		*ca.pool = x509.NewCertPool()
+		if !(*ca.pool).AppendCertsFromPEM(pem) || !tlsCfg.ClientCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s: no certificate found", ca.filename)
+		}
+	}
+	tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+	return tlsCfg, nil
+}
+
+// authenticate determines client role from request credentials.
+func (cfg AuthConfig) authenticate(r *http.Request) (role Role, ok bool) {
+	grant := func(r Role) {
+		if !ok || r.CanMutate() {
+			role, ok = r, true
+		}
+	}
+
+	if scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " "); found && strings.EqualFold(scheme, "Bearer") {
+		for t, r := range cfg.Tokens {
+			if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+				grant(r)
+			}
+		}
+	}
+
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		leaf := r.TLS.PeerCertificates[0]
+		intermediates := x509.NewCertPool()
+		for _, cert := range r.TLS.PeerCertificates[1:] {
+			intermediates.AddCert(cert)
+		}
+		for _, ca := range []struct {
+			pool *x509.CertPool
+			role Role
+		}{
+			{cfg.adminPool, RoleAdmin},
+			{cfg.readOnlyPool, RoleReadOnly},
+		} {
+			if ca.pool == nil {
+				continue
+			}
+			if _, e := leaf.Verify(x509.VerifyOptions{
+				Roots:         ca.pool,
+				Intermediates: intermediates,
+				KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			}); e == nil {
+				grant(ca.role)
+			}
+		}
+	}
+	return
+}
+
+// Handler wraps an HTTP handler with client authentication.
+// TLSConfig must be called before this function, so that client CAs are loaded.
+// The client role is attached to the request context, and can be retrieved with RoleFromContext.
+func (cfg AuthConfig) Handler(next http.Handler) http.Handler {
+	if !cfg.EnableAuth() {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), roleCtxKey{}, RoleAdmin)))
+		})
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role, ok := cfg.authenticate(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="NDN-DPDK"`)
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), roleCtxKey{}, role)))
+	})
+}
@@ -45,9 +45,13 @@ func AddQuery(f *graphql.Field) {
 }
 
 // AddMutation adds a top-level mutation field.
+// If the request has passed through AuthConfig.Handler, the mutation is rejected unless the client has RoleAdmin.
 func AddMutation(f *graphql.Field) {
 	name, resolve := f.Name, f.Resolve
 	f.Resolve = func(p graphql.ResolveParams) (any, error) {
+		if e := checkMutationRole(p.Context); e != nil {
+			return nil, e
+		}
 		defer func() {
 			if e := recover(); e != nil {
 				logger.Error("panic in GraphQL mutation resolver",
@@ -124,6 +128,7 @@ func Prepare() {
 			Response: w,
 			Request:  r,
 			Schema:   &sch,
+			Context:  context.WithoutCancel(r.Context()), // carry client role
 		})
 	})
 	httpHandler := handler.New(&handler.Config{
@@ -49,38 +49,49 @@ func (s *Singleton[T]) NodeConfig() (nc gqlserver.NodeConfig[T]) {
 		}
 		return
 	}
-	nc.Delete = func(source T) error {
-		if e := source.Close(); e != nil {
-			return e
-		}
+	nc.Delete = s.Delete
+	return
+}
 
-		s.Lock()
-		defer s.Unlock()
-		if source == s.value {
-			s.id++
-			var zero T
-			s.value = zero
-		}
-		return nil
+// Create creates the object if it does not exist.
+func (s *Singleton[T]) Create(f func() (value T, e error)) (value T, e error) {
+	s.Lock()
+	defer s.Unlock()
+	var zero T
+	if s.value != zero {
+		return zero, errors.New("object already exist")
 	}
-	return
+	if value, e = f(); e != nil {
+		return zero, e
+	}
+	s.value = value
+	s.id++
+	return value, nil
+}
+
+// Delete closes the object and clears the singleton if it is the current object.
+func (s *Singleton[T]) Delete(source T) error {
+	if e := source.Close(); e != nil {
+		return e
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if source == s.value {
+		s.id++
+		var zero T
+		s.value = zero
+	}
+	return nil
 }
 
 // CreateWith wraps a create object mutation resolver with singleton lock.
 func (s *Singleton[T]) CreateWith(f func(p graphql.ResolveParams) (value T, e error)) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (any, error) {
-		s.Lock()
-		defer s.Unlock()
-		var zero T
-		if s.value != zero {
-			return nil, errors.New("object already exist")
-		}
-		value, e := f(p)
+		value, e := s.Create(func() (T, error) { return f(p) })
 		if e != nil {
 			return nil, e
 		}
-		s.value = value
-		s.id++
 		return value, nil
 	}
 }
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ContentType is the HTTP Content-Type of text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultCacheInterval is the default duration that collected metrics are reused.
+const DefaultCacheInterval = time.Second
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+// WriteText writes metric families in text exposition format.
+func WriteText(w io.Writer, families []*Family) error {
+	var b bytes.Buffer
+	for _, f := range families {
+		if f.Help != "" {
+			fmt.Fprintf(&b, "# HELP %s %s\n", f.Name, helpEscaper.Replace(f.Help))
+		}
+		fmt.Fprintf(&b, "# TYPE %s %s\n", f.Name, f.Type)
+		for _, s := range f.Samples {
+			b.WriteString(f.Name)
+			if len(s.Labels) > 0 {
+				b.WriteByte('{')
+				for i, name := range slices.Sorted(maps.Keys(s.Labels)) {
+					if i > 0 {
+						b.WriteByte(',')
+					}
+					fmt.Fprintf(&b, `%s="%s"`, name, labelEscaper.Replace(s.Labels[name]))
+				}
+				b.WriteByte('}')
+			}
+			b.WriteByte(' ')
+			b.WriteString(formatValue(s.Value))
+			b.WriteByte('\n')
+		}
+	}
+	_, e := w.Write(b.Bytes())
+	return e
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Handler is an HTTP handler that serves collected metrics.
+//
+// Collected metrics are cached for CacheInterval, so that frequent or concurrent scrapes do not
+// repeatedly read counters. Collectors only read counters from shared memory and never wait for
+// data plane threads.
+type Handler struct {
+	// CacheInterval is the duration that collected metrics are reused.
+	CacheInterval time.Duration
+
+	mutex sync.Mutex
+	body  []byte
+	time  time.Time
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+func (h *Handler) get() []byte {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if now := time.Now(); h.body == nil || now.Sub(h.time) >= h.CacheInterval {
+		var b bytes.Buffer
+		WriteText(&b, Collect().Families())
+		h.body, h.time = b.Bytes(), now
+	}
+	return h.body
+}
+
+// ServeHTTP implements http.Handler interface.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body := h.get()
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Write(body)
+}
+
+// NewHandler creates a Handler with DefaultCacheInterval.
+func NewHandler() *Handler {
+	return &Handler{CacheInterval: DefaultCacheInterval}
+}
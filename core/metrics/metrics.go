@@ -0,0 +1,178 @@
+// Package metrics exports counters in Prometheus text exposition format.
+package metrics
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Prefix is the common prefix of metric names.
+const Prefix = "ndndpdk_"
+
+// Type is metric type.
+type Type string
+
+// Type values.
+const (
+	Counter Type = "counter"
+	Gauge   Type = "gauge"
+)
+
+// Labels is a set of label names and values.
+type Labels map[string]string
+
+// With returns a copy of labels with an additional label.
+func (l Labels) With(name, value string) Labels {
+	c := maps.Clone(l)
+	if c == nil {
+		c = Labels{}
+	}
+	c[name] = value
+	return c
+}
+
+// Sample is a metric value with labels.
+type Sample struct {
+	Labels Labels
+	Value  float64
+}
+
+// Family is a metric family.
+type Family struct {
+	Name    string
+	Help    string
+	Type    Type
+	Samples []Sample
+}
+
+// Collection collects metric families.
+// Methods are not thread-safe.
+type Collection struct {
+	families map[string]*Family
+}
+
+// Add appends a sample.
+// name is appended to Prefix; if the family already exists, help and typ are ignored.
+func (c *Collection) Add(name, help string, typ Type, value float64, labels Labels) {
+	name = Prefix + name
+	if typ == Counter && !strings.HasSuffix(name, "_total") {
+		name += "_total"
+	}
+	f := c.families[name]
+	if f == nil {
+		f = &Family{Name: name, Help: help, Type: typ}
+		c.families[name] = f
+	}
+	f.Samples = append(f.Samples, Sample{Labels: labels, Value: value})
+}
+
+// AddStruct appends samples from numeric fields of a counters struct.
+// Each metric name is prefix followed by JSON field name converted to snake case,
+// with the "n" prefix of a count field removed, such as "nDataHit" becoming "data_hit".
+// Help text is taken from `gqldesc` tag.
+// Fields are counters by default; a field may be tagged `metric:"gauge"` to become a gauge,
+// or `metric:"-"` to be skipped.
+// Embedded structs are flattened; other fields are ignored.
+func (c *Collection) AddStruct(prefix string, v any, labels Labels) {
+	val := reflect.ValueOf(v)
+	for _, field := range reflect.VisibleFields(val.Type()) {
+		tag := field.Tag.Get("metric")
+		if !field.IsExported() || field.Anonymous || tag == "-" {
+			continue
+		}
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if jsonName == "" || jsonName == "-" {
+			continue
+		}
+
+		fv := val.FieldByIndex(field.Index)
+		var value float64
+		switch fv.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			value = float64(fv.Uint())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			value = float64(fv.Int())
+		case reflect.Float32, reflect.Float64:
+			value = fv.Float()
+		default:
+			continue
+		}
+
+		typ := Counter
+		if tag == string(Gauge) {
+			typ = Gauge
+		}
+		if len(jsonName) > 1 && jsonName[0] == 'n' && unicode.IsUpper(rune(jsonName[1])) {
+			jsonName = jsonName[1:]
+		}
+		c.Add(prefix+SnakeCase(jsonName), field.Tag.Get("gqldesc"), typ, value, labels)
+	}
+}
+
+// Families returns collected metric families sorted by name.
+func (c *Collection) Families() (list []*Family) {
+	for _, name := range slices.Sorted(maps.Keys(c.families)) {
+		list = append(list, c.families[name])
+	}
+	return list
+}
+
+// NewCollection creates an empty Collection.
+func NewCollection() *Collection {
+	return &Collection{
+		families: map[string]*Family{},
+	}
+}
+
+// Collector appends metrics to a Collection.
+// It is invoked in a Go goroutine and must not block data plane threads.
+type Collector func(c *Collection)
+
+var (
+	collectorsMutex sync.Mutex
+	collectors      = map[string]Collector{}
+)
+
+// Register registers a collector.
+// key identifies the collector; registering the same key again replaces the previous collector,
+// and registering a nil collector removes it.
+func Register(key string, collector Collector) {
+	collectorsMutex.Lock()
+	defer collectorsMutex.Unlock()
+	if collector == nil {
+		delete(collectors, key)
+		return
+	}
+	collectors[key] = collector
+}
+
+// Collect invokes every registered collector.
+func Collect() *Collection {
+	collectorsMutex.Lock()
+	defer collectorsMutex.Unlock()
+	c := NewCollection()
+	for _, key := range slices.Sorted(maps.Keys(collectors)) {
+		collectors[key](c)
+	}
+	return c
+}
+
+// SnakeCase converts a camelCase name to snake_case.
+func SnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
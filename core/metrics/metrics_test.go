@@ -0,0 +1,94 @@
+package metrics_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/core/metrics"
+)
+
+type testCountersBase struct {
+	RxFrames uint64 `json:"rxFrames" gqldesc:"RX frames."`
+}
+
+type testCounters struct {
+	testCountersBase
+	NEntries int     `json:"nEntries" gqldesc:"Current entries." metric:"gauge"`
+	NDataHit uint64  `json:"nDataHit" gqldesc:"Data hits.\nsecond line"`
+	Skipped  uint64  `json:"skipped" metric:"-"`
+	Ratio    float64 `json:"ratio"`
+	Threads  []int   `json:"threads"`
+	NoTag    uint64
+}
+
+func TestSnakeCase(t *testing.T) {
+	assert, _ := makeAR(t)
+	assert.Equal("rx_frames", metrics.SnakeCase("rxFrames"))
+	assert.Equal("tx_shaper_throttles", metrics.SnakeCase("txShaperThrottles"))
+	assert.Equal("pit_token", metrics.SnakeCase("pitToken"))
+	assert.Equal("read_tsc", metrics.SnakeCase("readTSC"))
+}
+
+func TestWriteText(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	c := metrics.NewCollection()
+	labels := metrics.Labels{"face": "1"}
+	c.AddStruct("x_", testCounters{testCountersBase{5}, 3, 7, 9, 0.5, nil, 1}, labels)
+	c.AddStruct("x_", testCounters{NDataHit: 8}, labels.With("scheme", `a"b\c`))
+	c.Add("y", "", metrics.Gauge, 1, nil)
+	assert.Len(labels, 1)
+
+	var b strings.Builder
+	assert.NoError(metrics.WriteText(&b, c.Families()))
+	assert.Equal(`# HELP ndndpdk_x_data_hit_total Data hits.\nsecond line
+# TYPE ndndpdk_x_data_hit_total counter
+ndndpdk_x_data_hit_total{face="1"} 7
+ndndpdk_x_data_hit_total{face="1",scheme="a\"b\\c"} 8
+# HELP ndndpdk_x_entries Current entries.
+# TYPE ndndpdk_x_entries gauge
+ndndpdk_x_entries{face="1"} 3
+ndndpdk_x_entries{face="1",scheme="a\"b\\c"} 0
+# TYPE ndndpdk_x_ratio_total counter
+ndndpdk_x_ratio_total{face="1"} 0.5
+ndndpdk_x_ratio_total{face="1",scheme="a\"b\\c"} 0
+# HELP ndndpdk_x_rx_frames_total RX frames.
+# TYPE ndndpdk_x_rx_frames_total counter
+ndndpdk_x_rx_frames_total{face="1"} 5
+ndndpdk_x_rx_frames_total{face="1",scheme="a\"b\\c"} 0
+# TYPE ndndpdk_y gauge
+ndndpdk_y 1
+`, b.String())
+}
+
+func TestHandler(t *testing.T) {
+	assert, require := makeAR(t)
+
+	n := 0
+	metrics.Register("metrics_test", func(c *metrics.Collection) {
+		n++
+		c.Add("test_collect", "Collect invocations.", metrics.Counter, float64(n), nil)
+	})
+	defer metrics.Register("metrics_test", nil)
+
+	h := metrics.NewHandler()
+	h.CacheInterval = 200 * time.Millisecond
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+		res := rec.Result()
+		require.Equal(200, res.StatusCode)
+		assert.Equal(metrics.ContentType, res.Header.Get("Content-Type"))
+		body, e := io.ReadAll(res.Body)
+		require.NoError(e)
+		return string(body)
+	}
+
+	assert.Contains(scrape(), "ndndpdk_test_collect_total 1\n")
+	assert.Contains(scrape(), "ndndpdk_test_collect_total 1\n")
+	time.Sleep(300 * time.Millisecond)
+	assert.Contains(scrape(), "ndndpdk_test_collect_total 2\n")
+}
@@ -3,11 +3,22 @@
 static_assert(sizeof(rte_mbuf_timestamp_t) == sizeof(TscTime), "");
 
 int Mbuf_Timestamp_DynFieldOffset_ = -1;
+int Mbuf_EnqueueTimestamp_DynFieldOffset_ = -1;
 
 bool
 Mbuf_RegisterDynFields() {
   int res = rte_mbuf_dyn_rx_timestamp_register(&Mbuf_Timestamp_DynFieldOffset_, NULL);
-  return res == 0;
+  if (res != 0) {
+    return false;
+  }
+
+  static const struct rte_mbuf_dynfield enqueueTimestampDesc = {
+    .name = "ndndpdk_dynfield_enqueue_timestamp",
+    .size = sizeof(TscTime),
+    .align = __alignof__(TscTime),
+  };
+  Mbuf_EnqueueTimestamp_DynFieldOffset_ = rte_mbuf_dynfield_register(&enqueueTimestampDesc);
+  return Mbuf_EnqueueTimestamp_DynFieldOffset_ >= 0;
 }
 
 int
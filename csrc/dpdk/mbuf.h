@@ -14,6 +14,7 @@ enum {
 };
 
 extern int Mbuf_Timestamp_DynFieldOffset_;
+extern int Mbuf_EnqueueTimestamp_DynFieldOffset_;
 
 /** @brief Register mbuf dynfields. */
 bool
@@ -31,6 +32,23 @@ Mbuf_SetTimestamp(struct rte_mbuf* m, TscTime timestamp) {
   *RTE_MBUF_DYNFIELD(m, Mbuf_Timestamp_DynFieldOffset_, TscTime*) = timestamp;
 }
 
+/**
+ * @brief Retrieve mbuf enqueue timestamp.
+ *
+ * This is the time when the packet was enqueued to a face output queue or a helper thread.
+ * It is used for per-packet tracing, while @c Mbuf_GetTimestamp continues to carry arrival time.
+ */
+__attribute__((nonnull)) static inline TscTime
+Mbuf_GetEnqueueTimestamp(struct rte_mbuf* m) {
+  return *RTE_MBUF_DYNFIELD(m, Mbuf_EnqueueTimestamp_DynFieldOffset_, TscTime*);
+}
+
+/** @brief Assign mbuf enqueue timestamp. */
+__attribute__((nonnull)) static inline void
+Mbuf_SetEnqueueTimestamp(struct rte_mbuf* m, TscTime timestamp) {
+  *RTE_MBUF_DYNFIELD(m, Mbuf_EnqueueTimestamp_DynFieldOffset_, TscTime*) = timestamp;
+}
+
 /** @brief Retrieve mbuf MARK action value. */
 __attribute__((nonnull)) static inline uint32_t
 Mbuf_GetMark(const struct rte_mbuf* m) {
@@ -158,14 +158,17 @@ EthFace_SetupRxMemif(EthFacePriv* priv, const EthLocator* loc) {
 }
 
 uint16_t
-EthFace_TxBurst(Face* face, struct rte_mbuf** pkts, uint16_t nPkts) {
+EthFace_TxBurst(Face* face, int txThread, struct rte_mbuf** pkts, uint16_t nPkts) {
   EthFacePriv* priv = Face_GetPriv(face);
+  EthTxHdrFlags flags = face->impl->txFlowHash ? EthTxHdrFlagsFlowHash : 0;
   for (uint16_t i = 0; i < nPkts; ++i) {
     struct rte_mbuf* m = pkts[i];
     NDNDPDK_ASSERT(!face->txAlign.linearize || rte_pktmbuf_is_contiguous(m));
-    EthTxHdr_Prepend(&priv->txHdr, m, i == 0);
+    EthTxHdr_Prepend(&priv->txHdr, m, flags | (i == 0 ? EthTxHdrFlagsNewBurst : 0));
   }
-  return rte_eth_tx_burst(priv->port, 0, pkts, nPkts);
+  // TX thread i uses hardware TX queue i, which is served by the same TxLoop for every face
+  static_assert(MaxFaceTxThreads <= RTE_MAX_QUEUES_PER_PORT, "");
+  return rte_eth_tx_burst(priv->port, txThread, pkts, nPkts);
 }
 
 STATIC_ASSERT_FUNC_TYPE(Face_TxBurstFunc, EthFace_TxBurst);
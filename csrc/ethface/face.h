@@ -39,6 +39,6 @@ __attribute__((nonnull)) void
 EthFace_SetupRxMemif(EthFacePriv* priv, const EthLocator* loc);
 
 __attribute__((nonnull)) uint16_t
-EthFace_TxBurst(Face* face, struct rte_mbuf** pkts, uint16_t nPkts);
+EthFace_TxBurst(Face* face, int txThread, struct rte_mbuf** pkts, uint16_t nPkts);
 
 #endif // NDNDPDK_ETHFACE_FACE_H
@@ -32,13 +32,21 @@ TxEther(const EthTxHdr* hdr, struct rte_mbuf* m, EthTxHdrFlags flags) {
 }
 
 __attribute__((nonnull)) static __rte_always_inline void
-TxUdpCommon(const EthTxHdr* hdr, struct rte_udp_hdr* udp, uint16_t udpLen, EthTxHdrFlags flags) {
+TxUdpCommon(const EthTxHdr* hdr, const struct rte_mbuf* m, struct rte_udp_hdr* udp, uint16_t udpLen,
+            EthTxHdrFlags flags) {
   udp->dgram_len = rte_cpu_to_be_16(udpLen);
   switch (hdr->tunnel) {
     case 'V': {
       static_assert((VXLAN_SRCPORT_BASE & VXLAN_SRCPORT_MASK) == 0, "");
-      uint16_t incPort = (flags & EthTxHdrFlagsNewBurst) != 0 ? 1 : 0;
-      uint16_t srcPort = (*LCORE_VAR_SAFE(txVxlanSrcPort) += incPort);
+      uint16_t srcPort = 0;
+      if ((flags & EthTxHdrFlagsFlowHash) != 0) {
+        // all fragments of an L3 packet share the same source port, so that they are dispatched
+        // to the same RX queue by RSS on the receiver
+        srcPort = m->hash.usr;
+      } else {
+        uint16_t incPort = (flags & EthTxHdrFlagsNewBurst) != 0 ? 1 : 0;
+        srcPort = (*LCORE_VAR_SAFE(txVxlanSrcPort) += incPort);
+      }
       udp->src_port = rte_cpu_to_be_16((srcPort & VXLAN_SRCPORT_MASK) | VXLAN_SRCPORT_BASE);
       break;
     }
@@ -65,7 +73,7 @@ TxUdp4(const EthTxHdr* hdr, struct rte_mbuf* m, EthTxHdrFlags flags) {
   struct rte_udp_hdr* udp = RTE_PTR_ADD(ip, sizeof(*ip));
   uint16_t ipLen = m->pkt_len - hdr->l2len;
   ip->total_length = rte_cpu_to_be_16(ipLen);
-  TxUdpCommon(hdr, udp, ipLen - sizeof(*ip), flags);
+  TxUdpCommon(hdr, m, udp, ipLen - sizeof(*ip), flags);
   return ip;
 }
 
@@ -88,7 +96,7 @@ TxUdp6(const EthTxHdr* hdr, struct rte_mbuf* m, EthTxHdrFlags flags) {
   TxPrepend(hdr, m, flags);
   struct rte_ipv6_hdr* ip = rte_pktmbuf_mtod_offset(m, struct rte_ipv6_hdr*, hdr->l2len);
   struct rte_udp_hdr* udp = RTE_PTR_ADD(ip, sizeof(*ip));
-  TxUdpCommon(hdr, udp, m->pkt_len - hdr->l2len - sizeof(*ip), flags);
+  TxUdpCommon(hdr, m, udp, m->pkt_len - hdr->l2len - sizeof(*ip), flags);
   ip->payload_len = udp->dgram_len;
   return ip;
 }
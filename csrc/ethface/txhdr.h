@@ -21,6 +21,8 @@ typedef enum EthTxHdrFlags {
   EthTxHdrFlagsNewBurst = RTE_BIT32(0),
   /** @brief Whether mbuf contains Ethernet+IPv4 instead of NDN. */
   EthTxHdrFlagsGtpip = RTE_BIT32(1),
+  /** @brief Whether @c mbuf->hash.usr contains L3 packet flow hash, used as VXLAN UDP source port. */
+  EthTxHdrFlagsFlowHash = RTE_BIT32(2),
 } __rte_packed EthTxHdrFlags;
 
 typedef struct EthTxHdr EthTxHdr;
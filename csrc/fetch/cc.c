@@ -0,0 +1,172 @@
+#include "cc.h"
+
+#define FETCHCC_IW 2.0
+#define FETCHCC_AIMD_BETA 0.5
+#define FETCHCC_BBR_HIGH_GAIN 2.885 // 2/ln(2)
+#define FETCHCC_BBR_FULL_BW_THRESH 1.25
+#define FETCHCC_BBR_FULL_BW_COUNT 3
+#define FETCHCC_BBR_MIN_CWND 4.0
+#define FETCHCC_BBR_MINRTT_WINDOW_MS 10000
+#define FETCHCC_BBR_CRUISE_CYCLE 2
+
+static const double FetchCc_BbrProbeGain[] = {1.25, 0.75, 1.0, 1.0, 1.0, 1.0, 1.0, 1.0};
+
+void
+FetchCc_Init(FetchCc* cc) {
+  TcpCubic_Init(&cc->cubic);
+  cc->bbr = (FetchCcBbrState){0};
+  cc->cwnd = FETCHCC_IW;
+  cc->ssthresh = DBL_MAX;
+  cc->fixedCwnd = RTE_MAX(cc->fixedCwnd, 1);
+}
+
+__attribute__((nonnull)) static inline void
+FetchCc_IncreaseAimd(FetchCc* cc) {
+  if (cc->cwnd < cc->ssthresh) { // slow start
+    cc->cwnd += 1.0;
+  } else { // congestion avoidance
+    cc->cwnd += 1.0 / cc->cwnd;
+  }
+}
+
+__attribute__((nonnull)) static inline double
+FetchCc_BbrGain(const FetchCcBbrState* bbr) {
+  switch (bbr->mode) {
+    case FetchCcBbrStartup:
+      return FETCHCC_BBR_HIGH_GAIN;
+    case FetchCcBbrDrain:
+      return 1.0 / FETCHCC_BBR_HIGH_GAIN;
+    default:
+      return FetchCc_BbrProbeGain[bbr->cycleIndex];
+  }
+}
+
+/** @brief Record delivery rate of the completed round and advance the state machine. */
+__attribute__((nonnull)) static void
+FetchCc_BbrEndRound(FetchCcBbrState* bbr, TscTime now) {
+  bbr->bwSamples[bbr->nRounds % FetchCcBbrBwWindow] =
+    (double)bbr->roundDelivered / (double)(now - bbr->roundStart);
+  ++bbr->nRounds;
+  bbr->roundStart = now;
+  bbr->roundDelivered = 0;
+
+  bbr->btlBw = 0.0;
+  for (int i = 0; i < FetchCcBbrBwWindow; ++i) {
+    bbr->btlBw = RTE_MAX(bbr->btlBw, bbr->bwSamples[i]);
+  }
+
+  switch (bbr->mode) {
+    case FetchCcBbrStartup:
+      if (bbr->btlBw >= bbr->fullBw * FETCHCC_BBR_FULL_BW_THRESH) {
+        bbr->fullBw = bbr->btlBw;
+        bbr->fullBwCount = 0;
+      } else if (++bbr->fullBwCount >= FETCHCC_BBR_FULL_BW_COUNT) {
+        bbr->mode = FetchCcBbrDrain;
+      }
+      break;
+    case FetchCcBbrDrain:
+      bbr->mode = FetchCcBbrProbeBw;
+      bbr->cycleIndex = 0;
+      break;
+    case FetchCcBbrProbeBw:
+      bbr->cycleIndex = (bbr->cycleIndex + 1) % RTE_DIM(FetchCc_BbrProbeGain);
+      break;
+  }
+}
+
+/**
+ * @brief BBR-style window adjustment.
+ *
+ * Bottleneck bandwidth and min-RTT are estimated from Data arrivals. Before the first round
+ * completes, the window grows as in slow start. Afterwards, the window is the estimated
+ * bandwidth-delay product multiplied by a gain that depends on the mode:
+ * startup gain 2/ln2, drain gain ln2/2 for one round, then ProbeBW cycling 1.25, 0.75, 1, ...
+ * Since the fetcher is window-limited and has no pacing, the gain is applied to the window.
+ */
+__attribute__((nonnull)) static inline void
+FetchCc_IncreaseBbr(FetchCc* cc, TscTime now, TscDuration rtt) {
+  FetchCcBbrState* bbr = &cc->bbr;
+  if (rtt > 0 &&
+      (bbr->minRtt == 0 || rtt <= bbr->minRtt ||
+       (TscDuration)(now - bbr->minRttStamp) > TscDuration_FromMillis(FETCHCC_BBR_MINRTT_WINDOW_MS))) {
+    bbr->minRtt = rtt;
+    bbr->minRttStamp = now;
+  }
+
+  ++bbr->roundDelivered;
+  if (bbr->roundStart == 0) {
+    bbr->roundStart = now;
+  } else if (bbr->minRtt > 0 && (TscDuration)(now - bbr->roundStart) >= bbr->minRtt) {
+    FetchCc_BbrEndRound(bbr, now);
+  }
+
+  if (bbr->nRounds == 0) {
+    cc->cwnd += 1.0;
+    return;
+  }
+  double bdp = bbr->btlBw * bbr->minRtt;
+  cc->cwnd = RTE_MAX(FetchCc_BbrGain(bbr) * bdp, FETCHCC_BBR_MIN_CWND);
+}
+
+/**
+ * @brief BBR-style reaction to congestion signal.
+ *
+ * A congestion signal indicates the pipe is full: startup ends, ProbeBW stops probing upward and
+ * cruises at gain 1, and the window is capped at the estimated bandwidth-delay product.
+ */
+__attribute__((nonnull)) static inline void
+FetchCc_DecreaseBbr(FetchCc* cc) {
+  FetchCcBbrState* bbr = &cc->bbr;
+  if (bbr->nRounds == 0) {
+    cc->cwnd = RTE_MAX(cc->cwnd * FETCHCC_AIMD_BETA, FETCHCC_IW);
+    return;
+  }
+
+  switch (bbr->mode) {
+    case FetchCcBbrStartup:
+      bbr->mode = FetchCcBbrDrain;
+      break;
+    case FetchCcBbrDrain:
+      break;
+    case FetchCcBbrProbeBw:
+      bbr->cycleIndex = FETCHCC_BBR_CRUISE_CYCLE;
+      break;
+  }
+  double bdp = bbr->btlBw * bbr->minRtt;
+  cc->cwnd = RTE_MAX(RTE_MIN(cc->cwnd, bdp), FETCHCC_BBR_MIN_CWND);
+}
+
+void
+FetchCc_Increase(FetchCc* cc, TscTime now, TscDuration rtt, double sRtt) {
+  switch (cc->algo) {
+    case FetchCcCubic:
+      TcpCubic_Increase(&cc->cubic, now, sRtt);
+      break;
+    case FetchCcAimd:
+      FetchCc_IncreaseAimd(cc);
+      break;
+    case FetchCcBbr:
+      FetchCc_IncreaseBbr(cc, now, rtt);
+      break;
+    case FetchCcFixed:
+      break;
+  }
+}
+
+void
+FetchCc_Decrease(FetchCc* cc, TscTime now) {
+  switch (cc->algo) {
+    case FetchCcCubic:
+      TcpCubic_Decrease(&cc->cubic, now);
+      break;
+    case FetchCcAimd:
+      cc->cwnd *= FETCHCC_AIMD_BETA;
+      cc->ssthresh = RTE_MAX(cc->cwnd, FETCHCC_IW);
+      break;
+    case FetchCcBbr:
+      FetchCc_DecreaseBbr(cc);
+      break;
+    case FetchCcFixed:
+      break;
+  }
+}
@@ -0,0 +1,96 @@
+#ifndef NDNDPDK_FETCH_CC_H
+#define NDNDPDK_FETCH_CC_H
+
+/** @file */
+
+#include "tcpcubic.h"
+
+/** @brief Congestion control algorithm. */
+typedef enum FetchCcAlgo {
+  FetchCcCubic, ///< TCP CUBIC
+  FetchCcAimd,  ///< additive increase multiplicative decrease
+  FetchCcBbr,   ///< BBR-style model-based control
+  FetchCcFixed, ///< fixed window
+} __rte_packed FetchCcAlgo;
+
+enum {
+  /** @brief BBR bottleneck bandwidth max filter window, in rounds. */
+  FetchCcBbrBwWindow = 10,
+};
+
+/** @brief BBR state machine mode. */
+typedef enum FetchCcBbrMode {
+  FetchCcBbrStartup, ///< grow exponentially until bottleneck bandwidth is found
+  FetchCcBbrDrain,   ///< drain the queue created in startup
+  FetchCcBbrProbeBw, ///< cycle window gain around estimated BDP
+} __rte_packed FetchCcBbrMode;
+
+/**
+ * @brief BBR-style congestion control state.
+ *
+ * A round is one min-RTT long. At the end of each round, the delivery rate of that round is
+ * recorded, and the bottleneck bandwidth is the maximum delivery rate in recent rounds.
+ */
+typedef struct FetchCcBbrState {
+  double bwSamples[FetchCcBbrBwWindow]; ///< per-round delivery rate, Data per TSC unit
+  double btlBw;                         ///< bottleneck bandwidth estimate, Data per TSC unit
+  double fullBw;                        ///< btlBw when startup last saw significant growth
+  TscTime roundStart;                   ///< start time of current round, 0 if not started
+  TscTime minRttStamp;                  ///< when minRtt was last updated
+  TscDuration minRtt;                   ///< minimum RTT in recent window, 0 if unknown
+  uint32_t roundDelivered;              ///< Data delivered in current round
+  uint32_t nRounds;                     ///< number of completed rounds
+  uint8_t fullBwCount;                  ///< startup rounds without significant growth
+  uint8_t cycleIndex;                   ///< ProbeBW gain cycle position
+  FetchCcBbrMode mode;
+} FetchCcBbrState;
+
+/**
+ * @brief Fetcher congestion control.
+ *
+ * @c algo and @c fixedCwnd are configuration fields, which should be assigned before
+ * @c FetchCc_Init and are preserved across initialization.
+ */
+typedef struct FetchCc {
+  TcpCubic cubic;      ///< CUBIC state
+  FetchCcBbrState bbr; ///< BBR state
+  double cwnd;         ///< AIMD and BBR congestion window
+  double ssthresh;     ///< AIMD slow start threshold
+  uint32_t fixedCwnd;  ///< congestion window of fixed window mode
+  FetchCcAlgo algo;    ///< congestion control algorithm
+} FetchCc;
+
+/** @brief Reset congestion control state. */
+__attribute__((nonnull)) void
+FetchCc_Init(FetchCc* cc);
+
+/** @brief Retrieve congestion window. */
+__attribute__((nonnull)) static inline uint32_t
+FetchCc_GetCwnd(FetchCc* cc) {
+  switch (cc->algo) {
+    case FetchCcCubic:
+      return TcpCubic_GetCwnd(&cc->cubic);
+    case FetchCcFixed:
+      return cc->fixedCwnd;
+    default:
+      return RTE_MAX((uint32_t)cc->cwnd, 1);
+  }
+}
+
+/**
+ * @brief Window increase upon Data arrival.
+ * @param rtt RTT sample of this Data, or 0 if unavailable due to retransmission.
+ * @param sRtt smoothed RTT.
+ */
+__attribute__((nonnull)) void
+FetchCc_Increase(FetchCc* cc, TscTime now, TscDuration rtt, double sRtt);
+
+/**
+ * @brief Window decrease upon congestion signal.
+ *
+ * Caller must ensure this is invoked no more than once per RTT.
+ */
+__attribute__((nonnull)) void
+FetchCc_Decrease(FetchCc* cc, TscTime now);
+
+#endif // NDNDPDK_FETCH_CC_H
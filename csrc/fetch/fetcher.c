@@ -42,26 +42,51 @@ FetchTask_TxBurst(FetchTask* fp, FetchThread* fth) {
 }
 
 __attribute__((nonnull)) static inline bool
-FetchTask_DecodeData(FetchTask* fp, Packet* npkt, FetchLogicRxData* lpkt) {
+FetchTask_DecodeSegNum(FetchTask* fp, const PName* name, uint64_t* segNum) {
+  const uint8_t* seqNumComp = RTE_PTR_ADD(name->value, fp->tpl.prefixL);
+  return name->length > fp->tpl.prefixL + 1 &&
+         // this memcmp checks for SegmentNameComponent TLV-TYPE also
+         memcmp(name->value, fp->tpl.prefixV, fp->tpl.prefixL + 1) == 0 &&
+         Nni_Decode(seqNumComp[1], RTE_PTR_ADD(seqNumComp, 2), segNum);
+}
+
+__attribute__((nonnull)) static inline bool
+FetchTask_DecodePacket(FetchTask* fp, Packet* npkt, FetchLogicRxData* lpkt) {
   LpL3* lpl3 = Packet_GetLpL3Hdr(npkt);
   lpkt->congMark = lpl3->congMark;
 
+  if (unlikely(Packet_GetType(npkt) == PktNack)) {
+    const PNack* nack = Packet_GetNackHdr(npkt);
+    lpkt->nackReason = RTE_MAX(lpl3->nackReason, 1);
+    lpkt->isFinalBlock = false;
+    return FetchTask_DecodeSegNum(fp, &nack->interest.name, &lpkt->segNum);
+  }
+
   const PData* data = Packet_GetDataHdr(npkt);
+  lpkt->nackReason = 0;
   lpkt->isFinalBlock = data->isFinalBlock;
-
-  const uint8_t* seqNumComp = RTE_PTR_ADD(data->name.value, fp->tpl.prefixL);
-  return data->name.length > fp->tpl.prefixL + 1 &&
-         // this memcmp checks for SegmentNameComponent TLV-TYPE also
-         memcmp(data->name.value, fp->tpl.prefixV, fp->tpl.prefixL + 1) == 0 &&
-         Nni_Decode(seqNumComp[1], RTE_PTR_ADD(seqNumComp, 2), &lpkt->segNum);
+  return FetchTask_DecodeSegNum(fp, &data->name, &lpkt->segNum);
 }
 
+/** @brief Pending write context, stored in PData.helperScratch. */
+typedef struct FetchWriteCtx {
+  FetchTask* fp;
+  uint64_t* doneBitmap; ///< fp->logic.doneBitmap when the write was submitted
+  uint64_t segNum;
+  struct iovec iov[];
+} FetchWriteCtx;
+
+enum {
+  FetchWriteMaxIov =
+    (sizeof(((PData*)NULL)->helperScratch) - sizeof(FetchWriteCtx)) / sizeof(struct iovec),
+};
+
 __attribute__((nonnull)) static inline bool
 FetchTask_WriteData(FetchThread* fth, FetchTask* fp, Packet* npkt, FetchLogicRxData* lpkt) {
   const PData* data = Packet_GetDataHdr(npkt);
   struct rte_mbuf* pkt = Packet_ToMbuf(npkt);
-  struct iovec* iov = (struct iovec*)data->helperScratch;
-  if (unlikely(pkt->nb_segs > sizeof(data->helperScratch) / sizeof(iov[0]))) {
+  FetchWriteCtx* ctx = (FetchWriteCtx*)data->helperScratch;
+  if (unlikely(pkt->nb_segs > FetchWriteMaxIov)) {
     N_LOGW("%p WriteData seg=%" PRIu64 " frags=%" PRIu16 N_LOG_ERROR_STR, fp, lpkt->segNum,
            pkt->nb_segs, "too-many-frags");
     return false;
@@ -73,12 +98,32 @@ FetchTask_WriteData(FetchThread* fth, FetchTask* fp, Packet* npkt, FetchLogicRxD
     return false;
   }
 
-  int iovcnt = Mbuf_AsIovec(pkt, iov, data->contentOffset, data->contentL);
-  io_uring_prep_writev(sqe, fp->fd, iov, iovcnt, lpkt->segNum * fp->segmentLen);
+  ctx->fp = fp;
+  ctx->doneBitmap = fp->logic.doneBitmap;
+  ctx->segNum = lpkt->segNum;
+  int iovcnt = Mbuf_AsIovec(pkt, ctx->iov, data->contentOffset, data->contentL);
+  io_uring_prep_writev(sqe, fp->fd, ctx->iov, iovcnt, lpkt->segNum * fp->segmentLen);
   io_uring_sqe_set_data(sqe, pkt);
   return true;
 }
 
+/**
+ * @brief Handle write completion.
+ *
+ * If the whole payload has been written, the segment is marked done, unless the task slot has
+ * been reassigned since the write was submitted.
+ */
+__attribute__((nonnull)) static inline void
+FetchTask_WriteDone(struct rte_mbuf* pkt, int res) {
+  const PData* data = Packet_GetDataHdr(Packet_FromMbuf(pkt));
+  const FetchWriteCtx* ctx = (const FetchWriteCtx*)data->helperScratch;
+  FetchLogic* fl = &ctx->fp->logic;
+  if (likely((uint32_t)res == data->contentL) && ctx->doneBitmap != NULL &&
+      ctx->doneBitmap == rcu_dereference(fl->doneBitmap)) {
+    FetchLogic_SetDone(fl, ctx->segNum);
+  }
+}
+
 __attribute__((nonnull)) static __rte_always_inline uint32_t
 FetchTask_RxBurst(FetchThread* fth, FetchTask* fp, bool wantWrite) {
   TscTime now = rte_get_tsc_cycles();
@@ -92,16 +137,16 @@ FetchTask_RxBurst(FetchThread* fth, FetchTask* fp, bool wantWrite) {
   for (uint16_t i = 0; i < nRx; ++i) {
     Packet* npkt = npkts[i];
     FetchLogicRxData* lpkt = &lpkts[count];
-    bool ok = FetchTask_DecodeData(fp, npkt, lpkt);
-    if (wantWrite && likely(ok)) {
-      ok = FetchTask_WriteData(fth, fp, npkt, lpkt);
+    bool ok = FetchTask_DecodePacket(fp, npkt, lpkt);
+    bool written = false;
+    if (wantWrite && likely(ok) && lpkt->nackReason == 0) {
+      ok = written = FetchTask_WriteData(fth, fp, npkt, lpkt);
     }
 
-    if (unlikely(!ok)) {
+    if (!written) {
       discards[nDiscards++] = npkt;
-      continue;
     }
-    ++count;
+    count += (uint32_t)ok;
   }
   FetchLogic_RxDataBurst(&fp->logic, lpkts, count, now);
 
@@ -142,10 +187,12 @@ FetchThread_CqBurst(FetchThread* fth) {
   struct rte_mbuf* discards[MaxBurstSize];
   for (uint32_t i = 0; i < n; ++i) {
     struct io_uring_cqe* cqe = cqes[i];
+    discards[i] = io_uring_cqe_get_data(cqe);
     if (unlikely(cqe->res < 0)) {
       N_LOGW("%p CQE error" N_LOG_ERROR_ERRNO, fth, cqe->res);
+    } else {
+      FetchTask_WriteDone(discards[i], cqe->res);
     }
-    discards[i] = io_uring_cqe_get_data(cqe);
   }
   Uring_SeenCqes(&fth->ur, n);
 
@@ -1,6 +1,7 @@
 #include "logic.h"
 
 #include "../core/logger.h"
+#include "../ndni/an.h"
 
 N_LOG_INIT(FetchLogic);
 
@@ -21,24 +22,30 @@ FetchLogic_TxInterest(FetchLogic* fl, int* nNew, int* nRetx) {
     FetchWindow_Delete(&fl->win, seg->segNum);
   }
 
-  if (unlikely(fl->win.hiSegNum >= fl->segmentEnd)) { // reached final segment
-    return NULL;
-  }
+  while (likely(fl->win.hiSegNum < fl->segmentEnd)) {
+    FetchSeg* seg = FetchWindow_Append(&fl->win); // fetch new segment
+    if (unlikely(seg == NULL)) {                  // FetchWindow is full
+      return NULL;
+    }
+
+    if (unlikely(FetchLogic_IsDone(fl, seg->segNum))) { // written before resuming
+      ++fl->nSkipped;
+      FetchWindow_Delete(&fl->win, seg->segNum);
+      continue;
+    }
 
-  FetchSeg* seg = FetchWindow_Append(&fl->win); // fetch new segment
-  if (likely(seg != NULL)) {
     ++*nNew;
     // seg->rtoExpiry is zero'ed, safe to pass to MinTmr_After
     return seg;
   }
 
-  // FetchWindow is full
+  // reached final segment
   return NULL;
 }
 
 size_t
 FetchLogic_TxInterestBurst(FetchLogic* fl, uint64_t* segNums, size_t limit, TscTime now) {
-  uint32_t cwnd = TcpCubic_GetCwnd(&fl->ca);
+  uint32_t cwnd = FetchCc_GetCwnd(&fl->cc);
   size_t count = 0;
   int nNew = 0, nRetx = 0;
 
@@ -71,13 +78,13 @@ FetchLogic_DecreaseCwnd(FetchLogic* fl, const char* caller, uint64_t segNum, Tsc
   if (unlikely(now < fl->nextCwndDec)) {
     return false;
   }
-  TcpCubic_Decrease(&fl->ca, now);
+  FetchCc_Decrease(&fl->cc, now);
   fl->nextCwndDec = now + fl->rtte.rto;
 
   N_LOGD("%s fl=%p seg=%" PRIu64 " win=[%" PRIu64 ",%" PRIu64 ") rto=%" PRId64 " cwnd=%" PRIu32
          " nInFlight=%" PRIu32 "",
          caller, fl, segNum, fl->win.loSegNum, fl->win.hiSegNum, TscDuration_ToMillis(fl->rtte.rto),
-         TcpCubic_GetCwnd(&fl->ca), fl->nInFlight);
+         FetchCc_GetCwnd(&fl->cc), fl->nInFlight);
   return true;
 }
 
@@ -99,15 +106,16 @@ FetchLogic_RxData(FetchLogic* fl, TscTime now, uint64_t segNum, bool hasCongMark
     MinTmr_Cancel(&seg->rtoExpiry);
   }
 
+  TscDuration rtt = 0;
   if (likely(!seg->hasRetx)) { // RTT valid only if no retx was sent
-    TscDuration rtt = ((uint64_t)now - seg->txTime) & FetchSegTxTimeMask;
+    rtt = ((uint64_t)now - seg->txTime) & FetchSegTxTimeMask;
     RttEst_Push(&fl->rtte, now, rtt);
   }
 
   if (unlikely(hasCongMark)) {
     FetchLogic_DecreaseCwnd(fl, "RxDataCongMark", segNum, now);
   } else {
-    TcpCubic_Increase(&fl->ca, now, fl->rtte.rttv.sRtt);
+    FetchCc_Increase(&fl->cc, now, rtt, fl->rtte.rttv.sRtt);
   }
 
   if (unlikely(isFinalBlock)) {
@@ -117,9 +125,39 @@ FetchLogic_RxData(FetchLogic* fl, TscTime now, uint64_t segNum, bool hasCongMark
   FetchWindow_Delete(&fl->win, segNum);
 }
 
+/**
+ * @brief Handle Nack arrival.
+ *
+ * Nack~Congestion decreases the congestion window and retransmits the segment immediately.
+ * Other Nack reasons, such as NoRoute, do not indicate congestion; the segment is retransmitted
+ * upon RTO expiry, so that an unreachable producer is not flooded with Interests.
+ */
+__attribute__((nonnull)) static inline void
+FetchLogic_RxNack(FetchLogic* fl, TscTime now, uint64_t segNum, uint8_t reason) {
+  FetchSeg* seg = FetchWindow_Get(&fl->win, segNum);
+  if (unlikely(seg == NULL || seg->inRetxQ)) {
+    return;
+  }
+  ++fl->nRxNack;
+
+  if (reason != NackCongestion) {
+    return;
+  }
+  FetchLogic_DecreaseCwnd(fl, "RxNackCongestion", segNum, now);
+
+  --fl->nInFlight;
+  MinTmr_Cancel(&seg->rtoExpiry);
+  seg->inRetxQ = true;
+  cds_list_add_tail(&seg->retxNode, &fl->retxQ);
+}
+
 void
 FetchLogic_RxDataBurst(FetchLogic* fl, const FetchLogicRxData* pkts, size_t count, TscTime now) {
   for (size_t i = 0; i < count; ++i) {
+    if (unlikely(pkts[i].nackReason != 0)) {
+      FetchLogic_RxNack(fl, now, pkts[i].segNum, pkts[i].nackReason);
+      continue;
+    }
     FetchLogic_RxData(fl, now, pkts[i].segNum, pkts[i].congMark > 0, pkts[i].isFinalBlock);
   }
   if (unlikely(fl->finishTime == 0 && fl->win.loSegNum >= fl->segmentEnd)) {
@@ -168,7 +206,7 @@ void
 FetchLogic_Reset(FetchLogic* fl, uint64_t segmentBegin, uint64_t segmentEnd) {
   FetchWindow_Reset(&fl->win, segmentBegin);
   RttEst_Init(&fl->rtte);
-  TcpCubic_Init(&fl->ca);
+  FetchCc_Init(&fl->cc);
   MinSched_Clear(fl->sched);
 
   CDS_INIT_LIST_HEAD(&fl->retxQ);
@@ -178,5 +216,10 @@ FetchLogic_Reset(FetchLogic* fl, uint64_t segmentBegin, uint64_t segmentEnd) {
   fl->nextCwndDec = 0;
   fl->nTxRetx = 0;
   fl->nRxData = 0;
+  fl->nRxNack = 0;
+  fl->nSkipped = 0;
   fl->nInFlight = 0;
+  fl->doneBitmap = NULL;
+  fl->doneBegin = 0;
+  fl->doneEnd = 0;
 }
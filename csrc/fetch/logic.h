@@ -4,8 +4,9 @@
 /** @file */
 
 #include "../core/rttest.h"
-#include "tcpcubic.h"
+#include "cc.h"
 #include "window.h"
+#include <urcu-pointer.h>
 
 typedef TAILQ_HEAD(FetchRetxQueue, FetchSeg) FetchRetxQueue;
 
@@ -13,7 +14,7 @@ typedef TAILQ_HEAD(FetchRetxQueue, FetchSeg) FetchRetxQueue;
 typedef struct FetchLogic {
   FetchWindow win;
   RttEst rtte;
-  TcpCubic ca;
+  FetchCc cc;
   struct cds_list_head retxQ;
   MinSched* sched;
   uint64_t segmentEnd; ///< last segnum desired plus one
@@ -22,7 +23,19 @@ typedef struct FetchLogic {
   TscTime nextCwndDec; ///< when cwnd may be decreased again
   uint64_t nTxRetx;    ///< retransmitted Interests
   uint64_t nRxData;    ///< non-duplicate Data
+  uint64_t nRxNack;    ///< Nacks of pending Interests
+  uint64_t nSkipped;   ///< segments skipped because they are marked done
   uint32_t nInFlight;  ///< count of in-flight Interests
+
+  /**
+   * @brief Bitmap of segments that have been written to the output file.
+   *
+   * Bit i refers to segment number doneBegin+i.
+   * If not NULL, segments marked in this bitmap are skipped, so that an aborted task can be resumed.
+   */
+  uint64_t* doneBitmap;
+  uint64_t doneBegin; ///< first segment number in doneBitmap
+  uint64_t doneEnd;   ///< last segment number in doneBitmap plus one
 } FetchLogic;
 
 __attribute__((nonnull)) void
@@ -31,9 +44,36 @@ FetchLogic_Init(FetchLogic* fl, uint32_t winCapacity, int numaSocket);
 __attribute__((nonnull)) void
 FetchLogic_Free(FetchLogic* fl);
 
+/**
+ * @brief Reset to initial state.
+ * @pre @c fl->cc.algo and @c fl->cc.fixedCwnd are assigned.
+ * @post @c fl->doneBitmap is NULL.
+ */
 __attribute__((nonnull)) void
 FetchLogic_Reset(FetchLogic* fl, uint64_t segmentBegin, uint64_t segmentEnd);
 
+/** @brief Determine whether a segment is marked done in doneBitmap. */
+__attribute__((nonnull)) static inline bool
+FetchLogic_IsDone(FetchLogic* fl, uint64_t segNum) {
+  uint64_t* bitmap = rcu_dereference(fl->doneBitmap);
+  if (bitmap == NULL || segNum < fl->doneBegin || segNum >= fl->doneEnd) {
+    return false;
+  }
+  uint64_t i = segNum - fl->doneBegin;
+  return (bitmap[i >> 6] & RTE_BIT64(i & 0x3F)) != 0;
+}
+
+/** @brief Mark a segment done in doneBitmap, if it exists. */
+__attribute__((nonnull)) static inline void
+FetchLogic_SetDone(FetchLogic* fl, uint64_t segNum) {
+  uint64_t* bitmap = rcu_dereference(fl->doneBitmap);
+  if (bitmap == NULL || segNum < fl->doneBegin || segNum >= fl->doneEnd) {
+    return;
+  }
+  uint64_t i = segNum - fl->doneBegin;
+  bitmap[i >> 6] |= RTE_BIT64(i & 0x3F);
+}
+
 /**
  * @brief Request to transmit a burst of Interests.
  * @param[out] segNums segment numbers to retrieve.
@@ -45,12 +85,13 @@ FetchLogic_TxInterestBurst(FetchLogic* fl, uint64_t* segNums, size_t limit, TscT
 typedef struct FetchLogicRxData {
   uint64_t segNum;
   uint8_t congMark;
+  uint8_t nackReason; ///< Nack reason, or 0 if this is a Data packet
   bool isFinalBlock;
 } FetchLogicRxData;
 
 /**
- * @brief Notify Data arrival.
- * @param pkts fields extracted from arrived Data.
+ * @brief Notify Data or Nack arrival.
+ * @param pkts fields extracted from arrived Data or Nack.
  * @param count size of @p pkts array.
  */
 __attribute__((nonnull)) void
@@ -183,6 +183,7 @@ FileServerFd_New(FileServer* p, const PName* name, LName prefix, uint64_t hash,
 
   entry->refcnt = 1;
   entry->lsL = UINT32_MAX;
+  entry->digestState = FileServerFdDigestNone;
   entry->prefixL = prefix.length;
   rte_memcpy(entry->nameV, prefix.value, prefix.length);
   FileServerFd_PrepareVersionedName(p, entry);
@@ -255,6 +256,83 @@ FileServerFd_Clear(FileServer* p) {
   p->fdQCount = 0;
 }
 
+__attribute__((nonnull)) static inline bool
+FileServerFd_HasDigestState(const FileServerFd* entry) {
+  return entry->digestState != FileServerFdDigestNone && entry->digestVersion == entry->version &&
+         entry->digestSize == entry->st.stx_size;
+}
+
+/**
+ * @brief Submit SHA-256 digest computation job.
+ *
+ * If the job cannot be submitted, the digest state is unchanged, so that it would be retried on
+ * the next metadata request.
+ */
+__attribute__((nonnull)) static void
+FileServerFd_RequestDigest(FileServer* p, FileServerFd* entry) {
+  FileServerDigestJob* job = NULL;
+  if (unlikely(rte_mempool_get(p->digestMp, (void**)&job) != 0)) {
+    N_LOGD("RequestDigest fd=%d" N_LOG_ERROR("job-alloc-err"), entry->fd);
+    return;
+  }
+
+  job->fd = dup(entry->fd);
+  if (unlikely(job->fd < 0)) {
+    N_LOGW("RequestDigest fd=%d dup-err" N_LOG_ERROR_ERRNO, entry->fd, errno);
+    goto FAIL;
+  }
+  job->hash = entry->hh.hashv;
+  job->version = entry->version;
+  job->size = entry->st.stx_size;
+  job->ok = false;
+  job->prefixL = entry->prefixL;
+  rte_memcpy(job->prefixV, entry->nameV, entry->prefixL);
+
+  if (unlikely(rte_ring_enqueue(p->digestReq, job) != 0)) {
+    N_LOGD("RequestDigest fd=%d" N_LOG_ERROR("queue-full"), entry->fd);
+    close(job->fd);
+    goto FAIL;
+  }
+
+  entry->digestState = FileServerFdDigestPending;
+  entry->digestVersion = job->version;
+  entry->digestSize = job->size;
+  N_LOGD("RequestDigest fd=%d job-fd=%d version=%" PRIu64 " size=%" PRIu64, entry->fd, job->fd,
+         job->version, job->size);
+  return;
+
+FAIL:
+  rte_mempool_put(p->digestMp, job);
+}
+
+uint32_t
+FileServerFd_DigestBurst(FileServer* p) {
+  FileServerDigestJob* jobs[MaxBurstSize];
+  uint32_t n = rte_ring_sc_dequeue_burst(p->digestDone, (void**)jobs, RTE_DIM(jobs), NULL);
+  for (uint32_t i = 0; i < n; ++i) {
+    FileServerDigestJob* job = jobs[i];
+    LName prefix = {.length = job->prefixL, .value = job->prefixV};
+    FileServerFd* entry = NULL;
+    HASH_FIND_BYHASHVALUE(hh, p->fdHt, &prefix, 0, job->hash, entry);
+    if (entry == NULL || entry->digestState != FileServerFdDigestPending ||
+        entry->digestVersion != job->version || entry->digestSize != job->size) {
+      N_LOGD("DigestBurst version=%" PRIu64 " ok=%d drop=stale", job->version, (int)job->ok);
+      continue;
+    }
+
+    N_LOGD("DigestBurst fd=%d version=%" PRIu64 " ok=%d", entry->fd, job->version, (int)job->ok);
+    if (likely(job->ok)) {
+      rte_memcpy(entry->digest, job->digest, sizeof(entry->digest));
+      entry->digestState = FileServerFdDigestReady;
+      entry->metadataL = 0; // regenerate metadata to include digest
+    } else {
+      entry->digestState = FileServerFdDigestFailed;
+    }
+  }
+  rte_mempool_put_bulk(p->digestMp, (void**)jobs, n);
+  return n;
+}
+
 uint32_t
 FileServerFd_PrepareMetadata_(FileServer* p, FileServerFd* entry) {
   uint8_t* output = entry->metadataV;
@@ -297,6 +375,16 @@ FileServerFd_PrepareMetadata_(FileServer* p, FileServerFd* entry) {
   if (HAS_STAT_BIT(STATX_MTIME)) {
     APPEND_NNI(Mtime, 64, entry->version);
   }
+  if (p->digestMp != NULL && FileServerFd_IsFile(entry)) {
+    if (!FileServerFd_HasDigestState(entry)) {
+      FileServerFd_RequestDigest(p, entry);
+    } else if (entry->digestState == FileServerFdDigestReady) {
+      unaligned_uint32_t* tl = (void*)output;
+      *tl = TlvEncoder_ConstTL3(TtFileSha256, sizeof(entry->digest));
+      rte_memcpy(RTE_PTR_ADD(output, sizeof(*tl)), entry->digest, sizeof(entry->digest));
+      output += sizeof(*tl) + sizeof(entry->digest);
+    }
+  }
 
 #undef APPEND_NNI
 #undef HAS_STAT_BIT
@@ -17,6 +17,14 @@
  */
 #define FileServerFd_StatTime(t) ((uint64_t)(t).tv_sec * SPDK_SEC_TO_NSEC + (uint64_t)(t).tv_nsec)
 
+/** @brief SHA-256 digest state of a FileServerFd entry. */
+typedef enum FileServerFdDigestState {
+  FileServerFdDigestNone,    ///< digest not requested
+  FileServerFdDigestPending, ///< digest computation in progress
+  FileServerFdDigestReady,   ///< digest available
+  FileServerFdDigestFailed,  ///< digest computation failed
+} __rte_packed FileServerFdDigestState;
+
 /** @brief File descriptor related information in the file server. */
 typedef struct FileServerFd {
   RTE_MARKER self;                 ///< self reference used in HASH_ADD_BYHASHVALUE
@@ -26,18 +34,40 @@ typedef struct FileServerFd {
   uint8_t meta[16];                ///< MetaInfo (FinalBlockId only)
   uint64_t version;                ///< version number
   uint64_t lastSeg;                ///< last segment number
+  uint64_t digestVersion;          ///< file version of digestState
+  uint64_t digestSize;             ///< file size of digestState
   int fd;                          ///< file descriptor
   uint32_t lsL;                    ///< directory listing length (UINT32_MAX means invalid)
   uint16_t refcnt;                 ///< number of inflight SQEs referencing this entry
   uint16_t prefixL;                ///< mount+path TLV-LENGTH
   uint16_t versionedL;             ///< mount+path+[32=ls]+version TLV-LENGTH
   uint8_t metadataL;               ///< metadata length excluding Name (0 means invalid)
+  uint8_t digestState;             ///< SHA-256 digest state, FileServerFdDigestState
+  uint8_t digest[32];              ///< SHA-256 digest, valid if digestState is Ready
   uint8_t nameV[NameMaxLength];    ///< mount+path+[32=ls]+version TLV-VALUE
   char lsV[FileServerMaxLsResult]; ///< directory listing value
   uint8_t metadataV[FileServerEstimatedMetadataSize]; ///< metadata value excluding Name
 } FileServerFd;
 static_assert(FileServerEstimatedMetadataSize <= UINT8_MAX, "");
 
+/**
+ * @brief SHA-256 digest computation job.
+ *
+ * The worker thread submits a job to @c FileServer.digestReq ring.
+ * A Go goroutine computes the digest, so that reading a large file does not block the worker,
+ * and then returns the job to @c FileServer.digestDone ring.
+ */
+typedef struct FileServerDigestJob {
+  uint64_t hash;                  ///< fdHt hash value
+  uint64_t version;               ///< file version
+  uint64_t size;                  ///< file size
+  int fd;                         ///< duplicated file descriptor, closed by digest computation
+  bool ok;                        ///< whether digest computation succeeded
+  uint8_t digest[32];             ///< SHA-256 digest
+  uint16_t prefixL;               ///< mount+path TLV-LENGTH
+  uint8_t prefixV[NameMaxLength]; ///< mount+path TLV-VALUE
+} FileServerDigestJob;
+
 /** @brief Sentinel value to indicate file not found. */
 extern FileServerFd* FileServer_NotFound;
 
@@ -102,6 +132,13 @@ FileServerFd_PrepareMetadata(FileServer* p, FileServerFd* entry) {
 __attribute__((nonnull)) void
 FileServerFd_WriteMetadata(FileServerFd* entry, struct iovec* iov, int iovcnt);
 
+/**
+ * @brief Process completed SHA-256 digest jobs.
+ * @return number of processed jobs.
+ */
+__attribute__((nonnull)) uint32_t
+FileServerFd_DigestBurst(FileServer* p);
+
 /**
  * @brief Populate directory listing.
  * @param entry a valid FileServerFd entry representing a directory.
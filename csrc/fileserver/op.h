@@ -32,7 +32,8 @@ typedef struct FileServerOp {
  */
 #define FileServer_SignAndSend(p, ctx, fd, func, dataPkt, interestL3)                              \
   __extension__({                                                                                  \
-    Packet* dataNpkt = DataEnc_Sign((dataPkt), &(p)->mp, Face_PacketTxAlign((p)->face));           \
+    Packet* dataNpkt =                                                                             \
+      DataEnc_Sign((dataPkt), &(p)->mp, Face_PacketTxAlign((p)->face), (p)->signer);               \
     if (unlikely(dataNpkt == NULL)) {                                                              \
       N_LOGW(func " fd=%d drop=data-sign-err", (fd)->fd);                                          \
     } else {                                                                                       \
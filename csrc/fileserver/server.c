@@ -14,6 +14,9 @@ FileServer_Run(FileServer* p) {
   while (ThreadCtrl_Continue(p->ctrl, nProcessed)) {
     nProcessed += FileServer_RxBurst(p);
     nProcessed += FileServer_TxBurst(p);
+    if (p->digestMp != NULL) {
+      nProcessed += FileServerFd_DigestBurst(p);
+    }
   }
 
   Uring_Free(&p->ur);
@@ -31,9 +31,13 @@ typedef struct FileServer {
   FileServerCounters cnt;
 
   PacketMempools mp;
+  DataSigner* signer; ///< Data signer, NULL for Null signature
   struct rte_mempool* opMp;
   struct rte_mempool* fdMp;
   FileServerFd* fdHt;
+  struct rte_mempool* digestMp; ///< FileServerDigestJob mempool, NULL if SHA-256 is disabled
+  struct rte_ring* digestReq;   ///< SHA-256 digest jobs to be computed
+  struct rte_ring* digestDone;  ///< completed SHA-256 digest jobs
   struct cds_list_head fdQ;
   TscDuration statValidity;
   uint32_t versionBypassHi;
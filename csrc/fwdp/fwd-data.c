@@ -18,6 +18,7 @@ FwFwd_DataNeedDigest(FwFwd* fwd, FwFwdCtx* ctx) {
   // if crypto helper is unavailable, Interests with implicit digest should have been dropped
   NDNDPDK_ASSERT(fwd->cryptoHelper != NULL);
 
+  Mbuf_SetEnqueueTimestamp(ctx->pkt, rte_get_tsc_cycles()); // for hrlog
   int res = rte_ring_enqueue(fwd->cryptoHelper, ctx->npkt);
   if (unlikely(res != 0)) {
     N_LOGD("^ error=crypto-enqueue-error-%d", res);
@@ -97,6 +98,9 @@ FwFwd_RxData(FwFwd* fwd, FwFwdCtx* ctx) {
     FwFwdCtx_FreePkt(ctx);
     return;
   }
+  if (unlikely(Packet_GetDataHdr(ctx->npkt)->hasDigest)) { // returned from crypto helper
+    Hrlog_PostIf(HRLOG_CR, rte_get_tsc_cycles() - Mbuf_GetEnqueueTimestamp(ctx->pkt));
+  }
 
   PitFindResult pitFound = Pit_FindByData(fwd->pit, ctx->npkt, FwToken_GetPccToken(&ctx->rxToken));
   if (PitFindResult_Is(pitFound, PIT_FIND_NONE)) {
@@ -108,6 +112,7 @@ FwFwd_RxData(FwFwd* fwd, FwFwdCtx* ctx) {
     return;
   }
 
+  PrefixStat_Data(&fwd->prefixStat, &Packet_GetDataHdr(ctx->npkt)->name, ctx->pkt->pkt_len);
   ctx->nhFlt = ~0; // disallow any Interest forwarding
   rcu_read_lock();
 
@@ -94,6 +94,8 @@ FwFwd_InterestHitCsMemory(FwFwd* fwd, FwFwdCtx* ctx, CsEntry* csEntry) {
          outNpkt, LpPitToken_ToString(&ctx->rxToken));
   if (likely(outNpkt != NULL)) {
     struct rte_mbuf* outPkt = Packet_ToMbuf(outNpkt);
+    PrefixStat_Data(&fwd->prefixStat, &Packet_GetDataHdr(csEntry->data)->name,
+                    Packet_ToMbuf(csEntry->data)->pkt_len);
     outPkt->port = RTE_MBUF_PORT_INVALID;
     Mbuf_SetTimestamp(outPkt, ctx->rxTime);
     LpL3* lpl3 = Packet_GetLpL3Hdr(outNpkt);
@@ -115,6 +117,7 @@ FwFwd_InterestHitCsDisk(FwFwd* fwd, FwFwdCtx* ctx, CsEntry* csEntry) {
 
   N_LOGD("^ cs-entry-disk=%p disk-slot=%" PRIu64 " helper=disk data-npkt=%p", csEntry,
          csEntry->diskSlot, dataBuf);
+  Mbuf_SetEnqueueTimestamp(ctx->pkt, rte_get_tsc_cycles()); // for hrlog
   DiskStore_GetData(fwd->cs->diskStore, csEntry->diskSlot, ctx->npkt, dataBuf,
                     &csEntry->diskStored);
   NULLize(ctx->npkt);
@@ -127,12 +130,18 @@ FwFwd_RxInterest(FwFwd* fwd, FwFwdCtx* ctx) {
 
   N_LOGD("RxInterest interest-from=%" PRI_FaceID " npkt=%p dn-token=%s", ctx->rxFace, ctx->npkt,
          LpPitToken_ToString(&ctx->rxToken));
+  if (unlikely(interest->diskSlot != 0)) { // returned from disk helper
+    Hrlog_PostIf(HRLOG_DK, rte_get_tsc_cycles() - Mbuf_GetEnqueueTimestamp(ctx->pkt));
+  }
 
   if (unlikely(fwd->cryptoHelper == NULL && interest->name.hasDigestComp)) {
     N_LOGD("^ drop=no-crypto-helper");
     FwFwdCtx_FreePkt(ctx);
     return;
   }
+  if (likely(interest->diskSlot == 0)) { // Interest returned from disk helper was counted already
+    PrefixStat_Interest(&fwd->prefixStat, &interest->name);
+  }
 
   // query FIB, reply Nack if no FIB match
   rcu_read_lock();
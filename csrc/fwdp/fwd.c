@@ -30,6 +30,10 @@ FwFwd_RxBurst(FwFwd* fwd, PktType pktType, PktQueue* q, RxFunc process) {
     Packet_GetLpL3Hdr(Packet_FromMbuf(pkts[0]))->congMark = 1;
   }
 
+  bool hrlFq = Hrlog_IsEnabled(HRLOG_FQ);
+  HrlogEntry hrl[MaxBurstSize];
+  uint16_t nHrls = 0;
+
   for (uint32_t i = 0; i < pop.count; ++i) {
     FwFwdCtx ctx = {
       .fwd = fwd,
@@ -42,13 +46,43 @@ FwFwd_RxBurst(FwFwd* fwd, PktType pktType, PktQueue* q, RxFunc process) {
 
     TscDuration timeSinceRx = now - ctx.rxTime;
     RunningStat_Push(&fwd->latencyStat, timeSinceRx);
+    if (unlikely(hrlFq)) {
+      hrl[nHrls++] = HrlogEntry_New(HRLOG_FQ, timeSinceRx);
+    }
 
     process(fwd, &ctx);
   }
 
+  Hrlog_Post(hrl, nHrls);
+
   return pop.count;
 }
 
+void
+FwFwd_Reconfig(FwFwd* fwd) {
+  FwFwdReconfig* rc = &fwd->reconfig;
+  N_LOGI("Reconfig fwd-id=%" PRIu8, fwd->id);
+
+  fwd->suppressCfg = rc->suppressCfg;
+
+  PktQueue* queues[PktMax] = {
+    [PktInterest] = &fwd->queueI,
+    [PktData] = &fwd->queueD,
+    [PktNack] = &fwd->queueN,
+  };
+  for (PktType t = PktInterest; t < PktMax; ++t) {
+    PktQueue* q = queues[t];
+    const FwFwdQueueParams* qp = &rc->queue[t];
+    q->target = qp->target;
+    q->interval = qp->interval;
+    q->dequeueBurstSize = qp->dequeueBurstSize;
+  }
+
+  Cs_SetCapacity(fwd->cs, rc->csCapMemory, rc->csCapIndirect);
+
+  atomic_store_explicit(&rc->pending, false, memory_order_release);
+}
+
 int
 FwFwd_Run(FwFwd* fwd) {
   rcu_register_thread();
@@ -61,6 +95,9 @@ FwFwd_Run(FwFwd* fwd) {
   uint32_t nProcessed = 0;
   while (ThreadCtrl_Continue(fwd->ctrl, nProcessed)) {
     rcu_quiescent_state();
+    if (unlikely(FwFwd_IsReconfigPending(fwd))) {
+      FwFwd_Reconfig(fwd);
+    }
     Pit_TriggerTimers(fwd->pit);
 
     nProcessed += FwFwd_RxBurst(fwd, PktInterest, &fwd->queueI, FwFwd_RxInterest);
@@ -7,14 +7,37 @@
 #include "../dpdk/thread.h"
 #include "../fib/fib.h"
 #include "../fib/nexthop-filter.h"
+#include "../hrlog/entry.h"
 #include "../iface/face.h"
 #include "../iface/pktqueue.h"
 #include "../pcct/cs.h"
 #include "../pcct/pit.h"
 #include "../strategyapi/api.h"
+#include "prefix-stat.h"
 
 typedef struct FwFwdCtx FwFwdCtx;
 
+/** @brief Input queue parameters that can be changed at runtime. */
+typedef struct FwFwdQueueParams {
+  TscDuration target;        ///< delay target or CoDel target
+  TscDuration interval;      ///< CoDel interval
+  uint32_t dequeueBurstSize; ///< maximum dequeue burst size
+} FwFwdQueueParams;
+
+/**
+ * @brief Runtime reconfiguration request of a forwarding thread.
+ *
+ * The control thread fills all fields and then sets @c pending ; the forwarding thread applies
+ * the request at a quiescent point and then clears @c pending .
+ */
+typedef struct FwFwdReconfig {
+  PitSuppressConfig suppressCfg;
+  FwFwdQueueParams queue[PktMax]; ///< indexed by PktType
+  uint32_t csCapMemory;
+  uint32_t csCapIndirect;
+  atomic_bool pending;
+} FwFwdReconfig;
+
 /** @brief Forwarding thread. */
 typedef struct FwFwd {
   SgGlobal sgGlobal;
@@ -44,11 +67,35 @@ typedef struct FwFwd {
 
   /** @brief Statistics of latency from packet arrival to start processing. */
   RunningStat latencyStat;
+
+  FwFwdReconfig reconfig; ///< pending runtime reconfiguration
+
+  PrefixStat prefixStat; ///< per-prefix traffic accounting
 } FwFwd;
 
 __attribute__((nonnull)) int
 FwFwd_Run(FwFwd* fwd);
 
+/**
+ * @brief Apply runtime reconfiguration request.
+ * @pre Either this is invoked in the forwarding thread at a quiescent point, or the forwarding
+ *      thread is stopped.
+ */
+__attribute__((nonnull)) void
+FwFwd_Reconfig(FwFwd* fwd);
+
+/** @brief Request the forwarding thread to apply @c fwd->reconfig at next quiescent point. */
+__attribute__((nonnull)) static inline void
+FwFwd_RequestReconfig(FwFwd* fwd) {
+  atomic_store_explicit(&fwd->reconfig.pending, true, memory_order_release);
+}
+
+/** @brief Determine whether a reconfiguration request is still pending. */
+__attribute__((nonnull)) static inline bool
+FwFwd_IsReconfigPending(FwFwd* fwd) {
+  return atomic_load_explicit(&fwd->reconfig.pending, memory_order_acquire);
+}
+
 __attribute__((nonnull)) void
 FwFwd_RxInterest(FwFwd* fwd, FwFwdCtx* ctx);
 
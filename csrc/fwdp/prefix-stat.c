@@ -0,0 +1,120 @@
+#include "prefix-stat.h"
+
+void
+PrefixStat_Clear(PrefixStat* ps) {
+  for (uint32_t i = 0; i < ps->capacity; ++i) {
+    ps->entries[i] = (PrefixStatEntry){.nameL = UINT16_MAX, .heapPos = i};
+    ps->heap[i] = i;
+  }
+  for (uint32_t slot = 0; slot <= ps->tableMask; ++slot) {
+    ps->table[slot] = 0;
+  }
+}
+
+__attribute__((nonnull)) static inline PrefixStatEntry*
+PrefixStat_Find(PrefixStat* ps, uint64_t hash, LName prefix) {
+  for (uint32_t slot = hash & ps->tableMask; ps->table[slot] != 0;
+       slot = (slot + 1) & ps->tableMask) {
+    PrefixStatEntry* entry = &ps->entries[ps->table[slot] - 1];
+    if (entry->hash == hash && entry->nameL == prefix.length &&
+        memcmp(entry->nameV, prefix.value, prefix.length) == 0) {
+      return entry;
+    }
+  }
+  return NULL;
+}
+
+__attribute__((nonnull)) static inline void
+PrefixStat_TableInsert(PrefixStat* ps, uint32_t index) {
+  uint32_t slot = ps->entries[index].hash & ps->tableMask;
+  while (ps->table[slot] != 0) {
+    slot = (slot + 1) & ps->tableMask;
+  }
+  ps->table[slot] = index + 1;
+}
+
+__attribute__((nonnull)) static inline void
+PrefixStat_TableErase(PrefixStat* ps, uint32_t index) {
+  uint32_t mask = ps->tableMask;
+  uint32_t hole = ps->entries[index].hash & mask;
+  while (ps->table[hole] != index + 1) {
+    hole = (hole + 1) & mask;
+  }
+
+  // backward shift deletion, so that lookups need no tombstones
+  for (uint32_t next = (hole + 1) & mask; ps->table[next] != 0; next = (next + 1) & mask) {
+    uint32_t home = ps->entries[ps->table[next] - 1].hash & mask;
+    if (((next - home) & mask) >= ((next - hole) & mask)) {
+      ps->table[hole] = ps->table[next];
+      hole = next;
+    }
+  }
+  ps->table[hole] = 0;
+}
+
+/** @brief Restore heap order after the weight of entry at @p pos has increased. */
+__attribute__((nonnull)) static inline void
+PrefixStat_SiftDown(PrefixStat* ps, uint32_t pos) {
+  uint32_t index = ps->heap[pos];
+  uint64_t weight = ps->entries[index].weight;
+  while (true) {
+    uint32_t child = 2 * pos + 1;
+    if (child >= ps->capacity) {
+      break;
+    }
+    if (child + 1 < ps->capacity &&
+        ps->entries[ps->heap[child + 1]].weight < ps->entries[ps->heap[child]].weight) {
+      ++child;
+    }
+    if (ps->entries[ps->heap[child]].weight >= weight) {
+      break;
+    }
+    ps->heap[pos] = ps->heap[child];
+    ps->entries[ps->heap[pos]].heapPos = pos;
+    pos = child;
+  }
+  ps->heap[pos] = index;
+  ps->entries[index].heapPos = pos;
+}
+
+void
+PrefixStat_Add_(PrefixStat* ps, const PName* name, uint32_t nInterests, uint32_t dataBytes) {
+  uint16_t depth = RTE_MIN(ps->depth, name->nComps);
+  LName prefix = PName_GetPrefix(name, depth);
+  if (unlikely(prefix.length > PrefixStatMaxNameLength)) {
+    return;
+  }
+  uint64_t hash = PName_ComputePrefixHash(name, depth);
+
+  PrefixStatEntry* found = PrefixStat_Find(ps, hash, prefix);
+  if (unlikely(found == NULL)) {
+    // replace the entry with minimum weight
+    uint32_t victim = ps->heap[0];
+    found = &ps->entries[victim];
+    if (found->nameL != UINT16_MAX) {
+      PrefixStat_TableErase(ps, victim);
+    }
+
+    ++found->version;
+    rte_smp_wmb();
+    found->hash = hash;
+    found->nameL = prefix.length;
+    rte_memcpy(found->nameV, prefix.value, prefix.length);
+    found->error = found->weight;
+    found->nInterests = 0;
+    found->nData = 0;
+    found->dataBytes = 0;
+    rte_smp_wmb();
+    ++found->version;
+
+    PrefixStat_TableInsert(ps, victim);
+  }
+
+  ++found->weight;
+  PrefixStat_SiftDown(ps, found->heapPos);
+  found->nInterests += nInterests;
+  if (dataBytes > 0) {
+    ++found->nData;
+    found->dataBytes += dataBytes;
+  }
+}
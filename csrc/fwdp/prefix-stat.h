@@ -0,0 +1,87 @@
+#ifndef NDNDPDK_FWDP_PREFIX_STAT_H
+#define NDNDPDK_FWDP_PREFIX_STAT_H
+
+/** @file */
+
+#include "../ndni/name.h"
+
+enum {
+  /** @brief Maximum TLV-LENGTH of a name prefix tracked by PrefixStat. */
+  PrefixStatMaxNameLength = 128,
+};
+
+/** @brief Per-prefix traffic counters in PrefixStat. */
+typedef struct PrefixStatEntry {
+  uint32_t version; ///< incremented before and after changing the key; odd while changing
+  uint16_t nameL;   ///< prefix TLV-LENGTH; UINT16_MAX indicates unused entry
+  uint32_t heapPos; ///< position in @c PrefixStat.heap
+  uint64_t hash;    ///< prefix hash, key of @c PrefixStat.table
+  uint64_t weight;  ///< Space-Saving weight, number of sampled packets plus error
+  uint64_t error;   ///< weight inherited from evicted entry
+  uint64_t nInterests;
+  uint64_t nData;
+  uint64_t dataBytes;
+  uint8_t nameV[PrefixStatMaxNameLength];
+} PrefixStatEntry;
+
+/** @brief Packet sampler in PrefixStat. */
+typedef struct PrefixStatSampler {
+  uint32_t sampleMask; ///< take sample only if (counter & sampleMask) == 0
+  uint32_t counter;
+} PrefixStatSampler;
+
+/** @brief Determine whether to take a sample. */
+__attribute__((nonnull)) static __rte_always_inline bool
+PrefixStatSampler_Take(PrefixStatSampler* s) {
+  return (++s->counter & s->sampleMask) == 0;
+}
+
+/**
+ * @brief Sampled per-prefix traffic accounting.
+ *
+ * This uses the Space-Saving heavy hitters algorithm over name prefixes of @c depth components.
+ * Entries are indexed by prefix hash in an open addressing hash table, and ordered by weight in a
+ * binary min-heap, so that both lookup and eviction are sublinear.
+ * It is owned by a forwarding thread; the control thread may read entries concurrently, and
+ * should use @c PrefixStatEntry.version to detect changing keys.
+ */
+typedef struct PrefixStat {
+  PrefixStatEntry* entries;
+  uint32_t* heap;  ///< entry indices, min-heap by weight
+  uint32_t* table; ///< hash table of (entry index + 1), zero indicates empty slot
+  uint32_t tableMask;
+  uint32_t capacity;
+  PrefixStatSampler sampleI; ///< Interest sampler
+  PrefixStatSampler sampleD; ///< Data sampler
+  uint16_t depth;            ///< prefix length in components; 0 disables accounting
+} PrefixStat;
+
+/** @brief Initialize entries as unused. */
+__attribute__((nonnull)) void
+PrefixStat_Clear(PrefixStat* ps);
+
+__attribute__((nonnull)) void
+PrefixStat_Add_(PrefixStat* ps, const PName* name, uint32_t nInterests, uint32_t dataBytes);
+
+/** @brief Account an incoming Interest. */
+__attribute__((nonnull)) static __rte_always_inline void
+PrefixStat_Interest(PrefixStat* ps, const PName* name) {
+  if (likely(ps->depth == 0) || likely(!PrefixStatSampler_Take(&ps->sampleI))) {
+    return;
+  }
+  PrefixStat_Add_(ps, name, 1, 0);
+}
+
+/**
+ * @brief Account a Data that satisfies Interests.
+ * @param dataBytes Data packet length.
+ */
+__attribute__((nonnull)) static __rte_always_inline void
+PrefixStat_Data(PrefixStat* ps, const PName* name, uint32_t dataBytes) {
+  if (likely(ps->depth == 0) || likely(!PrefixStatSampler_Take(&ps->sampleD))) {
+    return;
+  }
+  PrefixStat_Add_(ps, name, 0, dataBytes);
+}
+
+#endif // NDNDPDK_FWDP_PREFIX_STAT_H
@@ -13,10 +13,20 @@ SgGetXsyms(uint32_t* nXsyms);
 __attribute__((nonnull)) void
 SgTriggerTimer(Pit* pit, PitEntry* pitEntry, uintptr_t fwd0);
 
-/** @brief Invoke the strategy. */
+/**
+ * @brief Invoke the strategy.
+ * @pre Calling thread holds rcu_read_lock.
+ */
 __attribute__((nonnull)) static inline uint64_t
 SgInvoke(StrategyCode* strategy, FwFwdCtx* ctx) {
-  return StrategyCodeProg_Run(strategy->main, ctx, sizeof(SgCtx));
+  if (likely(!Hrlog_IsEnabled(HRLOG_SG))) {
+    return StrategyCodeProg_Run(strategy->main, ctx, sizeof(SgCtx));
+  }
+
+  TscTime t0 = rte_get_tsc_cycles();
+  uint64_t res = StrategyCodeProg_Run(strategy->main, ctx, sizeof(SgCtx));
+  Hrlog_PostIf(HRLOG_SG, rte_get_tsc_cycles() - t0);
+  return res;
 }
 
 #endif // NDNDPDK_FWDP_STRATEGY_H
@@ -8,11 +8,22 @@
 #include <rte_ring.h>
 #include <urcu-pointer.h>
 
-/** @brief Action identifier in high resolution log. */
+/**
+ * @brief Action identifier in high resolution log.
+ *
+ * Except @c HRLOG_TS , each action is a distinct bit, so that a set of actions can be represented
+ * as a bitmask.
+ */
 typedef enum HrlogAction {
-  HRLOG_OI = 1, // Interest TX since RX
-  HRLOG_OD = 2, // retrieved Data TX since RX
-  HRLOG_OC = 4, // cached Data TX since Interest RX
+  HRLOG_TS = 0,   // time marker, value is TSC time
+  HRLOG_OI = 1,   // Interest TX since RX
+  HRLOG_OD = 2,   // retrieved Data TX since RX
+  HRLOG_OC = 4,   // cached Data TX since Interest RX
+  HRLOG_FQ = 8,   // forwarding thread dequeue since RX
+  HRLOG_SG = 16,  // strategy invocation duration
+  HRLOG_CR = 32,  // Data returned from crypto helper since enqueue
+  HRLOG_DK = 64,  // Interest returned from disk helper since request
+  HRLOG_TQ = 128, // face output queue sojourn
 } __rte_packed HrlogAction;
 
 /** @brief A high resolution log entry. */
@@ -34,11 +45,12 @@ typedef struct HrlogHeader {
 static_assert(sizeof(HrlogHeader) == 16, "");
 
 #define HRLOG_HEADER_MAGIC 0x35f0498a
-#define HRLOG_HEADER_VERSION 2
+#define HRLOG_HEADER_VERSION 3
 
 /** @brief RCU-protected pointer to hrlog collector queue. */
 typedef struct HrlogRingRef {
   struct rte_ring* r;
+  uint8_t actions; ///< bitmask of enabled HrlogAction
 } HrlogRingRef;
 
 extern HrlogRingRef theHrlogRing;
@@ -53,6 +65,21 @@ HrlogRing_Get() {
   return rcu_dereference(theHrlogRing.r);
 }
 
+/**
+ * @brief Return bitmask of enabled actions.
+ * @retval 0 hrlog collection is disabled.
+ */
+static __rte_always_inline uint8_t
+Hrlog_Actions() {
+  return CMM_LOAD_SHARED(theHrlogRing.actions);
+}
+
+/** @brief Determine whether an action is enabled. */
+static __rte_always_inline bool
+Hrlog_IsEnabled(HrlogAction act) {
+  return (Hrlog_Actions() & act) != 0;
+}
+
 /** @brief Post entries to hrlog collector queue. */
 __attribute__((nonnull)) static __rte_always_inline void
 HrlogRing_Post(struct rte_ring* r, HrlogEntry* entries, uint16_t count) {
@@ -77,4 +104,17 @@ Hrlog_Post(HrlogEntry* entries, uint16_t count) {
   }
 }
 
+/**
+ * @brief Post an entry to hrlog collector queue if the action is enabled.
+ * @pre Calling thread holds rcu_read_lock.
+ */
+static __rte_always_inline void
+Hrlog_PostIf(HrlogAction act, uint64_t value) {
+  if (likely(!Hrlog_IsEnabled(act))) {
+    return;
+  }
+  HrlogEntry entry = HrlogEntry_New(act, value);
+  Hrlog_Post(&entry, 1);
+}
+
 #endif // NDNDPDK_HRLOG_ENTRY_H
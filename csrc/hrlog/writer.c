@@ -9,25 +9,36 @@ HrlogWriter_Run(HrlogWriter* w) {
   HrlogHeader hdr = {.magic = HRLOG_HEADER_MAGIC, .version = HRLOG_HEADER_VERSION, .tschz = TscHz};
   void* buf[64];
 
+  // leave room for one time marker and one burst past w->count
   MmapFd m;
-  if (!MmapFd_Open(&m, w->filename, sizeof(hdr) + w->count * sizeof(buf[0]) + sizeof(buf))) {
+  if (!MmapFd_Open(&m, w->filename,
+                   sizeof(hdr) + (w->count + 1 + RTE_DIM(buf)) * sizeof(buf[0]))) {
     return 1;
   }
 
   rte_memcpy(MmapFd_At(&m, 0), &hdr, sizeof(hdr));
   HrlogEntry* output = MmapFd_At(&m, sizeof(hdr));
 
+  CMM_STORE_SHARED(theHrlogRing.actions, w->actions);
   struct rte_ring* oldRing = rcu_xchg_pointer(&theHrlogRing.r, w->queue);
   NDNDPDK_ASSERT(oldRing == NULL);
 
   int64_t nCollected = 0;
   int64_t count = 0;
+  TscTime nextMark = 0;
   while (ThreadCtrl_Continue(w->ctrl, count) && nCollected < w->count) {
+    TscTime now = rte_get_tsc_cycles();
+    if (now >= nextMark) {
+      output[nCollected++] = HrlogEntry_New(HRLOG_TS, now);
+      nextMark = now + w->markInterval;
+    }
+
     count = (int64_t)rte_ring_dequeue_burst(w->queue, buf, RTE_DIM(buf), NULL);
     rte_memcpy(&output[nCollected], buf, count * sizeof(buf[0]));
     nCollected += count;
   }
 
+  CMM_STORE_SHARED(theHrlogRing.actions, 0);
   oldRing = rcu_xchg_pointer(&theHrlogRing.r, NULL);
   NDNDPDK_ASSERT(oldRing == w->queue);
 
@@ -12,6 +12,8 @@ typedef struct HrlogWriter {
   struct rte_ring* queue;
   const char* filename;
   int64_t count;
+  TscDuration markInterval; ///< interval between HRLOG_TS time markers
+  uint8_t actions;          ///< bitmask of enabled HrlogAction
 } HrlogWriter;
 
 /** @brief Write high resolution logs to a file. */
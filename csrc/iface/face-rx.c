@@ -14,8 +14,12 @@ FaceRx_Input(Face* face, int rxThread, FaceRxInputCtx* ctx) {
 
     Packet* npkt = Packet_FromMbuf(pkt);
     if (unlikely(!Packet_Parse(npkt, face->impl->rxParseFor))) {
-      ++rxt->nDecodeErr;
-      N_LOGD("l2-decode-error face=%" PRI_FaceID " thread=%d", face->id, rxThread);
+      if (LpHeader_IsIdle(pkt)) {
+        ++rxt->nIdles;
+      } else {
+        ++rxt->nDecodeErr;
+        N_LOGD("l2-decode-error face=%" PRI_FaceID " thread=%d", face->id, rxThread);
+      }
       ctx->frees[ctx->nFree++] = pkt;
       continue;
     }
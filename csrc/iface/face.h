@@ -5,8 +5,10 @@
 
 #include "input-demux.h"
 #include "reassembler.h"
+#include "txshaper.h"
 
 #include "../core/urcu.h"
+#include "../hrlog/entry.h"
 #include "../pdump/source.h"
 #include <urcu/rcuhlist.h>
 
@@ -37,11 +39,13 @@ typedef uint16_t (*Face_TxLoopFunc)(Face* face, int txThread);
 
 /**
  * @brief Transmit a burst of L2 frames.
+ * @param txThread TX thread index, which may select a lower layer TX queue.
  * @param pkts L2 frames.
  * @return successfully queued frames.
  * @post FaceImpl owns queued frames, but does not own remaining frames.
  */
-typedef uint16_t (*Face_TxBurstFunc)(Face* face, struct rte_mbuf** pkts, uint16_t nPkts);
+typedef uint16_t (*Face_TxBurstFunc)(Face* face, int txThread, struct rte_mbuf** pkts,
+                                     uint16_t nPkts);
 
 enum {
   /// FaceRxThread.nFrames[cntNOctets] is nOctets counter
@@ -52,11 +56,16 @@ enum {
 typedef struct FaceRxThread {
   uint64_t nFrames[PktMax]; ///< nOctets or accepted L3 packets
   uint64_t nDecodeErr;      ///< decode errors
+  uint64_t nIdles;          ///< IDLE packets
   Reassembler reass;
 } __rte_cache_aligned FaceRxThread;
 
 /** @brief Face TX per-thread information. */
 typedef struct FaceTxThread {
+  struct cds_hlist_node txlNode; ///< TxLoop list node
+  FaceID faceID;                 ///< face ID
+  uint8_t index;                 ///< TX thread index within the face
+
   uint64_t nextSeqNum; ///< next fragmentation sequence number
 
   uint64_t nL3Fragmented; ///< L3 packets that required fragmentation
@@ -67,7 +76,12 @@ typedef struct FaceTxThread {
   uint64_t nOctets;         ///< sent+dropped L2 octets (including LpHeader)
   uint64_t nDroppedFrames;  ///< dropped L2 frames
   uint64_t nDroppedOctets;  ///< dropped L2 octets
+
+  uint64_t nIdles;  ///< sent IDLE packets
+  TscTime nextIdle; ///< when to send next IDLE packet if there's no other traffic
 } __rte_cache_aligned FaceTxThread;
+// FaceTxThread.index is uint8_t, and each TX thread owns 1/256 of NDNLPv2 sequence number space
+static_assert(MaxFaceTxThreads <= UINT8_MAX, "");
 
 /**
  * @brief Face details.
@@ -90,6 +104,12 @@ typedef struct FaceImpl {
   Face_TxLoopFunc txLoop;
   Face_TxBurstFunc txBurst;
   PdumpSourceRef txPdump;
+  TxShaper* txShaper;         ///< traffic shaper and scheduler, NULL if disabled
+  TscDuration txIdleInterval; ///< IDLE packet interval, zero if disabled
+  bool txFlowHash;            ///< whether to set L3 packet flow hash in @c mbuf->hash.usr
+
+  RTE_MARKER rejectMarker __rte_cache_aligned;
+  uint64_t nTxRejects; ///< L3 packets rejected by @c Face_TxBurst due to full output queue
 
   uint8_t priv[] __rte_cache_aligned;
 } FaceImpl;
@@ -98,10 +118,10 @@ typedef struct FaceImpl {
 struct Face {
   FaceImpl* impl;
   struct rte_ring* outputQueue;
-  struct cds_hlist_node txlNode;
   PacketTxAlign txAlign;
   FaceID id;
   FaceState state;
+  bool txTimestamp; ///< whether @c Face_TxBurst sets enqueue timestamp for TxShaper CoDel
 };
 static_assert(sizeof(Face) <= RTE_CACHE_LINE_SIZE, "");
 
@@ -143,13 +163,22 @@ Face_PacketTxAlign(FaceID faceID) {
  * @param count size of @p npkts array.
  *
  * This function is thread-safe.
+ * Enqueue timestamp is assigned only if it is needed by hrlog or TxShaper CoDel.
  */
 __attribute__((nonnull)) static inline void
 Face_TxBurst(FaceID faceID, Packet** npkts, uint16_t count) {
   Face* face = Face_Get(faceID);
   if (likely(face->state == FaceStateUp)) {
-    Mbuf_EnqueueVector((struct rte_mbuf**)npkts, count, face->outputQueue, true);
-    // TODO count rejects
+    if (unlikely(face->txTimestamp || Hrlog_IsEnabled(HRLOG_TQ))) {
+      TscTime now = rte_get_tsc_cycles();
+      for (uint16_t i = 0; i < count; ++i) {
+        Mbuf_SetEnqueueTimestamp(Packet_ToMbuf(npkts[i]), now);
+      }
+    }
+    uint32_t nRej = Mbuf_EnqueueVector((struct rte_mbuf**)npkts, count, face->outputQueue, true);
+    if (unlikely(nRej > 0)) {
+      __atomic_fetch_add(&face->impl->nTxRejects, nRej, __ATOMIC_RELAXED);
+    }
   } else {
     rte_pktmbuf_free_bulk((struct rte_mbuf**)npkts, count);
   }
@@ -69,25 +69,20 @@ CoDel_ShouldDrop(PktQueue* q, TscTime timestamp, TscTime now) {
   return drop;
 }
 
-__attribute__((nonnull)) static PktQueuePopResult
-PktQueue_PopCoDel(PktQueue* q, struct rte_mbuf* pkts[], uint32_t count, TscTime now) {
-  PktQueuePopResult res = PktQueue_PopFromRing(q, pkts, count);
-  if (unlikely(res.count == 0)) {
-    q->firstAboveTime = 0;
-    return res;
-  }
-  res.drop = CoDel_ShouldDrop(q, Mbuf_GetTimestamp(pkts[0]), now);
-  q->nDrops += (int)res.drop;
+bool
+PktQueue_CoDel(PktQueue* q, TscTime timestamp, TscTime now) {
+  bool drop = CoDel_ShouldDrop(q, timestamp, now);
+  q->nDrops += (int)drop;
 
   if (q->dropping) {
-    if (!res.drop) {
+    if (!drop) {
       q->dropping = false;
     } else if (now >= q->dropNext) {
       ++q->count;
       CoDel_NewtonStep(q);
       q->dropNext = CoDel_ControlLaw(q->dropNext, q->interval, q->recInvSqrt);
     }
-  } else if (res.drop) {
+  } else if (drop) {
     q->dropping = true;
     uint32_t delta = q->count - q->lastCount;
     if (delta > 1 && (TscDuration)(now - q->dropNext) < 16 * q->interval) {
@@ -100,6 +95,17 @@ PktQueue_PopCoDel(PktQueue* q, struct rte_mbuf* pkts[], uint32_t count, TscTime
     q->lastCount = q->count;
     q->dropNext = CoDel_ControlLaw(now, q->interval, q->recInvSqrt);
   }
+  return drop;
+}
+
+__attribute__((nonnull)) static PktQueuePopResult
+PktQueue_PopCoDel(PktQueue* q, struct rte_mbuf* pkts[], uint32_t count, TscTime now) {
+  PktQueuePopResult res = PktQueue_PopFromRing(q, pkts, count);
+  if (unlikely(res.count == 0)) {
+    q->firstAboveTime = 0;
+    return res;
+  }
+  res.drop = PktQueue_CoDel(q, Mbuf_GetTimestamp(pkts[0]), now);
   return res;
 }
 
@@ -56,6 +56,17 @@ typedef PktQueuePopResult (*PktQueue_PopFunc)(PktQueue* q, struct rte_mbuf* pkts
                                               TscTime now);
 extern const PktQueue_PopFunc PktQueue_PopJmp[];
 
+/**
+ * @brief Run CoDel algorithm on the head packet, without dequeuing from @c q->ring .
+ * @param timestamp when the head packet was enqueued.
+ * @return whether the head packet should be dropped/ECN-marked.
+ *
+ * This allows a queue other than @c q->ring to use CoDel state stored in @p q .
+ * The caller should set @c q->firstAboveTime to zero when its queue becomes empty.
+ */
+__attribute__((nonnull)) bool
+PktQueue_CoDel(PktQueue* q, TscTime timestamp, TscTime now);
+
 /** @brief Dequeue a burst of packets. */
 __attribute__((nonnull)) static inline PktQueuePopResult
 PktQueue_Pop(PktQueue* q, struct rte_mbuf* pkts[], uint32_t count, TscTime now) {
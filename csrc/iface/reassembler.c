@@ -61,7 +61,8 @@ Reassembler_Insert_(Reassembler* reass, Packet* fragment, LpL2* pm, hash_sig_t h
   rte_bit_clear(&pm->reassBitmap, pm->fragIndex);
   pm->reassFrags[pm->fragIndex] = fragment;
 
-  if (unlikely(reass->count >= reass->capacity)) {
+  uint32_t capacity = Reassembler_GetCapacity(reass);
+  while (unlikely(reass->count >= capacity)) { // capacity may have been lowered at runtime
     LpL2* evict = cds_list_first_entry(&reass->list, LpL2, reassNode);
     Reassembler_Drop_(reass, evict, rte_hash_hash(reass->table, &evict->seqNumBase));
   }
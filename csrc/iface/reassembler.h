@@ -14,7 +14,7 @@ typedef struct Reassembler {
   struct rte_hash* table;
   struct cds_list_head list;
   uint32_t count;
-  uint32_t capacity;
+  uint32_t capacity; ///< access via Reassembler_GetCapacity and Reassembler_SetCapacity
 } Reassembler;
 
 /**
@@ -29,6 +29,24 @@ typedef struct Reassembler {
 __attribute__((nonnull)) bool
 Reassembler_Init(Reassembler* reass, const char* id, uint32_t capacity, int numaSocket);
 
+/** @brief Read partial message store capacity. */
+__attribute__((nonnull)) static __rte_always_inline uint32_t
+Reassembler_GetCapacity(const Reassembler* reass) {
+  return __atomic_load_n(&reass->capacity, __ATOMIC_RELAXED);
+}
+
+/**
+ * @brief Change partial message store capacity.
+ * @param capacity new capacity, must not exceed the capacity passed to Reassembler_Init.
+ *
+ * This may be invoked from a thread other than the RX thread.
+ * The RX thread evicts excess partial messages upon accepting its next fragment.
+ */
+__attribute__((nonnull)) static inline void
+Reassembler_SetCapacity(Reassembler* reass, uint32_t capacity) {
+  __atomic_store_n(&reass->capacity, capacity, __ATOMIC_RELAXED);
+}
+
 /** @brief Release all memory except @p reass struct. */
 __attribute__((nonnull)) void
 Reassembler_Close(Reassembler* reass);
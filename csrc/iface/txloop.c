@@ -1,6 +1,7 @@
 #include "txloop.h"
 #include "../core/logger.h"
 #include "../hrlog/entry.h"
+#include "../ndni/tlv-encoder.h"
 #include "face-impl.h"
 
 N_LOG_INIT(TxLoop);
@@ -16,7 +17,7 @@ TxLoop_TxFrames(Face* face, int txThread, struct rte_mbuf** frames, uint16_t cou
     txt->nOctets += frames[i]->pkt_len;
   }
 
-  uint16_t nQueued = face->impl->txBurst(face, frames, count);
+  uint16_t nQueued = face->impl->txBurst(face, txThread, frames, count);
   uint16_t nRejects = count - nQueued;
   N_LOGV("TxFrames face=%" PRI_FaceID " txt=%d queued=%" PRIu16 " rejects=%" PRIu16, face->id,
          txThread, nQueued, nRejects);
@@ -31,19 +32,72 @@ TxLoop_TxFrames(Face* face, int txThread, struct rte_mbuf** frames, uint16_t cou
   }
 }
 
+/** @brief Send an IDLE packet if the face has been quiet for @c txIdleInterval . */
+__attribute__((nonnull)) static void
+TxLoop_Idle(Face* face, int txThread, bool hasTraffic, TscTime now) {
+  FaceTxThread* txt = &face->impl->tx[txThread];
+  if (hasTraffic) {
+    txt->nextIdle = now + face->impl->txIdleInterval;
+    return;
+  }
+  if (now < txt->nextIdle) {
+    return;
+  }
+  txt->nextIdle = now + face->impl->txIdleInterval;
+
+  struct rte_mbuf* frame = rte_pktmbuf_alloc(face->impl->txMempools.header);
+  if (unlikely(frame == NULL)) {
+    return;
+  }
+  frame->data_off = RTE_PKTMBUF_HEADROOM + LpHeaderHeadroom;
+  frame->hash.usr = 0;
+  TlvEncoder_PrependTL(frame, TtLpPacket, 0);
+  Mbuf_SetTimestamp(frame, now);
+  Packet_SetType(Packet_FromMbuf(frame), PktFragment);
+
+  ++txt->nIdles;
+  N_LOGV("Idle face=%" PRI_FaceID " txt=%d", face->id, txThread);
+  TxLoop_TxFrames(face, txThread, &frame, 1);
+}
+
+/**
+ * @brief Compute flow hash of an L3 packet.
+ *
+ * If the packet is fully parsed, this is derived from the name hash.
+ * Otherwise, this is a random number.
+ */
+__attribute__((nonnull)) static inline uint32_t
+TxLoop_FlowHash(Packet* npkt) {
+  const PName* name = Packet_GetName(npkt);
+  if (unlikely(name == NULL)) {
+    return (uint32_t)rte_rand();
+  }
+  uint64_t hash = PName_ComputeHash(name);
+  return (uint32_t)(hash ^ (hash >> 32));
+}
+
 __attribute__((nonnull)) static __rte_always_inline uint16_t
 TxLoop_Transfer(Face* face, int txThread, FaceTx_OutputFunc txOne, FaceTx_OutputFunc txFrag) {
   FaceTxThread* txt = &face->impl->tx[txThread];
+  TxShaper* shaper = face->impl->txShaper;
+  TscTime now = rte_get_tsc_cycles();
   Packet* npkts[MaxBurstSize];
-  uint16_t count = rte_ring_dequeue_burst(face->outputQueue, (void**)npkts, MaxBurstSize, NULL);
+  uint16_t count = 0;
+  if (shaper == NULL) {
+    count = rte_ring_dequeue_burst(face->outputQueue, (void**)npkts, MaxBurstSize, NULL);
+  } else {
+    count = TxShaper_Dequeue(shaper, face->outputQueue, npkts, MaxBurstSize, now);
+  }
 
   struct rte_mbuf* frames[MaxBurstSize + LpMaxFragments];
   uint16_t nFrames = 0;
   struct rte_ring* hrlRing = HrlogRing_Get();
-  HrlogEntry hrl[MaxBurstSize];
+  uint8_t hrlActs = hrlRing == NULL ? 0 : Hrlog_Actions();
+  HrlogEntry hrl[2 * MaxBurstSize];
   uint16_t nHrls = 0;
+  uint64_t nFramesBefore = txt->nFrames[PktFragment];
+  uint64_t nOctetsBefore = txt->nOctets;
 
-  TscTime now = rte_get_tsc_cycles();
   for (uint16_t i = 0; i < count; ++i) {
     Packet* npkt = npkts[i];
     PktType framePktType = PktType_ToFull(Packet_GetType(npkt));
@@ -51,26 +105,41 @@ TxLoop_Transfer(Face* face, int txThread, FaceTx_OutputFunc txOne, FaceTx_Output
     ++txt->nFrames[framePktType];
 
     struct rte_mbuf* pkt = Packet_ToMbuf(npkt);
-    if (hrlRing != NULL) {
+    if (hrlActs != 0) {
+      if (hrlActs & HRLOG_TQ) {
+        hrl[nHrls++] = HrlogEntry_New(HRLOG_TQ, now - Mbuf_GetEnqueueTimestamp(pkt));
+      }
       TscDuration latency = now - Mbuf_GetTimestamp(pkt);
+      HrlogAction act = HRLOG_TS;
       switch (framePktType) {
         case PktInterest:
-          hrl[nHrls++] = HrlogEntry_New(HRLOG_OI, latency);
+          act = HRLOG_OI;
           break;
         case PktData:
-          hrl[nHrls++] =
-            HrlogEntry_New(pkt->port == RTE_MBUF_PORT_INVALID ? HRLOG_OC : HRLOG_OD, latency);
+          act = pkt->port == RTE_MBUF_PORT_INVALID ? HRLOG_OC : HRLOG_OD;
           break;
         case PktNack:
           break;
         default:
           NDNDPDK_ASSERT(false);
       }
+      if (hrlActs & act) {
+        hrl[nHrls++] = HrlogEntry_New(act, latency);
+      }
+    }
+
+    uint32_t flowHash = 0;
+    if (face->impl->txFlowHash) {
+      flowHash = TxLoop_FlowHash(npkt);
     }
 
     FaceTx_CheckDirectFragmentMbuf_(pkt);
     bool isOneFragment = pkt->pkt_len <= face->txAlign.fragmentPayloadSize;
-    nFrames += (isOneFragment ? txOne : txFrag)(face, txThread, npkt, &frames[nFrames]);
+    uint16_t nNew = (isOneFragment ? txOne : txFrag)(face, txThread, npkt, &frames[nFrames]);
+    for (uint16_t j = 0; j < nNew; ++j) {
+      frames[nFrames + j]->hash.usr = flowHash; // all fragments of an L3 packet share flow hash
+    }
+    nFrames += nNew;
     if (unlikely(nFrames >= MaxBurstSize)) {
       TxLoop_TxFrames(face, txThread, frames, nFrames);
       nFrames = 0;
@@ -80,9 +149,19 @@ TxLoop_Transfer(Face* face, int txThread, FaceTx_OutputFunc txOne, FaceTx_Output
   if (likely(nFrames > 0)) {
     TxLoop_TxFrames(face, txThread, frames, nFrames);
   }
-  if (hrlRing != NULL) {
+  if (hrlActs != 0) {
     HrlogRing_Post(hrlRing, hrl, nHrls);
   }
+  if (unlikely(face->impl->txIdleInterval != 0) && txThread == 0) {
+    // packets held back by TxShaper are not idleness, and IDLE packets should not bypass TxShaper
+    bool hasTraffic = txt->nFrames[PktFragment] != nFramesBefore ||
+                      (shaper != NULL && TxShaper_HasPending(shaper, face->outputQueue));
+    TxLoop_Idle(face, txThread, hasTraffic, now);
+  }
+  if (shaper != NULL) {
+    TxShaper_Charge(shaper, txt->nFrames[PktFragment] - nFramesBefore,
+                    txt->nOctets - nOctetsBefore);
+  }
 
   return count;
 }
@@ -107,11 +186,11 @@ TxLoop_Run(TxLoop* txl) {
   while (ThreadCtrl_Continue(txl->ctrl, nProcessed)) {
     rcu_quiescent_state();
     rcu_read_lock();
-    Face* face;
+    FaceTxThread* txt;
     struct cds_hlist_node* pos;
-    cds_hlist_for_each_entry_rcu (face, pos, &txl->head, txlNode) {
-      static_assert(MaxFaceTxThreads == 1, "");
-      nProcessed += face->impl->txLoop(face, 0);
+    cds_hlist_for_each_entry_rcu (txt, pos, &txl->head, txlNode) {
+      Face* face = Face_Get(txt->faceID);
+      nProcessed += face->impl->txLoop(face, txt->index);
     }
     rcu_read_unlock();
   }
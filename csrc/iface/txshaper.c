@@ -0,0 +1,120 @@
+#include "txshaper.h"
+
+void
+TokenBucket_Init(TokenBucket* tb, uint64_t rate, uint64_t burst, TscTime now) {
+  *tb = (TokenBucket){
+    .rate = rate,
+    .lastRefill = now,
+  };
+  if (rate == 0) {
+    return;
+  }
+
+  burst = RTE_MIN(RTE_MAX(burst, 1), (uint64_t)(INT64_MAX / 2) / TscHz);
+  tb->capacity = burst * TscHz;
+  tb->tokens = tb->capacity;
+  tb->fillTime = tb->capacity / rate + 1;
+}
+
+void
+TxShaper_Init(TxShaper* sh) {
+  static const PktType tieBreak[] = {PktNack, PktData, PktInterest};
+  static_assert(RTE_DIM(tieBreak) == RTE_DIM(sh->order), "");
+
+  for (unsigned i = 0; i < RTE_DIM(sh->order); ++i) {
+    PktType t = tieBreak[i];
+    unsigned j = i;
+    for (; j > 0 && sh->weights[sh->order[j - 1]] < sh->weights[t]; --j) {
+      sh->order[j] = sh->order[j - 1];
+    }
+    sh->order[j] = t;
+    sh->credits[t] = sh->weights[t];
+  }
+}
+
+__attribute__((nonnull)) static inline void
+TxShaper_Classify(TxShaper* sh, struct rte_ring* outputQueue) {
+  Packet* npkts[MaxBurstSize];
+  uint32_t count = rte_ring_dequeue_burst(outputQueue, (void**)npkts, MaxBurstSize, NULL);
+  for (uint32_t i = 0; i < count; ++i) {
+    PktType t = PktType_ToFull(Packet_GetType(npkts[i]));
+    NDNDPDK_ASSERT(t != PktFragment);
+    if (unlikely(rte_ring_sp_enqueue(sh->queues[t], npkts[i]) != 0)) {
+      ++sh->nQueueDrops[t];
+      rte_pktmbuf_free(Packet_ToMbuf(npkts[i]));
+    }
+  }
+}
+
+__attribute__((nonnull)) static inline uint16_t
+TxShaper_DequeuePriority(TxShaper* sh, Packet** npkts, uint16_t limit) {
+  uint16_t n = 0;
+  for (unsigned i = 0; i < RTE_DIM(sh->order) && n < limit; ++i) {
+    n += rte_ring_sc_dequeue_burst(sh->queues[sh->order[i]], (void**)&npkts[n], limit - n, NULL);
+  }
+  return n;
+}
+
+__attribute__((nonnull)) static inline uint16_t
+TxShaper_DequeueWrr(TxShaper* sh, Packet** npkts, uint16_t limit) {
+  uint16_t n = 0;
+  for (int round = 0; round < 2 && n < limit; ++round) {
+    for (unsigned i = 0; i < RTE_DIM(sh->order) && n < limit; ++i) {
+      PktType t = sh->order[i];
+      uint32_t want = RTE_MIN((uint32_t)(limit - n), sh->credits[t]);
+      if (want == 0) {
+        continue;
+      }
+      uint32_t got = rte_ring_sc_dequeue_burst(sh->queues[t], (void**)&npkts[n], want, NULL);
+      sh->credits[t] -= got;
+      n += got;
+    }
+
+    if (n < limit) {
+      // every queue is either empty or out of credits: start a new round
+      for (unsigned i = 0; i < RTE_DIM(sh->order); ++i) {
+        PktType t = sh->order[i];
+        sh->credits[t] = sh->weights[t];
+      }
+    }
+  }
+  return n;
+}
+
+uint16_t
+TxShaper_Dequeue(TxShaper* sh, struct rte_ring* outputQueue, Packet** npkts, uint16_t count,
+                 TscTime now) {
+  if (sh->sched != TxSchedulerFifo) {
+    TxShaper_Classify(sh, outputQueue);
+  }
+
+  TokenBucket_Refill(&sh->frames, now);
+  TokenBucket_Refill(&sh->octets, now);
+  uint16_t limit = RTE_MIN((uint32_t)count, TokenBucket_Available(&sh->frames));
+  if (unlikely(limit == 0 || !TokenBucket_IsReady(&sh->octets))) {
+    sh->nThrottles += (int)TxShaper_HasPending(sh, outputQueue);
+    return 0;
+  }
+
+  uint16_t n = 0;
+  switch (sh->sched) {
+    case TxSchedulerPriority:
+      n = TxShaper_DequeuePriority(sh, npkts, limit);
+      break;
+    case TxSchedulerWrr:
+      n = TxShaper_DequeueWrr(sh, npkts, limit);
+      break;
+    default:
+      n = rte_ring_dequeue_burst(outputQueue, (void**)npkts, limit, NULL);
+      break;
+  }
+
+  if (sh->codel.pop == PktQueuePopActCoDel) {
+    if (n == 0) {
+      sh->codel.firstAboveTime = 0;
+    } else if (PktQueue_CoDel(&sh->codel, Mbuf_GetEnqueueTimestamp(Packet_ToMbuf(npkts[0])), now)) {
+      Packet_GetLpL3Hdr(npkts[0])->congMark = 1;
+    }
+  }
+  return n;
+}
@@ -0,0 +1,143 @@
+#ifndef NDNDPDK_IFACE_TXSHAPER_H
+#define NDNDPDK_IFACE_TXSHAPER_H
+
+/** @file */
+
+#include "../dpdk/tsc.h"
+#include "pktqueue.h"
+
+/**
+ * @brief Token bucket.
+ *
+ * Tokens are stored in (token * TscHz) unit, so that refilling requires only integer arithmetics.
+ */
+typedef struct TokenBucket {
+  uint64_t rate;        ///< tokens per second, zero means unlimited
+  int64_t capacity;     ///< bucket depth, in (token * TscHz) unit
+  int64_t tokens;       ///< current tokens, in (token * TscHz) unit; may become negative
+  TscDuration fillTime; ///< time needed to refill an empty bucket
+  TscTime lastRefill;   ///< last refill time
+} TokenBucket;
+
+/**
+ * @brief Initialize token bucket.
+ * @param rate tokens per second; zero means unlimited.
+ * @param burst bucket depth in tokens.
+ */
+__attribute__((nonnull)) void
+TokenBucket_Init(TokenBucket* tb, uint64_t rate, uint64_t burst, TscTime now);
+
+/** @brief Add tokens accumulated since last refill. */
+__attribute__((nonnull)) static __rte_always_inline void
+TokenBucket_Refill(TokenBucket* tb, TscTime now) {
+  TscDuration elapsed = now - tb->lastRefill;
+  tb->lastRefill = now;
+  if (elapsed >= tb->fillTime) {
+    tb->tokens = tb->capacity;
+  } else {
+    tb->tokens = RTE_MIN(tb->tokens + elapsed * (int64_t)tb->rate, tb->capacity);
+  }
+}
+
+/** @brief Determine whether the bucket has non-negative tokens. */
+__attribute__((nonnull)) static __rte_always_inline bool
+TokenBucket_IsReady(const TokenBucket* tb) {
+  return tb->rate == 0 || tb->tokens >= 0;
+}
+
+/**
+ * @brief Determine how many whole tokens are available.
+ * @return available tokens, or @c UINT32_MAX if unlimited.
+ */
+__attribute__((nonnull)) static __rte_always_inline uint32_t
+TokenBucket_Available(const TokenBucket* tb) {
+  if (tb->rate == 0) {
+    return UINT32_MAX;
+  }
+  if (tb->tokens <= 0) {
+    return 0;
+  }
+  return RTE_MIN((uint64_t)tb->tokens / TscHz, (uint64_t)UINT32_MAX);
+}
+
+/**
+ * @brief Take tokens from the bucket.
+ *
+ * Tokens may become negative, in which case subsequent transmissions are deferred until the
+ * deficit has been repaid.
+ */
+__attribute__((nonnull)) static __rte_always_inline void
+TokenBucket_Take(TokenBucket* tb, uint64_t n) {
+  if (tb->rate != 0) {
+    tb->tokens -= (int64_t)(n * TscHz);
+  }
+}
+
+/** @brief TX scheduling algorithm among L3 packet types. */
+typedef enum TxSchedulerKind {
+  TxSchedulerFifo,     ///< transmit in arrival order
+  TxSchedulerPriority, ///< strict priority
+  TxSchedulerWrr,      ///< weighted round robin
+} __rte_packed TxSchedulerKind;
+
+/**
+ * @brief TX traffic shaper and L3 packet type scheduler.
+ *
+ * This is owned by the TX thread of a face.
+ * If scheduler is not FIFO, L3 packets are moved from @c Face.outputQueue into per-type queues,
+ * and then dequeued according to the scheduling algorithm.
+ * If CoDel is enabled, the sojourn time since @c Face_TxBurst is measured on the first dequeued
+ * L3 packet, which is congestion-marked when CoDel decides to drop it.
+ */
+typedef struct TxShaper {
+  TokenBucket octets;              ///< octet rate limit
+  TokenBucket frames;              ///< frame rate limit
+  struct rte_ring* queues[PktMax]; ///< per L3 type queues, indexed by PktType
+  uint32_t weights[PktMax];        ///< per L3 type weight or priority
+  uint32_t credits[PktMax];        ///< WRR remaining credits in current round
+  PktType order[PktMax - 1];       ///< L3 types in descending weight order
+  TxSchedulerKind sched;           ///< scheduling algorithm
+  PktQueue codel;                  ///< CoDel state, enabled if pop is PktQueuePopActCoDel
+  uint64_t nThrottles;             ///< TxLoop iterations deferred due to insufficient tokens
+  uint64_t nQueueDrops[PktMax];    ///< L3 packets dropped due to full per-type queue
+} TxShaper;
+
+/**
+ * @brief Initialize L3 type ordering according to weights.
+ * @pre @c sh->weights is assigned.
+ */
+__attribute__((nonnull)) void
+TxShaper_Init(TxShaper* sh);
+
+/** @brief Determine whether L3 packets are waiting in the output queue or per-type queues. */
+__attribute__((nonnull)) static inline bool
+TxShaper_HasPending(TxShaper* sh, struct rte_ring* outputQueue) {
+  if (sh->sched == TxSchedulerFifo) {
+    return !rte_ring_empty(outputQueue);
+  }
+  for (unsigned i = 0; i < RTE_DIM(sh->order); ++i) {
+    if (!rte_ring_empty(sh->queues[sh->order[i]])) {
+      return true;
+    }
+  }
+  return false;
+}
+
+/**
+ * @brief Dequeue a burst of L3 packets subject to shaping and scheduling.
+ * @param outputQueue face output queue.
+ * @param[out] npkts dequeued L3 packets.
+ * @return number of dequeued L3 packets.
+ */
+__attribute__((nonnull)) uint16_t
+TxShaper_Dequeue(TxShaper* sh, struct rte_ring* outputQueue, Packet** npkts, uint16_t count,
+                 TscTime now);
+
+/** @brief Charge transmitted L2 frames to the token buckets. */
+__attribute__((nonnull)) static __rte_always_inline void
+TxShaper_Charge(TxShaper* sh, uint64_t nFrames, uint64_t nOctets) {
+  TokenBucket_Take(&sh->frames, nFrames);
+  TokenBucket_Take(&sh->octets, nOctets);
+}
+
+#endif // NDNDPDK_IFACE_TXSHAPER_H
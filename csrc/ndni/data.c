@@ -340,22 +340,44 @@ DataEnc_SignChain(struct rte_mbuf* pkt, struct rte_mbuf* tail, PacketMempools* m
 
 __attribute__((nonnull)) static inline struct rte_mbuf*
 DataEnc_SignDirect(struct rte_mbuf* pkt, struct rte_mbuf* tail, PacketMempools* mp,
-                   uint16_t fragmentPayloadSize) {
-  if (unlikely(tail->data_len + DataEncNullSigLen > fragmentPayloadSize ||
-               rte_pktmbuf_tailroom(tail) < DataEncNullSigLen)) {
+                   uint16_t fragmentPayloadSize, uint16_t trailerL) {
+  if (unlikely(tail->data_len + trailerL > fragmentPayloadSize ||
+               rte_pktmbuf_tailroom(tail) < trailerL)) {
     return DataEnc_SignChain(pkt, tail, mp);
   }
   return tail;
 }
 
+__attribute__((nonnull)) static inline bool
+DataEnc_AppendSig(struct rte_mbuf* pkt, struct rte_mbuf* tail, DataSigner* signer) {
+  uint8_t* room = rte_pktmbuf_mtod_offset(tail, uint8_t*, tail->data_len);
+  rte_memcpy(room, signer->sigInfo, signer->sigInfoL);
+  tail->data_len += signer->sigInfoL;
+  pkt->pkt_len += signer->sigInfoL;
+
+  // signed portion is from Name to SignatureInfo, i.e. the whole packet at this point
+  uint8_t* sigValue = RTE_PTR_ADD(room, signer->sigInfoL);
+  size_t sigL = 0;
+  if (unlikely(!DataSigner_Compute(signer, pkt, &sigValue[2], &sigL))) {
+    return false;
+  }
+  static_assert(DataSignerMaxSigValueLen < 0xFD, "");
+  sigValue[0] = TtDSigValue;
+  sigValue[1] = sigL;
+  tail->data_len += 2 + sigL;
+  pkt->pkt_len += 2 + sigL;
+  return true;
+}
+
 Packet*
-DataEnc_Sign(struct rte_mbuf* pkt, PacketMempools* mp, PacketTxAlign align) {
+DataEnc_Sign(struct rte_mbuf* pkt, PacketMempools* mp, PacketTxAlign align, DataSigner* signer) {
+  uint16_t trailerL = DataSigner_TrailerLen(signer);
   struct rte_mbuf* tail = rte_pktmbuf_lastseg(pkt);
   if (align.linearize) {
     NDNDPDK_ASSERT(RTE_MBUF_DIRECT(tail) && rte_mbuf_refcnt_read(tail) == 1);
-    tail = DataEnc_SignDirect(pkt, tail, mp, align.fragmentPayloadSize);
+    tail = DataEnc_SignDirect(pkt, tail, mp, align.fragmentPayloadSize, trailerL);
   } else if (RTE_MBUF_DIRECT(tail) && rte_mbuf_refcnt_read(tail) == 1) {
-    tail = DataEnc_SignDirect(pkt, tail, mp, UINT16_MAX);
+    tail = DataEnc_SignDirect(pkt, tail, mp, UINT16_MAX, trailerL);
   } else {
     tail = DataEnc_SignChain(pkt, tail, mp);
   }
@@ -364,8 +386,13 @@ DataEnc_Sign(struct rte_mbuf* pkt, PacketMempools* mp, PacketTxAlign align) {
     return NULL;
   }
 
-  rte_memcpy(rte_pktmbuf_mtod_offset(tail, void*, tail->data_len), &NullSig, DataEncNullSigLen);
-  tail->data_len += DataEncNullSigLen;
-  pkt->pkt_len += DataEncNullSigLen;
+  if (signer == NULL) {
+    rte_memcpy(rte_pktmbuf_mtod_offset(tail, void*, tail->data_len), &NullSig, DataEncNullSigLen);
+    tail->data_len += DataEncNullSigLen;
+    pkt->pkt_len += DataEncNullSigLen;
+  } else if (unlikely(!DataEnc_AppendSig(pkt, tail, signer))) {
+    rte_pktmbuf_free(pkt);
+    return NULL;
+  }
   return Packet_EncodeFinish_(pkt, TtData, PktSData);
 }
@@ -4,6 +4,7 @@
 /** @file */
 
 #include "name.h"
+#include "signer.h"
 
 /** @brief Parsed Data packet. */
 typedef struct PData {
@@ -113,19 +114,21 @@ DataEnc_EncodeRoom(LName prefix, LName suffix, const uint8_t* meta, uint32_t roo
                    struct iovec* roomIov, int* roomIovcnt, PacketMempools* mp, PacketTxAlign align);
 
 /**
- * @brief Append Null signature to Data.
+ * @brief Append signature to Data.
  * @param pkt result of @c DataEnc_EncodeTpl or @c DataEnc_EncodeRoom .
+ * @param signer Data signer, or NULL to append Null signature.
  * @return encoded packet, or NUL upon failure.
  * @post If failure, @p pkt is freed.
  */
-__attribute__((nonnull)) Packet*
-DataEnc_Sign(struct rte_mbuf* pkt, PacketMempools* mp, PacketTxAlign align);
+__attribute__((nonnull(1, 2))) Packet*
+DataEnc_Sign(struct rte_mbuf* pkt, PacketMempools* mp, PacketTxAlign align, DataSigner* signer);
 
 /** @brief Data encoder optimized for traffic generator. */
 typedef struct DataGen {
   struct rte_mbuf* tpl;
   LName suffix;
   const uint8_t* meta;
+  DataSigner* signer; ///< Data signer, NULL for Null signature
   struct iovec contentIov[1];
 } DataGen;
 
@@ -148,7 +151,7 @@ DataGen_Encode(DataGen* gen, LName prefix, PacketMempools* mp, PacketTxAlign ali
   if (unlikely(pkt == NULL)) {
     return NULL;
   }
-  return DataEnc_Sign(pkt, mp, align);
+  return DataEnc_Sign(pkt, mp, align, gen->signer);
 }
 
 #endif // NDNDPDK_NDNI_DATA_H
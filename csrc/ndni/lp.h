@@ -93,4 +93,17 @@ LpHeader_Parse(LpHeader* lph, struct rte_mbuf* pkt);
 __attribute__((nonnull)) void
 LpHeader_Prepend(struct rte_mbuf* pkt, const LpL3* l3, const LpL2* l2);
 
+/**
+ * @brief Determine whether mbuf contains an empty LpPacket.
+ *
+ * An empty LpPacket is an IDLE packet that carries no network layer packet.
+ * It is used for liveness detection.
+ * Trailing octets, such as Ethernet padding, are ignored.
+ */
+__attribute__((nonnull)) static inline bool
+LpHeader_IsIdle(const struct rte_mbuf* pkt) {
+  const uint8_t* b = rte_pktmbuf_mtod(pkt, const uint8_t*);
+  return pkt->data_len >= 2 && b[0] == TtLpPacket && b[1] == 0;
+}
+
 #endif // NDNDPDK_NDN_LP_H
@@ -0,0 +1,273 @@
+#include "signer.h"
+
+#include "../core/logger.h"
+#include "tlv-decoder.h"
+#include <openssl/crypto.h>
+#include <openssl/evp.h>
+#include <openssl/x509.h>
+
+N_LOG_INIT(DataSigner);
+
+/**
+ * @brief Load signing or verification key.
+ * @param isPublic whether @p key is a public key, for verification only.
+ */
+__attribute__((nonnull(1))) static bool
+DataSigner_LoadKey(DataSigner* signer, const uint8_t* key, size_t keyL, bool isPublic) {
+  switch (signer->sigType) {
+    case SigSha256:
+      signer->sigValueMaxL = 32;
+      return true;
+    case SigHmacWithSha256:
+      signer->key = EVP_PKEY_new_raw_private_key(EVP_PKEY_HMAC, NULL, key, keyL);
+      signer->sigValueMaxL = 32;
+      break;
+    case SigSha256WithEcdsa:
+    case SigEd25519: {
+      const uint8_t* der = key;
+      signer->key = isPublic ? d2i_PUBKEY(NULL, &der, keyL) : d2i_AutoPrivateKey(NULL, &der, keyL);
+      if (signer->key != NULL) {
+        signer->sigValueMaxL = RTE_MIN(EVP_PKEY_get_size(signer->key), DataSignerMaxSigValueLen);
+      }
+      break;
+    }
+    default:
+      N_LOGE("Init error sig-type=%" PRIu8 " unsupported", signer->sigType);
+      return false;
+  }
+
+  if (signer->key == NULL) {
+    N_LOGE("Init error sig-type=%" PRIu8 " bad-key", signer->sigType);
+    return false;
+  }
+  return true;
+}
+
+bool
+DataSigner_Init(DataSigner* signer, uint8_t sigType, const uint8_t* key, size_t keyL,
+                const uint8_t* sigInfo, uint16_t sigInfoL) {
+  NDNDPDK_ASSERT(sigInfoL <= sizeof(signer->sigInfo));
+  *signer = (const DataSigner){
+    .sigInfoL = sigInfoL,
+    .sigType = sigType,
+  };
+  rte_memcpy(signer->sigInfo, sigInfo, sigInfoL);
+
+  signer->mdCtx = EVP_MD_CTX_new();
+  return signer->mdCtx != NULL && DataSigner_LoadKey(signer, key, keyL, false);
+}
+
+bool
+DataSigner_InitVerifier(DataSigner* signer, uint8_t sigType, const uint8_t* key, size_t keyL) {
+  *signer = (const DataSigner){
+    .sigType = sigType,
+  };
+
+  signer->mdCtx = EVP_MD_CTX_new();
+  return signer->mdCtx != NULL && DataSigner_LoadKey(signer, key, keyL, true);
+}
+
+void
+DataSigner_Close(DataSigner* signer) {
+  EVP_MD_CTX_free(signer->mdCtx);
+  EVP_PKEY_free(signer->key);
+  rte_free(signer->scratch);
+  signer->mdCtx = NULL;
+  signer->key = NULL;
+  signer->scratch = NULL;
+}
+
+/** @brief Streaming update function, such as @c EVP_DigestUpdate . */
+typedef int (*DataSigner_UpdateFunc)(EVP_MD_CTX* ctx, const void* d, size_t cnt);
+
+/**
+ * @brief Feed a portion of @p pkt into a streaming operation, segment by segment.
+ * @param off offset of the portion.
+ * @param len length of the portion.
+ */
+__attribute__((nonnull)) static bool
+DataSigner_Update(DataSigner* signer, DataSigner_UpdateFunc update, struct rte_mbuf* pkt,
+                  uint32_t off, uint32_t len) {
+  struct rte_mbuf* m = pkt;
+  for (; m != NULL && off >= m->data_len; m = m->next) {
+    off -= m->data_len;
+  }
+  for (; m != NULL && len > 0; m = m->next) {
+    uint32_t segL = RTE_MIN(len, (uint32_t)m->data_len - off);
+    if (update(signer->mdCtx, rte_pktmbuf_mtod_offset(m, const uint8_t*, off), segL) != 1) {
+      return false;
+    }
+    len -= segL;
+    off = 0;
+  }
+  return len == 0;
+}
+
+// EVP_DigestSignUpdate and EVP_DigestVerifyUpdate are macros in some OpenSSL versions
+static int
+DataSigner_DigestSignUpdate(EVP_MD_CTX* ctx, const void* d, size_t cnt) {
+  return EVP_DigestSignUpdate(ctx, d, cnt);
+}
+
+static int
+DataSigner_DigestVerifyUpdate(EVP_MD_CTX* ctx, const void* d, size_t cnt) {
+  return EVP_DigestVerifyUpdate(ctx, d, cnt);
+}
+
+/**
+ * @brief Obtain contiguous portion of @p pkt .
+ * @return pointer to the portion; either within @p pkt or in @c signer->scratch .
+ */
+__attribute__((nonnull)) static const uint8_t*
+DataSigner_Linearize(DataSigner* signer, struct rte_mbuf* pkt, uint32_t off, uint32_t len) {
+  if (rte_pktmbuf_data_len(pkt) < off + len && signer->scratchCap < len) {
+    rte_free(signer->scratch);
+    signer->scratchCap = 0;
+    signer->scratch = rte_malloc("DataSigner.scratch", len, 0);
+    if (unlikely(signer->scratch == NULL)) {
+      return NULL;
+    }
+    signer->scratchCap = len;
+  }
+  return rte_pktmbuf_read(pkt, off, len, signer->scratch);
+}
+
+__attribute__((nonnull)) static bool
+DataSigner_ComputeDigest(DataSigner* signer, struct rte_mbuf* pkt, uint32_t off, uint32_t len,
+                         uint8_t* sig, size_t* sigL) {
+  if (EVP_DigestInit_ex(signer->mdCtx, EVP_sha256(), NULL) != 1 ||
+      !DataSigner_Update(signer, EVP_DigestUpdate, pkt, off, len)) {
+    return false;
+  }
+  unsigned int mdL = 0;
+  if (EVP_DigestFinal_ex(signer->mdCtx, sig, &mdL) != 1) {
+    return false;
+  }
+  *sigL = mdL;
+  return true;
+}
+
+__attribute__((nonnull)) static bool
+DataSigner_ComputeStream(DataSigner* signer, struct rte_mbuf* pkt, uint32_t off, uint32_t len,
+                         uint8_t* sig, size_t* sigL) {
+  return EVP_DigestSignInit(signer->mdCtx, NULL, EVP_sha256(), NULL, signer->key) == 1 &&
+         DataSigner_Update(signer, DataSigner_DigestSignUpdate, pkt, off, len) &&
+         EVP_DigestSignFinal(signer->mdCtx, sig, sigL) == 1;
+}
+
+__attribute__((nonnull)) static bool
+DataSigner_ComputeOneShot(DataSigner* signer, struct rte_mbuf* pkt, uint32_t off, uint32_t len,
+                          uint8_t* sig, size_t* sigL) {
+  // Ed25519 cannot sign in streaming mode, so that the packet must be linearized
+  const uint8_t* input = DataSigner_Linearize(signer, pkt, off, len);
+  return input != NULL && EVP_DigestSignInit(signer->mdCtx, NULL, NULL, NULL, signer->key) == 1 &&
+         EVP_DigestSign(signer->mdCtx, sig, sigL, input, len) == 1;
+}
+
+/** @brief Compute signature over a portion of @p pkt . */
+__attribute__((nonnull)) static bool
+DataSigner_ComputeOn(DataSigner* signer, struct rte_mbuf* pkt, uint32_t off, uint32_t len,
+                     uint8_t* sig, size_t* sigL) {
+  switch (signer->sigType) {
+    case SigSha256:
+      return DataSigner_ComputeDigest(signer, pkt, off, len, sig, sigL);
+    case SigHmacWithSha256:
+    case SigSha256WithEcdsa:
+      return DataSigner_ComputeStream(signer, pkt, off, len, sig, sigL);
+    case SigEd25519:
+      return DataSigner_ComputeOneShot(signer, pkt, off, len, sig, sigL);
+  }
+  return false;
+}
+
+bool
+DataSigner_Compute(DataSigner* signer, struct rte_mbuf* pkt, uint8_t* sig, size_t* sigL) {
+  TscTime t0 = rte_get_tsc_cycles();
+  *sigL = signer->sigValueMaxL;
+  bool ok = DataSigner_ComputeOn(signer, pkt, 0, pkt->pkt_len, sig, sigL);
+  RunningStatI_Push(&signer->latency, rte_get_tsc_cycles() - t0);
+
+  if (unlikely(!ok || *sigL > signer->sigValueMaxL)) {
+    ++signer->nErrors;
+    return false;
+  }
+  return true;
+}
+
+/**
+ * @brief Locate signed portion and SignatureValue.
+ * @param[out] signedL signed portion length, starting from @p signedOff .
+ * @param[out] sigOff SignatureValue TLV-VALUE offset.
+ * @param[out] sigL SignatureValue TLV-LENGTH.
+ */
+__attribute__((nonnull)) static bool
+DataSigner_ParseSig(struct rte_mbuf* pkt, uint32_t* sigType, uint32_t* signedOff,
+                    uint32_t* signedL, uint32_t* sigOff, uint32_t* sigL) {
+  TlvDecoder d = TlvDecoder_Init(pkt);
+  uint32_t length0, type0 = TlvDecoder_ReadTL(&d, &length0);
+  if (unlikely(type0 != TtData)) {
+    return false;
+  }
+  *signedOff = pkt->pkt_len - d.length;
+  *sigType = UINT32_MAX;
+
+  while (d.length > 0) {
+    uint32_t elementOff = pkt->pkt_len - d.length;
+    uint32_t length, type = TlvDecoder_ReadTL(&d, &length);
+    switch (type) {
+      case 0:
+        return false;
+      case TtDSigInfo: {
+        TlvDecoder vd = TlvDecoder_MakeValueDecoder(&d, length);
+        uint32_t typeL = 0;
+        if (unlikely(TlvDecoder_ReadTL(&vd, &typeL) != TtSigType ||
+                     !TlvDecoder_ReadNniTo(&vd, typeL, sigType))) {
+          return false;
+        }
+        break;
+      }
+      case TtDSigValue:
+        *signedL = elementOff - *signedOff;
+        *sigOff = pkt->pkt_len - d.length;
+        *sigL = length;
+        return *sigType != UINT32_MAX;
+      default:
+        TlvDecoder_Skip(&d, length);
+        break;
+    }
+  }
+  return false;
+}
+
+bool
+DataSigner_Verify(DataSigner* signer, struct rte_mbuf* pkt) {
+  uint32_t sigType = 0, signedOff = 0, signedL = 0, sigOff = 0, sigL = 0;
+  if (unlikely(!DataSigner_ParseSig(pkt, &sigType, &signedOff, &signedL, &sigOff, &sigL) ||
+               sigType != signer->sigType || sigL > DataSignerMaxSigValueLen)) {
+    return false;
+  }
+
+  uint8_t sigScratch[DataSignerMaxSigValueLen];
+  const uint8_t* sig = rte_pktmbuf_read(pkt, sigOff, sigL, sigScratch);
+
+  switch (signer->sigType) {
+    case SigSha256:
+    case SigHmacWithSha256: {
+      uint8_t expected[DataSignerMaxSigValueLen];
+      size_t expectedL = sizeof(expected);
+      return DataSigner_ComputeOn(signer, pkt, signedOff, signedL, expected, &expectedL) &&
+             expectedL == sigL && CRYPTO_memcmp(expected, sig, sigL) == 0;
+    }
+    case SigSha256WithEcdsa:
+      return EVP_DigestVerifyInit(signer->mdCtx, NULL, EVP_sha256(), NULL, signer->key) == 1 &&
+             DataSigner_Update(signer, DataSigner_DigestVerifyUpdate, pkt, signedOff, signedL) &&
+             EVP_DigestVerifyFinal(signer->mdCtx, sig, sigL) == 1;
+    case SigEd25519: {
+      const uint8_t* input = DataSigner_Linearize(signer, pkt, signedOff, signedL);
+      return input != NULL &&
+             EVP_DigestVerifyInit(signer->mdCtx, NULL, NULL, NULL, signer->key) == 1 &&
+             EVP_DigestVerify(signer->mdCtx, sig, sigL, input, signedL) == 1;
+    }
+  }
+  return false;
+}
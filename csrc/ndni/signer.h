@@ -0,0 +1,94 @@
+#ifndef NDNDPDK_NDNI_SIGNER_H
+#define NDNDPDK_NDNI_SIGNER_H
+
+/** @file */
+
+#include "../core/running-stat.h"
+#include "common.h"
+
+struct evp_md_ctx_st;
+struct evp_pkey_st;
+
+/**
+ * @brief Data signer.
+ *
+ * Each DataSigner contains an OpenSSL context and should be used by only one thread.
+ */
+typedef struct DataSigner {
+  struct evp_md_ctx_st* mdCtx;
+  struct evp_pkey_st* key; ///< private key, public key in verifier, NULL for SigSha256
+  uint8_t* scratch;        ///< linearization buffer for Ed25519
+  uint32_t scratchCap;     ///< @c scratch capacity
+  uint16_t sigInfoL;       ///< SignatureInfo TLV length
+  uint8_t sigValueMaxL;    ///< maximum SignatureValue TLV-LENGTH
+  uint8_t sigType;
+  uint64_t nErrors;     ///< signing errors
+  RunningStatI latency; ///< signing duration in TSC cycles
+  uint8_t sigInfo[DataSignerSigInfoBufLen];
+} DataSigner;
+
+/**
+ * @brief Initialize DataSigner.
+ * @param sigType SigType numeric value.
+ * @param key private key: raw secret for HMAC, PKCS #8 DER for ECDSA and Ed25519, ignored for
+ *            SHA256.
+ * @param sigInfo SignatureInfo TLV, must not exceed @c DataSignerSigInfoBufLen .
+ * @return whether success.
+ * @post If failure, @c DataSigner_Close should be invoked.
+ */
+__attribute__((nonnull(1, 5))) bool
+DataSigner_Init(DataSigner* signer, uint8_t sigType, const uint8_t* key, size_t keyL,
+                const uint8_t* sigInfo, uint16_t sigInfoL);
+
+/**
+ * @brief Initialize DataSigner for verification only.
+ * @param sigType SigType numeric value.
+ * @param key verification key: raw secret for HMAC, SubjectPublicKeyInfo DER for ECDSA and
+ *            Ed25519, ignored for SHA256.
+ * @return whether success.
+ * @post If failure, @c DataSigner_Close should be invoked.
+ *
+ * The DataSigner can be used with @c DataSigner_Verify but not @c DataSigner_Compute .
+ */
+__attribute__((nonnull(1))) bool
+DataSigner_InitVerifier(DataSigner* signer, uint8_t sigType, const uint8_t* key, size_t keyL);
+
+/** @brief Release DataSigner resources. */
+__attribute__((nonnull)) void
+DataSigner_Close(DataSigner* signer);
+
+/**
+ * @brief Return required tailroom to append SignatureInfo and SignatureValue.
+ * @param signer DataSigner, or NULL for Null signature.
+ */
+static inline uint16_t
+DataSigner_TrailerLen(const DataSigner* signer) {
+  if (signer == NULL) {
+    return DataEncNullSigLen;
+  }
+  return signer->sigInfoL + 2 + signer->sigValueMaxL;
+}
+
+/**
+ * @brief Compute signature.
+ * @param pkt packet containing the signed portion.
+ * @param[out] sig signature buffer, must have @c signer->sigValueMaxL room.
+ * @param[inout] sigL signature length.
+ * @return whether success.
+ */
+__attribute__((nonnull)) bool
+DataSigner_Compute(DataSigner* signer, struct rte_mbuf* pkt, uint8_t* sig, size_t* sigL);
+
+/**
+ * @brief Verify Data signature.
+ * @param pkt Data packet, starting from Data TLV-TYPE.
+ * @return whether Data has the same SigType as @p signer and its SignatureValue is valid.
+ *
+ * This requires a DataSigner initialized with @c DataSigner_InitVerifier with the public key of
+ * the signing party, or @c DataSigner_Init with the same key as the signing party.
+ * KeyLocator is not checked.
+ */
+__attribute__((nonnull)) bool
+DataSigner_Verify(DataSigner* signer, struct rte_mbuf* pkt);
+
+#endif // NDNDPDK_NDNI_SIGNER_H
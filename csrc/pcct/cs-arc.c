@@ -74,6 +74,57 @@ CsArc_Init(CsArc* arc, uint32_t c, uint32_t capB2) {
   arc->moveCtx = 0;
 }
 
+void
+CsArc_SetCapacity(CsArc* arc, uint32_t c) {
+  N_LOGD("SetCapacity arc=%p old-c=%" PRIu32 " new-c=%" PRIu32, arc, CsArc_c(arc), c);
+  arc->c = (double)c;
+  CsArc_c(arc) = c;
+  CsArc_2c(arc) = 2 * c;
+  CsArc_SetP(arc, RTE_MIN(arc->p, arc->c));
+
+  // |T1|+|B1| <= c
+  while (arc->T1.count + arc->B1.count > c) {
+    if (arc->B1.count > 0) {
+      CsEntry* deleting = CsList_GetFront(&arc->B1);
+      CsArc_Move(arc, deleting, B1, Del);
+    } else {
+      CsEntry* deleting = CsList_GetFront(&arc->T1);
+      CsArc_Move(arc, deleting, T1, Del);
+    }
+  }
+
+  // |T1|+|T2| <= c
+  while (arc->T1.count + arc->T2.count > c) {
+    if (arc->T1.count > CsArc_p(arc) || arc->T2.count == 0) {
+      CsEntry* moving = CsList_GetFront(&arc->T1);
+      CsArc_Move(arc, moving, T1, B1);
+    } else {
+      CsEntry* moving = CsList_GetFront(&arc->T2);
+      CsArc_Move(arc, moving, T2, B2);
+    }
+  }
+
+  // B2 ghost capacity: |B2| <= MAX(2c-|T1|-|T2|-|B1|, extended capacity)
+  uint32_t nT = arc->T1.count + arc->T2.count;
+  uint32_t capB2 = RTE_MAX(CsArc_2c(arc) - RTE_MIN(nT + arc->B1.count, CsArc_2c(arc)), arc->B2.capacity);
+  while (arc->B2.count > capB2) {
+    CsEntry* deleting = CsList_GetFront(&arc->B2);
+    CsArc_Move(arc, deleting, B2, Del);
+  }
+
+  // B1 ghost capacity: |B1| <= MIN(c-|T1|, 2c-|T1|-|T2|-|B2|)
+  uint32_t capB1 = CsArc_c(arc) - arc->T1.count;
+  if (nT + arc->B2.count < CsArc_2c(arc)) {
+    capB1 = RTE_MIN(capB1, CsArc_2c(arc) - nT - arc->B2.count);
+  } else {
+    capB1 = 0;
+  }
+  while (arc->B1.count > capB1) {
+    CsEntry* deleting = CsList_GetFront(&arc->B1);
+    CsArc_Move(arc, deleting, B1, Del);
+  }
+}
+
 __attribute__((nonnull)) static inline void
 CsArc_Replace(CsArc* arc, bool isB2) {
   if (isB2 ? arc->T1.count >= CsArc_p1(arc) : arc->T1.count > CsArc_p(arc)) {
@@ -28,6 +28,18 @@ CsArc_GetList(CsArc* arc, CsListID l) {
 __attribute__((nonnull)) void
 CsArc_Init(CsArc* arc, uint32_t c, uint32_t capB2);
 
+/**
+ * @brief Change nominal capacity.
+ * @param c new nominal capacity.
+ *
+ * If capacity is reduced, entries are moved toward Del list until ARC invariants are restored.
+ * Ghost lists B1 and B2 are trimmed to their capacities under the new @p c ; B2 retains its
+ * extended capacity given to CsArc_Init.
+ * Caller should then evict entries in Del list.
+ */
+__attribute__((nonnull)) void
+CsArc_SetCapacity(CsArc* arc, uint32_t c);
+
 /** @brief Return nominal capacity @c c . */
 __attribute__((nonnull)) static __rte_always_inline uint32_t
 CsArc_GetCapacity(const CsArc* arc) {
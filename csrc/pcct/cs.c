@@ -90,6 +90,20 @@ Cs_GetCapacity(Cs* cs, CsListID l) {
   return Cs_GetList(cs, l)->capacity;
 }
 
+void
+Cs_SetCapacity(Cs* cs, uint32_t capMemory, uint32_t capIndirect) {
+  N_LOGI("SetCapacity cs=%p memory=%" PRIu32 " indirect=%" PRIu32, cs, capMemory, capIndirect);
+  CsArc_SetCapacity(&cs->direct, capMemory);
+  while (cs->direct.Del.count > 0) {
+    Cs_Evict(cs, &cs->direct.Del, "direct", Cs_EvictEntryDirect);
+  }
+
+  cs->indirect.capacity = capIndirect;
+  while (cs->indirect.count > cs->indirect.capacity) {
+    Cs_Evict(cs, &cs->indirect, "indirect", Cs_EvictEntryIndirect);
+  }
+}
+
 uint32_t
 Cs_CountEntries(Cs* cs, CsListID l) {
   if (l == CslDirect) {
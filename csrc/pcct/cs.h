@@ -11,6 +11,14 @@
 __attribute__((nonnull)) uint32_t
 Cs_GetCapacity(Cs* cs, CsListID l);
 
+/**
+ * @brief Change capacity of in-memory direct entries and indirect entries.
+ *
+ * If capacity is reduced, excess entries are evicted immediately.
+ */
+__attribute__((nonnull)) void
+Cs_SetCapacity(Cs* cs, uint32_t capMemory, uint32_t capIndirect);
+
 /** @brief Get number of entries. */
 __attribute__((nonnull)) uint32_t
 Cs_CountEntries(Cs* cs, CsListID l);
@@ -2,16 +2,60 @@
 #include "../iface/faceid.h"
 #include "format.h"
 
+/** @brief Pass current chunk to streaming sink. */
+__attribute__((nonnull)) static void
+FlushChunk(PdumpWriter* w) {
+  if (w->chunk == NULL || w->chunk->len == 0) {
+    return;
+  }
+  // fullChunks has enough capacity for all chunks, so that enqueue cannot fail
+  rte_ring_enqueue(w->fullChunks, w->chunk);
+  w->chunk = NULL;
+}
+
+/**
+ * @brief Reserve output buffer for a block.
+ * @param len block length.
+ * @return pointer to output buffer, or NULL if the block cannot be written.
+ */
+__attribute__((nonnull)) static uint8_t*
+Reserve(PdumpWriter* w, uint32_t len) {
+  if (!w->stream) {
+    if (unlikely(w->pos + len > w->m.size)) {
+      w->full = true;
+      return NULL;
+    }
+    uint8_t* dst = MmapFd_At(&w->m, w->pos);
+    w->pos += len;
+    return dst;
+  }
+
+  if (w->chunk != NULL && w->chunk->len + len > sizeof(w->chunk->data)) {
+    FlushChunk(w);
+  }
+  if (unlikely(len > sizeof(w->chunk->data)) ||
+      (w->chunk == NULL && rte_ring_dequeue(w->freeChunks, (void**)&w->chunk) != 0)) {
+    ++w->nDropped;
+    return NULL;
+  }
+  uint8_t* dst = RTE_PTR_ADD(w->chunk->data, w->chunk->len);
+  w->chunk->len += len;
+  return dst;
+}
+
 __attribute__((nonnull)) static __rte_noinline void
 WriteBlock(PdumpWriter* w, struct rte_mbuf* pkt) {
   NDNDPDK_ASSERT(pkt->pkt_len % 4 == 0);
   NDNDPDK_ASSERT(pkt->pkt_len == pkt->data_len);
-  if (unlikely(w->pos + pkt->pkt_len > w->m.size)) {
-    w->full = true;
+  if (w->stream) {
+    // streaming sink obtains SHB and IDB from Go code
+    return;
+  }
+  uint8_t* dst = Reserve(w, pkt->pkt_len);
+  if (unlikely(dst == NULL)) {
     return;
   }
-  rte_memcpy(MmapFd_At(&w->m, w->pos), rte_pktmbuf_mtod(pkt, const uint8_t*), pkt->pkt_len);
-  w->pos += pkt->pkt_len;
+  rte_memcpy(dst, rte_pktmbuf_mtod(pkt, const uint8_t*), pkt->pkt_len);
 }
 
 /**
@@ -28,8 +72,8 @@ WriteEPB(PdumpWriter* w, struct rte_mbuf* pkt, PcapngEPB* epb, size_t hdrLen) {
 
   uint32_t totalLength = hdrLen + pkt->pkt_len + sizeof(PcapngTrailer);
   totalLength = (totalLength + 0x03) & (~0x03);
-  if (unlikely(w->pos + totalLength > w->m.size)) {
-    w->full = true;
+  uint8_t* dst = Reserve(w, totalLength);
+  if (unlikely(dst == NULL)) {
     return;
   }
 
@@ -44,17 +88,13 @@ WriteEPB(PdumpWriter* w, struct rte_mbuf* pkt, PcapngEPB* epb, size_t hdrLen) {
     .capLen = rte_cpu_to_le_32(pktLen),
     .origLen = rte_cpu_to_le_32(pktLen),
   };
-  rte_memcpy(MmapFd_At(&w->m, w->pos), epb, hdrLen);
-
-  uint8_t* dst = MmapFd_At(&w->m, w->pos + hdrLen);
-  Mbuf_ReadTo(pkt, 0, pkt->pkt_len, dst);
+  rte_memcpy(dst, epb, hdrLen);
+  Mbuf_ReadTo(pkt, 0, pkt->pkt_len, RTE_PTR_ADD(dst, hdrLen));
 
   PcapngTrailer trailer = {
     .totalLength = epb->totalLength,
   };
-  rte_memcpy(MmapFd_At(&w->m, w->pos + totalLength - sizeof(trailer)), &trailer, sizeof(trailer));
-
-  w->pos += totalLength;
+  rte_memcpy(RTE_PTR_ADD(dst, totalLength - sizeof(trailer)), &trailer, sizeof(trailer));
 }
 
 __attribute__((nonnull)) static inline void
@@ -100,7 +140,7 @@ ProcessMbuf(PdumpWriter* w, struct rte_mbuf* pkt) {
 
 int
 PdumpWriter_Run(PdumpWriter* w) {
-  if (!MmapFd_Open(&w->m, w->filename, w->maxSize)) {
+  if (!w->stream && !MmapFd_Open(&w->m, w->filename, w->maxSize)) {
     return 1;
   }
 
@@ -113,8 +153,17 @@ PdumpWriter_Run(PdumpWriter* w) {
       ProcessMbuf(w, pkts[i]);
     }
     rte_pktmbuf_free_bulk(pkts, count);
+
+    if (count == 0 && w->stream) {
+      // pass partial chunk to streaming sink when idle, to reduce latency
+      FlushChunk(w);
+    }
   }
 
+  if (w->stream) {
+    FlushChunk(w);
+    return 0;
+  }
   if (!MmapFd_Close(&w->m, w->filename, w->pos)) {
     return 2;
   }
@@ -5,17 +5,31 @@
 
 #include "../core/mmapfd.h"
 #include "../dpdk/thread.h"
+#include "enum.h"
+
+/** @brief Chunk of pcapng blocks passed from writer thread to a streaming sink. */
+typedef struct PdumpChunk {
+  uint32_t len;
+  uint8_t data[PdumpStreamChunkSize];
+} PdumpChunk;
 
 /** @brief Packet dump writer. */
 typedef struct PdumpWriter {
   ThreadCtrl ctrl;
   struct rte_ring* queue;
-  const char* filename;
+  const char* filename; ///< output filename, file sink only
   MmapFd m;
   size_t maxSize;
   size_t pos;
+
+  struct rte_ring* freeChunks; ///< empty chunks, streaming sink only
+  struct rte_ring* fullChunks; ///< filled chunks, streaming sink only
+  PdumpChunk* chunk;           ///< current chunk, streaming sink only
+  uint64_t nDropped;           ///< packets dropped due to no empty chunk
+
   uint32_t nextIntf;
   bool full;
+  bool stream;
   uint32_t intf[UINT16_MAX + 1];
 } PdumpWriter;
 
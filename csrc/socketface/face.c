@@ -13,7 +13,7 @@ SocketFace_HandleError_(Face* face, int err) {
 }
 
 uint16_t
-SocketFace_DgramTxBurst(Face* face, struct rte_mbuf** pkts, uint16_t nPkts) {
+SocketFace_DgramTxBurst(Face* face, int txThread, struct rte_mbuf** pkts, uint16_t nPkts) {
   SocketFacePriv* priv = Face_GetPriv(face);
   if (unlikely(priv->fd < 0)) {
     goto FREE;
@@ -28,6 +28,6 @@ SocketFace_HandleError(Face* face, int err) {
 
 /** @brief Transmit a burst of outgoing packets on datagram socket. */
 __attribute__((nonnull)) uint16_t
-SocketFace_DgramTxBurst(Face* face, struct rte_mbuf** pkts, uint16_t nPkts);
+SocketFace_DgramTxBurst(Face* face, int txThread, struct rte_mbuf** pkts, uint16_t nPkts);
 
 #endif // NDNDPDK_SOCKETFACE_FACE_H
@@ -15,6 +15,11 @@ TgcRx_GetSeqNumFromName(TgcRx* cr, const TgcRxPattern* pattern, const PName* nam
   } __rte_packed SeqNumF;
   static_assert(sizeof(SeqNumF) == TgcSeqNumSize, "");
 
+  if (cr->anySuffix) {
+    *seqNum = 0;
+    return name->length >= pattern->prefixLen;
+  }
+
   const SeqNumF* comp = RTE_PTR_ADD(name->value, pattern->prefixLen);
   if (unlikely(name->length < pattern->prefixLen + TgcSeqNumSize ||
                comp->tl != TlvEncoder_ConstTL1(TtGenericNameComponent, sizeof(uint64_t)))) {
@@ -24,19 +29,118 @@ TgcRx_GetSeqNumFromName(TgcRx* cr, const TgcRxPattern* pattern, const PName* nam
   return true;
 }
 
+/**
+ * @brief Record a received sequence number in the window.
+ * @return whether the sequence number has been received before.
+ */
+__attribute__((nonnull)) static bool
+TgcRxVerify_IsDuplicate(TgcRxVerify* v, uint64_t seqNum) {
+  int64_t ahead = (int64_t)(seqNum - v->hiSeqNum);
+  if (ahead > (int64_t)v->windowMask) {
+    memset(v->window, 0, ((size_t)v->windowMask + 1) / CHAR_BIT);
+    v->hiSeqNum = seqNum;
+  } else if (ahead > 0) {
+    for (uint64_t s = v->hiSeqNum + 1; (int64_t)(s - seqNum) <= 0; ++s) {
+      uint32_t bit = s & v->windowMask;
+      v->window[bit / 64] &= ~((uint64_t)1 << (bit % 64));
+    }
+    v->hiSeqNum = seqNum;
+  } else if (-ahead > (int64_t)v->windowMask) {
+    return false; // too old to tell
+  }
+
+  uint32_t bit = seqNum & v->windowMask;
+  uint64_t mask = (uint64_t)1 << (bit % 64);
+  bool seen = (v->window[bit / 64] & mask) != 0;
+  v->window[bit / 64] |= mask;
+  return seen;
+}
+
+/**
+ * @brief Verify Data packet.
+ * @return whether Data passes all verifications; failures are counted in @p pattern .
+ */
+__attribute__((nonnull)) static bool
+TgcRx_VerifyData(TgcRx* cr, TgcRxPattern* pattern, Packet* npkt, bool hasSeqNum,
+                 uint64_t seqNum) {
+  TgcRxVerify* v = pattern->verify;
+  const PData* data = Packet_GetDataHdr(npkt);
+  if (unlikely(!hasSeqNum || (v->nameL != 0 && data->name.length != v->nameL) ||
+               memcmp(data->name.value, v->prefixV, pattern->prefixLen) != 0)) {
+    ++pattern->nWrongNames;
+    return false;
+  }
+
+  if (v->txSeqNum != NULL) {
+    uint64_t txSeqNum = __atomic_load_n(v->txSeqNum, __ATOMIC_ACQUIRE);
+    if (unlikely((int64_t)(seqNum - txSeqNum) > 0)) {
+      ++pattern->nUnsolicited;
+      return false;
+    }
+    if (unlikely(TgcRxVerify_IsDuplicate(v, seqNum))) {
+      ++pattern->nDuplicates;
+      return false;
+    }
+
+    if (v->digests != NULL && txSeqNum - seqNum < v->windowMask) {
+      uint8_t expected[ImplicitDigestLength];
+      rte_mov32(expected, v->digests[seqNum & v->windowMask]);
+      // TX thread writes the slot of txSeqNum+1 before publishing it; if TX thread has advanced so
+      // far that the slot may have been overwritten during the copy, the digest cannot be checked
+      atomic_thread_fence(memory_order_acquire);
+      txSeqNum = __atomic_load_n(v->txSeqNum, __ATOMIC_RELAXED);
+
+      uint8_t digest[ImplicitDigestLength];
+      size_t digestL = 0;
+      if (txSeqNum - seqNum < v->windowMask &&
+          unlikely(!DataSigner_Compute(cr->digester, Packet_ToMbuf(npkt), digest, &digestL) ||
+                   memcmp(digest, expected, sizeof(digest)) != 0)) {
+        ++pattern->nDigestMismatches;
+        return false;
+      }
+    }
+  }
+
+  if (cr->verifier != NULL && unlikely(!DataSigner_Verify(cr->verifier, Packet_ToMbuf(npkt)))) {
+    ++pattern->nSigFailures;
+    return false;
+  }
+  return true;
+}
+
+/**
+ * @brief Determine RTT histogram bucket.
+ *
+ * Bucket 0 contains zero or negative RTT. Bucket i contains RTT within <tt>[2^(i-1), 2^i)</tt> TSC units.
+ * The last bucket also contains all longer RTTs.
+ */
+static __rte_always_inline unsigned
+TgcRx_RttBucket(TscDuration rtt) {
+  return RTE_MIN((unsigned)rte_fls_u64((uint64_t)RTE_MAX(rtt, 0)), (unsigned)TgcRttBuckets - 1);
+}
+
 __attribute__((nonnull)) static void
 TgcRx_ProcessData(TgcRx* cr, Packet* npkt, uint8_t id, TscTime sendTime) {
   TgcRxPattern* pattern = &cr->pattern[id];
   const PData* data = Packet_GetDataHdr(npkt);
 
-  uint64_t seqNum;
-  if (unlikely(!TgcRx_GetSeqNumFromName(cr, pattern, &data->name, &seqNum))) {
+  uint64_t seqNum = 0;
+  bool hasSeqNum = TgcRx_GetSeqNumFromName(cr, pattern, &data->name, &seqNum);
+  if (pattern->verify != NULL) {
+    if (unlikely(!TgcRx_VerifyData(cr, pattern, npkt, hasSeqNum, seqNum))) {
+      N_LOGD(">D pattern=%" PRIu8 " seq=%" PRIx64 " verify-fail", id, seqNum);
+      return;
+    }
+  } else if (unlikely(!hasSeqNum)) {
     return;
   }
 
   N_LOGD(">D pattern=%" PRIu8 " seq=%" PRIx64, id, seqNum);
   TscTime recvTime = Mbuf_GetTimestamp(Packet_ToMbuf(npkt));
-  RunningStatI_Push(&pattern->rtt, recvTime - sendTime);
+  TscDuration rtt = recvTime - sendTime;
+  RunningStatI_Push(&pattern->rtt, rtt);
+  ++pattern->rttHist[TgcRx_RttBucket(rtt)];
+  pattern->nCsHits += (uint64_t)(rtt <= pattern->csHitRtt);
 }
 
 __attribute__((nonnull)) static void
@@ -63,11 +167,13 @@ TgcRx_Run(TgcRx* cr) {
     for (uint16_t i = 0; i < pop.count; ++i) {
       Packet* npkt = Packet_FromMbuf(pkts[i]);
       const LpPitToken* token = &Packet_GetLpL3Hdr(npkt)->pitToken;
-      if (unlikely(token->length != TgcTokenLength || TgcToken_GetRunNum(token) != cr->runNum)) {
+      if (unlikely(token->length != TgcTokenLength ||
+                   TgcToken_GetPatternID(token) >= cr->nPatterns)) {
+        cr->nUnsolicited += (uint64_t)cr->verifying;
         continue;
       }
       uint8_t id = TgcToken_GetPatternID(token);
-      if (unlikely(id >= cr->nPatterns)) {
+      if (unlikely(TgcToken_GetRunNum(token) != cr->runNum)) {
         continue;
       }
       switch (Packet_GetType(npkt)) {
@@ -9,11 +9,40 @@
 #include "../dpdk/thread.h"
 #include "../iface/pktqueue.h"
 
+/**
+ * @brief Per-pattern verification state in traffic generator consumer.
+ *
+ * The window bitmap tracks received sequence numbers in <tt>(hiSeqNum-windowMask, hiSeqNum]</tt>.
+ *
+ * @c txSeqNum points to @c TgcTxPattern.seqNumV owned by the TX thread.
+ * The TX thread stores it with release semantics after recording the implicit digest of the new
+ * sequence number, and the RX thread loads it with acquire semantics before reading @c digests .
+ */
+typedef struct TgcRxVerify {
+  const uint64_t* txSeqNum; ///< last sequence number in TX thread, NULL if not monotonic
+  uint8_t (*digests)[ImplicitDigestLength]; ///< expected implicit digests, NULL if not digest pattern
+  uint64_t hiSeqNum;                        ///< highest received sequence number
+  uint32_t windowMask;                      ///< window size minus one
+  uint16_t nameL; ///< expected Data name TLV-LENGTH, zero if CanBePrefix
+  uint8_t prefixV[NameMaxLength];
+  uint64_t window[]; ///< bitmap of received sequence numbers
+} TgcRxVerify;
+
 /** @brief Per-pattern information in traffic generator consumer. */
 typedef struct TgcRxPattern {
   uint64_t nNacks;
+  uint64_t nCsHits; ///< Data with RTT not exceeding csHitRtt
   RunningStatI rtt;
+  uint64_t rttHist[TgcRttBuckets]; ///< RTT histogram, see TgcRx_RttBucket
+  TscDuration csHitRtt; ///< RTT threshold of presumed Content Store hits, zero disables
   uint16_t prefixLen;
+
+  TgcRxVerify* verify; ///< verification state, NULL if verifying mode is disabled
+  uint64_t nDuplicates;
+  uint64_t nUnsolicited;
+  uint64_t nWrongNames;
+  uint64_t nDigestMismatches;
+  uint64_t nSigFailures;
 } TgcRxPattern;
 
 /** @brief Traffic generator consumer RX thread. */
@@ -22,6 +51,11 @@ typedef struct TgcRx {
   PktQueue rxQueue;
   uint8_t runNum;
   uint8_t nPatterns;
+  bool anySuffix; ///< if true, name suffix is not required to be a sequence number
+  bool verifying; ///< if true, verifying mode is enabled
+  uint64_t nUnsolicited; ///< packets without valid PIT token, verifying mode only
+  DataSigner* verifier;  ///< Data signature verifier, NULL if disabled
+  DataSigner* digester;  ///< implicit digest calculator, NULL if not needed
   TgcRxPattern pattern[TgcMaxPatterns];
 } TgcRx;
 
@@ -4,6 +4,9 @@
 STATIC_ASSERT_FUNC_TYPE(TgcTxPattern_MakeSuffix, TgcTxPattern_MakeSuffix_Digest);
 STATIC_ASSERT_FUNC_TYPE(TgcTxPattern_MakeSuffix, TgcTxPattern_MakeSuffix_Offset);
 STATIC_ASSERT_FUNC_TYPE(TgcTxPattern_MakeSuffix, TgcTxPattern_MakeSuffix_Increment);
+STATIC_ASSERT_FUNC_TYPE(TgcTxPattern_MakeSuffix, TgcTxPattern_MakeSuffix_Zipf);
+STATIC_ASSERT_FUNC_TYPE(TgcTxPattern_MakeSuffix, TgcTxPattern_MakeSuffix_Uniform);
+STATIC_ASSERT_FUNC_TYPE(TgcTxPattern_MakeSuffix, TgcTxPattern_MakeSuffix_HotCold);
 
 N_LOG_INIT(Tgc);
 
@@ -64,10 +67,15 @@ TgcTxPattern_MakeSuffix_Digest(TgcTx* ct, uint8_t patternID, TgcTxPattern* patte
   PData* data = Packet_GetDataHdr(npkt);
   unaligned_uint64_t* dataSeqNum = RTE_PTR_ADD(
     PName_ToLName(&data->name).value, pattern->tpl.prefixL + TgcSeqNumSize - sizeof(uint64_t));
-  pattern->seqNumV = *dataSeqNum;
+  uint64_t seqNum = *dataSeqNum;
 
   static_assert(ImplicitDigestLength == 32, "");
   rte_mov32(pattern->digestV, data->digest);
+  if (dp->digests != NULL) {
+    rte_mov32(dp->digests[seqNum & dp->digestsMask], data->digest);
+  }
+  // publish seqNum after recording the digest, see TgcRxVerify.txSeqNum
+  __atomic_store_n(&pattern->seqNumV, seqNum, __ATOMIC_RELEASE);
   rte_pktmbuf_free(Packet_ToMbuf(npkt));
   return TgcSeqNumSize + ImplicitDigestSize;
 }
@@ -84,7 +92,45 @@ TgcTxPattern_MakeSuffix_Offset(TgcTx* ct, uint8_t patternID, TgcTxPattern* patte
 
 uint16_t
 TgcTxPattern_MakeSuffix_Increment(TgcTx* ct, uint8_t patternID, TgcTxPattern* pattern) {
-  ++pattern->seqNumV;
+  // publish seqNum to RX thread, see TgcRxVerify.txSeqNum
+  __atomic_store_n(&pattern->seqNumV, pattern->seqNumV + 1, __ATOMIC_RELEASE);
+  return TgcSeqNumSize;
+}
+
+uint16_t
+TgcTxPattern_MakeSuffix_Zipf(TgcTx* ct, uint8_t patternID, TgcTxPattern* pattern) {
+  const TgcTxPopularity* pop = pattern->popularity;
+  uint32_t r = pcg32_random_r(&ct->trafficRng);
+  uint32_t lo = 0, hi = pop->catalogSize - 1;
+  while (lo < hi) {
+    uint32_t mid = lo + (hi - lo) / 2;
+    if (r < pop->zipfCdf[mid]) {
+      hi = mid;
+    } else {
+      lo = mid + 1;
+    }
+  }
+  pattern->seqNumV = pop->seqNumBase + lo;
+  return TgcSeqNumSize;
+}
+
+uint16_t
+TgcTxPattern_MakeSuffix_Uniform(TgcTx* ct, uint8_t patternID, TgcTxPattern* pattern) {
+  const TgcTxPopularity* pop = pattern->popularity;
+  pattern->seqNumV = pop->seqNumBase + pcg32_boundedrand_r(&ct->trafficRng, pop->catalogSize);
+  return TgcSeqNumSize;
+}
+
+uint16_t
+TgcTxPattern_MakeSuffix_HotCold(TgcTx* ct, uint8_t patternID, TgcTxPattern* pattern) {
+  const TgcTxPopularity* pop = pattern->popularity;
+  uint32_t item = 0;
+  if (pcg32_random_r(&ct->trafficRng) < pop->hotThreshold) {
+    item = pcg32_boundedrand_r(&ct->trafficRng, pop->hotSize);
+  } else {
+    item = pop->hotSize + pcg32_boundedrand_r(&ct->trafficRng, pop->catalogSize - pop->hotSize);
+  }
+  pattern->seqNumV = pop->seqNumBase + item;
   return TgcSeqNumSize;
 }
 
@@ -131,8 +177,72 @@ TgcTx_Burst(TgcTx* ct) {
   Face_TxBurst(ct->face, (Packet**)pkts, MaxBurstSize);
 }
 
+/**
+ * @brief Transmit trace records that are due.
+ * @param[inout] pos position of next trace record.
+ * @return number of transmitted Interests.
+ */
+__attribute__((nonnull)) static uint16_t
+TgcTx_TraceBurst(TgcTx* ct, const TgcTrace* trace, uint32_t* pos, TscTime epoch) {
+  TscTime now = rte_get_tsc_cycles();
+  uint32_t end = *pos;
+  while (end < trace->nRecords && end - *pos < MaxBurstSize &&
+         epoch + trace->records[end].offset <= now) {
+    ++end;
+  }
+  uint16_t count = end - *pos;
+  if (count == 0) {
+    return 0;
+  }
+
+  struct rte_mbuf* pkts[MaxBurstSize];
+  int res = rte_pktmbuf_alloc_bulk(ct->interestMp, pkts, count);
+  if (unlikely(res != 0)) {
+    ++ct->nAllocError;
+    return 0;
+  }
+
+  for (uint16_t i = 0; i < count; ++i) {
+    const TgcTraceRecord* rec = &trace->records[*pos + i];
+    TgcTxPattern* pattern = &ct->pattern[rec->patternID];
+    ++pattern->nInterests;
+
+    LName suffix = (LName){.length = rec->suffixL, .value = &trace->suffixes[rec->suffixOffset]};
+    uint32_t nonce = pcg32_random_r(&ct->nonceRng);
+    Packet* npkt = InterestTemplate_Encode(&pattern->tpl, pkts[i], suffix, nonce);
+    TgcToken_Set(&Packet_GetLpL3Hdr(npkt)->pitToken, rec->patternID, ct->runNum, now);
+  }
+  N_LOGD("<I trace pos=%" PRIu32 " count=%" PRIu16, *pos, count);
+  Face_TxBurst(ct->face, (Packet**)pkts, count);
+  *pos = end;
+  return count;
+}
+
+__attribute__((nonnull)) static int
+TgcTx_RunTrace(TgcTx* ct, const TgcTrace* trace) {
+  TscTime epoch = rte_get_tsc_cycles();
+  uint32_t pos = 0;
+  uint16_t sent = 0;
+  while (ThreadCtrl_Continue(ct->ctrl, sent)) {
+    if (unlikely(pos == trace->nRecords)) {
+      if (!trace->loop) {
+        sent = 0;
+        continue;
+      }
+      pos = 0;
+      epoch += trace->period;
+    }
+    sent = TgcTx_TraceBurst(ct, trace, &pos, epoch);
+  }
+  return 0;
+}
+
 int
 TgcTx_Run(TgcTx* ct) {
+  if (ct->trace != NULL) {
+    return TgcTx_RunTrace(ct, ct->trace);
+  }
+
   TscTime nextTxBurst = rte_get_tsc_cycles();
   int sent = 0;
   while (ThreadCtrl_Continue(ct->ctrl, sent)) {
@@ -17,6 +17,8 @@ typedef struct TgcTxDigestPattern {
   CryptoQueuePair cqp;
   DataGen dataGen;
   LName prefix;
+  uint8_t (*digests)[ImplicitDigestLength]; ///< if not NULL, record implicit digests for verification
+  uint32_t digestsMask;                     ///< @c digests array size minus one
 } TgcTxDigestPattern;
 
 typedef uint16_t (*TgcTxPattern_MakeSuffix)(TgcTx* ct, uint8_t patternID, TgcTxPattern* pattern);
@@ -27,6 +29,25 @@ __attribute__((nonnull)) uint16_t
 TgcTxPattern_MakeSuffix_Offset(TgcTx* ct, uint8_t patternID, TgcTxPattern* pattern);
 __attribute__((nonnull)) uint16_t
 TgcTxPattern_MakeSuffix_Increment(TgcTx* ct, uint8_t patternID, TgcTxPattern* pattern);
+__attribute__((nonnull)) uint16_t
+TgcTxPattern_MakeSuffix_Zipf(TgcTx* ct, uint8_t patternID, TgcTxPattern* pattern);
+__attribute__((nonnull)) uint16_t
+TgcTxPattern_MakeSuffix_Uniform(TgcTx* ct, uint8_t patternID, TgcTxPattern* pattern);
+__attribute__((nonnull)) uint16_t
+TgcTxPattern_MakeSuffix_HotCold(TgcTx* ct, uint8_t patternID, TgcTxPattern* pattern);
+
+/**
+ * @brief Popularity-based name generation in traffic generator consumer.
+ *
+ * Catalog item @c i has sequence number <tt>seqNumBase + i</tt>.
+ */
+typedef struct TgcTxPopularity {
+  uint64_t seqNumBase;   ///< sequence number of the first catalog item
+  uint32_t catalogSize;  ///< number of catalog items
+  uint32_t hotSize;      ///< number of hot items, HotCold only
+  uint32_t hotThreshold; ///< hot set is selected if random value is below this, HotCold only
+  uint32_t zipfCdf[];    ///< cumulative probability in UINT32_MAX scale, Zipf only
+} TgcTxPopularity;
 
 /** @brief Per-pattern information in traffic generator consumer. */
 struct TgcTxPattern {
@@ -44,6 +65,7 @@ struct TgcTxPattern {
   union {
     TgcTxDigestPattern* digest;
     uint64_t seqNumOffset;
+    TgcTxPopularity* popularity;
   };
 
   InterestTemplate tpl;
@@ -53,6 +75,23 @@ static_assert(offsetof(TgcTxPattern, seqNumT) + TgcSeqNumSize + ImplicitDigestSi
                 offsetof(TgcTxPattern, digestV) + RTE_SIZEOF_FIELD(TgcTxPattern, digestV),
               "");
 
+/** @brief Trace record in traffic generator consumer. */
+typedef struct TgcTraceRecord {
+  TscDuration offset;    ///< TX time relative to start of trace
+  uint32_t suffixOffset; ///< name suffix position in TgcTrace.suffixes
+  uint16_t suffixL;      ///< name suffix length
+  uint8_t patternID;     ///< pattern that provides name prefix and Interest template
+} TgcTraceRecord;
+
+/** @brief Interest trace in traffic generator consumer. */
+typedef struct TgcTrace {
+  const uint8_t* suffixes; ///< name suffixes TLV-VALUE
+  TscDuration period;      ///< duration of one iteration
+  uint32_t nRecords;
+  bool loop; ///< whether to restart from the first record after the last record
+  TgcTraceRecord records[];
+} TgcTrace;
+
 /** @brief Traffic generator consumer TX thread. */
 struct TgcTx {
   ThreadCtrl ctrl;
@@ -61,6 +100,7 @@ struct TgcTx {
   uint8_t runNum;
   struct rte_mempool* interestMp;
   TscDuration burstInterval; ///< interval between two bursts
+  TgcTrace* trace;           ///< if not NULL, replay Interests from trace instead of patterns
 
   pcg32_random_t trafficRng;
   pcg32_random_t nonceRng;
@@ -0,0 +1,32 @@
+package ealthread
+
+import (
+	"strconv"
+
+	"github.com/usnistgov/ndn-dpdk/core/metrics"
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+)
+
+func init() {
+	metrics.Register("ealthread", func(c *metrics.Collection) {
+		for _, lc := range eal.Workers {
+			labels := metrics.Labels{
+				"lcore": strconv.Itoa(lc.ID()),
+				"role":  allocated[lc.ID()],
+			}
+			busy := 0.0
+			if lc.IsBusy() {
+				busy = 1.0
+			}
+			c.Add("worker_busy", "Whether the LCore is running.", metrics.Gauge, busy, labels)
+
+			thObj, ok := activeThread.Load(lc)
+			if !ok {
+				continue
+			}
+			if th, ok := thObj.(ThreadWithLoadStat); ok {
+				c.AddStruct("thread_", th.ThreadLoadStat(), labels)
+			}
+		}
+	})
+}
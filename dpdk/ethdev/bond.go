@@ -0,0 +1,138 @@
+package ethdev
+
+/*
+#include "../../csrc/dpdk/ethdev.h"
+#include <rte_eth_bond.h>
+#cgo LDFLAGS: -lrte_net_bond
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"unsafe"
+
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+	"go.uber.org/zap"
+)
+
+// BondMode indicates link aggregation mode.
+type BondMode string
+
+// BondMode values.
+const (
+	// BondActiveBackup transmits and receives on one member at a time.
+	// Another member takes over when the active member loses its link.
+	BondActiveBackup BondMode = "ACTIVE_BACKUP"
+
+	// BondBalance distributes outgoing frames among members by hashing L3 and L4 headers.
+	// The peer should aggregate the links as a static link aggregation group.
+	BondBalance BondMode = "BALANCE"
+
+	// BondLACP uses IEEE 802.3ad dynamic link aggregation.
+	// Outgoing frames are distributed among members by hashing L3 and L4 headers.
+	BondLACP BondMode = "LACP"
+)
+
+var bondModes = map[BondMode]C.uint8_t{
+	"":               C.BONDING_MODE_ACTIVE_BACKUP,
+	BondActiveBackup: C.BONDING_MODE_ACTIVE_BACKUP,
+	BondBalance:      C.BONDING_MODE_BALANCE,
+	BondLACP:         C.BONDING_MODE_8023AD,
+}
+
+// bondMembers maps from member EthDev to bonded EthDev.
+var (
+	bondMembers      = map[EthDev]EthDev{}
+	bondMembersMutex sync.RWMutex
+)
+
+// BondOf returns the bonded EthDev that contains dev as a member, or nil if dev is not a bond member.
+func BondOf(dev EthDev) EthDev {
+	bondMembersMutex.RLock()
+	defer bondMembersMutex.RUnlock()
+	return bondMembers[dev]
+}
+
+// NewBond creates a bonded EthDev via DPDK net_bonding driver.
+// Members must not be started; they are controlled by the bonded EthDev afterwards.
+// Closing the bonded EthDev releases the members, but does not close them.
+// The bonded EthDev initially uses the MAC address of the first member.
+//
+// In BondBalance and BondLACP modes, frames are distributed by hashing L3 and L4 headers.
+// Frames without IP headers, such as NDN over Ethernet, are always transmitted on the same member.
+func NewBond(members []EthDev, mode BondMode) (bond EthDev, e error) {
+	modeC, ok := bondModes[mode]
+	if !ok {
+		return nil, fmt.Errorf("unknown bonding mode %s", mode)
+	}
+	if len(members) == 0 {
+		return nil, errors.New("bonded EthDev requires at least one member")
+	}
+
+	bondMembersMutex.Lock()
+	defer bondMembersMutex.Unlock()
+	for i, member := range members {
+		if bondMembers[member] != nil {
+			return nil, fmt.Errorf("%s is already a member of %s", member, bondMembers[member])
+		}
+		if slices.Contains(members[:i], member) {
+			return nil, fmt.Errorf("%s is listed more than once", member)
+		}
+	}
+
+	name := "net_bonding" + eal.AllocObjectID("ethdev.Bond")
+	nameC := C.CString(name)
+	defer C.free(unsafe.Pointer(nameC))
+	logEntry := logger.With(
+		zap.String("name", name),
+		zap.String("mode", string(mode)),
+	)
+
+	res := C.rte_eth_bond_create(nameC, modeC, C.uint8_t(max(0, members[0].NumaSocket().ID())))
+	if res < 0 {
+		e = eal.MakeErrno(res)
+		logEntry.Error("rte_eth_bond_create error", zap.Error(e))
+		return nil, e
+	}
+	dev := ethDev(res)
+	defer func() {
+		if e != nil {
+			C.rte_eth_bond_free(nameC)
+		}
+	}()
+
+	if modeC != C.BONDING_MODE_ACTIVE_BACKUP {
+		if res := C.rte_eth_bond_xmit_policy_set(dev.cID(), C.BALANCE_XMIT_POLICY_LAYER34); res != 0 {
+			e = eal.MakeErrno(res)
+			logEntry.Error("rte_eth_bond_xmit_policy_set error", zap.Error(e))
+			return nil, e
+		}
+	}
+
+	for _, member := range members {
+		if res := C.rte_eth_bond_member_add(dev.cID(), member.(ethDev).cID()); res != 0 {
+			e = eal.MakeErrno(res)
+			logEntry.Error("rte_eth_bond_member_add error", member.ZapField("member"), zap.Error(e))
+			return nil, e
+		}
+	}
+
+	for _, member := range members {
+		bondMembers[member] = dev
+	}
+	OnClose(dev, func() {
+		bondMembersMutex.Lock()
+		for _, member := range members {
+			delete(bondMembers, member)
+		}
+		bondMembersMutex.Unlock()
+		nameC := C.CString(name)
+		defer C.free(unsafe.Pointer(nameC))
+		C.rte_eth_bond_free(nameC)
+	})
+
+	logEntry.Info("bonded ethdev created", dev.ZapField("id"), zap.Stringers("members", members))
+	return dev, nil
+}
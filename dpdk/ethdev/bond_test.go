@@ -0,0 +1,50 @@
+package ethdev_test
+
+import (
+	"testing"
+
+	"github.com/usnistgov/ndn-dpdk/dpdk/ethdev"
+	"github.com/usnistgov/ndn-dpdk/dpdk/ethdev/ethringdev"
+)
+
+func TestBond(t *testing.T) {
+	assert, require := makeAR(t)
+
+	pair, e := ethringdev.NewPair(ethringdev.PairConfig{RxPool: directMp})
+	require.NoError(e)
+	defer pair.Close()
+	portA, portB := pair.PortA, pair.PortB
+
+	_, e = ethdev.NewBond([]ethdev.EthDev{portA}, "ROUND_ROBIN")
+	assert.Error(e)
+	_, e = ethdev.NewBond(nil, ethdev.BondActiveBackup)
+	assert.Error(e)
+	_, e = ethdev.NewBond([]ethdev.EthDev{portA, portA}, ethdev.BondActiveBackup)
+	assert.Error(e)
+	assert.Nil(ethdev.BondOf(portA))
+
+	bond, e := ethdev.NewBond([]ethdev.EthDev{portA, portB}, ethdev.BondBalance)
+	require.NoError(e)
+	assert.Contains(ethdev.List(), bond)
+	assert.Equal(bond, ethdev.BondOf(portA))
+	assert.Equal(bond, ethdev.BondOf(portB))
+	assert.Nil(ethdev.BondOf(bond))
+	assert.Equal(portA.HardwareAddr(), bond.HardwareAddr())
+	assert.Equal(bond, ethdev.FromHardwareAddr(portA.HardwareAddr()))
+
+	_, e = ethdev.NewBond([]ethdev.EthDev{portB}, ethdev.BondActiveBackup)
+	assert.Error(e)
+	assert.Equal(bond, ethdev.BondOf(portB))
+
+	assert.NoError(bond.Close())
+	assert.Nil(ethdev.BondOf(portA))
+	assert.Nil(ethdev.BondOf(portB))
+	assert.NotContains(ethdev.List(), bond)
+
+	bond, e = ethdev.NewBond([]ethdev.EthDev{portB}, ethdev.BondActiveBackup)
+	require.NoError(e)
+	assert.Nil(ethdev.BondOf(portA))
+	assert.Equal(bond, ethdev.BondOf(portB))
+	assert.NoError(bond.Close())
+	assert.Nil(ethdev.BondOf(portB))
+}
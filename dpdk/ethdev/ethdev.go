@@ -187,11 +187,13 @@ func (dev ethDev) Start(cfg Config) error {
 		return bail("rte_eth_dev_start", res)
 	}
 
+	startLinkWatch(dev)
 	logEntry.Info("ethdev started")
 	return nil
 }
 
 func (dev ethDev) stop(close bool) error {
+	stopLinkWatch(dev)
 	if C.rte_eth_dev_is_valid_port(dev.cID()) == 0 { // already detached
 		return nil
 	}
@@ -298,10 +300,11 @@ func FromName(name string) EthDev {
 }
 
 // FromHardwareAddr returns the first EthDev with specified MAC address.
+// Members of a bonded EthDev are skipped, because they may share the MAC address of the bonded EthDev.
 func FromHardwareAddr(a net.HardwareAddr) EthDev {
 	for p := C.rte_eth_find_next(0); p < C.RTE_MAX_ETHPORTS; p = C.rte_eth_find_next(p + 1) {
 		dev := ethDev(p)
-		if bytes.Equal(dev.HardwareAddr(), a) {
+		if BondOf(dev) == nil && bytes.Equal(dev.HardwareAddr(), a) {
 			return dev
 		}
 	}
@@ -12,6 +12,7 @@ import (
 var (
 	GqlDriverKindEnum   *graphql.Enum
 	GqlConfigFieldTypes gqlserver.FieldTypes
+	GqlConfigInput      *graphql.InputObject
 )
 
 func init() {
@@ -22,4 +23,10 @@ func init() {
 		reflect.TypeFor[pciaddr.PCIAddress](): graphql.String,
 		reflect.TypeFor[map[string]any]():     gqlserver.JSON,
 	}
+
+	GqlConfigInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "NetifConfigInput",
+		Description: "Network interface selection and EthDev creation arguments.",
+		Fields:      gqlserver.BindInputFields[Config](GqlConfigFieldTypes),
+	})
 }
@@ -1,6 +1,9 @@
 package ethdev
 
 import (
+	"sync"
+	"time"
+
 	"github.com/usnistgov/ndn-dpdk/core/events"
 )
 
@@ -11,3 +14,54 @@ var closeEmitter = events.NewEmitter()
 func OnClose(dev EthDev, cb func()) (cancel func()) {
 	return closeEmitter.Once(dev.ID(), cb)
 }
+
+// LinkPollInterval is the interval of polling link status of started ports.
+// Link status changes are published as events.TypeEthLinkUp and events.TypeEthLinkDown.
+var LinkPollInterval = time.Second
+
+var (
+	linkWatchLock sync.Mutex
+	linkWatchers  = map[int]chan struct{}{}
+)
+
+// startLinkWatch starts polling link status of a started port.
+func startLinkWatch(dev EthDev) {
+	stopLinkWatch(dev)
+
+	linkWatchLock.Lock()
+	defer linkWatchLock.Unlock()
+	stop := make(chan struct{})
+	linkWatchers[dev.ID()] = stop
+
+	go func() {
+		ticker := time.NewTicker(LinkPollInterval)
+		defer ticker.Stop()
+		isDown := dev.IsDown()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			if down := dev.IsDown(); down != isDown {
+				isDown = down
+				evt := events.Event{Type: events.TypeEthLinkUp, Subject: dev.Name()}
+				if isDown {
+					evt.Type = events.TypeEthLinkDown
+				}
+				events.Publish(evt)
+			}
+		}
+	}()
+}
+
+// stopLinkWatch stops polling link status of a port.
+func stopLinkWatch(dev EthDev) {
+	linkWatchLock.Lock()
+	defer linkWatchLock.Unlock()
+	if stop := linkWatchers[dev.ID()]; stop != nil {
+		close(stop)
+		delete(linkWatchers, dev.ID())
+	}
+}
@@ -11,7 +11,8 @@ import (
 
 // GraphQL types.
 var (
-	GqlEthDevType *gqlserver.NodeType[EthDev]
+	GqlEthDevType   *gqlserver.NodeType[EthDev]
+	GqlBondModeEnum *graphql.Enum
 )
 
 func init() {
@@ -118,6 +119,30 @@ func init() {
 		RetrieveInt: FromID,
 	})
 
+	GqlBondModeEnum = gqlserver.NewStringEnum("EthBondMode", "Link aggregation mode.", BondActiveBackup, BondBalance, BondLACP)
+	GqlEthDevType.Object.AddFieldConfig("bond", &graphql.Field{
+		Type:        GqlEthDevType.Object,
+		Description: "Bonded Ethernet device that contains this device as a member.",
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			port := p.Source.(EthDev)
+			return BondOf(port), nil
+		},
+	})
+	GqlEthDevType.Object.AddFieldConfig("bondMembers", &graphql.Field{
+		Type:        gqlserver.NewListNonNullElem(GqlEthDevType.Object),
+		Description: "Member Ethernet devices of a bonded Ethernet device.",
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			port := p.Source.(EthDev)
+			list := []EthDev{}
+			for _, member := range List() {
+				if BondOf(member) == port {
+					list = append(list, member)
+				}
+			}
+			return list, nil
+		},
+	})
+
 	gqlserver.AddQuery(&graphql.Field{
 		Name:        "ethDevs",
 		Description: "List of Ethernet devices.",
@@ -0,0 +1,35 @@
+package ethdev
+
+import (
+	"strconv"
+
+	"github.com/usnistgov/ndn-dpdk/core/metrics"
+)
+
+func init() {
+	metrics.Register("ethdev", func(c *metrics.Collection) {
+		for _, port := range List() {
+			labels := metrics.Labels{
+				"port": strconv.Itoa(port.ID()),
+				"name": port.Name(),
+			}
+			stats := port.Stats()
+			for _, m := range []struct {
+				name  string
+				help  string
+				value uint64
+			}{
+				{"ethdev_rx_packets", "RX successful packets.", uint64(stats.Ipackets)},
+				{"ethdev_rx_octets", "RX successful bytes.", uint64(stats.Ibytes)},
+				{"ethdev_rx_missed", "RX packets dropped by hardware.", uint64(stats.Imissed)},
+				{"ethdev_rx_errors", "RX erroneous packets.", uint64(stats.Ierrors)},
+				{"ethdev_rx_nombuf", "RX mbuf allocation failures.", uint64(stats.Rx_nombuf)},
+				{"ethdev_tx_packets", "TX successful packets.", uint64(stats.Opackets)},
+				{"ethdev_tx_octets", "TX successful bytes.", uint64(stats.Obytes)},
+				{"ethdev_tx_errors", "TX failed packets.", uint64(stats.Oerrors)},
+			} {
+				c.Add(m.name, m.help, metrics.Counter, float64(m.value), labels)
+			}
+		}
+	})
+}
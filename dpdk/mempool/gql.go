@@ -9,6 +9,7 @@ package mempool
 import "C"
 import (
 	"errors"
+	"reflect"
 	"unsafe"
 
 	"github.com/graphql-go/graphql"
@@ -19,7 +20,10 @@ import (
 
 // GraphQL types.
 var (
-	GqlMemoryDiagType *graphql.Object
+	GqlMemoryDiagType    *graphql.Object
+	GqlLevelEnum         *graphql.Enum
+	GqlResourceUsageType *graphql.Object
+	GqlSocketHealthType  *graphql.Object
 )
 
 func makeFileDumpResolver(f func(fp *C.FILE)) graphql.FieldResolveFn {
@@ -74,4 +78,35 @@ func init() {
 			return "", nil
 		},
 	})
+
+	GqlLevelEnum = gqlserver.NewStringEnum("ResourceLevel", "Resource utilization level.", LevelNormal, LevelHigh, LevelExhausted)
+	levelFieldTypes := gqlserver.FieldTypes{
+		reflect.TypeFor[Level](): GqlLevelEnum,
+	}
+	GqlResourceUsageType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "ResourceUsage",
+		Description: "Utilization of a monitored mempool.",
+		Fields:      gqlserver.BindFields[ResourceUsage](levelFieldTypes),
+	})
+	GqlResourceUsageType.AddFieldConfig("numaSocket", eal.GqlWithNumaSocket)
+
+	levelFieldTypes[reflect.TypeFor[ResourceUsage]()] = GqlResourceUsageType
+	GqlSocketHealthType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "SocketHealth",
+		Description: "Resource headroom on a NUMA socket.",
+		Fields:      gqlserver.BindFields[SocketHealth](levelFieldTypes),
+	})
+	GqlSocketHealthType.AddFieldConfig("numaSocket", eal.GqlWithNumaSocket)
+
+	gqlserver.AddQuery(&graphql.Field{
+		Name:        "health",
+		Description: "Resource headroom of packet buffer mempools and PCCTs, summarized per NUMA socket.",
+		Type:        gqlserver.NewListNonNullBoth(GqlSocketHealthType),
+		Resolve: func(graphql.ResolveParams) (any, error) {
+			if eal.MainThread == nil {
+				return nil, errors.New("EAL not ready")
+			}
+			return Health(), nil
+		},
+	})
 }
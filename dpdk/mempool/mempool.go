@@ -4,6 +4,28 @@ package mempool
 /*
 #include "../../csrc/core/common.h"
 #include <rte_mempool.h>
+
+typedef struct MempoolList {
+	struct rte_mempool** list;
+	uint32_t count;
+	uint32_t capacity;
+} MempoolList;
+
+static void
+MempoolList_Append(struct rte_mempool* mp, void* arg) {
+	MempoolList* ml = arg;
+	if (ml->count < ml->capacity) {
+		ml->list[ml->count] = mp;
+	}
+	++ml->count;
+}
+
+static uint32_t
+MempoolList_Walk(struct rte_mempool** list, uint32_t capacity) {
+	MempoolList ml = {.list = list, .capacity = capacity};
+	rte_mempool_walk(MempoolList_Append, &ml);
+	return ml.count;
+}
 */
 import "C"
 import (
@@ -76,6 +98,16 @@ func (mp *Mempool) SizeofElement() int {
 	return int(mp.elt_size)
 }
 
+// Capacity returns maximum number of objects.
+func (mp *Mempool) Capacity() int {
+	return int(mp.size)
+}
+
+// NumaSocket returns the NUMA socket where memory is allocated.
+func (mp *Mempool) NumaSocket() eal.NumaSocket {
+	return eal.NumaSocketFromID(int(mp.socket_id))
+}
+
 // CountAvailable returns number of available objects.
 func (mp *Mempool) CountAvailable() int {
 	return int(C.rte_mempool_avail_count(mp.ptr()))
@@ -114,6 +146,21 @@ func FromPtr(ptr unsafe.Pointer) *Mempool {
 	return (*Mempool)(ptr)
 }
 
+// List returns a list of existing mempools.
+func List() []*Mempool {
+	for {
+		n := C.MempoolList_Walk(nil, 0)
+		if n == 0 {
+			return nil
+		}
+		list := make([]*Mempool, n)
+		if m := C.MempoolList_Walk(cptr.FirstPtr[*C.struct_rte_mempool](list), n); m == n {
+			return list
+		}
+		// mempool created or freed concurrently, try again
+	}
+}
+
 // Alloc allocates several objects.
 func Alloc[T any, A ~[]T](mp *Mempool, objs A) error {
 	if len(objs) == 0 {
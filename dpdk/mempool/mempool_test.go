@@ -1,9 +1,13 @@
 package mempool_test
 
 import (
+	"sync"
 	"testing"
+	"time"
 	"unsafe"
 
+	"github.com/usnistgov/ndn-dpdk/core/events"
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
 	"github.com/usnistgov/ndn-dpdk/dpdk/mempool"
 )
 
@@ -40,3 +44,78 @@ func TestMempool(t *testing.T) {
 	mempool.Free(mp, objs[1:30])
 	assert.Equal(63, mp.CountAvailable())
 }
+
+func TestMonitor(t *testing.T) {
+	assert, require := makeAR(t)
+
+	mp, e := mempool.New(mempool.Config{
+		Capacity:    63,
+		ElementSize: 64,
+		Socket:      eal.NumaSocketFromID(0),
+	})
+	require.NoError(e)
+	defer mp.Close()
+
+	defer mempool.AddMonitorSource(mempool.MonitorSource{
+		Kind:           "TEST",
+		PressureEvent:  events.TypeMempoolPressure,
+		ExhaustedEvent: events.TypeMempoolExhausted,
+		Walk: func(cb func(name string, mp *mempool.Mempool)) {
+			cb("MONITORTEST", mp)
+		},
+	})()
+	findUsage := func() (u mempool.ResourceUsage) {
+		for _, h := range mempool.Health() {
+			for _, u := range h.Resources {
+				if u.Name == "MONITORTEST" {
+					return u
+				}
+			}
+		}
+		require.FailNow("MONITORTEST not found")
+		return
+	}
+
+	var received []events.Event
+	var receivedLock sync.Mutex
+	defer events.Subscribe(func(evt events.Event) {
+		if evt.Subject == "MONITORTEST" {
+			receivedLock.Lock()
+			defer receivedLock.Unlock()
+			received = append(received, evt)
+		}
+	})()
+	mempool.StartMonitor(mempool.MonitorConfig{
+		Interval:       10,
+		HighWaterMarks: map[string]float64{"MONITORTEST": 0.5, "TEST": 0.2},
+	})
+	defer mempool.StopMonitor()
+
+	assert.Equal(mempool.LevelNormal, findUsage().Level)
+
+	var objs [63]unsafe.Pointer
+	require.NoError(mempool.Alloc(mp, objs[:40]))
+	u := findUsage()
+	assert.Equal(mempool.LevelHigh, u.Level)
+	assert.Equal(40, u.InUse)
+	assert.InDelta(0.5, u.HighWaterMark, 0.001)
+	time.Sleep(50 * time.Millisecond)
+
+	require.NoError(mempool.Alloc(mp, objs[40:]))
+	assert.Equal(mempool.LevelExhausted, findUsage().Level)
+	time.Sleep(50 * time.Millisecond)
+
+	mempool.Free(mp, objs[:])
+	assert.Equal(mempool.LevelNormal, findUsage().Level)
+	time.Sleep(50 * time.Millisecond)
+
+	receivedLock.Lock()
+	defer receivedLock.Unlock()
+	if assert.Len(received, 3) {
+		assert.Equal(events.TypeMempoolPressure, received[0].Type)
+		assert.Equal(mempool.LevelHigh, received[0].Details["level"])
+		assert.Equal(events.TypeMempoolExhausted, received[1].Type)
+		assert.Equal(events.TypeMempoolPressure, received[2].Type)
+		assert.Equal(mempool.LevelNormal, received[2].Details["level"])
+	}
+}
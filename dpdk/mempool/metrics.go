@@ -0,0 +1,23 @@
+package mempool
+
+import (
+	"github.com/usnistgov/ndn-dpdk/core/metrics"
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+)
+
+func init() {
+	metrics.Register("mempool", func(c *metrics.Collection) {
+		if eal.MainThread == nil {
+			return
+		}
+		for _, mp := range List() {
+			labels := metrics.Labels{
+				"mempool": mp.String(),
+				"socket":  mp.NumaSocket().String(),
+			}
+			c.Add("mempool_capacity", "Maximum number of objects.", metrics.Gauge, float64(mp.Capacity()), labels)
+			c.Add("mempool_in_use", "Number of allocated objects.", metrics.Gauge, float64(mp.CountInUse()), labels)
+			c.Add("mempool_available", "Number of available objects.", metrics.Gauge, float64(mp.CountAvailable()), labels)
+		}
+	})
+}
@@ -0,0 +1,315 @@
+package mempool
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/core/events"
+	"github.com/usnistgov/ndn-dpdk/core/logging"
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+	"go.uber.org/zap"
+)
+
+var logger = logging.New("mempool")
+
+// Level indicates resource utilization level.
+type Level string
+
+// Level values.
+const (
+	LevelNormal    Level = "NORMAL"
+	LevelHigh      Level = "HIGH"
+	LevelExhausted Level = "EXHAUSTED"
+)
+
+func (lvl Level) severity() int {
+	switch lvl {
+	case LevelHigh:
+		return 1
+	case LevelExhausted:
+		return 2
+	}
+	return 0
+}
+
+// Default monitoring parameters.
+const (
+	DefaultMonitorInterval = 1000
+	DefaultHighWaterMark   = 0.9
+)
+
+// MonitorConfig contains resource monitoring configuration.
+type MonitorConfig struct {
+	// Interval is the polling interval.
+	// Default is 1 second.
+	Interval nnduration.Milliseconds `json:"interval,omitempty"`
+
+	// HighWaterMark is the utilization ratio, between 0.0 and 1.0, at or above which a resource is under pressure.
+	// Default is 0.9.
+	HighWaterMark float64 `json:"highWaterMark,omitempty"`
+
+	// HighWaterMarks overrides HighWaterMark for specific resources.
+	// Each key is a resource name, such as "DIRECT", or a resource kind, such as "PCCT".
+	// If both match, the resource name takes priority.
+	HighWaterMarks map[string]float64 `json:"highWaterMarks,omitempty"`
+}
+
+func (cfg MonitorConfig) highWaterMark(kind, name string) float64 {
+	for _, key := range []string{name, kind} {
+		if hwm, ok := cfg.HighWaterMarks[key]; ok && hwm > 0 {
+			return hwm
+		}
+	}
+	if cfg.HighWaterMark > 0 {
+		return cfg.HighWaterMark
+	}
+	return DefaultHighWaterMark
+}
+
+// MonitorSource provides mempools to be monitored.
+type MonitorSource struct {
+	// Kind identifies the kind of resources, such as "PKTMBUF" or "PCCT".
+	Kind string
+
+	// PressureEvent is the event type published when utilization level changes.
+	PressureEvent events.Type
+
+	// ExhaustedEvent is the event type published when a resource becomes exhausted.
+	ExhaustedEvent events.Type
+
+	// Walk invokes cb on each monitored mempool.
+	// The mempool must not be freed while cb is running.
+	Walk func(cb func(name string, mp *Mempool))
+}
+
+var (
+	monitorLock    sync.Mutex
+	monitorSources []*MonitorSource
+	monitorCfg     MonitorConfig
+	monitorStop    chan struct{}
+	monitorLevels  = map[*Mempool]Level{}
+)
+
+// AddMonitorSource registers a source of monitored mempools.
+// Returns a function that unregisters the source.
+// It must be called before any mempool walked by the source is freed, unless the source stops walking that mempool.
+func AddMonitorSource(src MonitorSource) (remove func()) {
+	monitorLock.Lock()
+	defer monitorLock.Unlock()
+	ptr := &src
+	monitorSources = append(monitorSources, ptr)
+	return func() {
+		monitorLock.Lock()
+		defer monitorLock.Unlock()
+		monitorSources = slices.DeleteFunc(monitorSources, func(s *MonitorSource) bool { return s == ptr })
+	}
+}
+
+// ResourceUsage describes utilization of a monitored mempool.
+type ResourceUsage struct {
+	Kind          string         `json:"kind" gqldesc:"Resource kind."`
+	Name          string         `json:"name" gqldesc:"Resource name."`
+	Mempool       string         `json:"mempool" gqldesc:"Mempool name."`
+	Socket        eal.NumaSocket `json:"-"`
+	Capacity      int            `json:"capacity" gqldesc:"Maximum number of objects."`
+	InUse         int            `json:"inUse" gqldesc:"Number of allocated objects."`
+	Available     int            `json:"available" gqldesc:"Number of available objects."`
+	Utilization   float64        `json:"utilization" gqldesc:"Ratio of allocated objects to capacity."`
+	HighWaterMark float64        `json:"highWaterMark" gqldesc:"Utilization ratio at or above which the resource is under pressure."`
+	Level         Level          `json:"level" gqldesc:"Utilization level."`
+}
+
+// NumaSocket implements eal.WithNumaSocket interface.
+func (u ResourceUsage) NumaSocket() eal.NumaSocket {
+	return u.Socket
+}
+
+func makeResourceUsage(kind, name string, mp *Mempool, hwm float64) (u ResourceUsage) {
+	u = ResourceUsage{
+		Kind:          kind,
+		Name:          name,
+		Mempool:       mp.String(),
+		Socket:        mp.NumaSocket(),
+		Capacity:      mp.Capacity(),
+		InUse:         mp.CountInUse(),
+		Available:     mp.CountAvailable(),
+		HighWaterMark: hwm,
+		Level:         LevelNormal,
+	}
+	if u.Capacity > 0 {
+		u.Utilization = float64(u.InUse) / float64(u.Capacity)
+	}
+
+	switch {
+	case u.Available == 0:
+		u.Level = LevelExhausted
+	case u.Utilization >= hwm:
+		u.Level = LevelHigh
+	}
+	return u
+}
+
+type usageWithSource struct {
+	ResourceUsage
+	mp  *Mempool
+	src *MonitorSource
+}
+
+func collectUsage(cfg MonitorConfig) (list []usageWithSource) {
+	for _, src := range monitorSources {
+		src.Walk(func(name string, mp *Mempool) {
+			list = append(list, usageWithSource{
+				ResourceUsage: makeResourceUsage(src.Kind, name, mp, cfg.highWaterMark(src.Kind, name)),
+				mp:            mp,
+				src:           src,
+			})
+		})
+	}
+	return list
+}
+
+// SocketHealth summarizes resource headroom on a NUMA socket.
+type SocketHealth struct {
+	Socket    eal.NumaSocket  `json:"-"`
+	Level     Level           `json:"level" gqldesc:"Highest utilization level among resources."`
+	Headroom  float64         `json:"headroom" gqldesc:"Lowest ratio of available objects to capacity among resources."`
+	Resources []ResourceUsage `json:"resources" gqldesc:"Monitored resources."`
+}
+
+// NumaSocket implements eal.WithNumaSocket interface.
+func (h SocketHealth) NumaSocket() eal.NumaSocket {
+	return h.Socket
+}
+
+// Health summarizes resource headroom per NUMA socket.
+func Health() (list []SocketHealth) {
+	monitorLock.Lock()
+	defer monitorLock.Unlock()
+
+	bySocket := map[eal.NumaSocket]*SocketHealth{}
+	for _, u := range collectUsage(monitorCfg) {
+		h := bySocket[u.Socket]
+		if h == nil {
+			h = &SocketHealth{
+				Socket:   u.Socket,
+				Level:    LevelNormal,
+				Headroom: 1,
+			}
+			bySocket[u.Socket] = h
+		}
+		if u.Level.severity() > h.Level.severity() {
+			h.Level = u.Level
+		}
+		h.Headroom = min(h.Headroom, 1-u.Utilization)
+		h.Resources = append(h.Resources, u.ResourceUsage)
+	}
+
+	for _, h := range bySocket {
+		list = append(list, *h)
+	}
+	slices.SortFunc(list, func(a, b SocketHealth) int { return cmp.Compare(a.Socket.ID(), b.Socket.ID()) })
+	return list
+}
+
+// StartMonitor starts or reconfigures periodic resource monitoring.
+// When utilization level of a resource changes, it logs a message and publishes an event.
+func StartMonitor(cfg MonitorConfig) {
+	monitorLock.Lock()
+	defer monitorLock.Unlock()
+	if monitorStop != nil {
+		close(monitorStop)
+	}
+	monitorCfg = cfg
+	monitorStop = make(chan struct{})
+
+	go func(stop <-chan struct{}) {
+		ticker := time.NewTicker(cfg.Interval.DurationOr(DefaultMonitorInterval))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				pollMonitor(stop)
+			}
+		}
+	}(monitorStop)
+}
+
+// StopMonitor stops periodic resource monitoring.
+func StopMonitor() {
+	monitorLock.Lock()
+	defer monitorLock.Unlock()
+	if monitorStop != nil {
+		close(monitorStop)
+		monitorStop = nil
+	}
+	clear(monitorLevels)
+}
+
+func pollMonitor(stop <-chan struct{}) {
+	monitorLock.Lock()
+	defer monitorLock.Unlock()
+	select {
+	case <-stop:
+		return
+	default:
+	}
+
+	seen := map[*Mempool]bool{}
+	for _, u := range collectUsage(monitorCfg) {
+		seen[u.mp] = true
+		prev, ok := monitorLevels[u.mp]
+		if !ok {
+			prev = LevelNormal
+		}
+		monitorLevels[u.mp] = u.Level
+		if u.Level == prev {
+			continue
+		}
+
+		logEntry := logger.With(
+			zap.String("kind", u.Kind),
+			zap.String("name", u.Name),
+			zap.String("mempool", u.Mempool),
+			u.Socket.ZapField("socket"),
+			zap.Int("capacity", u.Capacity),
+			zap.Int("in-use", u.InUse),
+			zap.Float64("high-water-mark", u.HighWaterMark),
+			zap.String("prev-level", string(prev)),
+			zap.String("level", string(u.Level)),
+		)
+		if u.Level.severity() > prev.severity() {
+			logEntry.Warn("resource pressure increased")
+		} else {
+			logEntry.Info("resource pressure decreased")
+		}
+
+		evt := events.Event{
+			Type:    u.src.PressureEvent,
+			Subject: u.Name,
+			Details: map[string]any{
+				"kind":        u.Kind,
+				"mempool":     u.Mempool,
+				"socket":      u.Socket,
+				"capacity":    u.Capacity,
+				"inUse":       u.InUse,
+				"utilization": u.Utilization,
+				"level":       u.Level,
+			},
+		}
+		if u.Level == LevelExhausted {
+			evt.Type = u.src.ExhaustedEvent
+		}
+		events.Publish(evt)
+	}
+
+	for mp := range monitorLevels {
+		if !seen[mp] {
+			delete(monitorLevels, mp)
+		}
+	}
+}
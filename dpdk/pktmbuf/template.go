@@ -6,8 +6,11 @@ package pktmbuf
 import "C"
 import (
 	"strings"
+	"sync"
 
+	"github.com/usnistgov/ndn-dpdk/core/events"
 	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+	"github.com/usnistgov/ndn-dpdk/dpdk/mempool"
 	"go.uber.org/zap"
 )
 
@@ -56,9 +59,10 @@ type Template interface {
 }
 
 type template struct {
-	id    string
-	cfg   PoolConfig
-	pools map[eal.NumaSocket]*Pool
+	id        string
+	cfg       PoolConfig
+	poolsLock sync.Mutex
+	pools     map[eal.NumaSocket]*Pool
 }
 
 func (tpl *template) ID() string {
@@ -99,6 +103,8 @@ func (tpl *template) Update(update PoolConfig) Template {
 }
 
 func (tpl *template) Pools() (list []PoolInfo) {
+	tpl.poolsLock.Lock()
+	defer tpl.poolsLock.Unlock()
 	for socket, pool := range tpl.pools {
 		list = append(list, PoolInfo{Pool: pool, socket: socket})
 	}
@@ -106,6 +112,8 @@ func (tpl *template) Pools() (list []PoolInfo) {
 }
 
 func (tpl *template) Get(socket eal.NumaSocket) *Pool {
+	tpl.poolsLock.Lock()
+	defer tpl.poolsLock.Unlock()
 	logEntry := logger.With(zap.String("template", tpl.id))
 
 	useSocket := socket
@@ -183,6 +191,19 @@ func init() {
 	Indirect = RegisterTemplate("INDIRECT", PoolConfig{
 		Capacity: 1048575,
 	})
+
+	mempool.AddMonitorSource(mempool.MonitorSource{
+		Kind:           "PKTMBUF",
+		PressureEvent:  events.TypeMempoolPressure,
+		ExhaustedEvent: events.TypeMempoolExhausted,
+		Walk: func(cb func(name string, mp *mempool.Mempool)) {
+			for id, tpl := range templates {
+				for _, pool := range tpl.Pools() {
+					cb(id, &pool.Mempool)
+				}
+			}
+		},
+	})
 }
 
 // TemplateUpdates contains updates to several mempool templates.
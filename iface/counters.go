@@ -7,6 +7,7 @@ import "C"
 import (
 	"fmt"
 	"reflect"
+	"slices"
 
 	"github.com/usnistgov/ndn-dpdk/ndni"
 )
@@ -22,11 +23,12 @@ type RxCounters struct {
 	RxDecodeErrs   uint64 `json:"rxDecodeErrs" gqldesc:"RX decode errors."`
 	RxReassPackets uint64 `json:"rxReassPackets" gqldesc:"RX packets that were reassembled."`
 	RxReassDrops   uint64 `json:"rxReassDrops" gqldesc:"RX frames that were dropped by reassembler."`
+	RxIdles        uint64 `json:"rxIdles" gqldesc:"RX NDNLPv2 IDLE packets."`
 }
 
 func (cnt RxCounters) String() string {
-	return fmt.Sprintf("%dfrm %db %dI %dD %dN %derr reass=(%dpkt %ddrop)",
-		cnt.RxFrames, cnt.RxOctets, cnt.RxInterests, cnt.RxData, cnt.RxNacks, cnt.RxDecodeErrs, cnt.RxReassPackets, cnt.RxReassDrops)
+	return fmt.Sprintf("%dfrm %db %dI %dD %dN %didle %derr reass=(%dpkt %ddrop)",
+		cnt.RxFrames, cnt.RxOctets, cnt.RxInterests, cnt.RxData, cnt.RxNacks, cnt.RxIdles, cnt.RxDecodeErrs, cnt.RxReassPackets, cnt.RxReassDrops)
 }
 
 func (cnt *RxCounters) readFrom(c *C.FaceRxThread) {
@@ -38,8 +40,9 @@ func (cnt *RxCounters) readFrom(c *C.FaceRxThread) {
 	cnt.RxDecodeErrs = uint64(c.nDecodeErr)
 	cnt.RxReassPackets = uint64(c.reass.nDeliverPackets)
 	cnt.RxReassDrops = uint64(c.reass.nDropFragments)
+	cnt.RxIdles = uint64(c.nIdles)
 
-	cnt.RxFrames = cnt.RxInterests + cnt.RxData + cnt.RxNacks - cnt.RxReassPackets + uint64(c.reass.nDeliverFragments) + cnt.RxReassDrops
+	cnt.RxFrames = cnt.RxInterests + cnt.RxData + cnt.RxNacks - cnt.RxReassPackets + uint64(c.reass.nDeliverFragments) + cnt.RxReassDrops + cnt.RxIdles
 }
 
 // TxCounters contains face/queue TX counters.
@@ -54,11 +57,18 @@ type TxCounters struct {
 	TxFragBad   uint64 `json:"txFragBad" gqldesc:"TX fragmentation failures."`
 	TxAllocErrs uint64 `json:"txAllocErrs" gqldesc:"TX allocation errors."`
 	TxDropped   uint64 `json:"txDropped" gqldesc:"TX dropped L2 frames due to full queue."`
+	TxIdles     uint64 `json:"txIdles" gqldesc:"TX NDNLPv2 IDLE packets."`
+	TxRejects   uint64 `json:"txRejects" gqldesc:"TX L3 packets rejected due to full output queue."`
+
+	TxShaperThrottles uint64 `json:"txShaperThrottles" gqldesc:"TX iterations deferred by traffic shaper."`
+	TxSchedDrops      uint64 `json:"txSchedDrops" gqldesc:"TX L3 packets dropped due to full scheduler queue."`
+	TxCoDelMarks      uint64 `json:"txCoDelMarks" gqldesc:"TX L3 packets congestion-marked by CoDel."`
 }
 
 func (cnt TxCounters) String() string {
-	return fmt.Sprintf("%dfrm %db %dI %dD %dN frag=(%dgood %dbad) alloc=%derr %ddropped",
-		cnt.TxFrames, cnt.TxOctets, cnt.TxInterests, cnt.TxData, cnt.TxNacks, cnt.TxFragGood, cnt.TxFragBad, cnt.TxAllocErrs, cnt.TxDropped)
+	return fmt.Sprintf("%dfrm %db %dI %dD %dN %didle %drej frag=(%dgood %dbad) alloc=%derr %ddropped shaper=(%dthrottle %ddrop %dmark)",
+		cnt.TxFrames, cnt.TxOctets, cnt.TxInterests, cnt.TxData, cnt.TxNacks, cnt.TxIdles, cnt.TxRejects, cnt.TxFragGood, cnt.TxFragBad, cnt.TxAllocErrs, cnt.TxDropped,
+		cnt.TxShaperThrottles, cnt.TxSchedDrops, cnt.TxCoDelMarks)
 }
 
 func (cnt *TxCounters) readFrom(c *C.FaceTxThread) {
@@ -72,6 +82,7 @@ func (cnt *TxCounters) readFrom(c *C.FaceTxThread) {
 	cnt.TxFragBad = uint64(c.nL3OverLength + c.nAllocFails)
 	cnt.TxAllocErrs = uint64(c.nAllocFails)
 	cnt.TxDropped = uint64(c.nDroppedFrames)
+	cnt.TxIdles = uint64(c.nIdles)
 }
 
 // Counters contains face counters.
@@ -80,27 +91,23 @@ type Counters struct {
 	TxCounters
 
 	RxThreads []RxCounters `json:"rxThreads"`
+	TxThreads []TxCounters `json:"txThreads"`
 }
 
 func (cnt Counters) String() string {
 	return fmt.Sprintf("RX %s TX %s", cnt.RxCounters, cnt.TxCounters)
 }
 
-func (cnt *Counters) sumRx() {
-	zeroIndex := len(cnt.RxThreads)
-	sumV := reflect.ValueOf(&cnt.RxCounters).Elem()
-	for i, thCnt := range cnt.RxThreads {
+// sumThreads adds per-thread counters into sum.
+func sumThreads[T any](sum *T, threads []T) {
+	sumV := reflect.ValueOf(sum).Elem()
+	for _, thCnt := range threads {
 		thV := reflect.ValueOf(thCnt)
-		if thV.IsZero() {
-			zeroIndex = min(zeroIndex, i)
-		} else {
-			for field := range sumV.NumField() {
-				sumF, thF := sumV.Field(field), thV.Field(field)
-				sumF.SetUint(sumF.Uint() + thF.Uint())
-			}
+		for field := range sumV.NumField() {
+			sumF, thF := sumV.Field(field), thV.Field(field)
+			sumF.SetUint(sumF.Uint() + thF.Uint())
 		}
 	}
-	cnt.RxThreads = cnt.RxThreads[:zeroIndex]
 }
 
 // Counters retrieves face counters.
@@ -115,9 +122,22 @@ func (f *face) Counters() (cnt Counters) {
 		rxCnt.readFrom(&rxt)
 		cnt.RxThreads = append(cnt.RxThreads, rxCnt)
 	}
-	cnt.sumRx()
+	sumThreads(&cnt.RxCounters, cnt.RxThreads)
+	isZero := func(rxCnt RxCounters) bool { return rxCnt == RxCounters{} }
+	if zeroIndex := slices.IndexFunc(cnt.RxThreads, isZero); zeroIndex >= 0 {
+		cnt.RxThreads = cnt.RxThreads[:zeroIndex] // omit unused RX threads
+	}
 
-	cnt.TxCounters.readFrom(&c.impl.tx[0])
+	for i := range f.nTxThreads {
+		var txCnt TxCounters
+		txCnt.readFrom(&c.impl.tx[i])
+		cnt.TxThreads = append(cnt.TxThreads, txCnt)
+	}
+	sumThreads(&cnt.TxCounters, cnt.TxThreads)
+	cnt.TxRejects = uint64(c.impl.nTxRejects)
+	if c.impl.txShaper != nil {
+		cnt.TxCounters.readTxShaper(c.impl.txShaper)
+	}
 
 	return cnt
 }
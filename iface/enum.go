@@ -15,7 +15,7 @@ const (
 	MaxFaceRxThreads = 8
 
 	// MaxFaceTxThreads is the maximum number of TX threads in a face.
-	MaxFaceTxThreads = 1
+	MaxFaceTxThreads = 4
 
 	// MinReassemblerCapacity is the minimum partial message store capacity in the reassembler.
 	MinReassemblerCapacity = 4
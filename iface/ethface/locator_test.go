@@ -568,3 +568,39 @@ func TestLocatorTxHdr(t *testing.T) {
 	assert.EqualValues(an.UDPPortNDN, gtp6InnerUDP.DstPort)
 	assert.Zero(gtp6InnerUDP.Checksum)
 }
+
+func TestLocatorTxHdrFlowHash(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	payload, _ := tlv.EncodeFrom(ndn.MakeInterest("/I"))
+	txHdr := ethport.NewTxHdr(parseLocator(`{
+		"scheme": "vxlan",
+		"local": "02:00:00:00:00:01",
+		"remote": "02:00:00:00:00:02",
+		"localIP": "192.168.37.1",
+		"remoteIP": "192.168.37.2",
+		"vxlan": 0,
+		"innerLocal": "02:00:00:00:00:03",
+		"innerRemote": "02:00:00:00:00:04"
+	}`), false)
+	srcPort := func(opts ethport.TxHdrPrependOptions) uint16 {
+		pkt := makePacket(payload)
+		defer pkt.Close()
+		txHdr.Prepend(pkt, opts)
+
+		parsed := checkPacketLayers(t, pkt.Bytes(),
+			layers.LayerTypeEthernet, layers.LayerTypeIPv4, layers.LayerTypeUDP, layers.LayerTypeVXLAN, layers.LayerTypeEthernet,
+			ndnlayer.LayerTypeTLV, ndnlayer.LayerTypeNDN)
+		return uint16(parsed.Layer(layers.LayerTypeUDP).(*layers.UDP).SrcPort)
+	}
+
+	assert.EqualValues(0xC000|0x1234, srcPort(ethport.TxHdrPrependOptions{UseFlowHash: true, FlowHash: 0x1234}))
+	assert.EqualValues(0xC000|0x3FFF, srcPort(ethport.TxHdrPrependOptions{UseFlowHash: true, FlowHash: 0xFFFFFFFF}))
+	assert.EqualValues(0xC000, srcPort(ethport.TxHdrPrependOptions{UseFlowHash: true, FlowHash: 0x4000}))
+
+	// flow hash takes priority over burst-based increment, so that the same flow stays on the same
+	// source port regardless of burst boundaries
+	for range 4 {
+		assert.EqualValues(0xC000|0x0567, srcPort(ethport.TxHdrPrependOptions{NewBurst: true, UseFlowHash: true, FlowHash: 0x0567}))
+	}
+}
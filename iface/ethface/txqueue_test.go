@@ -0,0 +1,262 @@
+package ethface_test
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+	"github.com/usnistgov/ndn-dpdk/dpdk/ethdev"
+	"github.com/usnistgov/ndn-dpdk/dpdk/ethdev/ethringdev"
+	"github.com/usnistgov/ndn-dpdk/dpdk/pktmbuf"
+	"github.com/usnistgov/ndn-dpdk/iface"
+	"github.com/usnistgov/ndn-dpdk/iface/ethface"
+	"github.com/usnistgov/ndn-dpdk/iface/ethport"
+	"github.com/usnistgov/ndn-dpdk/iface/ifacetestenv"
+	"github.com/usnistgov/ndn-dpdk/ndn/ndnlayer"
+	"github.com/usnistgov/ndn-dpdk/ndni"
+	"github.com/usnistgov/ndn-dpdk/ndni/ndnitestenv"
+	"go4.org/must"
+)
+
+// txQueueFixture connects a Port having multiple TX queues to a raw EthDev that captures transmitted frames.
+// TX queue i of the Port delivers frames to RX queue i of the peer.
+type txQueueFixture struct {
+	t    testing.TB
+	port *ethport.Port
+	peer ethdev.EthDev
+}
+
+func newTxQueueFixture(t testing.TB, nQueues int) (fixture *txQueueFixture) {
+	_, require := makeAR(t)
+	fixture = &txQueueFixture{t: t}
+
+	pair, e := ethringdev.NewPair(ethringdev.PairConfig{
+		NQueues: nQueues,
+		RxPool:  ndni.PacketMempool.Get(eal.NumaSocket{}),
+	})
+	require.NoError(e)
+	t.Cleanup(func() { must.Close(pair) })
+	t.Cleanup(ifacetestenv.ClearFacesLCores)
+
+	fixture.port, e = ethport.New(ethport.Config{
+		EthDev:   pair.PortA,
+		MTU:      1500,
+		TxQueues: nQueues,
+	})
+	require.NoError(e)
+
+	fixture.peer = pair.PortB
+	require.NoError(fixture.peer.Start(pair.EthDevConfig()))
+	return fixture
+}
+
+func (fixture *txQueueFixture) VxlanLocator(vni int) (loc ethface.VxlanLocator) {
+	loc.EthDev = fixture.port.EthDev()
+	loc.Local.HardwareAddr = loc.EthDev.HardwareAddr()
+	loc.Remote.HardwareAddr, _ = net.ParseMAC("02:00:00:00:00:02")
+	loc.LocalIP = netip.MustParseAddr("192.168.37.1")
+	loc.RemoteIP = netip.MustParseAddr("192.168.37.2")
+	loc.VXLAN = vni
+	loc.InnerLocal.HardwareAddr, _ = net.ParseMAC("02:00:00:00:00:03")
+	loc.InnerRemote.HardwareAddr, _ = net.ParseMAC("02:00:00:00:00:04")
+	return
+}
+
+// Receive collects frames arriving at each RX queue of the peer, until no frame arrives for a while.
+func (fixture *txQueueFixture) Receive() (frames [][][]byte) {
+	rxqs := fixture.peer.RxQueues()
+	frames = make([][][]byte, len(rxqs))
+	vec := make(pktmbuf.Vector, iface.MaxBurstSize)
+	for idle, deadline := 0, time.Now().Add(5*time.Second); idle < 20 && time.Now().Before(deadline); {
+		idle++
+		for i, rxq := range rxqs {
+			n := rxq.RxBurst(vec)
+			for _, pkt := range vec[:n] {
+				frames[i] = append(frames[i], pkt.Bytes())
+			}
+			vec[:n].Close()
+			if n > 0 {
+				idle = 0
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return
+}
+
+func TestTxQueuesPlacement(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := newTxQueueFixture(t, 2)
+
+	locA := fixture.VxlanLocator(1)
+	locA.NTxQueues = 2
+	faceA, e := locA.CreateFace()
+	require.NoError(e)
+	assert.Equal(2, faceA.NTxThreads())
+	assert.Len(faceA.Counters().TxThreads, 2)
+
+	// SelectTxLoop places TX threads of the same face on distinct TxLoops
+	txlA := iface.ListFaceTxLoops(faceA)
+	require.Len(txlA, 2)
+	require.NotNil(txlA[0])
+	require.NotNil(txlA[1])
+	assert.NotEqual(txlA[0], txlA[1])
+
+	// TX thread i of every face is placed on the TxLoop serving hardware TX queue i
+	locB := fixture.VxlanLocator(2)
+	locB.NTxQueues = 2
+	faceB, e := locB.CreateFace()
+	require.NoError(e)
+	assert.Equal(txlA, iface.ListFaceTxLoops(faceB))
+
+	locC := fixture.VxlanLocator(3)
+	faceC, e := locC.CreateFace()
+	require.NoError(e)
+	assert.Equal(1, faceC.NTxThreads())
+	assert.Equal(txlA[:1], iface.ListFaceTxLoops(faceC))
+
+	assert.Equal(3, txlA[0].CountFaces())
+	assert.Equal(2, txlA[1].CountFaces())
+
+	// closing faces releases their TX threads
+	must.Close(faceB)
+	assert.Equal(2, txlA[0].CountFaces())
+	assert.Equal(1, txlA[1].CountFaces())
+
+	// cannot exceed Port TX queues
+	locD := fixture.VxlanLocator(4)
+	locD.NTxQueues = 3
+	_, e = locD.CreateFace()
+	assert.Error(e)
+}
+
+func TestTxQueuesChooseTxLoop(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := newTxQueueFixture(t, 2)
+
+	var created []iface.TxLoop
+	oldChooseTxLoop := iface.ChooseTxLoop
+	defer func() { iface.ChooseTxLoop = oldChooseTxLoop }()
+	iface.ChooseTxLoop = func(face iface.Face, txThread int) iface.TxLoop {
+		txl := iface.NewTxLoop(face.NumaSocket())
+		created = append(created, txl)
+		return txl
+	}
+
+	loc := fixture.VxlanLocator(1)
+	loc.NTxQueues = 2
+	face, e := loc.CreateFace()
+	require.NoError(e)
+
+	// ChooseTxLoop is invoked for each TX thread, so that TX threads do not collapse onto one TxLoop
+	require.Len(created, 2)
+	assert.Equal(created, iface.ListFaceTxLoops(face))
+
+	must.Close(face)
+	for _, txl := range created {
+		must.Close(txl)
+	}
+}
+
+func TestTxQueuesRejectRxQueues(t *testing.T) {
+	assert, _ := makeAR(t)
+	fixture := newTxQueueFixture(t, 1)
+
+	// RSS cannot spread non-VXLAN faces
+	locEther := makeEtherLocator(fixture.port.EthDev())
+	locEther.NRxQueues = 2
+	_, e := locEther.CreateFace()
+	assert.Error(e)
+
+	// Port is not using RxFlow
+	locVxlan := fixture.VxlanLocator(1)
+	locVxlan.NRxQueues = 2
+	_, e = locVxlan.CreateFace()
+	assert.Error(e)
+
+	locVxlan.NRxQueues = 1
+	_, e = locVxlan.CreateFace()
+	assert.NoError(e)
+}
+
+func TestTxQueuesFlowHash(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := newTxQueueFixture(t, 2)
+
+	loc := fixture.VxlanLocator(1)
+	loc.NTxQueues = 2
+	face, e := loc.CreateFace()
+	require.NoError(e)
+
+	const nNames = 16
+	content := make([]byte, 3000)
+	randBytes(content)
+	mp := ndnitestenv.MakeMempools()
+	txAlign := face.TxAlign()
+	for range 2 {
+		pkts := make([]*ndni.Packet, 0, 2*nNames)
+		for i := range nNames {
+			pkts = append(pkts, makeInterest(fmt.Sprintf("/I/%d", i)))
+			data := ndnitestenv.MakeData(fmt.Sprintf("/D/%d", i), content)
+			if txAlign.Linearize {
+				linear := data.Clone(mp, txAlign)
+				data.Close()
+				data = linear
+			}
+			pkts = append(pkts, data)
+		}
+		iface.TxBurst(face.ID(), pkts)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	interestPorts := map[string][]uint16{}
+	fragPorts := map[uint64][]uint16{}
+	nFrames := 0
+	for _, queueFrames := range fixture.Receive() {
+		for _, wire := range queueFrames {
+			nFrames++
+			parsed := gopacket.NewPacket(wire, layers.LayerTypeEthernet, gopacket.NoCopy)
+			udp, ok := parsed.Layer(layers.LayerTypeUDP).(*layers.UDP)
+			require.True(ok)
+			srcPort := uint16(udp.SrcPort)
+			assert.GreaterOrEqual(srcPort, uint16(0xC000))
+
+			l3, ok := parsed.Layer(ndnlayer.LayerTypeNDN).(*ndnlayer.NDN)
+			require.True(ok)
+			switch pkt := l3.Packet; {
+			case pkt.Interest != nil:
+				name := pkt.Interest.Name.String()
+				interestPorts[name] = append(interestPorts[name], srcPort)
+			case pkt.Fragment != nil:
+				first := pkt.Fragment.SeqNum - uint64(pkt.Fragment.FragIndex)
+				fragPorts[first] = append(fragPorts[first], srcPort)
+			}
+		}
+	}
+	assert.Greater(nFrames, 4*nNames)
+
+	// the same name always maps to the same UDP source port
+	assert.Len(interestPorts, nNames)
+	distinct := map[uint16]bool{}
+	for name, ports := range interestPorts {
+		if assert.Len(ports, 2, name) {
+			assert.Equal(ports[0], ports[1], name)
+		}
+		distinct[ports[0]] = true
+	}
+	assert.Greater(len(distinct), 1)
+
+	// all fragments of the same packet have the same UDP source port
+	assert.Len(fragPorts, 2*nNames)
+	for first, ports := range fragPorts {
+		assert.Greater(len(ports), 1, first)
+		for _, port := range ports {
+			assert.Equal(ports[0], port, first)
+		}
+	}
+}
@@ -6,6 +6,7 @@ package ethport
 import "C"
 import (
 	"errors"
+	"fmt"
 	"math"
 	"net"
 
@@ -35,12 +36,25 @@ type FaceConfig struct {
 	Port string `json:"port,omitempty"`
 
 	// NRxQueues is the number of RX queues for this face.
-	// It is meaningful only if the port is using RxFlow.
-	// For most DPDK drivers, it is effective in improving performance on VXLAN face only.
+	// Each RX queue is a separate RxGroup, which may be served by a distinct RxLoop.
+	// Values greater than 1 require a VXLAN face on a Port using RxFlow, because RSS can only spread
+	// a single face's traffic by the UDP source port that the sender derives from the packet name.
+	// Other faces are rejected.
 	//
 	// Default is 1.
 	NRxQueues int `json:"nRxQueues,omitempty"`
 
+	// NTxQueues is the number of hardware TX queues for this face.
+	// Each TX queue is served by a TxLoop, so that a high-rate face can transmit from multiple threads.
+	// It must not exceed Port TxQueues; pass-through face supports only one TX queue.
+	//
+	// On a VXLAN face, the UDP source port of each frame is derived from the name of the network layer
+	// packet, so that a receiver using RSS can spread the face across multiple RX queues while keeping
+	// all NDNLPv2 fragments of the same packet on the same queue.
+	//
+	// Default is 1.
+	NTxQueues int `json:"nTxQueues,omitempty"`
+
 	// DisableTxMultiSegOffload forces every packet to be copied into a linear buffer in software.
 	DisableTxMultiSegOffload bool `json:"disableTxMultiSegOffload,omitempty"`
 
@@ -135,8 +149,30 @@ func NewFace(port *Port, loc Locator) (iface.Face, error) {
 			useTxChecksumOffload := !cfg.DisableTxChecksumOffload && face.port.devInfo.HasTxChecksumOffload()
 			NewTxHdr(face.loc, useTxChecksumOffload).copyToC(&face.priv.txHdr)
 
+			initResult.NTxThreads = max(1, cfg.NTxQueues)
+			if initResult.NTxThreads > len(face.port.txl) {
+				return initResult, fmt.Errorf("%d TX queues requested but only %d available on Port", initResult.NTxThreads, len(face.port.txl))
+			}
+			// EthFace_TxBurst transmits on ethdev TX queue that equals TX thread index
+			initResult.TxQueuePerThread = true
+			locC := face.loc.EthLocatorC()
+			isVxlan := C.EthLocator_Classify(locC.ptr()).tunnel == 'V'
+			initResult.TxFlowHash = isVxlan
+			if cfg.NRxQueues > 1 {
+				if !isVxlan {
+					return initResult, errors.New("multiple RX queues are supported on VXLAN face only")
+				}
+				if _, ok := face.port.rxImpl.(*rxFlow); !ok {
+					return initResult, errors.New("multiple RX queues require Port using RxFlow")
+				}
+			}
+
 			if face.loc.Scheme() == SchemePassthru {
+				if initResult.NTxThreads > 1 {
+					return initResult, errors.New("pass-through face supports only one TX queue")
+				}
 				passthruInit(face, &initResult)
+				initResult.TxQueuePerThread = false
 			}
 
 			initResult.TxLinearize = !useTxMultiSegOffload
@@ -172,7 +208,7 @@ func NewFace(port *Port, loc Locator) (iface.Face, error) {
 
 			face.port.activateTx(face)
 			face.logger.Info("face started",
-				face.port.txl.LCore().ZapField("txl-lc"),
+				face.port.txl[0].LCore().ZapField("txl-lc"),
 			)
 			face.port.faces[id] = face
 			return nil
@@ -202,6 +238,12 @@ func NewFace(port *Port, loc Locator) (iface.Face, error) {
 			}
 			return nil
 		},
+		ValidateUpdate: func(cfg iface.UpdateConfig) error {
+			if face.loc.Scheme() == SchemePassthru && (cfg.MTU != 0 || cfg.TxShaper != nil) {
+				return errors.New("pass-through face does not support changing MTU or TxShaper")
+			}
+			return nil
+		},
 		Close: func() error {
 			face.priv = nil // freed by iface.Face.Close()
 
@@ -1,6 +1,9 @@
 package ethport
 
 import (
+	"maps"
+	"reflect"
+
 	"github.com/graphql-go/graphql"
 	"github.com/usnistgov/ndn-dpdk/core/gqlserver"
 	"github.com/usnistgov/ndn-dpdk/core/jsonhelper"
@@ -14,6 +17,7 @@ var (
 	GqlRxGroupInterface *gqlserver.Interface
 	GqlRxgFlowType      *graphql.Object
 	GqlRxgTableType     *graphql.Object
+	GqlBondConfigInput  *graphql.InputObject
 )
 
 func gqlDefineRxGroup[T iface.RxGroup](oc graphql.ObjectConfig) *graphql.Object {
@@ -110,10 +114,21 @@ func init() {
 		},
 	})
 
+	GqlBondConfigInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "EthBondConfigInput",
+		Description: "Link aggregation configuration.",
+		Fields: gqlserver.BindInputFields[BondConfig](gqlserver.FieldTypes{
+			reflect.TypeFor[ethnetif.Config](): ethnetif.GqlConfigInput,
+			reflect.TypeFor[ethdev.BondMode](): ethdev.GqlBondModeEnum,
+		}),
+	})
+	portConfigFieldTypes := maps.Clone(ethnetif.GqlConfigFieldTypes)
+	portConfigFieldTypes[reflect.TypeFor[BondConfig]()] = GqlBondConfigInput
+
 	gqlserver.AddMutation(&graphql.Field{
 		Name:        "createEthPort",
 		Description: "Create an Ethernet port.",
-		Args:        gqlserver.BindArguments[Config](ethnetif.GqlConfigFieldTypes),
+		Args:        gqlserver.BindArguments[Config](portConfigFieldTypes),
 		Type:        ethdev.GqlEthDevType.Object,
 		Resolve: func(p graphql.ResolveParams) (any, error) {
 			var cfg Config
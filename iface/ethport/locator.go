@@ -101,11 +101,17 @@ func (hdr TxHdr) IPLen() int {
 //	newBurst: whether pkt is the first packet in a burst. It increments UDP source port in VXLAN
 //	          headers. If NDN network layer packet is fragmented, only the first fragment might
 //	          start a new burst, so that all fragments have the same UDP source port.
+//	useFlowHash: whether flowHash is used as UDP source port in VXLAN headers instead, as done by
+//	             TxLoop on a face with multiple TX threads.
 func (hdr TxHdr) Prepend(pkt *pktmbuf.Packet, opts TxHdrPrependOptions) {
 	var flags C.EthTxHdrFlags
 	if opts.NewBurst {
 		flags |= C.EthTxHdrFlagsNewBurst
 	}
+	if opts.UseFlowHash {
+		flags |= C.EthTxHdrFlagsFlowHash
+		*(*C.uint32_t)(unsafe.Pointer(&(*C.struct_rte_mbuf)(pkt.Ptr()).hash)) = C.uint32_t(opts.FlowHash)
+	}
 	if opts.Gtpip {
 		flags |= C.EthTxHdrFlagsGtpip
 	}
@@ -121,6 +127,8 @@ func NewTxHdr(loc Locator, hasChecksumOffloads bool) (hdr TxHdr) {
 
 // TxHdrPrependOptions indicates options for TxHdr.Prepend.
 type TxHdrPrependOptions struct {
-	NewBurst bool
-	Gtpip    bool
+	NewBurst    bool
+	Gtpip       bool
+	UseFlowHash bool
+	FlowHash    uint32
 }
@@ -42,10 +42,15 @@ type Config struct {
 
 	RxQueueSize int `json:"rxQueueSize,omitempty" gqldesc:"Hardware RX queue capacity."`
 	TxQueueSize int `json:"txQueueSize,omitempty" gqldesc:"Hardware TX queue capacity."`
+	TxQueues    int `json:"txQueues,omitempty" gqldesc:"Number of hardware TX queues, each served by a TxLoop."`
 
 	MTU int `json:"mtu,omitempty" gqldesc:"Change interface MTU (excluding Ethernet/VLAN headers)."`
 
 	RxFlowQueues int `json:"rxFlowQueues,omitempty" gqldesc:"Enable RxFlow and set maximum queue count."`
+
+	// Bond aggregates several network interfaces into a bonded EthDev.
+	// If specified, ethnetif.Config fields are ignored.
+	Bond *BondConfig `json:"bond,omitempty" gqldesc:"Aggregate several network interfaces."`
 }
 
 // ensureEthDev creates EthDev if it's not set.
@@ -53,12 +58,67 @@ func (cfg *Config) ensureEthDev() (e error) {
 	if cfg.EthDev != nil {
 		return nil
 	}
+	if cfg.Bond != nil {
+		cfg.EthDev, e = cfg.Bond.createEthDev()
+		return e
+	}
 	if cfg.EthDev, e = ethnetif.CreateEthDev(cfg.Config); e != nil {
 		return e
 	}
 	return nil
 }
 
+// BondConfig contains link aggregation configuration.
+//
+// The bonded EthDev is created via DPDK net_bonding driver.
+// Faces on the port keep working as long as at least one member has an active link.
+// In BALANCE and LACP modes, traffic is distributed among members by L3 and L4 headers: VXLAN
+// faces are spread by name, because the outer UDP source port is derived from the name; other
+// faces always transmit on the same member.
+type BondConfig struct {
+	Members []ethnetif.Config `json:"members" gqldesc:"Member network interfaces."`
+	Mode    ethdev.BondMode   `json:"mode,omitempty" gqldesc:"Link aggregation mode."`
+}
+
+// createEthDev creates member EthDevs and the bonded EthDev.
+// Member EthDevs created by this function are closed upon failure, or when the bonded EthDev is closed.
+func (cfg BondConfig) createEthDev() (bond ethdev.EthDev, e error) {
+	existing := ethdev.List()
+	var members, created []ethdev.EthDev
+	closeCreated := func() {
+		for _, member := range created {
+			if e := member.Close(); e != nil {
+				logger.Warn("bond member close error", member.ZapField("member"), zap.Error(e))
+			}
+		}
+	}
+	defer func() {
+		if e != nil {
+			closeCreated()
+		}
+	}()
+
+	for i, memberCfg := range cfg.Members {
+		member, e := ethnetif.CreateEthDev(memberCfg)
+		if e != nil {
+			return nil, fmt.Errorf("bond member %d: %w", i, e)
+		}
+		if !slices.Contains(existing, member) && !slices.Contains(created, member) {
+			created = append(created, member)
+		}
+		if ports[member] != nil {
+			return nil, fmt.Errorf("bond member %d: Port already exists on %s", i, member.Name())
+		}
+		members = append(members, member)
+	}
+
+	if bond, e = ethdev.NewBond(members, cfg.Mode); e != nil {
+		return nil, e
+	}
+	ethdev.OnClose(bond, closeCreated)
+	return bond, nil
+}
+
 // applyDefaults applies defaults.
 // cfg.EthDev must be set before calling this function.
 func (cfg *Config) applyDefaults() {
@@ -71,6 +131,7 @@ func (cfg *Config) applyDefaults() {
 	if cfg.TxQueueSize == 0 {
 		cfg.TxQueueSize = DefaultTxQueueSize
 	}
+	cfg.TxQueues = max(1, cfg.TxQueues)
 }
 
 // Port organizes EthFaces on an EthDev.
@@ -84,7 +145,7 @@ type Port struct {
 	faces        map[iface.ID]*Face
 	rxBouncePool *pktmbuf.Pool
 	rxImpl       rxImpl
-	txl          iface.TxLoop
+	txl          []iface.TxLoop // TxLoop serving each hardware TX queue
 }
 
 // EthDev returns the Ethernet device.
@@ -92,6 +153,11 @@ func (port *Port) EthDev() ethdev.EthDev {
 	return port.dev
 }
 
+// Config returns the configuration used to create this port, with defaults applied.
+func (port *Port) Config() Config {
+	return port.cfg
+}
+
 // Faces returns a list of active faces.
 func (port *Port) Faces() (list []iface.Face) {
 	port.mutex.Lock()
@@ -168,28 +234,39 @@ func (port *Port) startDev(nRxQueues int, promisc bool) (e error) {
 			Socket:   socket,
 			RxPool:   rxPool,
 		}}, nRxQueues),
-		TxQueues: []ethdev.TxQueueConfig{{
+		TxQueues: slices.Repeat([]ethdev.TxQueueConfig{{
 			Capacity: port.cfg.TxQueueSize,
 			Socket:   socket,
-		}},
+		}}, port.cfg.TxQueues),
 		MTU:     port.cfg.MTU,
 		Promisc: promisc,
 	}
 	return port.dev.Start(cfg)
 }
 
+// activateTx adds face TX threads to TxLoops.
+// Since a hardware TX queue is not thread-safe, TX thread i of every face is placed on the same
+// TxLoop that serves hardware TX queue i.
 func (port *Port) activateTx(face iface.Face) {
-	if port.txl == nil {
-		port.txl = iface.ActivateTxFace(face)
-	} else {
-		port.txl.Add(face)
+	if !slices.ContainsFunc(port.txl, func(txl iface.TxLoop) bool { return txl != nil }) {
+		copy(port.txl, iface.ActivateTxFace(face))
+		return
+	}
+
+	for i := range face.NTxThreads() {
+		if port.txl[i] == nil {
+			if port.txl[i] = iface.ChooseTxLoop(face, i); port.txl[i] == nil {
+				port.txl[i] = iface.SelectTxLoop(face, port.txl...)
+			}
+		}
+		port.txl[i].Add(face, i)
 	}
 }
 
 func (port *Port) deactivateTx(face iface.Face) {
 	iface.DeactivateTxFace(face)
 	if len(port.faces) == 0 {
-		port.txl = nil
+		clear(port.txl)
 	}
 }
 
@@ -203,18 +280,30 @@ func New(cfg Config) (port *Port, e error) {
 	if ports[cfg.EthDev] != nil {
 		return nil, errors.New("Port already exists")
 	}
+	if bond := ethdev.BondOf(cfg.EthDev); bond != nil {
+		return nil, fmt.Errorf("EthDev is a member of bonded EthDev %s", bond)
+	}
 
 	cfg.applyDefaults()
 	if ndni.PacketMempool.Config().Dataroom < pktmbuf.DefaultHeadroom+cfg.MTU {
 		return nil, errors.New("PacketMempool dataroom is too small for requested MTU")
 	}
+	devInfo := cfg.EthDev.DevInfo()
+	maxTxQueues := iface.MaxFaceTxThreads
+	if devInfo.Max_tx_queues > 0 {
+		maxTxQueues = min(maxTxQueues, int(devInfo.Max_tx_queues))
+	}
+	if cfg.TxQueues > maxTxQueues {
+		return nil, fmt.Errorf("%d TX queues requested but only %d allowed", cfg.TxQueues, maxTxQueues)
+	}
 
 	port = &Port{
 		cfg:     cfg,
 		logger:  logger.With(cfg.EthDev.ZapField("port")),
 		dev:     cfg.EthDev,
-		devInfo: cfg.EthDev.DevInfo(),
+		devInfo: devInfo,
 		faces:   map[iface.ID]*Face{},
+		txl:     make([]iface.TxLoop, cfg.TxQueues),
 	}
 	switch port.devInfo.Driver() {
 	case ethdev.DriverXDP:
@@ -257,6 +346,16 @@ func Find(dev ethdev.EthDev) *Port {
 	return ports[dev]
 }
 
+// List returns a list of open ports.
+func List() (list []*Port) {
+	portsMutex.RLock()
+	defer portsMutex.RUnlock()
+	for _, port := range ports {
+		list = append(list, port)
+	}
+	return list
+}
+
 func init() {
 	iface.OnCloseAll(func() {
 		portsMutex.Lock()
@@ -50,3 +50,21 @@ func OnFaceClosed(cb func(ID)) (cancel func()) {
 func OnCloseAll(cb func()) (cancel func()) {
 	return emitter.On(evtCloseAll, cb)
 }
+
+func init() {
+	publish := func(t events.Type) func(ID) {
+		return func(id ID) {
+			events.Publish(events.Event{Type: t, Face: int(id)})
+		}
+	}
+	OnFaceNew(func(id ID) {
+		evt := events.Event{Type: events.TypeFaceCreated, Face: int(id)}
+		if face := Get(id); face != nil {
+			evt.Subject = face.Locator().Scheme()
+		}
+		events.Publish(evt)
+	})
+	OnFaceUp(publish(events.TypeFaceUp))
+	OnFaceDown(publish(events.TypeFaceDown))
+	OnFaceClosed(publish(events.TypeFaceDestroyed))
+}
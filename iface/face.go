@@ -7,8 +7,10 @@ package iface
 */
 import "C"
 import (
+	"errors"
 	"fmt"
 	"io"
+	"sync/atomic"
 	"unsafe"
 
 	"github.com/usnistgov/ndn-dpdk/core/cptr"
@@ -47,12 +49,19 @@ type Face interface {
 	// TxAlign returns TX packet alignment requirement.
 	TxAlign() ndni.PacketTxAlign
 
+	// NTxThreads returns number of TX threads.
+	NTxThreads() int
+
 	// EnableInputDemuxes enables per-face InputDemuxes.
 	// They can then be retrieved with DemuxOf() method.
 	EnableInputDemuxes()
 
 	// SetDown changes face UP/DOWN state.
+	// If the face is administratively down, it cannot be changed to UP.
 	SetDown(isDown bool)
+
+	// Update changes face configuration at runtime.
+	Update(cfg UpdateConfig) error
 }
 
 // Config contains face configuration.
@@ -77,6 +86,14 @@ type Config struct {
 	// If this is less than MinMTU or greater than the maximum, the face will fail to initialize.
 	MTU int `json:"mtu,omitempty"`
 
+	// TxShaper enables egress traffic shaping and scheduling.
+	// If omitted, L3 packets are transmitted as fast as the lower layer accepts, in arrival order.
+	TxShaper *TxShaperConfig `json:"txShaper,omitempty"`
+
+	// Liveness enables face liveness monitor.
+	// If omitted, face UP/DOWN state is not affected by whether the peer is responsive.
+	Liveness *LivenessConfig `json:"liveness,omitempty"`
+
 	maxMTU int
 }
 
@@ -109,6 +126,32 @@ func (c *Config) checkMTU() error {
 	return nil
 }
 
+// UpdateConfig contains face configuration changes.
+// Zero or nil fields are unchanged.
+type UpdateConfig struct {
+	// MTU changes the maximum size of outgoing NDNLP packets.
+	// The face must use chained TX mbufs; if the face requires linear TX mbufs, packets already queued
+	// for transmission have been fragmented according to the previous MTU, so that MTU cannot be changed.
+	MTU int `json:"mtu,omitempty"`
+
+	// ReassemblerCapacity changes the partial message store capacity in the reassembler.
+	// It must be between MinReassemblerCapacity and the capacity at face creation, because the
+	// reassembler hash table cannot be enlarged while the face is receiving.
+	ReassemblerCapacity int `json:"reassemblerCapacity,omitempty"`
+
+	// TxShaper enables or changes egress traffic shaping and scheduling, including per L3 type
+	// queue capacity and CoDel parameters.
+	// L3 packets pending in per-type queues of the previous TxShaper are dropped.
+	TxShaper *TxShaperConfig `json:"txShaper,omitempty"`
+
+	// DisableTxShaper disables egress traffic shaping and scheduling.
+	DisableTxShaper bool `json:"disableTxShaper,omitempty"`
+
+	// AdminDown changes administrative state.
+	// An administratively down face stays DOWN until it is administratively brought up.
+	AdminDown *bool `json:"adminDown,omitempty"`
+}
+
 // NewParams contains parameters to New().
 type NewParams struct {
 	Config
@@ -145,6 +188,12 @@ type NewParams struct {
 	// ExCounters callback returns extended counters.
 	// This is optional.
 	ExCounters func() any
+
+	// ValidateUpdate callback checks whether a configuration change is supported by the face.
+	// It should return an error if some fields cannot be changed for the locator scheme.
+	// This is optional.
+	// This is always invoked on the main thread.
+	ValidateUpdate func(cfg UpdateConfig) error
 }
 
 // InitResult contains results of NewParams.Init callback.
@@ -167,6 +216,22 @@ type InitResult struct {
 
 	// TxBurst is a C function of C.Face_TxBurstFunc type.
 	TxBurst unsafe.Pointer
+
+	// NTxThreads is the number of TX threads.
+	// Each TX thread may be placed on a different TxLoop, and they dequeue from the same output queue.
+	// TxBurst is invoked with the TX thread index, which may select a lower layer TX queue.
+	// Default is 1. Maximum is MaxFaceTxThreads.
+	// Multiple TX threads require TxQueuePerThread; TxShaper is not supported.
+	NTxThreads int
+
+	// TxQueuePerThread indicates that TxBurst transmits on a separate lower layer TX queue for each
+	// TX thread index, so that TxBurst may be invoked concurrently from multiple TX threads.
+	TxQueuePerThread bool
+
+	// TxFlowHash indicates whether TxLoop should compute a flow hash of each L3 packet.
+	// The flow hash is derived from the name if available, and stored in mbuf hash.usr field of
+	// every L2 frame, so that all fragments of an L3 packet carry the same flow hash.
+	TxFlowHash bool
 }
 
 // New creates a Face.
@@ -193,6 +258,9 @@ func newFace(p NewParams) (Face, error) {
 		stopCallback:       p.Stop,
 		closeCallback:      p.Close,
 		exCountersCallback: p.ExCounters,
+		validateUpdate:     p.ValidateUpdate,
+		maxMTU:             p.maxMTU,
+		maxReassCapacity:   p.ReassemblerCapacity,
 	}
 	logEntry := logger.With(
 		f.id.ZapField("id"),
@@ -232,15 +300,48 @@ func newFace(p NewParams) (Face, error) {
 		fragmentPayloadSize: C.uint16_t(p.MTU - ndni.LpHeaderHeadroom),
 	}
 	c.impl.txBurst = C.Face_TxBurstFunc(initResult.TxBurst)
+	c.impl.txFlowHash = C.bool(initResult.TxFlowHash)
 	(*ndni.Mempools)(unsafe.Pointer(&c.impl.txMempools)).Assign(p.Socket)
 
-	outputQueue, e := ringbuffer.New(p.OutputQueueSize, p.Socket, ringbuffer.ProducerMulti, ringbuffer.ConsumerSingle)
+	f.nTxThreads = max(1, initResult.NTxThreads)
+	if f.nTxThreads > MaxFaceTxThreads {
+		logEntry.Warn("too many TX threads", zap.Int("n-tx-threads", f.nTxThreads))
+		return f.clear(), fmt.Errorf("number of TX threads cannot exceed %d", MaxFaceTxThreads)
+	}
+	if f.nTxThreads > 1 && !initResult.TxQueuePerThread {
+		logEntry.Warn("multiple TX threads require per-thread TX queues", zap.Int("n-tx-threads", f.nTxThreads))
+		return f.clear(), errors.New("multiple TX threads require per-thread TX queues")
+	}
+	if f.nTxThreads > 1 && p.TxShaper != nil {
+		logEntry.Warn("TxShaper requires single TX thread", zap.Int("n-tx-threads", f.nTxThreads))
+		return f.clear(), errors.New("TxShaper requires single TX thread")
+	}
+	for i := range f.nTxThreads {
+		txt := &c.impl.tx[i]
+		txt.faceID = c.id
+		txt.index = C.uint8_t(i)
+		txt.nextSeqNum = C.uint64_t(i) << 56 // avoid sequence number collision among TX threads
+	}
+
+	outputQueueConsumer := ringbuffer.ConsumerSingle
+	if f.nTxThreads > 1 {
+		outputQueueConsumer = ringbuffer.ConsumerMulti
+	}
+	outputQueue, e := ringbuffer.New(p.OutputQueueSize, p.Socket, ringbuffer.ProducerMulti, outputQueueConsumer)
 	if e != nil {
 		logEntry.Warn("outputQueue error", zap.Error(e))
 		return f.clear(), e
 	}
 	c.outputQueue = (*C.struct_rte_ring)(outputQueue.Ptr())
 
+	if p.TxShaper != nil {
+		if c.impl.txShaper, e = newTxShaper(*p.TxShaper, p.Socket); e != nil {
+			logEntry.Warn("TxShaper error", zap.Error(e))
+			return f.clear(), e
+		}
+		c.txTimestamp = C.bool(txShaperNeedsTimestamp(c.impl.txShaper))
+	}
+
 	for i := range MaxFaceRxThreads {
 		reassID := C.CString(eal.AllocObjectID("iface.Reassembler"))
 		defer C.free(unsafe.Pointer(reassID))
@@ -257,6 +358,10 @@ func newFace(p NewParams) (Face, error) {
 		return f.clear(), e
 	}
 
+	if p.Liveness != nil {
+		f.liveness = startLivenessMonitor(f, *p.Liveness)
+	}
+
 	gFaces[f.id] = initResult.Face
 	emitter.Emit(evtFaceNew, f.id)
 	logEntry.Info("face created")
@@ -270,6 +375,12 @@ type face struct {
 	stopCallback       func() error
 	closeCallback      func() error
 	exCountersCallback func() any
+	validateUpdate     func(cfg UpdateConfig) error
+	maxMTU             int
+	maxReassCapacity   int
+	nTxThreads         int
+	liveness           *livenessMonitor
+	adminDown          atomic.Bool
 }
 
 func (f *face) ptr() *C.Face {
@@ -298,6 +409,10 @@ func (f *face) Close() (e error) {
 }
 
 func (f *face) close() error {
+	if f.liveness != nil {
+		f.liveness.Close()
+		f.liveness = nil
+	}
 	f.ptr().state = StateDown
 	emitter.Emit(evtFaceClosing, f.id)
 
@@ -324,6 +439,9 @@ func (f *face) clear() Face {
 		if c.impl.rxDemuxes != nil {
 			eal.Free(c.impl.rxDemuxes)
 		}
+		if c.impl.txShaper != nil {
+			closeTxShaper(c.impl.txShaper)
+		}
 		eal.Free(c.impl)
 		c.impl = nil
 	}
@@ -347,6 +465,10 @@ func (f *face) TxAlign() ndni.PacketTxAlign {
 	return *(*ndni.PacketTxAlign)(unsafe.Pointer(&f.ptr().txAlign))
 }
 
+func (f *face) NTxThreads() int {
+	return f.nTxThreads
+}
+
 func (f *face) DemuxOf(t ndni.PktType) *InputDemux {
 	demuxes := f.ptr().impl.rxDemuxes
 	if demuxes == nil {
@@ -366,6 +488,8 @@ func (f *face) EnableInputDemuxes() {
 func (f *face) SetDown(isDown bool) {
 	id, c := f.id, f.ptr()
 	switch {
+	case !isDown && f.adminDown.Load():
+		// don't change state if face is administratively down
 	case isDown && c.state == StateUp:
 		c.state = StateDown
 		emitter.Emit(evtFaceDown, id)
@@ -376,6 +500,87 @@ func (f *face) SetDown(isDown bool) {
 	// don't change state if face is closing/removed
 }
 
+func (f *face) Update(cfg UpdateConfig) (e error) {
+	eal.CallMain(func() { e = f.update(cfg) })
+	return e
+}
+
+func (f *face) update(cfg UpdateConfig) (e error) {
+	c := f.ptr()
+	if c.impl == nil {
+		return errors.New("face is closed")
+	}
+
+	if cfg.MTU != 0 {
+		if c.txAlign.linearize {
+			return errors.New("MTU cannot be changed on a face that requires linear TX mbufs")
+		}
+		if cfg.MTU < MinMTU || cfg.MTU > f.maxMTU {
+			return fmt.Errorf("face MTU must be between %d and %d", MinMTU, f.maxMTU)
+		}
+	}
+	if cfg.ReassemblerCapacity != 0 &&
+		(cfg.ReassemblerCapacity < MinReassemblerCapacity || cfg.ReassemblerCapacity > f.maxReassCapacity) {
+		return fmt.Errorf("reassembler capacity must be between %d and %d", MinReassemblerCapacity, f.maxReassCapacity)
+	}
+	if cfg.TxShaper != nil && cfg.DisableTxShaper {
+		return errors.New("txShaper and disableTxShaper are mutually exclusive")
+	}
+	if cfg.TxShaper != nil && f.nTxThreads > 1 {
+		return errors.New("TxShaper requires single TX thread")
+	}
+	if f.validateUpdate != nil {
+		if e := f.validateUpdate(cfg); e != nil {
+			return e
+		}
+	}
+
+	var shaper *C.TxShaper
+	if cfg.TxShaper != nil {
+		if shaper, e = newTxShaper(*cfg.TxShaper, f.socket); e != nil {
+			return e
+		}
+	}
+
+	logEntry := logger.With(f.id.ZapField("id"))
+	if txShaperNeedsTimestamp(shaper) {
+		// packets enqueued while the face is paused must carry enqueue timestamp
+		c.txTimestamp = true
+	}
+	if cfg.MTU != 0 || cfg.TxShaper != nil || cfg.DisableTxShaper {
+		pauseTxFace(f, func() {
+			if cfg.MTU != 0 {
+				c.txAlign.fragmentPayloadSize = C.uint16_t(cfg.MTU - ndni.LpHeaderHeadroom)
+				logEntry = logEntry.With(zap.Int("mtu", cfg.MTU))
+			}
+			if cfg.TxShaper != nil || cfg.DisableTxShaper {
+				if old := c.impl.txShaper; old != nil {
+					closeTxShaper(old)
+				}
+				c.impl.txShaper = shaper
+				c.txTimestamp = C.bool(txShaperNeedsTimestamp(shaper))
+				logEntry = logEntry.With(zap.Bool("tx-shaper", shaper != nil))
+			}
+		})
+	}
+
+	if cfg.ReassemblerCapacity != 0 {
+		for i := range c.impl.rx {
+			C.Reassembler_SetCapacity(&c.impl.rx[i].reass, C.uint32_t(cfg.ReassemblerCapacity))
+		}
+		logEntry = logEntry.With(zap.Int("reassembler-capacity", cfg.ReassemblerCapacity))
+	}
+
+	if cfg.AdminDown != nil {
+		f.adminDown.Store(*cfg.AdminDown)
+		f.SetDown(*cfg.AdminDown)
+		logEntry = logEntry.With(zap.Bool("admin-down", *cfg.AdminDown))
+	}
+
+	logEntry.Info("face updated")
+	return nil
+}
+
 // IsDown returns true if the face does not exist or is down.
 func IsDown(id ID) bool {
 	return bool(C.Face_IsDown(C.FaceID(id)))
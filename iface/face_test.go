@@ -1,11 +1,14 @@
 package iface_test
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/usnistgov/ndn-dpdk/core/events"
 	"github.com/usnistgov/ndn-dpdk/iface"
 	"github.com/usnistgov/ndn-dpdk/iface/intface"
+	"github.com/usnistgov/ndn-dpdk/iface/socketface"
 	"github.com/usnistgov/ndn-dpdk/ndni"
 	"github.com/usnistgov/ndn-dpdk/ndni/ndnitestenv"
 )
@@ -37,6 +40,30 @@ func TestTxBurst(t *testing.T) {
 	assert.NotNil(collect.Get(0).Data)
 }
 
+func TestTxRejects(t *testing.T) {
+	assert, require := makeAR(t)
+
+	face := intface.Must(intface.New(socketface.Config{
+		Config: iface.Config{
+			OutputQueueSize: iface.MinOutputQueueSize,
+		},
+	}))
+	defer face.D.Close()
+
+	pkts := make([]*ndni.Packet, 400)
+	for i := range pkts {
+		pkts[i] = ndnitestenv.MakeInterest(fmt.Sprintf("/A/%d", i))
+	}
+	iface.TxBurst(face.ID, pkts)
+
+	cnt := face.D.Counters()
+	assert.GreaterOrEqual(cnt.TxRejects, uint64(len(pkts)-iface.MinOutputQueueSize))
+	require.Eventually(func() bool {
+		cnt = face.D.Counters()
+		return cnt.TxInterests+cnt.TxRejects == uint64(len(pkts))
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
 func TestEvents(t *testing.T) {
 	assert, require := makeAR(t)
 
@@ -93,3 +120,79 @@ func TestEvents(t *testing.T) {
 	}
 	assert.True(iface.IsDown(id1))
 }
+
+func TestUpdate(t *testing.T) {
+	assert, require := makeAR(t)
+
+	face := intface.MustNew()
+	defer face.D.Close()
+	collect := intface.Collect(face)
+	id := face.ID
+
+	assert.Error(face.D.Update(iface.UpdateConfig{MTU: iface.MinMTU - 1}))
+	assert.Error(face.D.Update(iface.UpdateConfig{ReassemblerCapacity: iface.MaxReassemblerCapacity}))
+	assert.Error(face.D.Update(iface.UpdateConfig{
+		TxShaper:        &iface.TxShaperConfig{},
+		DisableTxShaper: true,
+	}))
+
+	require.NoError(face.D.Update(iface.UpdateConfig{
+		MTU:                 1200,
+		ReassemblerCapacity: iface.MinReassemblerCapacity,
+		TxShaper:            &iface.TxShaperConfig{PacketRate: 1000},
+	}))
+	assert.EqualValues(1200-ndni.LpHeaderHeadroom, face.D.TxAlign().FragmentPayloadSize)
+
+	pkts := make([]*ndni.Packet, 1)
+	pkts[0] = ndnitestenv.MakeData("/A", make([]byte, 3000))
+	iface.TxBurst(id, pkts)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(1, collect.Count())
+	assert.EqualValues(1, face.D.Counters().TxFragGood)
+
+	require.NoError(face.D.Update(iface.UpdateConfig{DisableTxShaper: true}))
+
+	adminDown, adminUp := true, false
+	require.NoError(face.D.Update(iface.UpdateConfig{AdminDown: &adminDown}))
+	assert.True(iface.IsDown(id))
+	face.SetDown(false)
+	assert.True(iface.IsDown(id))
+	require.NoError(face.D.Update(iface.UpdateConfig{AdminDown: &adminUp}))
+	assert.False(iface.IsDown(id))
+}
+
+func TestEventBus(t *testing.T) {
+	assert, require := makeAR(t)
+
+	face := intface.MustNew()
+	id := face.ID
+
+	var evts []events.Event
+	filter := events.Filter{Face: int(id)}
+	defer events.Subscribe(func(evt events.Event) {
+		if filter.Match(evt) {
+			evts = append(evts, evt)
+		}
+	})()
+
+	face.SetDown(true)
+	face.SetDown(false)
+	require.NoError(face.D.Close())
+
+	types := []events.Type{}
+	for _, evt := range evts {
+		types = append(types, evt.Type)
+	}
+	assert.Equal([]events.Type{events.TypeFaceDown, events.TypeFaceUp, events.TypeFaceDestroyed}, types)
+	for i := 1; i < len(evts); i++ {
+		assert.Greater(evts[i].Seq, evts[i-1].Seq)
+	}
+
+	evts, filter = nil, events.Filter{Types: []events.Type{events.TypeFaceCreated}}
+	face = intface.MustNew()
+	defer face.D.Close()
+	if assert.Len(evts, 1) {
+		assert.Equal(int(face.ID), evts[0].Face)
+		assert.NotEmpty(evts[0].Subject)
+	}
+}
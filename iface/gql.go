@@ -5,11 +5,14 @@ import (
 	"reflect"
 
 	"github.com/graphql-go/graphql"
+	"github.com/usnistgov/ndn-dpdk/core/events"
+	"github.com/usnistgov/ndn-dpdk/core/events/eventsgql"
 	"github.com/usnistgov/ndn-dpdk/core/gqlserver"
 	"github.com/usnistgov/ndn-dpdk/core/jsonhelper"
 	"github.com/usnistgov/ndn-dpdk/core/nnduration"
 	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
 	"github.com/usnistgov/ndn-dpdk/dpdk/ealthread"
+	"github.com/zyedidia/generic/set"
 )
 
 var (
@@ -27,8 +30,17 @@ var (
 	GqlTxCountersType   *graphql.Object
 	GqlCountersType     *graphql.Object
 	GqlRxGroupInterface *gqlserver.Interface
+	GqlStateEventType   *graphql.Object
 )
 
+// faceStateOf maps operational event types to face states reported in faceState subscription.
+var faceStateOf = map[events.Type]string{
+	events.TypeFaceCreated:   "new",
+	events.TypeFaceUp:        "up",
+	events.TypeFaceDown:      "down",
+	events.TypeFaceDestroyed: "closed",
+}
+
 func init() {
 	GqlPktQueueInput = graphql.NewInputObject(graphql.InputObjectConfig{
 		Name:        "FacePktQueueInput",
@@ -71,12 +83,10 @@ func init() {
 			},
 			"txLoop": &graphql.Field{
 				Type:        ealthread.GqlWorkerType.Object,
-				Description: "TxLoop serving this face.",
+				Description: "TxLoop serving first TX thread of this face.",
 				Resolve: func(p graphql.ResolveParams) (any, error) {
-					txLoopLock.Lock()
-					defer txLoopLock.Unlock()
 					face := p.Source.(Face)
-					txl := mapFaceTxl[face.ID()]
+					txl := ListFaceTxLoops(face)[0]
 					if txl == nil {
 						return nil, nil
 					}
@@ -84,6 +94,20 @@ func init() {
 					return gqlserver.Optional(lc), nil
 				},
 			},
+			"txLoops": &graphql.Field{
+				Type:        gqlserver.NewListNonNullElem(ealthread.GqlWorkerType.Object),
+				Description: "TxLoops serving each TX thread of this face.",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					face := p.Source.(Face)
+					list := []eal.LCore{}
+					for _, txl := range ListFaceTxLoops(face) {
+						if txl != nil {
+							list = append(list, txl.LCore())
+						}
+					}
+					return list, nil
+				},
+			},
 		},
 	}, gqlserver.NodeConfig[Face]{
 		RetrieveInt: func(id int) Face {
@@ -126,6 +150,37 @@ func init() {
 		},
 	})
 
+	gqlserver.AddMutation(&graphql.Field{
+		Name:        "updateFace",
+		Description: "Change face configuration at runtime.",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Description: "Face ID.",
+				Type:        gqlserver.NonNullID,
+			},
+			"config": &graphql.ArgumentConfig{
+				Description: "JSON object that satisfies the schema of UpdateConfig type.",
+				Type:        gqlserver.NonNullJSON,
+			},
+		},
+		Type: graphql.NewNonNull(GqlFaceType.Object),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			face := GqlFaceType.Retrieve(p.Args["id"].(string))
+			if face == nil {
+				return nil, errors.New("face not found")
+			}
+
+			var cfg UpdateConfig
+			if e := jsonhelper.Roundtrip(p.Args["config"], &cfg, jsonhelper.DisallowUnknownFields); e != nil {
+				return nil, e
+			}
+			if e := face.Update(cfg); e != nil {
+				return nil, e
+			}
+			return face, nil
+		},
+	})
+
 	GqlRxCountersType = graphql.NewObject(graphql.ObjectConfig{
 		Name:   "FaceRxCounters",
 		Fields: gqlserver.BindFields[RxCounters](nil),
@@ -163,6 +218,98 @@ func init() {
 		},
 	})
 
+	GqlStateEventType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "FaceStateEvent",
+		Description: "Face state change event.",
+		Fields: graphql.Fields{
+			"nid": &graphql.Field{
+				Type:        gqlserver.NonNullInt,
+				Description: "Numeric face identifier.",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					evt := p.Source.(events.Event)
+					return evt.Face, nil
+				},
+			},
+			"state": &graphql.Field{
+				Type:        gqlserver.NonNullString,
+				Description: "New state: new, up, down, or closed.",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					evt := p.Source.(events.Event)
+					return faceStateOf[evt.Type], nil
+				},
+			},
+			"face": &graphql.Field{
+				Type:        GqlFaceType.Object,
+				Description: "The face, or null if it has been closed.",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					evt := p.Source.(events.Event)
+					return gqlserver.Optional(Get(ID(evt.Face))), nil
+				},
+			},
+		},
+	})
+	eventsgql.RetrieveFace = func(id string) (nid int, ok bool) {
+		if face := GqlFaceType.Retrieve(id); face != nil {
+			return int(face.ID()), true
+		}
+		return 0, false
+	}
+
+	gqlserver.AddSubscription(&graphql.Field{
+		Name: "faceState",
+		Description: "Face state changes, including creation, UP/DOWN transitions, and closure." +
+			" This is a view of face events in the operational event bus, see 'events' subscription.",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Description: "Face ID. If omitted, report state changes of all faces.",
+				Type:        graphql.ID,
+			},
+		},
+		Type: graphql.NewNonNull(GqlStateEventType),
+		Subscribe: func(p graphql.ResolveParams) (any, error) {
+			filter := events.Filter{
+				Types: []events.Type{events.TypeFaceCreated, events.TypeFaceUp, events.TypeFaceDown, events.TypeFaceDestroyed},
+			}
+			if id, ok := p.Args["id"].(string); ok {
+				face := GqlFaceType.Retrieve(id)
+				if face == nil {
+					return nil, nil
+				}
+				filter.Face = int(face.ID())
+			}
+
+			ch := make(chan events.Event, 64)
+			cancel := events.Subscribe(func(evt events.Event) {
+				if !filter.Match(evt) {
+					return
+				}
+				select {
+				case ch <- evt:
+				default: // subscriber is too slow, drop event
+				}
+			})
+
+			return gqlserver.PublishChan(func(updates chan<- any) {
+				defer cancel()
+				for {
+					select {
+					case <-p.Context.Done():
+						return
+					case evt := <-ch:
+						select {
+						case updates <- evt:
+						case <-p.Context.Done():
+							return
+						}
+						if filter.Face != 0 && evt.Type == events.TypeFaceDestroyed {
+							return
+						}
+					}
+				}
+			})
+		},
+	})
+
 	GqlRxGroupInterface = gqlserver.NewInterface(graphql.InterfaceConfig{
 		Name: "RxGroup",
 		Fields: graphql.Fields{
@@ -200,12 +347,14 @@ func init() {
 				return nil, nil
 			}
 
-			list := []Face{}
-			for id, t := range mapFaceTxl {
+			ids := set.NewMapset[ID]()
+			for key, t := range mapFaceTxl {
 				if t == txl {
-					list = append(list, Get(id))
+					ids.Put(key.id)
 				}
 			}
+			list := []Face{}
+			ids.Each(func(id ID) { list = append(list, Get(id)) })
 			return list, nil
 		},
 	})
@@ -0,0 +1,7 @@
+package ifacetest
+
+import (
+	"github.com/usnistgov/ndn-dpdk/core/testenv"
+)
+
+var makeAR = testenv.MakeAR
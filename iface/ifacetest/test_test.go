@@ -0,0 +1,13 @@
+package ifacetest
+
+import (
+	"testing"
+
+	"github.com/usnistgov/ndn-dpdk/core/testenv"
+	"github.com/usnistgov/ndn-dpdk/dpdk/ealtestenv"
+)
+
+func TestMain(m *testing.M) {
+	ealtestenv.Init()
+	testenv.Exit(m.Run())
+}
@@ -0,0 +1,269 @@
+package ifacetest
+
+/*
+#include "../../csrc/iface/txshaper.h"
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+	"github.com/usnistgov/ndn-dpdk/dpdk/pktmbuf"
+	"github.com/usnistgov/ndn-dpdk/dpdk/ringbuffer"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndni"
+	"github.com/usnistgov/ndn-dpdk/ndni/ndnitestenv"
+)
+
+// tbTicks returns the shortest duration, in TSC units, for a token bucket to accumulate n tokens at rate.
+func tbTicks(n, rate uint64) C.TscTime {
+	return C.TscTime((n*eal.TscHz + rate - 1) / rate)
+}
+
+func ctestTokenBucket(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	var tb C.TokenBucket
+	now := C.TscTime(1 << 40)
+	C.TokenBucket_Init(&tb, 200, 10, now)
+	assert.EqualValues(10, C.TokenBucket_Available(&tb))
+	assert.True(bool(C.TokenBucket_IsReady(&tb)))
+
+	C.TokenBucket_Take(&tb, 10)
+	assert.EqualValues(0, C.TokenBucket_Available(&tb))
+	assert.True(bool(C.TokenBucket_IsReady(&tb)))
+
+	now += tbTicks(5, 200)
+	C.TokenBucket_Refill(&tb, now)
+	assert.EqualValues(5, C.TokenBucket_Available(&tb))
+
+	C.TokenBucket_Take(&tb, 8) // deficit of 3 tokens
+	assert.EqualValues(0, C.TokenBucket_Available(&tb))
+	assert.False(bool(C.TokenBucket_IsReady(&tb)))
+
+	now += tbTicks(2, 200)
+	C.TokenBucket_Refill(&tb, now)
+	assert.False(bool(C.TokenBucket_IsReady(&tb)))
+
+	now += tbTicks(1, 200)
+	C.TokenBucket_Refill(&tb, now)
+	assert.True(bool(C.TokenBucket_IsReady(&tb)))
+	assert.EqualValues(0, C.TokenBucket_Available(&tb))
+
+	now += C.TscTime(eal.TscHz)
+	C.TokenBucket_Refill(&tb, now)
+	assert.EqualValues(10, C.TokenBucket_Available(&tb), "capped at burst")
+
+	C.TokenBucket_Init(&tb, 0, 0, now)
+	C.TokenBucket_Take(&tb, 1000)
+	assert.EqualValues(math.MaxUint32, C.TokenBucket_Available(&tb))
+	assert.True(bool(C.TokenBucket_IsReady(&tb)))
+}
+
+type txShaperFixture struct {
+	t      testing.TB
+	sh     *C.TxShaper
+	output *ringbuffer.Ring
+	Now    C.TscTime
+}
+
+// Send enqueues L3 packets into the output queue, with enqueue timestamp set to current time.
+func (f *txShaperFixture) Send(pkts ...*ndni.Packet) {
+	_, require := makeAR(f.t)
+	for _, pkt := range pkts {
+		C.Mbuf_SetEnqueueTimestamp((*C.struct_rte_mbuf)(pkt.Ptr()), f.Now)
+	}
+	require.Equal(len(pkts), ringbuffer.Enqueue(f.output, pkts))
+}
+
+// Dequeue invokes TxShaper_Dequeue at current time, charges dequeued packets as one frame each,
+// and returns the dequeued packets.
+func (f *txShaperFixture) Dequeue(count int) (pkts []ndn.Packet) {
+	npkts := make([]*C.Packet, count)
+	n := int(C.TxShaper_Dequeue(f.sh, (*C.struct_rte_ring)(f.output.Ptr()), &npkts[0], C.uint16_t(count), f.Now))
+	C.TxShaper_Charge(f.sh, C.uint64_t(n), 0)
+	for _, npkt := range npkts[:n] {
+		pkt := ndni.PacketFromPtr(unsafe.Pointer(npkt))
+		pkts = append(pkts, pkt.ToNPacket())
+		pkt.Close()
+	}
+	return pkts
+}
+
+func countTypes(pkts []ndn.Packet) (nInterests, nData, nNacks int) {
+	for _, pkt := range pkts {
+		switch {
+		case pkt.Nack != nil:
+			nNacks++
+		case pkt.Interest != nil:
+			nInterests++
+		case pkt.Data != nil:
+			nData++
+		}
+	}
+	return
+}
+
+func newTxShaperFixture(t testing.TB, sched C.TxSchedulerKind, weights map[ndni.PktType]int, queueCapacity int) (f *txShaperFixture) {
+	_, require := makeAR(t)
+	f = &txShaperFixture{
+		t:   t,
+		sh:  eal.Zmalloc[C.TxShaper]("TxShaper", C.sizeof_TxShaper, eal.NumaSocket{}),
+		Now: C.TscTime(1 << 40),
+	}
+
+	output, e := ringbuffer.New(256, eal.NumaSocket{}, ringbuffer.ProducerMulti, ringbuffer.ConsumerSingle)
+	require.NoError(e)
+	f.output = output
+
+	f.sh.sched = sched
+	for _, pt := range []ndni.PktType{ndni.PktInterest, ndni.PktData, ndni.PktNack} {
+		f.sh.weights[pt] = C.uint32_t(max(1, weights[pt]))
+		if sched != C.TxSchedulerFifo {
+			q, e := ringbuffer.New(queueCapacity, eal.NumaSocket{}, ringbuffer.ProducerSingle, ringbuffer.ConsumerSingle)
+			require.NoError(e)
+			f.sh.queues[pt] = (*C.struct_rte_ring)(q.Ptr())
+		}
+	}
+	C.TxShaper_Init(f.sh)
+	C.TokenBucket_Init(&f.sh.frames, 0, 0, f.Now)
+	C.TokenBucket_Init(&f.sh.octets, 0, 0, f.Now)
+
+	t.Cleanup(func() {
+		vec := make(pktmbuf.Vector, 64)
+		drain := func(r *ringbuffer.Ring) {
+			for n := ringbuffer.Dequeue(r, vec); n > 0; n = ringbuffer.Dequeue(r, vec) {
+				vec[:n].Close()
+			}
+			r.Close()
+		}
+		drain(f.output)
+		for _, q := range f.sh.queues {
+			if r := ringbuffer.FromPtr(unsafe.Pointer(q)); r != nil {
+				drain(r)
+			}
+		}
+		eal.Free(f.sh)
+	})
+	return f
+}
+
+func makeInterests(prefix string, n int) (pkts []*ndni.Packet) {
+	for i := range n {
+		pkts = append(pkts, ndnitestenv.MakeInterest(fmt.Sprintf("/%s/%d", prefix, i)))
+	}
+	return
+}
+
+func makeData(prefix string, n int) (pkts []*ndni.Packet) {
+	for i := range n {
+		pkts = append(pkts, ndnitestenv.MakeData(fmt.Sprintf("/%s/%d", prefix, i)))
+	}
+	return
+}
+
+func ctestTxShaperRate(t *testing.T) {
+	assert, _ := makeAR(t)
+	f := newTxShaperFixture(t, C.TxSchedulerFifo, nil, 0)
+	C.TokenBucket_Init(&f.sh.frames, 200, 10, f.Now)
+
+	f.Send(makeInterests("A", 30)...)
+	assert.Len(f.Dequeue(64), 10)
+	assert.Len(f.Dequeue(64), 0)
+	assert.EqualValues(1, f.sh.nThrottles)
+
+	f.Now += tbTicks(5, 200)
+	assert.Len(f.Dequeue(64), 5)
+	assert.Len(f.Dequeue(64), 0)
+	assert.EqualValues(2, f.sh.nThrottles)
+
+	f.Now += C.TscTime(eal.TscHz)
+	assert.Len(f.Dequeue(64), 10)
+	f.Now += C.TscTime(eal.TscHz)
+	assert.Len(f.Dequeue(64), 5)
+	assert.Len(f.Dequeue(64), 0)
+	assert.EqualValues(2, f.sh.nThrottles, "empty queue is not a throttle")
+}
+
+func ctestTxShaperPriority(t *testing.T) {
+	assert, _ := makeAR(t)
+	f := newTxShaperFixture(t, C.TxSchedulerPriority, map[ndni.PktType]int{ndni.PktInterest: 2}, 64)
+
+	f.Send(makeData("D", 5)...)
+	f.Send(makeInterests("I", 5)...)
+	f.Send(ndnitestenv.MakeNack(ndn.MakeInterest("/N/0"), an.NackNoRoute), ndnitestenv.MakeNack(ndn.MakeInterest("/N/1"), an.NackNoRoute))
+
+	nInterests, nData, nNacks := countTypes(f.Dequeue(4))
+	assert.Equal([]int{4, 0, 0}, []int{nInterests, nData, nNacks})
+	// Nack and Data have the same priority; ties are broken in Nack, Data, Interest order
+	nInterests, nData, nNacks = countTypes(f.Dequeue(4))
+	assert.Equal([]int{1, 1, 2}, []int{nInterests, nData, nNacks})
+	nInterests, nData, nNacks = countTypes(f.Dequeue(64))
+	assert.Equal([]int{0, 4, 0}, []int{nInterests, nData, nNacks})
+}
+
+func ctestTxShaperWrr(t *testing.T) {
+	assert, _ := makeAR(t)
+	f := newTxShaperFixture(t, C.TxSchedulerWrr, map[ndni.PktType]int{ndni.PktInterest: 2}, 64)
+
+	f.Send(makeInterests("I", 12)...)
+	f.Send(makeData("D", 12)...)
+
+	nInterests, nData, _ := countTypes(f.Dequeue(6))
+	assert.Equal(4, nInterests)
+	assert.Equal(2, nData)
+
+	total := 6
+	for range 20 {
+		total += len(f.Dequeue(6))
+	}
+	assert.Equal(24, total)
+}
+
+func ctestTxShaperQueueDrop(t *testing.T) {
+	assert, _ := makeAR(t)
+	f := newTxShaperFixture(t, C.TxSchedulerPriority, nil, 64)
+	dataQueue := ringbuffer.FromPtr(unsafe.Pointer(f.sh.queues[ndni.PktData]))
+
+	f.Send(makeData("D", 200)...)
+	nDequeued := 0
+	for range 4 {
+		nDequeued += len(f.Dequeue(1))
+	}
+	assert.Equal(4, nDequeued)
+	assert.Zero(f.output.CountInUse())
+	assert.NotZero(f.sh.nQueueDrops[ndni.PktData])
+	assert.EqualValues(200, nDequeued+dataQueue.CountInUse()+int(f.sh.nQueueDrops[ndni.PktData]))
+	assert.Zero(f.sh.nQueueDrops[ndni.PktInterest])
+}
+
+func ctestTxShaperCoDel(t *testing.T) {
+	assert, _ := makeAR(t)
+	f := newTxShaperFixture(t, C.TxSchedulerFifo, nil, 0)
+	f.sh.codel.pop = C.PktQueuePopActCoDel
+	f.sh.codel.target = C.TscDuration(eal.ToTscDuration(5 * time.Millisecond))
+	f.sh.codel.interval = C.TscDuration(eal.ToTscDuration(20 * time.Millisecond))
+	t0 := f.Now
+	f.Send(makeInterests("A", 3)...)
+
+	isMarked := func(pkts []ndn.Packet) bool {
+		return assert.Len(pkts, 1) && pkts[0].Lp.CongMark != 0
+	}
+
+	f.Now = t0 + C.TscTime(eal.ToTscDuration(1*time.Millisecond))
+	assert.False(isMarked(f.Dequeue(1)), "sojourn below target")
+	f.Now = t0 + C.TscTime(eal.ToTscDuration(10*time.Millisecond))
+	assert.False(isMarked(f.Dequeue(1)), "sojourn above target, first interval")
+	f.Now = t0 + C.TscTime(eal.ToTscDuration(40*time.Millisecond))
+	assert.True(isMarked(f.Dequeue(1)), "sojourn above target for an interval")
+	assert.EqualValues(1, f.sh.codel.nDrops)
+
+	f.Send(makeInterests("B", 1)...)
+	assert.False(isMarked(f.Dequeue(1)), "sojourn below target")
+	assert.EqualValues(1, f.sh.codel.nDrops)
+}
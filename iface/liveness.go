@@ -0,0 +1,137 @@
+package iface
+
+/*
+#include "../csrc/iface/face.h"
+*/
+import "C"
+import (
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/core/cptr"
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+	"go.uber.org/zap"
+)
+
+// Limits and defaults of LivenessConfig.
+const (
+	MinLivenessInterval     = 10 * time.Millisecond
+	DefaultLivenessInterval = 300 * time.Millisecond
+
+	DefaultLivenessDetectMultiplier = 3
+)
+
+// LivenessConfig contains face liveness monitor configuration.
+//
+// The liveness monitor operates similar to BFD asynchronous mode.
+// The TX thread sends an NDNLPv2 IDLE packet (an empty LpPacket) whenever the face has not transmitted any frame for Interval.
+// The monitor checks RX counters every Interval, and considers the peer alive if any frame, including IDLE packets, has been received.
+// After DetectMultiplier consecutive intervals without receiving any frame, the face is marked DOWN.
+// It is marked UP again as soon as a frame is received.
+//
+// Both ends should enable the liveness monitor with similar Interval, otherwise an idle link would be mistaken as a dead link.
+// The liveness monitor is ineffective on pass-through faces.
+type LivenessConfig struct {
+	// Interval is the interval between IDLE packets and between liveness checks.
+	//
+	// The minimum is MinLivenessInterval.
+	// If this value is zero, it defaults to DefaultLivenessInterval.
+	Interval nnduration.Milliseconds `json:"interval,omitempty"`
+
+	// DetectMultiplier is the number of consecutive intervals without received frames, after which the face is marked DOWN.
+	//
+	// If this value is zero or negative, it defaults to DefaultLivenessDetectMultiplier.
+	DetectMultiplier int `json:"detectMultiplier,omitempty"`
+}
+
+func (cfg *LivenessConfig) applyDefaults() {
+	if cfg.Interval == 0 {
+		cfg.Interval = nnduration.Milliseconds(DefaultLivenessInterval / time.Millisecond)
+	}
+	cfg.Interval = max(cfg.Interval, nnduration.Milliseconds(MinLivenessInterval/time.Millisecond))
+	if cfg.DetectMultiplier <= 0 {
+		cfg.DetectMultiplier = DefaultLivenessDetectMultiplier
+	}
+}
+
+// livenessMonitor marks a face DOWN or UP according to received frames.
+type livenessMonitor struct {
+	f      *face
+	cfg    LivenessConfig
+	logger *zap.Logger
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+func (m *livenessMonitor) rxOctets() (n uint64) {
+	c := m.f.ptr()
+	for i := range c.impl.rx {
+		n += uint64(c.impl.rx[i].nFrames[C.FaceRxThread_cntNOctets])
+	}
+	return n
+}
+
+func (m *livenessMonitor) run() {
+	defer close(m.done)
+	ticker := time.NewTicker(m.cfg.Interval.Duration())
+	defer ticker.Stop()
+
+	lastRx, missed, downByMonitor := m.rxOctets(), 0, false
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+
+		if downByMonitor && !IsDown(m.f.id) { // administratively brought up
+			downByMonitor, missed = false, 0
+		}
+
+		if rx := m.rxOctets(); rx != lastRx {
+			lastRx, missed = rx, 0
+			if downByMonitor {
+				downByMonitor = false
+				m.logger.Info("face liveness restored")
+				m.setDown(false)
+			}
+			continue
+		}
+
+		if missed++; missed >= m.cfg.DetectMultiplier && !downByMonitor && !IsDown(m.f.id) {
+			downByMonitor = true
+			m.logger.Info("face liveness lost", zap.Int("missed", missed))
+			m.setDown(true)
+		}
+	}
+}
+
+// setDown changes face UP/DOWN state on the main thread, where face state changes are serialized.
+func (m *livenessMonitor) setDown(isDown bool) {
+	eal.PostMain(cptr.Func0.Void(func() {
+		if Get(m.f.id) == Face(m.f) { // face may have been closed before this runs
+			m.f.SetDown(isDown)
+		}
+	}))
+}
+
+func (m *livenessMonitor) Close() error {
+	close(m.stop)
+	<-m.done
+	return nil
+}
+
+func startLivenessMonitor(f *face, cfg LivenessConfig) *livenessMonitor {
+	cfg.applyDefaults()
+	f.ptr().impl.txIdleInterval = C.TscDuration(eal.ToTscDuration(cfg.Interval.Duration()))
+
+	m := &livenessMonitor{
+		f:      f,
+		cfg:    cfg,
+		logger: logger.With(f.id.ZapField("face")),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go m.run()
+	return m
+}
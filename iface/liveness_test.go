@@ -0,0 +1,75 @@
+package iface_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
+	"github.com/usnistgov/ndn-dpdk/iface"
+	"github.com/usnistgov/ndn-dpdk/iface/intface"
+	"github.com/usnistgov/ndn-dpdk/iface/socketface"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+)
+
+func TestLiveness(t *testing.T) {
+	assert, require := makeAR(t)
+
+	face := intface.Must(intface.New(socketface.Config{
+		Config: iface.Config{
+			Liveness: &iface.LivenessConfig{
+				Interval:         nnduration.Milliseconds(20),
+				DetectMultiplier: 3,
+			},
+		},
+	}))
+	defer face.D.Close()
+	collect := intface.Collect(face)
+
+	downs, ups := make(chan struct{}, 16), make(chan struct{}, 16)
+	defer iface.OnFaceDown(func(id iface.ID) {
+		if id == face.ID {
+			downs <- struct{}{}
+		}
+	})()
+	defer iface.OnFaceUp(func(id iface.ID) {
+		if id == face.ID {
+			ups <- struct{}{}
+		}
+	})()
+	waitEvent := func(ch <-chan struct{}) bool {
+		select {
+		case <-ch:
+			return true
+		case <-time.After(2 * time.Second):
+			return false
+		}
+	}
+
+	// application side is silent
+	require.True(waitEvent(downs))
+	assert.True(iface.IsDown(face.ID))
+	assert.Zero(collect.Count())
+	cnt := face.D.Counters()
+	assert.NotZero(cnt.TxIdles)
+
+	// application side transmits
+	face.Tx <- ndn.MakeInterest("/A")
+	require.True(waitEvent(ups))
+	assert.False(iface.IsDown(face.ID))
+
+	// administratively down face is not brought up by the liveness monitor
+	adminDown := true
+	require.NoError(face.D.Update(iface.UpdateConfig{AdminDown: &adminDown}))
+	require.True(waitEvent(downs))
+	nRx := face.D.Counters().RxFrames
+	face.Tx <- ndn.MakeInterest("/B")
+	require.Eventually(func() bool { return face.D.Counters().RxFrames > nRx }, time.Second, time.Millisecond)
+	// the monitor would have brought the face up within one interval, had it not been administratively down
+	select {
+	case <-ups:
+		assert.Fail("face brought up while administratively down")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.True(iface.IsDown(face.ID))
+	assert.Empty(downs)
+}
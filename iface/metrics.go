@@ -0,0 +1,19 @@
+package iface
+
+import (
+	"strconv"
+
+	"github.com/usnistgov/ndn-dpdk/core/metrics"
+)
+
+func init() {
+	metrics.Register("iface", func(c *metrics.Collection) {
+		for _, face := range List() {
+			labels := metrics.Labels{
+				"face":   strconv.Itoa(int(face.ID())),
+				"scheme": face.Locator().Scheme(),
+			}
+			c.AddStruct("face_", face.Counters(), labels)
+		}
+	})
+}
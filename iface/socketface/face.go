@@ -114,6 +114,12 @@ func Wrap(transport sockettransport.Transport, cfg Config) (iface.Face, error) {
 			iface.DeactivateTxFace(face)
 			return nil
 		},
+		ValidateUpdate: func(cfg iface.UpdateConfig) error {
+			if mtu := face.transport.MTU(); cfg.MTU > mtu {
+				return fmt.Errorf("socket face MTU cannot exceed transport MTU %d", mtu)
+			}
+			return nil
+		},
 		Close: func() error {
 			return nil
 		},
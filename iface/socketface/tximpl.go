@@ -2,7 +2,7 @@ package socketface
 
 /*
 #include "../../csrc/socketface/face.h"
-extern uint16_t go_SocketFace_TxBurst(Face* faceC, struct rte_mbuf** pkts, uint16_t nPkts);
+extern uint16_t go_SocketFace_TxBurst(Face* faceC, int txThread, struct rte_mbuf** pkts, uint16_t nPkts);
 STATIC_ASSERT_FUNC_TYPE(Face_TxBurstFunc, go_SocketFace_TxBurst);
 */
 import "C"
@@ -27,7 +27,7 @@ func (impl *txImpl) String() string {
 }
 
 //export go_SocketFace_TxBurst
-func go_SocketFace_TxBurst(faceC *C.Face, pkts **C.struct_rte_mbuf, nPkts C.uint16_t) C.uint16_t {
+func go_SocketFace_TxBurst(faceC *C.Face, txThread C.int, pkts **C.struct_rte_mbuf, nPkts C.uint16_t) C.uint16_t {
 	face := iface.Get(iface.ID(faceC.id)).(*socketFace)
 	vec := pktmbuf.VectorFromPtr(unsafe.Pointer(pkts), int(nPkts))
 	defer vec.Close()
@@ -7,6 +7,7 @@ import "C"
 import (
 	"io"
 	"math"
+	"slices"
 	"sync"
 	"unsafe"
 
@@ -34,9 +35,14 @@ type TxLoop interface {
 	ealthread.ThreadWithLoadStat
 	io.Closer
 
+	// CountFaces returns number of face TX threads in this TxLoop.
 	CountFaces() int
-	Add(face Face)
-	Remove(face Face)
+
+	// Add adds a face TX thread.
+	Add(face Face, txThread int)
+
+	// Remove removes a face TX thread.
+	Remove(face Face, txThread int)
 }
 
 // NewTxLoop creates a TxLoop.
@@ -88,58 +94,71 @@ func (txl *txLoop) CountFaces() int {
 	return txl.nFaces
 }
 
-func (txl *txLoop) Add(face Face) {
+func (txl *txLoop) Add(face Face, txThread int) {
 	txLoopLock.Lock()
 	defer txLoopLock.Unlock()
 
-	id := face.ID()
+	key := faceTxThread{face.ID(), txThread}
 	logEntry := logger.With(
 		zap.Uintptr("txl-ptr", uintptr(unsafe.Pointer(txl.c))),
 		txl.LCore().ZapField("txl-lc"),
-		id.ZapField("face"),
+		key.id.ZapField("face"),
+		zap.Int("tx-thread", txThread),
 	)
 
-	if mapFaceTxl[id] != nil {
-		logEntry.Panic("face is in another TxLoop")
+	if txThread < 0 || txThread >= face.NTxThreads() {
+		logEntry.Panic("TX thread index out of range")
+	}
+	if mapFaceTxl[key] != nil {
+		logEntry.Panic("face TX thread is in another TxLoop")
 	}
-	mapFaceTxl[id] = txl
+	mapFaceTxl[key] = txl
 	txl.nFaces++
 
-	logEntry.Debug("adding face to TxLoop")
-	faceC := (*C.Face)(face.Ptr())
-	C.cds_hlist_add_head_rcu(&faceC.txlNode, &txl.c.head)
+	logEntry.Debug("adding face TX thread to TxLoop")
+	txt := &(*C.Face)(face.Ptr()).impl.tx[txThread]
+	C.cds_hlist_add_head_rcu(&txt.txlNode, &txl.c.head)
 }
 
-func (txl *txLoop) Remove(face Face) {
+func (txl *txLoop) Remove(face Face, txThread int) {
 	txLoopLock.Lock()
 	defer txLoopLock.Unlock()
 
-	id := face.ID()
+	key := faceTxThread{face.ID(), txThread}
 	logEntry := logger.With(
 		zap.Uintptr("txl-ptr", uintptr(unsafe.Pointer(txl.c))),
 		txl.LCore().ZapField("txl-lc"),
-		id.ZapField("face"),
+		key.id.ZapField("face"),
+		zap.Int("tx-thread", txThread),
 	)
 
-	if mapFaceTxl[id] != txl {
-		logEntry.Panic("face is not in this TxLoop")
+	if mapFaceTxl[key] != txl {
+		logEntry.Panic("face TX thread is not in this TxLoop")
 	}
-	delete(mapFaceTxl, id)
+	delete(mapFaceTxl, key)
 	txl.nFaces--
 
-	logEntry.Debug("removing face from TxLoop")
-	faceC := (*C.Face)(face.Ptr())
-	C.cds_hlist_del_rcu(&faceC.txlNode)
+	logEntry.Debug("removing face TX thread from TxLoop")
+	txt := &(*C.Face)(face.Ptr()).impl.tx[txThread]
+	C.cds_hlist_del_rcu(&txt.txlNode)
 	urcu.Synchronize()
 }
 
+// faceTxThread identifies a TX thread of a face.
+type faceTxThread struct {
+	id    ID
+	index int
+}
+
 var (
 	// ChooseTxLoop customizes TxLoop selection in ActivateTxFace.
+	// It is invoked once per TX thread of the face; if it returns non-nil, the TX thread is added
+	// to the returned TxLoop.
 	// Return nil to use default algorithm.
-	ChooseTxLoop = func(face Face) TxLoop { return nil }
+	ChooseTxLoop = func(face Face, txThread int) TxLoop { return nil }
 
 	txLoopThreads = set.NewMapset[TxLoop]()
-	mapFaceTxl    = map[ID]TxLoop{}
+	mapFaceTxl    = map[faceTxThread]TxLoop{}
 	txLoopLock    sync.Mutex
 )
 
@@ -150,30 +169,28 @@ func ListTxLoops() (list []TxLoop) {
 	return txLoopThreads.Keys()
 }
 
-// ActivateTxFace selects an TxLoop and adds the face to it.
-// Returns chosen TxLoop.
-//
-// The default logic selects among existing TxLoops for the least loaded one, preferably on the
-// same NUMA socket as the face. In case no TxLoop exists, one is created and launched
-// automatically. This does not respect LCoreAlloc, and may panic if no LCore is available.
-//
-// This logic may be overridden via ChooseTxLoop.
-func ActivateTxFace(face Face) TxLoop {
-	if txl := ChooseTxLoop(face); txl != nil {
-		txl.Add(face)
-		return txl
+// ListFaceTxLoops returns TxLoops serving each TX thread of a face.
+// Inactive TX threads have nil elements.
+func ListFaceTxLoops(face Face) (list []TxLoop) {
+	txLoopLock.Lock()
+	defer txLoopLock.Unlock()
+	list = make([]TxLoop, face.NTxThreads())
+	for i := range list {
+		list[i] = mapFaceTxl[faceTxThread{face.ID(), i}]
 	}
+	return list
+}
 
+// SelectTxLoop selects a TxLoop for a face TX thread, without adding the face into it.
+//
+// The default logic selects among existing TxLoops for the least loaded one, preferably on the
+// same NUMA socket as the face, and preferably not among avoid list.
+// In case no TxLoop exists, or every existing TxLoop is among avoid list, a new TxLoop is created
+// and launched on an LCore allocated for RoleTx, so that TX threads of the same face are spread
+// across TxLoops. If no LCore is available, an existing TxLoop is reused, or this function panics
+// if no TxLoop exists.
+func SelectTxLoop(face Face, avoid ...TxLoop) TxLoop {
 	socket := face.NumaSocket()
-	if txLoopThreads.Size() == 0 {
-		txl := NewTxLoop(socket)
-		if e := ealthread.AllocLaunch(txl); e != nil {
-			logger.Panic("no RxLoop available and cannot launch new RxLoop", zap.Error(e))
-		}
-		txl.Add(face)
-		return txl
-	}
-
 	var bestTxl TxLoop
 	bestScore := math.MaxInt32
 	txLoopThreads.Each(func(txl TxLoop) {
@@ -181,18 +198,75 @@ func ActivateTxFace(face Face) TxLoop {
 		if !socket.Match(txl.NumaSocket()) {
 			score += 1000000
 		}
+		if slices.Contains(avoid, txl) {
+			score += 10000
+		}
 		if score <= bestScore {
 			bestTxl, bestScore = txl, score
 		}
 	})
-	bestTxl.Add(face)
+	if bestTxl != nil && !slices.Contains(avoid, bestTxl) {
+		return bestTxl
+	}
+
+	txl := NewTxLoop(socket)
+	e := ealthread.AllocLaunch(txl)
+	switch {
+	case e == nil:
+		return txl
+	case bestTxl == nil:
+		logger.Panic("no TxLoop available and cannot launch new TxLoop", zap.Error(e))
+	}
+	txl.Close()
+	logger.Debug("cannot launch new TxLoop, sharing TxLoop among TX threads of the same face",
+		face.ID().ZapField("face"),
+		zap.Error(e),
+	)
 	return bestTxl
 }
 
-// DeactivateTxFace removes the Face from the owning TxLoop.
+// ActivateTxFace selects TxLoops and adds every TX thread of the face.
+// Returns chosen TxLoop of each TX thread.
+//
+// The default logic invokes SelectTxLoop for each TX thread, so that TX threads of the same face
+// are preferably placed on distinct TxLoops.
+//
+// This logic may be overridden per TX thread via ChooseTxLoop.
+func ActivateTxFace(face Face) (list []TxLoop) {
+	list = make([]TxLoop, face.NTxThreads())
+	for i := range list {
+		if list[i] = ChooseTxLoop(face, i); list[i] == nil {
+			list[i] = SelectTxLoop(face, list[:i]...)
+		}
+		list[i].Add(face, i)
+	}
+	return list
+}
+
+// pauseTxFace temporarily removes the face from its TxLoops while f is executing.
+// Packets enqueued during this period are transmitted after the face is added back.
+func pauseTxFace(face Face, f func()) {
+	list := ListFaceTxLoops(face)
+	for i, txl := range list {
+		if txl != nil {
+			txl.Remove(face, i)
+		}
+	}
+	defer func() {
+		for i, txl := range list {
+			if txl != nil {
+				txl.Add(face, i)
+			}
+		}
+	}()
+	f()
+}
+
+// DeactivateTxFace removes every TX thread of the Face from the owning TxLoops.
 func DeactivateTxFace(face Face) {
-	txLoopLock.Lock()
-	txl := mapFaceTxl[face.ID()]
-	txLoopLock.Unlock()
-	txl.Remove(face)
+	for i, txl := range ListFaceTxLoops(face) {
+		if txl != nil {
+			txl.Remove(face, i)
+		}
+	}
 }
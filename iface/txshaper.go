@@ -0,0 +1,195 @@
+package iface
+
+/*
+#include "../csrc/iface/txshaper.h"
+*/
+import "C"
+import (
+	"fmt"
+	"time"
+	"unsafe"
+
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+	"github.com/usnistgov/ndn-dpdk/dpdk/pktmbuf"
+	"github.com/usnistgov/ndn-dpdk/dpdk/ringbuffer"
+	"github.com/usnistgov/ndn-dpdk/ndni"
+	"github.com/zyedidia/generic"
+)
+
+// Limits and defaults of TxShaperConfig.
+const (
+	DefaultTxShaperBurst = 10 * time.Millisecond
+
+	MinTxShaperQueueCapacity     = 64
+	DefaultTxShaperQueueCapacity = 1024
+
+	MaxTxShaperWeight = 65536
+
+	DefaultTxCoDelTarget   = 5 * time.Millisecond
+	DefaultTxCoDelInterval = 100 * time.Millisecond
+)
+
+// TxScheduler indicates the egress scheduling algorithm among L3 packet types.
+type TxScheduler string
+
+// TxScheduler values.
+const (
+	// TxSchedulerFIFO transmits L3 packets in arrival order.
+	TxSchedulerFIFO TxScheduler = "fifo"
+
+	// TxSchedulerPriority transmits L3 packets in strict priority order.
+	// A packet type with greater weight is served first.
+	// Ties are broken in Nack, Data, Interest order.
+	TxSchedulerPriority TxScheduler = "priority"
+
+	// TxSchedulerWRR transmits L3 packets in weighted round robin.
+	// In each round, a packet type can send up to its weight number of packets.
+	TxSchedulerWRR TxScheduler = "wrr"
+)
+
+var txSchedulerKinds = map[TxScheduler]C.TxSchedulerKind{
+	"":                  C.TxSchedulerFifo,
+	TxSchedulerFIFO:     C.TxSchedulerFifo,
+	TxSchedulerPriority: C.TxSchedulerPriority,
+	TxSchedulerWRR:      C.TxSchedulerWrr,
+}
+
+// TxShaperConfig contains egress traffic shaping and scheduling configuration.
+type TxShaperConfig struct {
+	// BitRate is the maximum output rate in bits per second.
+	// It counts NDNLPv2 frames, excluding lower layer headers such as Ethernet/UDP headers.
+	// Zero means unlimited.
+	BitRate uint64 `json:"bitRate,omitempty"`
+
+	// PacketRate is the maximum output rate in frames per second.
+	// Zero means unlimited.
+	PacketRate uint64 `json:"packetRate,omitempty"`
+
+	// Burst is the token bucket depth, expressed as the duration of transmission at configured rates.
+	// Default is DefaultTxShaperBurst.
+	Burst nnduration.Nanoseconds `json:"burst,omitempty"`
+
+	// Scheduler selects the egress scheduling algorithm among L3 packet types.
+	// Default is TxSchedulerFIFO.
+	Scheduler TxScheduler `json:"scheduler,omitempty"`
+
+	// InterestWeight is the weight or priority of Interest packets.
+	// Default is 1.
+	InterestWeight int `json:"interestWeight,omitempty"`
+
+	// DataWeight is the weight or priority of Data packets.
+	// Default is 1.
+	DataWeight int `json:"dataWeight,omitempty"`
+
+	// NackWeight is the weight or priority of Nack packets.
+	// Default is 1.
+	NackWeight int `json:"nackWeight,omitempty"`
+
+	// QueueCapacity is the capacity of each per L3 type queue.
+	// It is effective only if Scheduler is not TxSchedulerFIFO.
+	//
+	// The minimum is MinTxShaperQueueCapacity.
+	// If this value is less than the minimum, it defaults to DefaultTxShaperQueueCapacity.
+	// Otherwise, it is adjusted up to the next power of 2.
+	QueueCapacity int `json:"queueCapacity,omitempty"`
+
+	// CoDel enables CoDel active queue management.
+	// If omitted, L3 packets are not congestion-marked by the TX thread.
+	CoDel *TxCoDelConfig `json:"coDel,omitempty"`
+}
+
+// TxCoDelConfig contains CoDel active queue management configuration on face output.
+//
+// The sojourn time is measured from when an L3 packet is enqueued into the face output queue to
+// when it is dequeued by the TX thread, including time spent in per L3 type queues.
+// When CoDel decides to drop a packet, the packet is transmitted with NDNLPv2 CongestionMark.
+type TxCoDelConfig struct {
+	// Target is the CoDel TARGET parameter.
+	// Default is DefaultTxCoDelTarget.
+	Target nnduration.Nanoseconds `json:"target,omitempty"`
+
+	// Interval is the CoDel INTERVAL parameter.
+	// Default is DefaultTxCoDelInterval.
+	Interval nnduration.Nanoseconds `json:"interval,omitempty"`
+}
+
+func (cfg *TxShaperConfig) applyDefaults() error {
+	if _, ok := txSchedulerKinds[cfg.Scheduler]; !ok {
+		return fmt.Errorf("unknown TX scheduler %s", cfg.Scheduler)
+	}
+	for _, w := range []*int{&cfg.InterestWeight, &cfg.DataWeight, &cfg.NackWeight} {
+		*w = generic.Clamp(*w, 1, MaxTxShaperWeight)
+	}
+	cfg.QueueCapacity = ringbuffer.AlignCapacity(cfg.QueueCapacity, MinTxShaperQueueCapacity, DefaultTxShaperQueueCapacity)
+	return nil
+}
+
+func newTxShaper(cfg TxShaperConfig, socket eal.NumaSocket) (sh *C.TxShaper, e error) {
+	if e = cfg.applyDefaults(); e != nil {
+		return nil, e
+	}
+
+	sh = eal.Zmalloc[C.TxShaper]("TxShaper", C.sizeof_TxShaper, socket)
+	sh.sched = txSchedulerKinds[cfg.Scheduler]
+	sh.weights[ndni.PktInterest] = C.uint32_t(cfg.InterestWeight)
+	sh.weights[ndni.PktData] = C.uint32_t(cfg.DataWeight)
+	sh.weights[ndni.PktNack] = C.uint32_t(cfg.NackWeight)
+	C.TxShaper_Init(sh)
+
+	burst := cfg.Burst.DurationOr(nnduration.Nanoseconds(DefaultTxShaperBurst)).Seconds()
+	now := C.TscTime(eal.TscNow())
+	C.TokenBucket_Init(&sh.octets, C.uint64_t(cfg.BitRate/8), C.uint64_t(float64(cfg.BitRate/8)*burst), now)
+	C.TokenBucket_Init(&sh.frames, C.uint64_t(cfg.PacketRate), C.uint64_t(float64(cfg.PacketRate)*burst), now)
+
+	if cfg.CoDel != nil {
+		sh.codel.pop = C.PktQueuePopActCoDel
+		sh.codel.target = C.TscDuration(eal.ToTscDuration(cfg.CoDel.Target.DurationOr(nnduration.Nanoseconds(DefaultTxCoDelTarget))))
+		sh.codel.interval = C.TscDuration(eal.ToTscDuration(cfg.CoDel.Interval.DurationOr(nnduration.Nanoseconds(DefaultTxCoDelInterval))))
+	}
+
+	if sh.sched != C.TxSchedulerFifo {
+		for _, t := range []ndni.PktType{ndni.PktInterest, ndni.PktData, ndni.PktNack} {
+			q, e := ringbuffer.New(cfg.QueueCapacity, socket, ringbuffer.ProducerSingle, ringbuffer.ConsumerSingle)
+			if e != nil {
+				closeTxShaper(sh)
+				return nil, e
+			}
+			sh.queues[t] = (*C.struct_rte_ring)(q.Ptr())
+		}
+	}
+	return sh, nil
+}
+
+// txShaperNeedsTimestamp determines whether TxShaper CoDel requires Face_TxBurst to set enqueue timestamp.
+func txShaperNeedsTimestamp(sh *C.TxShaper) bool {
+	return sh != nil && sh.codel.pop == C.PktQueuePopActCoDel
+}
+
+func closeTxShaper(sh *C.TxShaper) {
+	vec := make(pktmbuf.Vector, MaxBurstSize)
+	for i, q := range sh.queues {
+		ring := ringbuffer.FromPtr(unsafe.Pointer(q))
+		if ring == nil {
+			continue
+		}
+		for {
+			n := ringbuffer.Dequeue(ring, vec)
+			if n == 0 {
+				break
+			}
+			vec[:n].Close()
+		}
+		ring.Close()
+		sh.queues[i] = nil
+	}
+	eal.Free(sh)
+}
+
+func (cnt *TxCounters) readTxShaper(sh *C.TxShaper) {
+	cnt.TxShaperThrottles = uint64(sh.nThrottles)
+	cnt.TxCoDelMarks = uint64(sh.codel.nDrops)
+	for _, n := range sh.nQueueDrops {
+		cnt.TxSchedDrops += uint64(n)
+	}
+}
@@ -0,0 +1,129 @@
+package iface_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
+	"github.com/usnistgov/ndn-dpdk/iface"
+	"github.com/usnistgov/ndn-dpdk/iface/intface"
+	"github.com/usnistgov/ndn-dpdk/iface/socketface"
+	"github.com/usnistgov/ndn-dpdk/ndni"
+	"github.com/usnistgov/ndn-dpdk/ndni/ndnitestenv"
+)
+
+// Token bucket and scheduler arithmetics are tested with synthetic clock in package ifacetest.
+// These tests only verify that TxShaper is wired into TxLoop, and avoid timing-sensitive assertions.
+
+func TestTxShaperRate(t *testing.T) {
+	assert, require := makeAR(t)
+
+	face := intface.Must(intface.New(socketface.Config{
+		Config: iface.Config{
+			TxShaper: &iface.TxShaperConfig{
+				PacketRate: 200,
+				Burst:      nnduration.Nanoseconds(50 * time.Millisecond),
+			},
+		},
+	}))
+	defer face.D.Close()
+	collect := intface.Collect(face)
+
+	pkts := make([]*ndni.Packet, 100)
+	for i := range pkts {
+		pkts[i] = ndnitestenv.MakeInterest(fmt.Sprintf("/A/%d", i))
+	}
+	t0 := time.Now()
+	iface.TxBurst(face.ID, pkts)
+
+	require.Eventually(func() bool { return collect.Count() == len(pkts) }, 5*time.Second, 10*time.Millisecond)
+	// 10 packets are sent in the initial burst, the other 90 packets take at least 450ms at 200pps
+	assert.GreaterOrEqual(time.Since(t0), 400*time.Millisecond)
+
+	cnt := face.D.Counters()
+	assert.NotZero(cnt.TxShaperThrottles)
+	assert.Zero(cnt.TxSchedDrops)
+	assert.Zero(cnt.TxCoDelMarks)
+	assert.Zero(cnt.TxRejects)
+}
+
+func TestTxShaperPriority(t *testing.T) {
+	assert, require := makeAR(t)
+
+	face := intface.Must(intface.New(socketface.Config{
+		Config: iface.Config{
+			TxShaper: &iface.TxShaperConfig{
+				PacketRate:     100,
+				Burst:          nnduration.Nanoseconds(10 * time.Millisecond),
+				Scheduler:      iface.TxSchedulerPriority,
+				InterestWeight: 2,
+				DataWeight:     1,
+				QueueCapacity:  64,
+			},
+		},
+	}))
+	defer face.D.Close()
+	collect := intface.Collect(face)
+
+	pkts := make([]*ndni.Packet, 100)
+	for i := range pkts {
+		if i < 50 {
+			pkts[i] = ndnitestenv.MakeData(fmt.Sprintf("/D/%d", i))
+		} else {
+			pkts[i] = ndnitestenv.MakeInterest(fmt.Sprintf("/I/%d", i))
+		}
+	}
+	iface.TxBurst(face.ID, pkts)
+
+	require.Eventually(func() bool { return collect.Count() == len(pkts) }, 5*time.Second, 10*time.Millisecond)
+	nInterests, nData := 0, 0
+	for _, pkt := range collect.Clear()[:len(pkts)/2] {
+		switch {
+		case pkt.Interest != nil:
+			nInterests++
+		case pkt.Data != nil:
+			nData++
+		}
+	}
+	assert.Greater(nInterests, nData)
+	assert.Zero(face.D.Counters().TxSchedDrops)
+}
+
+func TestTxShaperCoDel(t *testing.T) {
+	assert, require := makeAR(t)
+
+	face := intface.Must(intface.New(socketface.Config{
+		Config: iface.Config{
+			TxShaper: &iface.TxShaperConfig{
+				PacketRate: 100,
+				Burst:      nnduration.Nanoseconds(10 * time.Millisecond),
+				CoDel: &iface.TxCoDelConfig{
+					Target:   nnduration.Nanoseconds(5 * time.Millisecond),
+					Interval: nnduration.Nanoseconds(20 * time.Millisecond),
+				},
+			},
+		},
+	}))
+	defer face.D.Close()
+	collect := intface.Collect(face)
+
+	pkts := make([]*ndni.Packet, 40)
+	for i := range pkts {
+		pkts[i] = ndnitestenv.MakeInterest(fmt.Sprintf("/A/%d", i))
+	}
+	iface.TxBurst(face.ID, pkts)
+
+	require.Eventually(func() bool { return collect.Count() == len(pkts) }, 5*time.Second, 10*time.Millisecond)
+	nMarked := 0
+	for _, pkt := range collect.Clear() {
+		if pkt.Lp.CongMark != 0 {
+			nMarked++
+		}
+	}
+	assert.NotZero(nMarked)
+	assert.Less(nMarked, len(pkts)) // packets before first interval elapses are not marked
+
+	cnt := face.D.Counters()
+	assert.EqualValues(nMarked, cnt.TxCoDelMarks)
+}
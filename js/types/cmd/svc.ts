@@ -1,4 +1,4 @@
-import type { EalConfig, LCoreAllocConfig, PktmbufPoolTemplateUpdates } from "../dpdk.js";
+import type { EalConfig, LCoreAllocConfig, PktmbufPoolTemplateUpdates, ResourceMonitorConfig } from "../dpdk.js";
 import type { FwdpConfig } from "../fwdp.js";
 import type { FaceLocator, SocketFaceGlobalConfig } from "../iface.js";
 import type { FileServerConfig } from "../tg/mod.js";
@@ -9,6 +9,8 @@ export interface ActivateArgsCommon<Roles extends string = never> {
   lcoreAlloc?: LCoreAllocConfig<Roles | "HRLOG" | "PDUMP">;
 
   socketFace?: SocketFaceGlobalConfig;
+
+  resourceMonitor?: ResourceMonitorConfig;
 }
 
 /**
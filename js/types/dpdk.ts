@@ -81,6 +81,32 @@ export interface PktmbufPoolConfig {
  */
 export type PktmbufPoolTemplateUpdates<K extends string = string> = Partial<Record<K, PktmbufPoolConfig>>;
 
+/**
+ * Mempool and PCCT utilization monitoring configuration.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/dpdk/mempool#MonitorConfig>
+ */
+export interface ResourceMonitorConfig {
+  /**
+   * Polling interval in milliseconds.
+   * @default 1000
+   */
+  interval?: Uint;
+
+  /**
+   * Utilization ratio at or above which a resource is under pressure.
+   * @exclusiveMinimum 0
+   * @maximum 1
+   * @default 0.9
+   */
+  highWaterMark?: number;
+
+  /**
+   * Per-resource high-water marks, keyed by resource name, such as mempool template ID, or resource kind, such as "PCCT".
+   * If both match, the resource name takes priority.
+   */
+  highWaterMarks?: Record<string, number>;
+}
+
 /**
  * EthDev selection and creation arguments.
  * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/dpdk/ethdev/ethnetif#Config>
@@ -21,6 +21,7 @@ export interface FwdpConfig {
   fwdDataQueue?: PktQueueConfig;
   fwdNackQueue?: PktQueueConfig;
   latencySampleInterval?: Uint;
+  prefixStat?: FwdpPrefixStatConfig;
 }
 
 export interface FwdpCryptoConfig {
@@ -28,6 +29,28 @@ export interface FwdpCryptoConfig {
   opPoolCapacity?: Uint;
 }
 
+export interface FwdpPrefixStatConfig {
+  /**
+   * Name prefix length in components; zero disables per-prefix traffic accounting.
+   * @default 0
+   */
+  depth?: Uint;
+
+  /**
+   * Number of tracked prefixes per forwarding thread.
+   * @minimum 1
+   * @maximum 4096
+   * @default 256
+   */
+  capacity?: Uint;
+
+  /**
+   * Sampling interval, adjusted up to the next power of two.
+   * @default 16
+   */
+  sampleInterval?: Uint;
+}
+
 export type FwdpDiskConfig = BdevLocator & {
   /**
    * @min 1.00
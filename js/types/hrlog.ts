@@ -2,9 +2,15 @@ import type { Counter } from "./core.js";
 import type { LCore } from "./dpdk.js";
 
 export enum HrlogAction {
+  TS = 0,
   OI = 1,
   OD = 2,
   OC = 4,
+  FQ = 8,
+  SG = 16,
+  CR = 32,
+  DK = 64,
+  TQ = 128,
 }
 
 export interface HrlogHistogram {
@@ -12,3 +18,19 @@ export interface HrlogHistogram {
   LCore: LCore;
   Counts: Counter[];
 }
+
+/** ndndpdk-hrlog-analyze JSON output line. */
+export interface HrlogStatsRow {
+  window: number;
+  /** Window start time, in seconds since first time marker. */
+  start: number;
+  action: keyof typeof HrlogAction;
+  /** LCore ID; omitted in aggregate row. */
+  lcore?: LCore;
+  count: number;
+  /** Durations in microseconds. */
+  min: number;
+  mean: number;
+  max: number;
+  percentiles: Record<string, number>;
+}
@@ -1,4 +1,4 @@
-import type { Counter, NNMilliseconds, Uint } from "./core.js";
+import type { Counter, NNMilliseconds, NNNanoseconds, Uint } from "./core.js";
 import type { EthNetifConfig } from "./dpdk.js";
 
 /**
@@ -37,6 +37,114 @@ export interface FaceConfig {
    * @maximum 65000
    */
   mtu?: Uint;
+
+  txShaper?: TxShaperConfig;
+
+  liveness?: LivenessConfig;
+}
+
+/**
+ * Face configuration changes.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/iface#UpdateConfig>
+ */
+export interface FaceUpdateConfig {
+  /**
+   * @minimum 960
+   * @maximum 65000
+   */
+  mtu?: Uint;
+
+  /**
+   * @minimum 4
+   * @maximum 8192
+   */
+  reassemblerCapacity?: Uint;
+
+  txShaper?: TxShaperConfig;
+  disableTxShaper?: boolean;
+  adminDown?: boolean;
+}
+
+/**
+ * Face liveness monitor configuration.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/iface#LivenessConfig>
+ */
+export interface LivenessConfig {
+  /**
+   * @minimum 10
+   * @default 300
+   */
+  interval?: NNMilliseconds;
+
+  /**
+   * @minimum 1
+   * @default 3
+   */
+  detectMultiplier?: Uint;
+}
+
+/**
+ * Egress traffic shaping and scheduling configuration.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/iface#TxShaperConfig>
+ */
+export interface TxShaperConfig {
+  bitRate?: Uint;
+  packetRate?: Uint;
+
+  /**
+   * @default 10000000
+   */
+  burst?: NNNanoseconds;
+
+  /**
+   * @default "fifo"
+   */
+  scheduler?: "fifo" | "priority" | "wrr";
+
+  /**
+   * @minimum 1
+   * @maximum 65536
+   * @default 1
+   */
+  interestWeight?: Uint;
+
+  /**
+   * @minimum 1
+   * @maximum 65536
+   * @default 1
+   */
+  dataWeight?: Uint;
+
+  /**
+   * @minimum 1
+   * @maximum 65536
+   * @default 1
+   */
+  nackWeight?: Uint;
+
+  /**
+   * @minimum 64
+   * @default 1024
+   */
+  queueCapacity?: Uint;
+
+  coDel?: TxCoDelConfig;
+}
+
+/**
+ * CoDel active queue management configuration on face output.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/iface#TxCoDelConfig>
+ */
+export interface TxCoDelConfig {
+  /**
+   * @default 5000000
+   */
+  target?: NNNanoseconds;
+
+  /**
+   * @default 100000000
+   */
+  interval?: NNNanoseconds;
 }
 
 /**
@@ -56,6 +164,13 @@ export type EthPortConfig = EthNetifConfig & {
    */
   txQueueSize?: Uint;
 
+  /**
+   * @minimum 1
+   * @maximum 4
+   * @default 1
+   */
+  txQueues?: Uint;
+
   /**
    * @minimum 960
    * @maximum 65000
@@ -63,8 +178,23 @@ export type EthPortConfig = EthNetifConfig & {
   mtu?: Uint;
 
   rxFlowQueues?: number;
+
+  bond?: EthBondConfig;
 };
 
+/**
+ * Ethernet link aggregation configuration.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/iface/ethport#BondConfig>
+ */
+export interface EthBondConfig {
+  members: EthNetifConfig[];
+
+  /**
+   * @default "ACTIVE_BACKUP"
+   */
+  mode?: "ACTIVE_BACKUP" | "BALANCE" | "LACP";
+}
+
 interface EthFaceConfig extends FaceConfig {
   port?: string;
 
@@ -75,6 +205,13 @@ interface EthFaceConfig extends FaceConfig {
    */
   nRxQueues?: Uint;
 
+  /**
+   * @minimum 1
+   * @maximum 4
+   * @default 1
+   */
+  nTxQueues?: Uint;
+
   disableTxMultiSegOffload?: boolean;
   disableTxChecksumOffload?: boolean;
 }
@@ -285,6 +422,7 @@ export interface SocketFaceLocator extends SocketFaceConfig {
  */
 export interface FaceCounters extends FaceRxCounters, FaceTxCounters {
   rxThreads: FaceRxCounters[];
+  txThreads: FaceTxCounters[];
 }
 
 export interface FaceRxCounters {
@@ -297,6 +435,7 @@ export interface FaceRxCounters {
   rxDecodeErrs: Counter;
   rxReassPackets: Counter;
   rxReassDrops: Counter;
+  rxIdles: Counter;
 }
 
 export interface FaceTxCounters {
@@ -310,4 +449,10 @@ export interface FaceTxCounters {
   txFragBad: Counter;
   txAllocErrs: Counter;
   txDropped: Counter;
+  txIdles: Counter;
+  txRejects: Counter;
+
+  txShaperThrottles: Counter;
+  txSchedDrops: Counter;
+  txCoDelMarks: Counter;
 }
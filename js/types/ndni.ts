@@ -1,4 +1,4 @@
-import type { NNMilliseconds, Uint } from "./core.js";
+import type { Counter, NNMilliseconds, RunningStatSnapshot, Uint } from "./core.js";
 
 /** Name represented as canonical URI. */
 export type Name = string;
@@ -50,4 +50,53 @@ export interface DataGen {
    * @minimum 0
    */
   payloadLen?: Uint;
+
+  /**
+   * Data signing.
+   * If omitted, Data packets have Null signature.
+   */
+  signing?: SigningConfig;
+}
+
+/**
+ * Data signing config.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/ndni#SigningConfig>
+ */
+export interface SigningConfig {
+  type: "sha256" | "ecdsa" | "hmac" | "ed25519";
+
+  /** KeyLocator name, ignored for sha256. */
+  keyName?: Name;
+
+  /**
+   * Private key file, ignored for sha256.
+   * This should be PKCS#8 PEM for ecdsa and ed25519, or raw secret for hmac.
+   * If omitted, a random key is generated.
+   */
+  keyFile?: string;
+}
+
+/**
+ * Data signature verification config.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/ndni#VerifyingConfig>
+ */
+export interface VerifyingConfig {
+  type: SigningConfig["type"];
+
+  /**
+   * Verification key file, required except for sha256.
+   * This should be PEM public key or X.509 certificate for ecdsa and ed25519, or raw secret for hmac.
+   */
+  keyFile?: string;
+}
+
+/**
+ * Data signing counters.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/ndni#SignerCounters>
+ */
+export interface SignerCounters {
+  nErrors: Counter;
+
+  /** Signing latency in nanoseconds. */
+  latency: RunningStatSnapshot;
 }
@@ -1,5 +1,5 @@
 import type { Counter, NNNanoseconds, RunningStatSnapshot, Uint } from "../core.js";
-import type { DataGen, InterestTemplate } from "../ndni.js";
+import type { DataGen, InterestTemplate, VerifyingConfig } from "../ndni.js";
 import type { PktQueueConfig } from "../pktqueue.js";
 
 /**
@@ -10,6 +10,8 @@ export interface TgcConfig {
   rxQueue?: PktQueueConfig.Plain | PktQueueConfig.Delay;
   interval: NNNanoseconds;
   patterns: TgcPattern[];
+  trace?: TgcTraceConfig;
+  verify?: TgcVerifyConfig;
 }
 
 /**
@@ -29,9 +31,72 @@ export interface TgcPattern extends InterestTemplate {
   seqNumOffset?: Uint;
 
   digest?: DataGen;
+
+  popularity?: TgcPopularityConfig;
+
+  /**
+   * @default 0
+   */
+  csHitRtt?: NNNanoseconds;
+}
+
+/**
+ * Traffic generator consumer popularity-based name generation config.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/app/tgconsumer#PopularityConfig>
+ */
+export interface TgcPopularityConfig {
+  distribution: "zipf" | "uniform" | "hotcold";
+
+  /**
+   * @minimum 1
+   */
+  catalogSize: Uint;
+
+  /**
+   * @default 1
+   */
+  alpha?: number;
+
+  hotSize?: Uint;
+
+  /**
+   * @default 0.8
+   */
+  hotProbability?: number;
 }
 
-export interface TgcCounters extends TgcCounters.PacketCounters {
+/**
+ * Traffic generator consumer trace replay config.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/app/tgconsumer#TraceConfig>
+ */
+export interface TgcTraceConfig {
+  filename: string;
+  format?: "csv" | "pcapng";
+
+  /**
+   * @default 1
+   */
+  speedup?: number;
+
+  loop?: boolean;
+}
+
+/**
+ * Traffic generator consumer verifying mode config.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/app/tgconsumer#VerifyConfig>
+ */
+export interface TgcVerifyConfig {
+  /**
+   * @default 65536
+   * @minimum 64
+   * @maximum 4194304
+   */
+  windowSize?: Uint;
+
+  signing?: VerifyingConfig;
+}
+
+export interface TgcCounters extends TgcCounters.PacketCounters, TgcCounters.VerifyCounters {
   nAllocError: Counter;
   rtt: RunningStatSnapshot;
   perPattern: TgcCounters.PatternCounters[];
@@ -44,7 +109,24 @@ export namespace TgcCounters {
     nNacks: Counter;
   }
 
-  export interface PatternCounters extends PacketCounters {
+  export interface VerifyCounters {
+    nDuplicates: Counter;
+    nUnsolicited: Counter;
+    nWrongNames: Counter;
+    nDigestMismatches: Counter;
+    nSigFailures: Counter;
+  }
+
+  export interface PatternCounters extends PacketCounters, VerifyCounters {
+    nCsHits: Counter;
+    csHitRtt: NNNanoseconds;
     rtt: RunningStatSnapshot;
+    rttHistogram: RttBucket[];
+  }
+
+  export interface RttBucket {
+    lower: NNNanoseconds;
+    upper: NNNanoseconds;
+    count: Counter;
   }
 }
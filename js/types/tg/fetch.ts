@@ -1,8 +1,8 @@
-import type { Counter, NNNanoseconds, Uint } from "../core.js";
+import type { Counter, NNMilliseconds, NNNanoseconds, Uint } from "../core.js";
 import type { InterestTemplate } from "../ndni.js";
 import type { PktQueueConfig } from "../pktqueue.js";
 
-export interface FetcherConfig {
+export interface FetcherConfig extends FetchCcConfig {
   /**
    * @minimum 1
    * @default 1
@@ -22,15 +22,69 @@ export interface FetcherConfig {
    * @default 65536
    */
   windowCapacity?: Uint;
+
+  historyInterval?: NNMilliseconds;
+
+  /**
+   * Maximum number of retained history samples; negative value disables history.
+   * @default 600
+   */
+  historyCapacity?: number;
 }
 
-export interface FetchTaskDef extends InterestTemplate {
+export interface FetchCcConfig {
+  /** @default "cubic" */
+  congestionControl?: "cubic" | "aimd" | "bbr" | "fixed";
+
+  /**
+   * @minimum 1
+   * @maximum 2147483647
+   * @default 64
+   */
+  fixedCwnd?: Uint;
+}
+
+export interface FetchTaskDef extends InterestTemplate, FetchCcConfig {
+  /**
+   * Perform RDR metadata discovery.
+   * If true, prefix should be unversioned, and segmentEnd, fileSize, segmentLen are filled from metadata.
+   * @default false
+   */
+  discover?: boolean;
+
   segmentBegin?: Uint;
   segmentEnd?: Uint;
 
   filename?: string;
   fileSize?: Uint;
   segmentLen?: Uint;
+
+  /**
+   * Keep a sidecar bitmap of written segments, and skip them when the task is restarted.
+   * @default false
+   */
+  resume?: boolean;
+
+  /**
+   * Expected SHA-256 digest of output file, in hexadecimal.
+   */
+  sha256?: string;
+}
+
+export interface FetchDirDef extends InterestTemplate, FetchCcConfig {
+  outputDir: string;
+
+  /**
+   * @minimum 1
+   * @default 4
+   */
+  concurrency?: Uint;
+
+  /**
+   * @minimum 1
+   * @default 32
+   */
+  maxDepth?: Uint;
 }
 
 export interface FetchCounters {
@@ -43,4 +97,22 @@ export interface FetchCounters {
   nInFlight: Counter;
   nTxRetx: Counter;
   nRxData: Counter;
+  nRxNack: Counter;
+  nSkipped: Counter;
+}
+
+export interface FetchDirCounters {
+  elapsed: NNNanoseconds;
+  finished?: NNNanoseconds;
+  listed: boolean;
+  nDirs: Counter;
+  nFiles: Counter;
+  nRunning: Counter;
+  nFinished: Counter;
+  nFailed: Counter;
+  nOctets: Counter;
+  failures: Array<{
+    name: string;
+    error: string;
+  }>;
 }
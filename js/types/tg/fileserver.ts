@@ -1,5 +1,5 @@
 import type { Counter, NNNanoseconds, Ratio, Uint } from "../core.js";
-import type { Name } from "../ndni.js";
+import type { Name, SignerCounters, SigningConfig } from "../ndni.js";
 import type { PktQueueConfig } from "../pktqueue.js";
 
 /**
@@ -18,7 +18,9 @@ export interface FileServerConfig {
   keepFds?: Uint;
   resolveBeneath?: boolean;
   statValidity?: NNNanoseconds;
+  sha256?: boolean;
   wantVersionBypass?: boolean;
+  signing?: SigningConfig;
 }
 
 /**
@@ -47,4 +49,5 @@ export interface FileServerCounters {
   uringSubmitNonBlock: Counter;
   uringSubmitWait: Counter;
   cqeFail: Counter;
+  signing: SignerCounters;
 }
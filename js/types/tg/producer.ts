@@ -1,5 +1,5 @@
 import type { Counter, Uint } from "../core.js";
-import type { DataGen, Name } from "../ndni.js";
+import type { DataGen, Name, SignerCounters } from "../ndni.js";
 import type { PktQueueConfig } from "../pktqueue.js";
 
 /**
@@ -53,6 +53,7 @@ export interface TgpCounters {
   nInterests: Counter;
   nNoMatch: Counter;
   nAllocError: Counter;
+  signing: SignerCounters;
 }
 export namespace TgpCounters {
   export interface PatternCounters {
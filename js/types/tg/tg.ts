@@ -1,3 +1,4 @@
+import type { NNMilliseconds, NNNanoseconds, Uint } from "../core.js";
 import type { FaceLocator } from "../iface.js";
 import type { TgcConfig } from "./consumer.js";
 import type { FetcherConfig } from "./fetch.js";
@@ -10,6 +11,7 @@ import type { TgpConfig } from "./producer.js";
  */
 export type TgConfig = {
   face: FaceLocator;
+  scenario?: TgScenario;
 } & ({
   producer?: TgpConfig;
 } | {
@@ -19,3 +21,24 @@ export type TgConfig = {
 } | {
   fetcher?: FetcherConfig;
 });
+
+/**
+ * Traffic generator scenario.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/app/tg#Scenario>
+ */
+export interface TgScenario {
+  /** @minItems 1 */
+  phases: TgPhase[];
+}
+
+/**
+ * Traffic generator scenario phase.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/app/tg#Phase>
+ */
+export interface TgPhase {
+  name?: string;
+  duration: NNMilliseconds;
+  interval?: NNNanoseconds;
+  weights?: Uint[];
+  replyWeights?: Array<Uint[] | null>;
+}
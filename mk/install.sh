@@ -9,6 +9,7 @@ install -m0644 build/lib/bpf/*.o "$DESTBPF/"
 install -d -m0755 "$DESTBIN"
 install -m0755 build/bin/ndndpdk-ctrl "$DESTBIN/"
 install -m0755 build/bin/ndndpdk-godemo "$DESTBIN/"
+install -m0755 build/bin/ndndpdk-hrlog-analyze "$DESTBIN/"
 install -m0755 build/bin/ndndpdk-hrlog2histogram "$DESTBIN/"
 install -m0755 build/bin/ndndpdk-jrproxy "$DESTBIN/"
 install -m0755 build/bin/ndndpdk-svc "$DESTBIN/"
@@ -131,9 +131,12 @@ func (f *face) rxLoop() {
 			continue
 		}
 
-		if pkt.Fragment == nil {
+		switch {
+		case pkt.Fragment == nil && pkt.Interest == nil && pkt.Data == nil && pkt.Nack == nil:
+			// NDNLPv2 IDLE packet
+		case pkt.Fragment == nil:
 			f.rx <- &pkt
-		} else {
+		default:
 			full, e := f.reassembler.Accept(&pkt)
 			if e == nil && full != nil {
 				f.rx <- full
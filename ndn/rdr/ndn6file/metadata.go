@@ -3,8 +3,10 @@
 package ndn6file
 
 import (
+	"crypto/sha256"
 	"encoding"
 	"math"
+	"slices"
 	"time"
 
 	"github.com/usnistgov/ndn-dpdk/ndn"
@@ -23,6 +25,9 @@ const (
 	TtCtime       = 0xF50A
 	TtMtime       = 0xF50C
 
+	// TtSha256 is an NDN-DPDK extension that carries SHA-256 digest of file content.
+	TtSha256 = 0xF510
+
 	_ = "enumgen::TtFile:Tt"
 )
 
@@ -42,6 +47,7 @@ type Metadata struct {
 	Btime       time.Time
 	Ctime       time.Time
 	Mtime       time.Time
+	Sha256      []byte
 }
 
 var (
@@ -93,6 +99,9 @@ func (m Metadata) MarshalBinary() (value []byte, e error) {
 	if !m.Mtime.IsZero() {
 		extensions = append(extensions, tlv.TLVNNI(TtMtime, m.Mtime.UnixNano()))
 	}
+	if len(m.Sha256) > 0 {
+		extensions = append(extensions, tlv.TLVBytes(TtSha256, m.Sha256))
+	}
 	return m.Encode(extensions...)
 }
 
@@ -131,5 +140,12 @@ func (m *Metadata) UnmarshalBinary(value []byte) error {
 			m.Mtime = time.Unix(0, int64(de.UnmarshalNNI(math.MaxInt64, &e, tlv.ErrRange)))
 			return e
 		},
+		TtSha256: func(de tlv.DecodingElement) error {
+			if de.Length() != sha256.Size {
+				return tlv.ErrRange
+			}
+			m.Sha256 = slices.Clone(de.Value)
+			return nil
+		},
 	})
 }
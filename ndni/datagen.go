@@ -8,6 +8,7 @@ import (
 	"unsafe"
 
 	"github.com/usnistgov/ndn-dpdk/core/nnduration"
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
 	"github.com/usnistgov/ndn-dpdk/dpdk/pktmbuf"
 	"github.com/usnistgov/ndn-dpdk/ndn"
 	"github.com/usnistgov/ndn-dpdk/ndn/an"
@@ -80,10 +81,19 @@ func (gen *DataGen) Init(m *pktmbuf.Packet, args ...any) {
 	C.rte_pktmbuf_trim(gen.tpl, C.uint16_t(C.size_t(pktmbuf.PacketFromPtr(unsafe.Pointer(gen.tpl)).Len())-gen.contentIov[0].iov_len))
 }
 
+// Signer returns the DataSigner, or nil if Data packets have Null signature.
+func (gen *DataGen) Signer() *DataSigner {
+	return (*DataSigner)(gen.signer)
+}
+
 // Close discards this DataGen.
 func (gen *DataGen) Close() error {
 	tpl := pktmbuf.PacketFromPtr(unsafe.Pointer(gen.tpl))
+	signer := gen.Signer()
 	*gen = DataGen{}
+	if signer != nil {
+		signer.Close()
+	}
 	return tpl.Close()
 }
 
@@ -106,10 +116,32 @@ type DataGenConfig struct {
 	Suffix          ndn.Name                `json:"suffix,omitempty"`
 	FreshnessPeriod nnduration.Milliseconds `json:"freshnessPeriod,omitempty"`
 	PayloadLen      int                     `json:"payloadLen,omitempty"`
+
+	// Signing enables Data signing.
+	// If omitted, Data packets have Null signature.
+	Signing *SigningConfig `json:"signing,omitempty"`
+}
+
+// Validate validates the configuration.
+func (cfg *DataGenConfig) Validate() error {
+	if cfg.Signing != nil {
+		return cfg.Signing.Validate()
+	}
+	return nil
 }
 
 // Apply initializes DataGen.
+// If Signing is specified, cfg.Validate must have been invoked.
+// Panics on error.
 func (cfg DataGenConfig) Apply(gen *DataGen, m *pktmbuf.Packet) {
 	content := make([]byte, cfg.PayloadLen)
 	gen.Init(m, cfg.Suffix, cfg.FreshnessPeriod.Duration(), content)
+
+	if cfg.Signing != nil {
+		signer, e := NewDataSigner(cfg.Signing, eal.NumaSocketFromID(int(gen.tpl.pool.socket_id)))
+		if e != nil {
+			logger.Panic("NewDataSigner error", zap.Error(e))
+		}
+		gen.signer = signer.ptr()
+	}
 }
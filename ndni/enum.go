@@ -66,6 +66,13 @@ const (
 		1 + 1 + 1 + 1 + 1 + // DSigInfo
 		1 + 1 // DSigValue
 
+	// DataSignerSigInfoBufLen is the buffer length for SignatureInfo in DataSigner.
+	DataSignerSigInfoBufLen = 256
+
+	// DataSignerMaxSigValueLen is the maximum TLV-LENGTH of SignatureValue created by DataSigner.
+	// This is the maximum DER-encoded ECDSA P-256 signature length.
+	DataSignerMaxSigValueLen = 72
+
 	// DataGenBufLen is the buffer length for DataGen.
 	DataGenBufLen = 0 +
 		1 + 3 + NameMaxLength + // Name suffix
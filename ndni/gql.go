@@ -7,6 +7,7 @@ import (
 	"github.com/graphql-go/graphql/language/ast"
 	"github.com/usnistgov/ndn-dpdk/core/gqlserver"
 	"github.com/usnistgov/ndn-dpdk/core/nnduration"
+	"github.com/usnistgov/ndn-dpdk/core/runningstat"
 	"github.com/usnistgov/ndn-dpdk/ndn"
 )
 
@@ -15,7 +16,9 @@ var (
 	GqlNameType                   *graphql.Scalar
 	GqlInterestTemplateInput      *graphql.InputObject
 	GqlInterestTemplateFieldTypes gqlserver.FieldTypes
+	GqlSigningInput               *graphql.InputObject
 	GqlDataGenInput               *graphql.InputObject
+	GqlSignerCountersType         *graphql.Object
 )
 
 func init() {
@@ -59,12 +62,26 @@ func init() {
 		Fields:      gqlserver.BindInputFields[InterestTemplateConfig](GqlInterestTemplateFieldTypes),
 	})
 
+	GqlSigningInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "SigningInput",
+		Description: "Data signing config.",
+		Fields: gqlserver.BindInputFields[SigningConfig](gqlserver.FieldTypes{
+			reflect.TypeFor[ndn.Name](): gqlserver.NonNullString,
+		}),
+	})
 	GqlDataGenInput = graphql.NewInputObject(graphql.InputObjectConfig{
 		Name:        "DataGenInput",
 		Description: "Data generator template.",
 		Fields: gqlserver.BindInputFields[DataGenConfig](gqlserver.FieldTypes{
 			reflect.TypeFor[ndn.Name]():                gqlserver.NonNullString,
 			reflect.TypeFor[nnduration.Milliseconds](): nnduration.GqlMilliseconds,
+			reflect.TypeFor[SigningConfig]():           GqlSigningInput,
+		}),
+	})
+	GqlSignerCountersType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "SignerCounters",
+		Fields: gqlserver.BindFields[SignerCounters](gqlserver.FieldTypes{
+			reflect.TypeFor[runningstat.Snapshot](): runningstat.GqlSnapshotType,
 		}),
 	})
 }
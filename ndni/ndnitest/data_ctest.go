@@ -169,7 +169,7 @@ func ctestDataEnc(t *testing.T) {
 			tpl.mbuf, unsafe.SliceData(tplIov[:]), tplIovcnt, mpC, align)
 		require.NotNil(m)
 
-		pkt := toPacket(unsafe.Pointer(C.DataEnc_Sign(m, mpC, align)))
+		pkt := toPacket(unsafe.Pointer(C.DataEnc_Sign(m, mpC, align, nil)))
 		require.NotNil(pkt)
 		defer pkt.Close()
 
@@ -191,7 +191,7 @@ func ctestDataEnc(t *testing.T) {
 			tpl.mbuf, unsafe.SliceData(tplIov[:]), tplIovcnt, mpC, align)
 		require.NotNil(m)
 
-		pkt := toPacket(unsafe.Pointer(C.DataEnc_Sign(m, mpC, align)))
+		pkt := toPacket(unsafe.Pointer(C.DataEnc_Sign(m, mpC, align, nil)))
 		require.NotNil(pkt)
 		defer pkt.Close()
 
@@ -218,7 +218,7 @@ func ctestDataEnc(t *testing.T) {
 
 		fillContent(roomIov[:], roomIovcnt)
 
-		pkt := toPacket(unsafe.Pointer(C.DataEnc_Sign(m, mpC, align)))
+		pkt := toPacket(unsafe.Pointer(C.DataEnc_Sign(m, mpC, align, nil)))
 		require.NotNil(pkt)
 		defer pkt.Close()
 
@@ -242,7 +242,7 @@ func ctestDataEnc(t *testing.T) {
 		require.NotNil(m)
 
 		fillContent(roomIov[:], roomIovcnt)
-		pkt := toPacket(unsafe.Pointer(C.DataEnc_Sign(m, mpC, align)))
+		pkt := toPacket(unsafe.Pointer(C.DataEnc_Sign(m, mpC, align, nil)))
 		require.NotNil(pkt)
 		defer pkt.Close()
 
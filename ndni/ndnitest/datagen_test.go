@@ -2,12 +2,23 @@ package ndnitest
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
 	"github.com/usnistgov/ndn-dpdk/ndn"
 	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
 	"github.com/usnistgov/ndn-dpdk/ndni"
 )
 
@@ -69,3 +80,129 @@ func TestDataGen(t *testing.T) {
 		}
 	})
 }
+
+func TestDataGenSigning(t *testing.T) {
+	_, require := makeAR(t)
+	payloadMp := ndni.PayloadMempool.Get(eal.NumaSocket{})
+	var mp ndni.Mempools
+	mp.Assign(eal.NumaSocket{}, ndni.DataMempool)
+
+	dir := t.TempDir()
+	keyName := ndn.ParseName("/A/KEY/k")
+	writePkcs8 := func(filename string, pvt any) string {
+		der, e := x509.MarshalPKCS8PrivateKey(pvt)
+		require.NoError(e)
+		filename = filepath.Join(dir, filename)
+		require.NoError(os.WriteFile(filename, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+		return filename
+	}
+	writePkix := func(filename string, pub any) string {
+		der, e := x509.MarshalPKIXPublicKey(pub)
+		require.NoError(e)
+		filename = filepath.Join(dir, filename)
+		require.NoError(os.WriteFile(filename, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o644))
+		return filename
+	}
+
+	ecPvt, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(e)
+	ecPub, e := keychain.NewECDSAPublicKey(keyName, &ecPvt.PublicKey)
+	require.NoError(e)
+	edPubRaw, edPvt, e := ed25519.GenerateKey(rand.Reader)
+	require.NoError(e)
+	edPub, e := keychain.NewEd25519PublicKey(keyName, edPubRaw)
+	require.NoError(e)
+	hmacKey := []byte("0123456789ABCDEF0123456789ABCDEF")
+	hmacFile := filepath.Join(dir, "hmac.key")
+	require.NoError(os.WriteFile(hmacFile, hmacKey, 0o600))
+
+	verifyHmac := func(data ndn.Data) error {
+		return data.VerifyWith(func(_ ndn.Name, si ndn.SigInfo) (ndn.LLVerify, error) {
+			if si.Type != an.SigHmacWithSha256 {
+				return nil, ndn.ErrSigType
+			}
+			return func(input, sig []byte) error {
+				h := hmac.New(sha256.New, hmacKey)
+				h.Write(input)
+				if !hmac.Equal(h.Sum(nil), sig) {
+					return ndn.ErrSigValue
+				}
+				return nil
+			}, nil
+		})
+	}
+
+	for _, tt := range []struct {
+		cfg    ndni.SigningConfig
+		vcfg   ndni.VerifyingConfig
+		verify func(data ndn.Data) error
+	}{
+		{
+			cfg:    ndni.SigningConfig{Type: ndni.SigningSha256},
+			vcfg:   ndni.VerifyingConfig{Type: ndni.SigningSha256},
+			verify: func(data ndn.Data) error { return ndn.DigestSigning.Verify(data) },
+		},
+		{
+			cfg:    ndni.SigningConfig{Type: ndni.SigningEcdsa, KeyName: keyName, KeyFile: writePkcs8("ecdsa.pem", ecPvt)},
+			vcfg:   ndni.VerifyingConfig{Type: ndni.SigningEcdsa, KeyFile: writePkix("ecdsa.pub", &ecPvt.PublicKey)},
+			verify: func(data ndn.Data) error { return ecPub.Verify(data) },
+		},
+		{
+			cfg:    ndni.SigningConfig{Type: ndni.SigningHmac, KeyName: keyName, KeyFile: hmacFile},
+			vcfg:   ndni.VerifyingConfig{Type: ndni.SigningHmac, KeyFile: hmacFile},
+			verify: verifyHmac,
+		},
+		{
+			cfg:    ndni.SigningConfig{Type: ndni.SigningEd25519, KeyName: keyName, KeyFile: writePkcs8("ed25519.pem", edPvt)},
+			vcfg:   ndni.VerifyingConfig{Type: ndni.SigningEd25519, KeyFile: writePkix("ed25519.pub", edPubRaw)},
+			verify: func(data ndn.Data) error { return edPub.Verify(data) },
+		},
+	} {
+		t.Run(string(tt.cfg.Type), func(t *testing.T) {
+			assert, require := makeAR(t)
+
+			genCfg := ndni.DataGenConfig{
+				Suffix:     ndn.ParseName("/suffix"),
+				PayloadLen: 1200,
+				Signing:    &tt.cfg,
+			}
+			require.NoError(genCfg.Validate())
+
+			var gen ndni.DataGen
+			genCfg.Apply(&gen, payloadMp.MustAlloc(1)[0])
+			defer gen.Close()
+			signer := gen.Signer()
+			require.NotNil(signer)
+
+			require.NoError(tt.vcfg.Validate())
+			verifier, e := ndni.NewDataVerifier(&tt.vcfg, eal.NumaSocket{})
+			require.NoError(e)
+			defer verifier.Close()
+
+			for _, fragmentPayloadSize := range []int{0, 1000} {
+				pkt := gen.Encode(ndn.ParseName("/prefix"), &mp, fragmentPayloadSize)
+				require.NotNil(pkt)
+				data := pkt.ToNPacket().Data
+				require.NotNil(data)
+				nameEqual(assert, "/prefix/suffix", data)
+				assert.Len(data.Content, 1200)
+				assert.NoError(tt.verify(*data))
+				assert.True(signer.Verify(pkt))
+				assert.True(verifier.Verify(pkt))
+				pkt.Close()
+			}
+
+			cnt := signer.Counters()
+			assert.EqualValues(2, cnt.Latency.Count)
+			assert.Zero(cnt.NErrors)
+		})
+	}
+
+	cfg := ndni.SigningConfig{Type: ndni.SigningEcdsa, KeyFile: writePkcs8("ed25519-as-ecdsa.pem", edPvt)}
+	require.Error(cfg.Validate())
+
+	vcfg := ndni.VerifyingConfig{Type: ndni.SigningEcdsa, KeyFile: writePkix("ed25519-as-ecdsa.pub", edPubRaw)}
+	require.Error(vcfg.Validate())
+	vcfg = ndni.VerifyingConfig{Type: ndni.SigningEcdsa, KeyFile: cfg.KeyFile}
+	require.Error(vcfg.Validate())
+}
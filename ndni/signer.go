@@ -0,0 +1,377 @@
+package ndni
+
+/*
+#include "../csrc/ndni/signer.h"
+*/
+import "C"
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"unsafe"
+
+	"github.com/usnistgov/ndn-dpdk/core/runningstat"
+	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// SigningType indicates Data signature type.
+type SigningType string
+
+// SigningType values.
+const (
+	SigningSha256  SigningType = "sha256"
+	SigningEcdsa   SigningType = "ecdsa"
+	SigningHmac    SigningType = "hmac"
+	SigningEd25519 SigningType = "ed25519"
+)
+
+// hmacKeyLen is the length of randomly generated HMAC key.
+const hmacKeyLen = 32
+
+// SigningConfig configures Data signing.
+//
+// Each thread that encodes Data has its own DataSigner created from the same SigningConfig.
+// Signing is performed synchronously in the encoding thread, so that it reduces throughput accordingly.
+type SigningConfig struct {
+	// Type is the signature type.
+	Type SigningType `json:"type"`
+
+	// KeyName is the Name in KeyLocator.
+	// If empty, KeyLocator is omitted.
+	// This is ignored for SigningSha256.
+	KeyName ndn.Name `json:"keyName,omitempty"`
+
+	// KeyFile is the private key file.
+	// For SigningEcdsa and SigningEd25519, it should be a PEM file that contains a PKCS #8 private key,
+	// where the ECDSA key must use P-256 curve.
+	// For SigningHmac, the whole file content is used as the HMAC key.
+	// If empty, a random key is generated.
+	// This is ignored for SigningSha256.
+	KeyFile string `json:"keyFile,omitempty"`
+
+	sigType uint8
+	key     []byte
+	sigInfo []byte
+}
+
+// Validate validates the configuration and loads or generates the private key.
+// This must be called before creating DataSigner; if the key is randomly generated, every DataSigner
+// created from the same SigningConfig shares the same key.
+func (cfg *SigningConfig) Validate() (e error) {
+	si := ndn.SigInfo{}
+	switch cfg.Type {
+	case SigningSha256:
+		si.Type, cfg.key = an.SigSha256, nil
+	case SigningHmac:
+		si.Type = an.SigHmacWithSha256
+		if cfg.KeyFile == "" {
+			cfg.key = make([]byte, hmacKeyLen)
+			rand.Read(cfg.key)
+		} else if cfg.key, e = os.ReadFile(cfg.KeyFile); e != nil {
+			return e
+		}
+		if len(cfg.key) == 0 {
+			return errors.New("HMAC key is empty")
+		}
+	case SigningEcdsa:
+		si.Type = an.SigSha256WithEcdsa
+		if cfg.key, e = cfg.loadPkcs8(func() (any, error) { return ecdsa.GenerateKey(elliptic.P256(), rand.Reader) }); e != nil {
+			return e
+		}
+	case SigningEd25519:
+		si.Type = an.SigEd25519
+		if cfg.key, e = cfg.loadPkcs8(func() (any, error) {
+			_, pvt, e := ed25519.GenerateKey(rand.Reader)
+			return pvt, e
+		}); e != nil {
+			return e
+		}
+	default:
+		return fmt.Errorf("unknown signing type %s", cfg.Type)
+	}
+	cfg.sigType = uint8(si.Type)
+
+	if si.Type != an.SigSha256 {
+		si.KeyLocator.Name = cfg.KeyName
+	}
+	if cfg.sigInfo, e = tlv.EncodeFrom(si.EncodeAs(an.TtDSigInfo)); e != nil {
+		return e
+	}
+	if len(cfg.sigInfo) > DataSignerSigInfoBufLen {
+		return errors.New("KeyName too long")
+	}
+	return nil
+}
+
+// TrailerLen returns the maximum length of SignatureInfo and SignatureValue.
+// If cfg is nil, returns the length of Null signature.
+// cfg.Validate must have been invoked.
+func (cfg *SigningConfig) TrailerLen() int {
+	if cfg == nil {
+		return DataEncNullSigLen
+	}
+	sigValueMaxLen := DataSignerMaxSigValueLen
+	switch cfg.Type {
+	case SigningSha256, SigningHmac:
+		sigValueMaxLen = 32
+	case SigningEd25519:
+		sigValueMaxLen = ed25519.SignatureSize
+	}
+	return len(cfg.sigInfo) + 2 + sigValueMaxLen
+}
+
+// loadPkcs8 reads a PKCS #8 private key from KeyFile, or generates a private key if KeyFile is empty.
+func (cfg SigningConfig) loadPkcs8(generate func() (any, error)) (der []byte, e error) {
+	var pvt any
+	if cfg.KeyFile == "" {
+		if pvt, e = generate(); e != nil {
+			return nil, e
+		}
+	} else {
+		file, e := os.ReadFile(cfg.KeyFile)
+		if e != nil {
+			return nil, e
+		}
+		block, _ := pem.Decode(file)
+		if block == nil || block.Type != "PRIVATE KEY" {
+			return nil, fmt.Errorf("%s: PKCS #8 PRIVATE KEY block not found", cfg.KeyFile)
+		}
+		if pvt, e = x509.ParsePKCS8PrivateKey(block.Bytes); e != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.KeyFile, e)
+		}
+	}
+
+	switch key := pvt.(type) {
+	case *ecdsa.PrivateKey:
+		if cfg.Type != SigningEcdsa || key.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("%s: unexpected ECDSA key", cfg.KeyFile)
+		}
+	case ed25519.PrivateKey:
+		if cfg.Type != SigningEd25519 {
+			return nil, fmt.Errorf("%s: unexpected Ed25519 key", cfg.KeyFile)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported key type %T", cfg.KeyFile, pvt)
+	}
+	return x509.MarshalPKCS8PrivateKey(pvt)
+}
+
+// VerifyingConfig configures Data signature verification.
+//
+// Each thread that verifies Data has its own DataSigner created from the same VerifyingConfig.
+type VerifyingConfig struct {
+	// Type is the signature type.
+	Type SigningType `json:"type"`
+
+	// KeyFile is the verification key file.
+	// For SigningEcdsa and SigningEd25519, it should be a PEM file that contains a PKIX public key
+	// or an X.509 certificate, where the ECDSA key must use P-256 curve.
+	// For SigningHmac, the whole file content is used as the HMAC key, which is the shared secret
+	// between the signing and verifying parties.
+	// It is required except for SigningSha256.
+	KeyFile string `json:"keyFile,omitempty"`
+
+	sigType   uint8
+	key       []byte
+	validated bool
+}
+
+// Validate validates the configuration and loads the verification key.
+// This must be called before creating DataSigner.
+func (cfg *VerifyingConfig) Validate() (e error) {
+	cfg.validated, cfg.key = false, nil
+	switch cfg.Type {
+	case SigningSha256:
+		cfg.sigType, cfg.validated = uint8(an.SigSha256), true
+		return nil
+	case SigningHmac:
+		cfg.sigType = uint8(an.SigHmacWithSha256)
+	case SigningEcdsa:
+		cfg.sigType = uint8(an.SigSha256WithEcdsa)
+	case SigningEd25519:
+		cfg.sigType = uint8(an.SigEd25519)
+	default:
+		return fmt.Errorf("unknown signing type %s", cfg.Type)
+	}
+
+	if cfg.KeyFile == "" {
+		return errors.New("signature verification requires KeyFile")
+	}
+	file, e := os.ReadFile(cfg.KeyFile)
+	if e != nil {
+		return e
+	}
+	if cfg.Type == SigningHmac {
+		if len(file) == 0 {
+			return errors.New("HMAC key is empty")
+		}
+		cfg.key = file
+	} else if cfg.key, e = cfg.loadPublicKey(file); e != nil {
+		return e
+	}
+	cfg.validated = true
+	return nil
+}
+
+// loadPublicKey parses a PKIX public key or X.509 certificate, and returns the public key in PKIX DER.
+func (cfg VerifyingConfig) loadPublicKey(file []byte) (der []byte, e error) {
+	var pub any
+	switch block, _ := pem.Decode(file); {
+	case block == nil:
+		return nil, fmt.Errorf("%s: PEM block not found", cfg.KeyFile)
+	case block.Type == "PUBLIC KEY":
+		if pub, e = x509.ParsePKIXPublicKey(block.Bytes); e != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.KeyFile, e)
+		}
+	case block.Type == "CERTIFICATE":
+		cert, e := x509.ParseCertificate(block.Bytes)
+		if e != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.KeyFile, e)
+		}
+		pub = cert.PublicKey
+	default:
+		return nil, fmt.Errorf("%s: PUBLIC KEY or CERTIFICATE block not found", cfg.KeyFile)
+	}
+
+	switch key := pub.(type) {
+	case *ecdsa.PublicKey:
+		if cfg.Type != SigningEcdsa || key.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("%s: unexpected ECDSA key", cfg.KeyFile)
+		}
+	case ed25519.PublicKey:
+		if cfg.Type != SigningEd25519 {
+			return nil, fmt.Errorf("%s: unexpected Ed25519 key", cfg.KeyFile)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported key type %T", cfg.KeyFile, pub)
+	}
+	return x509.MarshalPKIXPublicKey(pub)
+}
+
+// DataSigner is a per-thread Data signer.
+type DataSigner C.DataSigner
+
+// NewDataSigner creates a DataSigner.
+// cfg.Validate must have been invoked.
+func NewDataSigner(cfg *SigningConfig, socket eal.NumaSocket) (*DataSigner, error) {
+	if cfg.sigInfo == nil {
+		return nil, errors.New("SigningConfig is not validated")
+	}
+
+	c := eal.Zmalloc[C.DataSigner]("DataSigner", C.sizeof_DataSigner, socket)
+	var keyPtr *C.uint8_t
+	if len(cfg.key) > 0 {
+		keyPtr = (*C.uint8_t)(unsafe.SliceData(cfg.key))
+	}
+	sigInfo := cfg.sigInfo
+	ok := C.DataSigner_Init(c, C.uint8_t(cfg.sigType), keyPtr, C.size_t(len(cfg.key)),
+		(*C.uint8_t)(unsafe.SliceData(sigInfo)), C.uint16_t(len(sigInfo)))
+	signer := (*DataSigner)(c)
+	if !ok {
+		signer.Close()
+		return nil, fmt.Errorf("DataSigner_Init(%s) error", cfg.Type)
+	}
+	signer.latency().Init(0)
+	return signer, nil
+}
+
+// NewDataVerifier creates a DataSigner for verification only.
+// cfg.Validate must have been invoked.
+func NewDataVerifier(cfg *VerifyingConfig, socket eal.NumaSocket) (*DataSigner, error) {
+	if !cfg.validated {
+		return nil, errors.New("VerifyingConfig is not validated")
+	}
+
+	c := eal.Zmalloc[C.DataSigner]("DataSigner", C.sizeof_DataSigner, socket)
+	var keyPtr *C.uint8_t
+	if len(cfg.key) > 0 {
+		keyPtr = (*C.uint8_t)(unsafe.SliceData(cfg.key))
+	}
+	ok := C.DataSigner_InitVerifier(c, C.uint8_t(cfg.sigType), keyPtr, C.size_t(len(cfg.key)))
+	signer := (*DataSigner)(c)
+	if !ok {
+		signer.Close()
+		return nil, fmt.Errorf("DataSigner_InitVerifier(%s) error", cfg.Type)
+	}
+	signer.latency().Init(0)
+	return signer, nil
+}
+
+// DataSignerFromPtr converts *C.DataSigner pointer to DataSigner.
+func DataSignerFromPtr(ptr unsafe.Pointer) *DataSigner {
+	return (*DataSigner)(ptr)
+}
+
+// Ptr returns *C.DataSigner pointer.
+func (signer *DataSigner) Ptr() unsafe.Pointer {
+	return unsafe.Pointer(signer)
+}
+
+func (signer *DataSigner) ptr() *C.DataSigner {
+	return (*C.DataSigner)(signer)
+}
+
+func (signer *DataSigner) latency() *runningstat.IntStat {
+	return runningstat.IntFromPtr(unsafe.Pointer(&signer.ptr().latency))
+}
+
+// TrailerLen returns the maximum length of SignatureInfo and SignatureValue.
+func (signer *DataSigner) TrailerLen() int {
+	return int(C.DataSigner_TrailerLen(signer.ptr()))
+}
+
+// Verify verifies Data signature.
+// The DataSigner should be created by NewDataVerifier, or by NewDataSigner with the same key as the signing party.
+// KeyLocator is not checked.
+func (signer *DataSigner) Verify(pkt *Packet) bool {
+	return bool(C.DataSigner_Verify(signer.ptr(), (*C.struct_rte_mbuf)(pkt.Mbuf().Ptr())))
+}
+
+// Counters returns signing counters.
+func (signer *DataSigner) Counters() SignerCounters {
+	return SignerCounters{
+		NErrors: uint64(signer.ptr().nErrors),
+		Latency: signer.latency().Read().Scale(eal.TscNanos),
+	}
+}
+
+// ClearCounters clears signing counters.
+// The signer should not be in use, otherwise race conditions may occur.
+func (signer *DataSigner) ClearCounters() {
+	signer.ptr().nErrors = 0
+	signer.latency().Init(0)
+}
+
+// Close releases the DataSigner.
+func (signer *DataSigner) Close() error {
+	C.DataSigner_Close(signer.ptr())
+	eal.Free(signer.ptr())
+	return nil
+}
+
+// SignerCounters contains Data signing counters.
+type SignerCounters struct {
+	NErrors uint64               `json:"nErrors" gqldesc:"Signing errors."`
+	Latency runningstat.Snapshot `json:"latency" gqldesc:"Signing latency in nanoseconds."`
+}
+
+// Add combines counters.
+func (cnt SignerCounters) Add(other SignerCounters) SignerCounters {
+	return SignerCounters{
+		NErrors: cnt.NErrors + other.NErrors,
+		Latency: cnt.Latency.Add(other.Latency),
+	}
+}
+
+func (cnt SignerCounters) String() string {
+	return fmt.Sprintf("%dsigned %derr latency=%0.0fns", cnt.Latency.Count, cnt.NErrors, cnt.Latency.Mean)
+}